@@ -0,0 +1,46 @@
+// Package audit records destructive and privilege-sensitive admin
+// operations (deletes, role changes, review decisions) so they can be
+// traced back to the actor who performed them.
+package audit
+
+import (
+	"encoding/json"
+	"log/slog"
+
+	"github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/middleware"
+	"github.com/1055373165/blog/models"
+	"github.com/gin-gonic/gin"
+)
+
+// Record persists an AuditLog entry for action against entity/entityID,
+// snapshotting before and after as JSON. Either may be nil when not
+// applicable. A write failure is logged and otherwise ignored: audit
+// logging must never fail the operation it is recording.
+func Record(c *gin.Context, action, entityType string, entityID uint, before, after any) {
+	entry := models.AuditLog{
+		ActorID:    middleware.CurrentUserID(c),
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Before:     marshal(before),
+		After:      marshal(after),
+		IP:         c.ClientIP(),
+	}
+
+	if err := db.DB.Create(&entry).Error; err != nil {
+		slog.Error("failed to record audit log entry", "request_id", middleware.RequestID(c),
+			"action", action, "entity_type", entityType, "entity_id", entityID, "error", err)
+	}
+}
+
+func marshal(v any) string {
+	if v == nil {
+		return ""
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
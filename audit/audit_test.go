@@ -0,0 +1,64 @@
+package audit
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	blogdb "github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	conn, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	if err := conn.AutoMigrate(&models.AuditLog{}); err != nil {
+		t.Fatalf("failed to migrate test db: %v", err)
+	}
+	blogdb.DB = conn
+	return conn
+}
+
+func TestRecordPersistsEntryWithBeforeAndAfter(t *testing.T) {
+	db := setupTestDB(t)
+
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("DELETE", "/api/categories/1", nil)
+	c.Set("user_id", uint(7))
+
+	before := map[string]any{"id": 1, "name": "Go"}
+	Record(c, "delete", "category", 1, before, nil)
+
+	var entries []models.AuditLog
+	if err := db.Find(&entries).Error; err != nil {
+		t.Fatalf("failed to query audit log: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 audit log entry, got %d", len(entries))
+	}
+
+	got := entries[0]
+	if got.ActorID != 7 || got.Action != "delete" || got.EntityType != "category" || got.EntityID != 1 {
+		t.Errorf("unexpected entry: %+v", got)
+	}
+	if got.Before == "" || got.After != "" {
+		t.Errorf("expected non-empty before and empty after, got before=%q after=%q", got.Before, got.After)
+	}
+}
+
+func TestRecordDoesNotPanicOnWriteFailure(t *testing.T) {
+	setupTestDB(t)
+	blogdb.DB.Migrator().DropTable(&models.AuditLog{})
+
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("DELETE", "/api/categories/1", nil)
+
+	Record(c, "delete", "category", 1, nil, nil)
+}
@@ -0,0 +1,96 @@
+// Package avatar renders a deterministic identicon-style PNG - a solid
+// background color with a seed's first letter centered in white - for
+// handler.GetDefaultAvatar, served to any user who has never uploaded
+// their own avatar.
+package avatar
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// palette is the fixed set of background colors a generated avatar is
+// deterministically chosen from by hashing its seed, so the same seed
+// always renders the same color.
+var palette = []color.RGBA{
+	{R: 0xef, G: 0x44, B: 0x44, A: 0xff},
+	{R: 0xf9, G: 0x73, B: 0x16, A: 0xff},
+	{R: 0xca, G: 0x8a, B: 0x04, A: 0xff},
+	{R: 0x16, G: 0xa3, B: 0x4a, A: 0xff},
+	{R: 0x06, G: 0xb6, B: 0xd4, A: 0xff},
+	{R: 0x25, G: 0x63, B: 0xeb, A: 0xff},
+	{R: 0x7c, G: 0x3a, B: 0xed, A: 0xff},
+	{R: 0xdb, G: 0x27, B: 0x77, A: 0xff},
+}
+
+// Generate renders a size x size PNG: a background color chosen
+// deterministically from seed, with seed's first ASCII letter (if it
+// has one) centered in white.
+//
+// Text is drawn with x/image's bundled basicfont, which only covers
+// ASCII - the same limitation as package social's generated cards (see
+// its doc comment) - so a seed with no ASCII letter in it renders with
+// just the background color.
+func Generate(seed string, size int) ([]byte, error) {
+	if size < 1 {
+		size = 1
+	}
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(img, img.Bounds(), image.NewUniform(paletteColor(seed)), image.Point{}, draw.Src)
+
+	if letter := firstASCIILetter(seed); letter != "" {
+		drawCenteredLetter(img, letter, size)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// paletteColor picks palette's entry deterministically from a hash of
+// seed.
+func paletteColor(seed string) color.RGBA {
+	sum := sha256.Sum256([]byte(seed))
+	return palette[int(sum[0])%len(palette)]
+}
+
+// firstASCIILetter returns seed's first ASCII letter, uppercased, or ""
+// if it has none.
+func firstASCIILetter(seed string) string {
+	upper := strings.ToUpper(seed)
+	for i := 0; i < len(upper); i++ {
+		if c := upper[i]; c >= 'A' && c <= 'Z' {
+			return string(c)
+		}
+	}
+	return ""
+}
+
+// drawCenteredLetter draws letter in white, centered in a size x size
+// image.
+func drawCenteredLetter(img draw.Image, letter string, size int) {
+	face := basicfont.Face7x13
+	metrics := face.Metrics()
+	width := font.MeasureString(face, letter).Ceil()
+	x := (size - width) / 2
+	y := (size+metrics.Ascent.Ceil()-metrics.Descent.Ceil())/2 + metrics.Descent.Ceil()
+
+	d := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(color.White),
+		Face: face,
+		Dot:  fixed.Point26_6{X: fixed.I(x), Y: fixed.I(y)},
+	}
+	d.DrawString(letter)
+}
@@ -0,0 +1,36 @@
+package avatar
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+)
+
+func TestGenerateProducesValidPNGOfRequestedSize(t *testing.T) {
+	data, err := Generate("alice", 64)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to decode generated PNG: %v", err)
+	}
+	if bounds := img.Bounds(); bounds.Dx() != 64 || bounds.Dy() != 64 {
+		t.Errorf("expected 64x64, got %dx%d", bounds.Dx(), bounds.Dy())
+	}
+}
+
+func TestGenerateIsDeterministicPerSeed(t *testing.T) {
+	first, err := Generate("alice", 32)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	second, err := Generate("alice", 32)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Errorf("expected identical output for the same seed")
+	}
+}
@@ -0,0 +1,217 @@
+// Package backup produces logical, gzip-compressed JSON dumps of every
+// database table for operational recovery, without depending on an
+// external tool like mysqldump (this tree's only driver is SQLite; see
+// db.Init).
+package backup
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrNotFound is returned when a named backup has no dump file under dir.
+var ErrNotFound = errors.New("backup not found")
+
+// Status is the outcome of a backup run.
+type Status string
+
+const (
+	StatusReady  Status = "ready"
+	StatusFailed Status = "failed"
+)
+
+// TableResult records one table's outcome within a backup run.
+type TableResult struct {
+	Table string `json:"table"`
+	Rows  int    `json:"rows"`
+	Error string `json:"error,omitempty"`
+}
+
+// Manifest describes one backup run. It's persisted alongside its dump
+// file as <name>.meta.json so List can enumerate runs without reading
+// the (potentially large) dump itself.
+type Manifest struct {
+	Name      string        `json:"name"`
+	CreatedAt time.Time     `json:"created_at"`
+	Status    Status        `json:"status"`
+	SizeBytes int64         `json:"size_bytes"`
+	Tables    []TableResult `json:"tables"`
+}
+
+// Run dumps every table in db to a new gzip-compressed JSON file under
+// dir, named backup-<createdAt>.json.gz, and writes its Manifest
+// alongside it. A table that fails to read is recorded with its error
+// in Tables and marks the whole run StatusFailed, rather than being
+// silently dropped from the dump; the remaining tables are still
+// attempted so one bad table doesn't block the rest.
+func Run(db *gorm.DB, dir string, createdAt time.Time) (*Manifest, error) {
+	tables, err := db.Migrator().GetTables()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	sort.Strings(tables)
+
+	name := "backup-" + createdAt.UTC().Format("20060102T150405Z")
+	manifest := &Manifest{Name: name, CreatedAt: createdAt, Status: StatusReady}
+	dump := make(map[string][]map[string]any, len(tables))
+
+	for _, table := range tables {
+		var rows []map[string]any
+		if err := db.Table(table).Find(&rows).Error; err != nil {
+			manifest.Status = StatusFailed
+			manifest.Tables = append(manifest.Tables, TableResult{Table: table, Error: err.Error()})
+			continue
+		}
+		dump[table] = rows
+		manifest.Tables = append(manifest.Tables, TableResult{Table: table, Rows: len(rows)})
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create backup dir: %w", err)
+	}
+
+	if err := writeGzipJSON(dumpPath(dir, name), dump); err != nil {
+		return nil, fmt.Errorf("failed to write dump: %w", err)
+	}
+	if info, err := os.Stat(dumpPath(dir, name)); err == nil {
+		manifest.SizeBytes = info.Size()
+	}
+	if err := writeManifest(dir, manifest); err != nil {
+		return nil, fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// List returns every backup's manifest under dir, newest first. A
+// missing dir is treated as zero backups rather than an error, since
+// nothing has run yet.
+func List(dir string) ([]Manifest, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var manifests []Manifest
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".meta.json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			continue
+		}
+		var m Manifest
+		if err := json.Unmarshal(data, &m); err != nil {
+			continue
+		}
+		manifests = append(manifests, m)
+	}
+	sort.Slice(manifests, func(i, j int) bool { return manifests[i].CreatedAt.After(manifests[j].CreatedAt) })
+	return manifests, nil
+}
+
+// DumpFile returns the path to name's dump file under dir, for
+// streaming a download, after stripping any path separators from name
+// so a caller-supplied name can't escape dir.
+func DumpFile(dir, name string) (string, error) {
+	path := dumpPath(dir, filepath.Base(name))
+	if _, err := os.Stat(path); err != nil {
+		return "", ErrNotFound
+	}
+	return path, nil
+}
+
+// Prune deletes every backup under dir but the keep newest, for
+// retention.
+func Prune(dir string, keep int) error {
+	manifests, err := List(dir)
+	if err != nil {
+		return err
+	}
+	if len(manifests) <= keep {
+		return nil
+	}
+	for _, m := range manifests[keep:] {
+		os.Remove(dumpPath(dir, m.Name))
+		os.Remove(manifestPath(dir, m.Name))
+	}
+	return nil
+}
+
+func dumpPath(dir, name string) string {
+	return filepath.Join(dir, name+".json.gz")
+}
+
+func manifestPath(dir, name string) string {
+	return filepath.Join(dir, name+".meta.json")
+}
+
+func writeGzipJSON(path string, data any) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	defer gz.Close()
+
+	return json.NewEncoder(gz).Encode(data)
+}
+
+func writeManifest(dir string, m *Manifest) error {
+	f, err := os.Create(manifestPath(dir, m.Name))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return json.NewEncoder(f).Encode(m)
+}
+
+// StartScheduler runs a backup (and prunes to retain the newest keep)
+// every interval, logging failures rather than returning them since
+// there's no caller left to hand them to. It returns a stop function,
+// mirroring presence.Tracker.Start. Unlike package jobs, this loop is
+// conditional on cfg.BackupScheduleEnabled and predates the jobs
+// framework; it hasn't been migrated onto it.
+func StartScheduler(db *gorm.DB, dir string, keep int, interval time.Duration, logf func(format string, args ...any)) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				manifest, err := Run(db, dir, time.Now())
+				if err != nil {
+					logf("backup scheduler: run failed: %v", err)
+					continue
+				}
+				if manifest.Status != StatusReady {
+					logf("backup scheduler: %s completed with failed tables", manifest.Name)
+				}
+				if err := Prune(dir, keep); err != nil {
+					logf("backup scheduler: prune failed: %v", err)
+				}
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
@@ -0,0 +1,164 @@
+package backup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type testWidget struct {
+	ID   uint `gorm:"primaryKey"`
+	Name string
+}
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	conn, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	if err := conn.AutoMigrate(&testWidget{}); err != nil {
+		t.Fatalf("failed to migrate test db: %v", err)
+	}
+	return conn
+}
+
+func TestRunDumpsAllRowsAndWritesManifest(t *testing.T) {
+	conn := setupTestDB(t)
+	conn.Create(&testWidget{Name: "a"})
+	conn.Create(&testWidget{Name: "b"})
+
+	dir := t.TempDir()
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	manifest, err := Run(conn, dir, createdAt)
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if manifest.Status != StatusReady {
+		t.Errorf("expected StatusReady, got %q", manifest.Status)
+	}
+	if manifest.SizeBytes == 0 {
+		t.Error("expected a non-zero dump size")
+	}
+
+	var widgetResult *TableResult
+	for i := range manifest.Tables {
+		if manifest.Tables[i].Table == "test_widgets" {
+			widgetResult = &manifest.Tables[i]
+		}
+	}
+	if widgetResult == nil {
+		t.Fatal("expected test_widgets in the manifest's table list")
+	}
+	if widgetResult.Rows != 2 {
+		t.Errorf("expected 2 rows dumped for test_widgets, got %d", widgetResult.Rows)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, manifest.Name+".json.gz")); err != nil {
+		t.Errorf("expected dump file to exist: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, manifest.Name+".meta.json")); err != nil {
+		t.Errorf("expected manifest file to exist: %v", err)
+	}
+}
+
+func TestListReturnsNewestFirst(t *testing.T) {
+	conn := setupTestDB(t)
+	dir := t.TempDir()
+
+	older, err := Run(conn, dir, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	newer, err := Run(conn, dir, time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	manifests, err := List(dir)
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if len(manifests) != 2 {
+		t.Fatalf("expected 2 manifests, got %d", len(manifests))
+	}
+	if manifests[0].Name != newer.Name || manifests[1].Name != older.Name {
+		t.Errorf("expected newest-first order, got %q then %q", manifests[0].Name, manifests[1].Name)
+	}
+}
+
+func TestListOnMissingDirReturnsEmpty(t *testing.T) {
+	manifests, err := List(filepath.Join(t.TempDir(), "does-not-exist"))
+	if err != nil {
+		t.Fatalf("expected no error for a missing dir, got %v", err)
+	}
+	if len(manifests) != 0 {
+		t.Errorf("expected no manifests, got %d", len(manifests))
+	}
+}
+
+func TestDumpFileRejectsPathTraversal(t *testing.T) {
+	conn := setupTestDB(t)
+	dir := t.TempDir()
+	manifest, err := Run(conn, dir, time.Now())
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	if _, err := DumpFile(dir, manifest.Name); err != nil {
+		t.Errorf("expected the real backup to resolve, got %v", err)
+	}
+	if _, err := DumpFile(dir, "../../etc/passwd"); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound for a path-traversal name, got %v", err)
+	}
+}
+
+func TestPruneKeepsOnlyNewest(t *testing.T) {
+	conn := setupTestDB(t)
+	dir := t.TempDir()
+
+	var names []string
+	for i := 0; i < 3; i++ {
+		m, err := Run(conn, dir, time.Date(2026, 1, i+1, 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatalf("Run returned error: %v", err)
+		}
+		names = append(names, m.Name)
+	}
+
+	if err := Prune(dir, 1); err != nil {
+		t.Fatalf("Prune returned error: %v", err)
+	}
+
+	manifests, err := List(dir)
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if len(manifests) != 1 {
+		t.Fatalf("expected 1 manifest to remain, got %d", len(manifests))
+	}
+	if manifests[0].Name != names[len(names)-1] {
+		t.Errorf("expected the newest backup %q to remain, got %q", names[len(names)-1], manifests[0].Name)
+	}
+	if _, err := DumpFile(dir, names[0]); err != ErrNotFound {
+		t.Errorf("expected the oldest backup's dump file to be pruned, got %v", err)
+	}
+}
+
+func TestRunReturnsErrorWhenTableListingFails(t *testing.T) {
+	conn := setupTestDB(t)
+	sqlDB, err := conn.DB()
+	if err != nil {
+		t.Fatalf("failed to get underlying sql.DB: %v", err)
+	}
+	sqlDB.Close()
+
+	if _, err := Run(conn, t.TempDir(), time.Now()); err == nil {
+		t.Fatal("expected Run to return an error once the connection is closed")
+	}
+}
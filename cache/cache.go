@@ -0,0 +1,111 @@
+// Package cache provides a small in-process, TTL-based cache for hot read
+// endpoints whose underlying data changes rarely. It is explicitly
+// best-effort: safe to lose on restart and, by design, not shared across
+// instances, so callers should favor short TTLs plus explicit invalidation
+// over relying on it for correctness.
+package cache
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type entry struct {
+	value     any
+	expiresAt time.Time
+}
+
+// Cache is a generic get-or-compute cache with TTL and explicit
+// invalidation, safe for concurrent use.
+type Cache struct {
+	mu      sync.RWMutex
+	entries map[string]entry
+	ttl     time.Duration
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// New creates a Cache whose entries expire ttl after being set.
+func New(ttl time.Duration) *Cache {
+	return &Cache{entries: make(map[string]entry), ttl: ttl}
+}
+
+// GetOrCompute returns the cached value for key if present and unexpired,
+// otherwise calls compute, stores its result, and returns it.
+func (c *Cache) GetOrCompute(key string, compute func() (any, error)) (any, error) {
+	c.mu.RLock()
+	e, ok := c.entries[key]
+	c.mu.RUnlock()
+	if ok && time.Now().Before(e.expiresAt) {
+		c.hits.Add(1)
+		return e.value, nil
+	}
+
+	c.misses.Add(1)
+	value, err := compute()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[key] = entry{value: value, expiresAt: time.Now().Add(c.ttl)}
+	c.mu.Unlock()
+	return value, nil
+}
+
+// Invalidate removes key from the cache, if present.
+func (c *Cache) Invalidate(key string) {
+	c.mu.Lock()
+	delete(c.entries, key)
+	c.mu.Unlock()
+}
+
+// Stats reports cumulative hit/miss counters for the debug endpoint.
+type Stats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+}
+
+// Stats returns the cache's cumulative hit/miss counters.
+func (c *Cache) Stats() Stats {
+	return Stats{Hits: c.hits.Load(), Misses: c.misses.Load()}
+}
+
+// Keys used by the hot-read caches wrapped around handlers.
+const (
+	KeyCategoryTree    = "category_tree"
+	KeyPopularTags     = "popular_tags"
+	KeyStatsSummary    = "stats_summary"
+	KeyPopularArticles = "popular_articles"
+	KeyHomeFeed        = "home_feed"
+)
+
+// TaxonomyTrendsKey returns the cache key for the taxonomy-trends endpoint
+// at a given window size, since its result varies by months.
+func TaxonomyTrendsKey(months int) string {
+	return "taxonomy_trends_" + strconv.Itoa(months)
+}
+
+// Default is the process-wide cache instance, initialized by main from
+// configuration. Handlers may read it directly; it is nil until Init runs.
+var Default *Cache
+
+// Init sets up Default with the given TTL.
+func Init(ttl time.Duration) {
+	Default = New(ttl)
+}
+
+// InvalidateAllReadCaches clears every known hot-read key. Mutating
+// handlers call this (or Invalidate a narrower key) so admins never see
+// stale data after their own writes.
+func InvalidateAllReadCaches() {
+	if Default == nil {
+		return
+	}
+	for _, key := range []string{KeyCategoryTree, KeyPopularTags, KeyStatsSummary, KeyPopularArticles, KeyHomeFeed} {
+		Default.Invalidate(key)
+	}
+}
@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGetOrComputeCachesAndExpires(t *testing.T) {
+	c := New(20 * time.Millisecond)
+	calls := 0
+	compute := func() (any, error) {
+		calls++
+		return calls, nil
+	}
+
+	v1, err := c.GetOrCompute("k", compute)
+	if err != nil || v1 != 1 {
+		t.Fatalf("expected first call to compute 1, got %v err=%v", v1, err)
+	}
+	v2, err := c.GetOrCompute("k", compute)
+	if err != nil || v2 != 1 {
+		t.Fatalf("expected cached hit to return 1, got %v err=%v", v2, err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected compute to run once, ran %d times", calls)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	v3, err := c.GetOrCompute("k", compute)
+	if err != nil || v3 != 2 {
+		t.Fatalf("expected expired entry to recompute to 2, got %v err=%v", v3, err)
+	}
+
+	stats := c.Stats()
+	if stats.Hits != 1 || stats.Misses != 2 {
+		t.Errorf("expected 1 hit and 2 misses, got %+v", stats)
+	}
+}
+
+func TestInvalidate(t *testing.T) {
+	c := New(time.Minute)
+	c.GetOrCompute("k", func() (any, error) { return 1, nil })
+	c.Invalidate("k")
+
+	calls := 0
+	c.GetOrCompute("k", func() (any, error) { calls++; return 2, nil })
+	if calls != 1 {
+		t.Errorf("expected invalidated key to recompute")
+	}
+}
+
+func TestGetOrComputeErrorNotCached(t *testing.T) {
+	c := New(time.Minute)
+	wantErr := errors.New("boom")
+	_, err := c.GetOrCompute("k", func() (any, error) { return nil, wantErr })
+	if err != wantErr {
+		t.Fatalf("expected error to propagate, got %v", err)
+	}
+}
@@ -0,0 +1,15 @@
+// Command gendocs regenerates docs/openapi.json from the swag
+// annotations on the handlers. Run via `go generate ./...`.
+package main
+
+import (
+	"log"
+
+	"github.com/1055373165/blog/gendocs"
+)
+
+func main() {
+	if err := gendocs.Run(".", "docs"); err != nil {
+		log.Fatal(err)
+	}
+}
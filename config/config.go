@@ -0,0 +1,157 @@
+package config
+
+import (
+	"os"
+	"strconv"
+)
+
+// Config holds runtime configuration loaded from the environment, with
+// sane defaults for local development.
+type Config struct {
+	Port      string
+	DSN       string
+	JWTSecret string
+	UploadDir string
+	// UploadMaxBytes caps how large a single multipart upload body may
+	// be, enforced with http.MaxBytesReader before the file is read -
+	// unlike the client-supplied multipart header's Size field, a
+	// request over this cap is rejected before it's fully read off the
+	// wire.
+	UploadMaxBytes int64
+	// MaxJSONBodyBytes caps the body of an ordinary JSON request,
+	// enforced with http.MaxBytesReader via middleware.MaxBodyBytes
+	// before binding. It's small relative to UploadMaxBytes on purpose
+	// - /api/upload is registered ahead of the middleware that applies
+	// this default and carries its own, larger cap instead.
+	MaxJSONBodyBytes int64
+	// PublicBase is the externally reachable base URL used to build
+	// absolute links to uploaded files and media. When empty, callers
+	// derive it from the incoming request's Host and X-Forwarded-Proto
+	// headers instead, so reverse-proxied deployments work unconfigured.
+	PublicBase string
+
+	SearchIndexPath string
+
+	// CacheTTLSeconds controls how long hot-read cache entries stay valid.
+	CacheTTLSeconds int
+
+	// LogLevel is one of "debug", "info", "warn", "error".
+	LogLevel string
+	// SlowQueryThresholdMS is the GORM query duration, in milliseconds,
+	// above which a query is logged as slow.
+	SlowQueryThresholdMS int
+	// QueryTimeoutMS bounds how long a single db.WithCtx query may run
+	// before its context is canceled, so a client disconnect or a runaway
+	// LIKE search can't pin a connection indefinitely. 0 disables the
+	// timeout (the context is still derived from the caller's, so a
+	// client disconnect still cancels it - there's just no additional
+	// server-side ceiling).
+	QueryTimeoutMS int
+
+	// MetricsToken gates /metrics; requests must present it as a Bearer
+	// token. Empty (the default) keeps the endpoint closed.
+	MetricsToken string
+
+	// BackupDir is where POST /api/admin/backups writes timestamped dumps.
+	BackupDir string
+	// BackupRetention is how many backups Prune keeps; older ones are
+	// deleted after each scheduled run.
+	BackupRetention int
+	// BackupScheduleEnabled starts a daily background backup job alongside
+	// the server when true. Off by default so tests and local dev don't
+	// accumulate dumps.
+	BackupScheduleEnabled bool
+
+	// ExportDir is where POST /api/admin/export/static stages and writes
+	// static site export tar.gz files.
+	ExportDir string
+
+	// MasteryDecayJobIntervalSeconds and SavedSearchDigestJobIntervalSeconds
+	// are the package jobs schedules for the two jobs registered at
+	// startup, in seconds.
+	MasteryDecayJobIntervalSeconds      int
+	SavedSearchDigestJobIntervalSeconds int
+
+	// JobTimeoutSeconds bounds how long a single scheduled job run may
+	// take before its context is canceled, so a stuck aggregation can't
+	// hold a database connection forever.
+	JobTimeoutSeconds int
+
+	// Env is the deployment environment: "development" (the default),
+	// "staging", or "production". Gates environment-sensitive features
+	// like the demo data seeder, which must never run against
+	// production data.
+	Env string
+}
+
+var App *Config
+
+// Load reads configuration from the environment and caches it in App.
+func Load() *Config {
+	App = &Config{
+		Port:             getEnv("BLOG_PORT", "8080"),
+		DSN:              getEnv("BLOG_DSN", "blog.db"),
+		JWTSecret:        getEnv("BLOG_JWT_SECRET", "dev-secret"),
+		UploadDir:        getEnv("BLOG_UPLOAD_DIR", "uploads"),
+		UploadMaxBytes:   getEnvInt64("BLOG_UPLOAD_MAX_BYTES", 200<<20),
+		MaxJSONBodyBytes: getEnvInt64("BLOG_MAX_JSON_BODY_BYTES", 1<<20),
+		PublicBase:       getEnv("BLOG_PUBLIC_BASE", ""),
+
+		SearchIndexPath: getEnv("BLOG_SEARCH_INDEX", "search.bleve"),
+
+		CacheTTLSeconds: getEnvInt("BLOG_CACHE_TTL_SECONDS", 60),
+
+		LogLevel:             getEnv("BLOG_LOG_LEVEL", "info"),
+		SlowQueryThresholdMS: getEnvInt("BLOG_SLOW_QUERY_THRESHOLD_MS", 200),
+		QueryTimeoutMS:       getEnvInt("BLOG_QUERY_TIMEOUT_MS", 5000),
+
+		MetricsToken: getEnv("BLOG_METRICS_TOKEN", ""),
+
+		BackupDir:             getEnv("BLOG_BACKUP_DIR", "backups"),
+		BackupRetention:       getEnvInt("BLOG_BACKUP_RETENTION", 7),
+		BackupScheduleEnabled: getEnvBool("BLOG_BACKUP_SCHEDULE_ENABLED", false),
+
+		ExportDir: getEnv("BLOG_EXPORT_DIR", "exports"),
+
+		MasteryDecayJobIntervalSeconds:      getEnvInt("BLOG_JOB_MASTERY_DECAY_INTERVAL_SECONDS", 3600),
+		SavedSearchDigestJobIntervalSeconds: getEnvInt("BLOG_JOB_SAVED_SEARCH_DIGEST_INTERVAL_SECONDS", 86400),
+		JobTimeoutSeconds:                   getEnvInt("BLOG_JOB_TIMEOUT_SECONDS", 300),
+
+		Env: getEnv("BLOG_ENV", "development"),
+	}
+	return App
+}
+
+func getEnv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func getEnvInt(key string, fallback int) int {
+	if v := os.Getenv(key); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	return fallback
+}
+
+func getEnvInt64(key string, fallback int64) int64 {
+	if v := os.Getenv(key); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return n
+		}
+	}
+	return fallback
+}
+
+func getEnvBool(key string, fallback bool) bool {
+	if v := os.Getenv(key); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return fallback
+}
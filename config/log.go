@@ -0,0 +1,24 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+)
+
+// NewLogger builds the process-wide structured logger at the level named
+// by cfg.LogLevel, emitting JSON suitable for log aggregation.
+func NewLogger(cfg *Config) *slog.Logger {
+	var level slog.Level
+	switch cfg.LogLevel {
+	case "debug":
+		level = slog.LevelDebug
+	case "warn":
+		level = slog.LevelWarn
+	case "error":
+		level = slog.LevelError
+	default:
+		level = slog.LevelInfo
+	}
+	handler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: level})
+	return slog.New(handler)
+}
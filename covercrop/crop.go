@@ -0,0 +1,71 @@
+// Package covercrop computes a focal-point-aware crop of a cover image
+// and scales it to the requested dimensions, for handler.GetArticleCover.
+package covercrop
+
+import (
+	"image"
+
+	"golang.org/x/image/draw"
+)
+
+// Crop returns a new width x height image, cropped from src around the
+// normalized (0-1) focal point (focalX, focalY) and scaled to fit. The
+// crop rectangle is the largest region of src with the target aspect
+// ratio that fits inside src's bounds, centered on the focal point and
+// clamped so it never spills outside those bounds.
+func Crop(src image.Image, focalX, focalY float64, width, height int) image.Image {
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	if srcW <= 0 || srcH <= 0 || width <= 0 || height <= 0 {
+		return dst
+	}
+
+	cropRect := focalCropRect(bounds, srcW, srcH, focalX, focalY, float64(width)/float64(height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, cropRect, draw.Over, nil)
+	return dst
+}
+
+// focalCropRect returns the largest targetRatio-shaped rectangle that
+// fits inside bounds, positioned to keep (focalX, focalY) - normalized
+// against srcW/srcH - as close to its center as bounds allow.
+func focalCropRect(bounds image.Rectangle, srcW, srcH int, focalX, focalY, targetRatio float64) image.Rectangle {
+	srcRatio := float64(srcW) / float64(srcH)
+
+	var cropW, cropH int
+	if srcRatio > targetRatio {
+		cropH = srcH
+		cropW = int(float64(cropH) * targetRatio)
+	} else {
+		cropW = srcW
+		cropH = int(float64(cropW) / targetRatio)
+	}
+	if cropW < 1 {
+		cropW = 1
+	}
+	if cropH < 1 {
+		cropH = 1
+	}
+
+	focalPxX := bounds.Min.X + int(focalX*float64(srcW))
+	focalPxY := bounds.Min.Y + int(focalY*float64(srcH))
+
+	x0 := clamp(focalPxX-cropW/2, bounds.Min.X, bounds.Max.X-cropW)
+	y0 := clamp(focalPxY-cropH/2, bounds.Min.Y, bounds.Max.Y-cropH)
+
+	return image.Rect(x0, y0, x0+cropW, y0+cropH)
+}
+
+func clamp(v, min, max int) int {
+	if max < min {
+		return min
+	}
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
@@ -0,0 +1,31 @@
+package covercrop
+
+import (
+	"image"
+	"testing"
+)
+
+func TestCropProducesRequestedDimensions(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 1600, 900))
+	dst := Crop(src, 0.5, 0.5, 400, 300)
+	bounds := dst.Bounds()
+	if bounds.Dx() != 400 || bounds.Dy() != 300 {
+		t.Errorf("expected 400x300, got %dx%d", bounds.Dx(), bounds.Dy())
+	}
+}
+
+func TestFocalCropRectStaysInsideBoundsNearEdges(t *testing.T) {
+	bounds := image.Rect(0, 0, 1000, 500)
+	rect := focalCropRect(bounds, 1000, 500, 0.0, 1.0, 16.0/9.0)
+	if !rect.In(bounds) {
+		t.Errorf("expected crop rect %v to stay inside bounds %v when focal point is at a corner", rect, bounds)
+	}
+}
+
+func TestFocalCropRectCentersOnFocalPoint(t *testing.T) {
+	bounds := image.Rect(0, 0, 1000, 1000)
+	rect := focalCropRect(bounds, 1000, 1000, 0.5, 0.5, 1.0)
+	if rect.Dx() != 1000 || rect.Dy() != 1000 {
+		t.Errorf("expected a centered focal point on a square source to keep the full frame, got %v", rect)
+	}
+}
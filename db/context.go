@@ -0,0 +1,30 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/1055373165/blog/config"
+	"gorm.io/gorm"
+)
+
+// WithCtx returns DB scoped to ctx, so a client disconnect (or any other
+// cancellation of ctx) aborts in-flight queries instead of letting them run
+// to completion against an abandoned response. When config.App.QueryTimeoutMS
+// is set, it also bounds the query to that duration from now, so a runaway
+// query can't hold a connection forever even if the client stays connected.
+// Callers should derive ctx from the incoming request, e.g.
+// db.WithCtx(c.Request.Context()).
+func WithCtx(ctx context.Context) *gorm.DB {
+	if config.App != nil && config.App.QueryTimeoutMS > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(config.App.QueryTimeoutMS)*time.Millisecond)
+		// The query this context is used for runs after WithCtx returns, so
+		// cancel can't be called here directly; release it as soon as ctx
+		// is done (by timeout, by the query finishing and the caller
+		// canceling a parent context, or by client disconnect) rather than
+		// leaving the timer to fire on its own.
+		context.AfterFunc(ctx, cancel)
+	}
+	return DB.WithContext(ctx)
+}
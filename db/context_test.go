@@ -0,0 +1,78 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/1055373165/blog/config"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// slowCountQuery takes long enough in SQLite to observe cancellation
+// mid-run without needing a SLEEP builtin, which SQLite doesn't have.
+const slowCountQuery = "WITH RECURSIVE cnt(x) AS (SELECT 1 UNION ALL SELECT x+1 FROM cnt WHERE x<500000000) SELECT count(*) FROM cnt"
+
+func setupContextTestDB(t *testing.T) {
+	t.Helper()
+	conn, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	DB = conn
+}
+
+func TestWithCtxAbortsQueryWhenParentContextIsCanceled(t *testing.T) {
+	setupContextTestDB(t)
+	config.App = &config.Config{QueryTimeoutMS: 0}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+
+	start := time.Now()
+	var n int
+	err := WithCtx(ctx).Raw(slowCountQuery).Scan(&n).Error
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got err %v, want context.Canceled", err)
+	}
+	if elapsed > 2*time.Second {
+		t.Errorf("expected the query to abort promptly after cancellation, took %v", elapsed)
+	}
+}
+
+func TestWithCtxAppliesConfiguredQueryTimeout(t *testing.T) {
+	setupContextTestDB(t)
+	config.App = &config.Config{QueryTimeoutMS: 20}
+
+	start := time.Now()
+	var n int
+	err := WithCtx(context.Background()).Raw(slowCountQuery).Scan(&n).Error
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got err %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed > 2*time.Second {
+		t.Errorf("expected the query to abort promptly after its timeout, took %v", elapsed)
+	}
+}
+
+func TestWithCtxRunsNormallyWithoutATimeoutConfigured(t *testing.T) {
+	setupContextTestDB(t)
+	config.App = &config.Config{QueryTimeoutMS: 0}
+
+	var n int
+	if err := WithCtx(context.Background()).Raw("SELECT 1").Scan(&n).Error; err != nil {
+		t.Fatalf("WithCtx query returned error: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("got %d, want 1", n)
+	}
+}
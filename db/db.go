@@ -0,0 +1,27 @@
+package db
+
+import (
+	"log/slog"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// DB is the process-wide database handle, initialized by Init.
+var DB *gorm.DB
+
+// Init opens the database connection described by dsn and stores it in DB.
+// Slow queries (above slowThreshold) are logged via log, tagged with the
+// request ID carried on each query's context.
+func Init(dsn string, log *slog.Logger, slowThreshold time.Duration) (*gorm.DB, error) {
+	conn, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
+		Logger:         NewSlowQueryLogger(log, slowThreshold),
+		TranslateError: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	DB = conn
+	return conn, nil
+}
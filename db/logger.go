@@ -0,0 +1,50 @@
+package db
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/1055373165/blog/middleware"
+	"gorm.io/gorm/logger"
+)
+
+// SlowQueryLogger wraps a GORM logger.Interface and additionally emits a
+// structured slog warning, tagged with the request ID, for any query
+// slower than Threshold.
+type SlowQueryLogger struct {
+	logger.Interface
+	Logger    *slog.Logger
+	Threshold time.Duration
+}
+
+// NewSlowQueryLogger builds a SlowQueryLogger around GORM's default
+// logger at Warn level, so normal query noise stays quiet while slow
+// queries and errors are still surfaced.
+func NewSlowQueryLogger(log *slog.Logger, threshold time.Duration) *SlowQueryLogger {
+	return &SlowQueryLogger{
+		Interface: logger.Default.LogMode(logger.Warn),
+		Logger:    log,
+		Threshold: threshold,
+	}
+}
+
+// Trace delegates to the wrapped logger and additionally logs a
+// structured "slow_query" event, carrying the request ID from ctx, when
+// elapsed exceeds Threshold.
+func (l *SlowQueryLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	l.Interface.Trace(ctx, begin, fc, err)
+
+	elapsed := time.Since(begin)
+	if l.Threshold <= 0 || elapsed < l.Threshold {
+		return
+	}
+
+	sql, rows := fc()
+	l.Logger.Warn("slow_query",
+		"request_id", middleware.RequestIDFromContext(ctx),
+		"sql", sql,
+		"rows", rows,
+		"elapsed_ms", elapsed.Milliseconds(),
+	)
+}
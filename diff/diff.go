@@ -0,0 +1,142 @@
+// Package diff computes word-level diffs between two pieces of
+// Markdown/plain text, suitable for highlighting in a frontend.
+package diff
+
+import "unicode"
+
+// maxDiffCells bounds the LCS table's cell count (len(a)*len(b) tokens)
+// so Words stays well within a request timeout; the straightforward
+// O(n*m) LCS below is simple and fast enough for typical article-sized
+// diffs, but not for pathologically large documents. Beyond the
+// threshold, Words returns a TooLarge summary instead of running it.
+const maxDiffCells = 4_000_000
+
+// SegmentType classifies a Segment of a Result.
+type SegmentType string
+
+const (
+	SegmentUnchanged SegmentType = "unchanged"
+	SegmentAdded     SegmentType = "added"
+	SegmentRemoved   SegmentType = "removed"
+)
+
+// Segment is a contiguous run of tokens sharing the same SegmentType.
+type Segment struct {
+	Type SegmentType `json:"type"`
+	Text string      `json:"text"`
+}
+
+// Result is the output of Words: either a list of diff Segments, or
+// (if the inputs were too large to diff within budget) a TooLarge
+// summary with byte lengths the caller can show instead.
+type Result struct {
+	Segments []Segment `json:"segments,omitempty"`
+	TooLarge bool      `json:"too_large"`
+	ALen     int       `json:"a_len,omitempty"`
+	BLen     int       `json:"b_len,omitempty"`
+}
+
+// Words diffs a against b at word granularity. Each run of non-space,
+// non-Han characters is one word; each Han (CJK) character is its own
+// word, since CJK text has no whitespace between words but
+// insertions/deletions should still be visible at character
+// granularity; whitespace runs are preserved as their own tokens so
+// line breaks and indentation survive the diff.
+func Words(a, b string) Result {
+	aw := tokenize(a)
+	bw := tokenize(b)
+	if len(aw)*len(bw) > maxDiffCells {
+		return Result{TooLarge: true, ALen: len(a), BLen: len(b)}
+	}
+	return Result{Segments: mergeSegments(lcsDiff(aw, bw))}
+}
+
+func tokenize(s string) []string {
+	var words []string
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, string(current))
+			current = current[:0]
+		}
+	}
+	for _, r := range s {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			flush()
+			words = append(words, string(r))
+		case unicode.IsSpace(r):
+			flush()
+			words = append(words, string(r))
+		default:
+			current = append(current, r)
+		}
+	}
+	flush()
+	return words
+}
+
+// lcsDiff runs a classic longest-common-subsequence diff over word
+// tokens a and b, returning one Segment per token (to be merged by
+// mergeSegments).
+func lcsDiff(a, b []string) []Segment {
+	n, m := len(a), len(b)
+	dp := make([][]int, n+1)
+	for i := range dp {
+		dp[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				dp[i][j] = dp[i+1][j+1] + 1
+			case dp[i+1][j] >= dp[i][j+1]:
+				dp[i][j] = dp[i+1][j]
+			default:
+				dp[i][j] = dp[i][j+1]
+			}
+		}
+	}
+
+	segments := make([]Segment, 0, n+m)
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			segments = append(segments, Segment{Type: SegmentUnchanged, Text: a[i]})
+			i++
+			j++
+		case dp[i+1][j] >= dp[i][j+1]:
+			segments = append(segments, Segment{Type: SegmentRemoved, Text: a[i]})
+			i++
+		default:
+			segments = append(segments, Segment{Type: SegmentAdded, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		segments = append(segments, Segment{Type: SegmentRemoved, Text: a[i]})
+	}
+	for ; j < m; j++ {
+		segments = append(segments, Segment{Type: SegmentAdded, Text: b[j]})
+	}
+	return segments
+}
+
+// mergeSegments collapses adjacent same-type Segments from a
+// per-token diff into one Segment per run.
+func mergeSegments(tokens []Segment) []Segment {
+	if len(tokens) == 0 {
+		return nil
+	}
+	merged := []Segment{tokens[0]}
+	for _, s := range tokens[1:] {
+		last := &merged[len(merged)-1]
+		if last.Type == s.Type {
+			last.Text += s.Text
+		} else {
+			merged = append(merged, s)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,69 @@
+package diff
+
+import "testing"
+
+func TestWordsDetectsAddedAndRemovedWords(t *testing.T) {
+	result := Words("the quick brown fox", "the quick red fox jumps")
+	if result.TooLarge {
+		t.Fatal("expected a small diff to not be marked too large")
+	}
+
+	var removed, added string
+	for _, s := range result.Segments {
+		switch s.Type {
+		case SegmentRemoved:
+			removed += s.Text
+		case SegmentAdded:
+			added += s.Text
+		}
+	}
+	if removed != "brown" {
+		t.Errorf("expected removed text %q, got %q", "brown", removed)
+	}
+	if added != "red jumps" {
+		t.Errorf("expected added text %q, got %q", "red jumps", added)
+	}
+}
+
+func TestWordsTreatsEachHanCharacterAsItsOwnWord(t *testing.T) {
+	result := Words("你好世界", "你好新世界")
+	if result.TooLarge {
+		t.Fatal("expected a small diff to not be marked too large")
+	}
+
+	var added string
+	for _, s := range result.Segments {
+		if s.Type == SegmentAdded {
+			added += s.Text
+		}
+	}
+	if added != "新" {
+		t.Errorf("expected only the single inserted character 新, got %q", added)
+	}
+}
+
+func TestWordsReturnsUnchangedForIdenticalInput(t *testing.T) {
+	result := Words("no changes here", "no changes here")
+	for _, s := range result.Segments {
+		if s.Type != SegmentUnchanged {
+			t.Errorf("expected every segment unchanged, got %+v", s)
+		}
+	}
+}
+
+func TestWordsBailsOutForOversizedInput(t *testing.T) {
+	var big string
+	for i := 0; i < 2500; i++ {
+		big += "word "
+	}
+	result := Words(big, big+"extra ")
+	if !result.TooLarge {
+		t.Fatal("expected an oversized diff to be marked too large")
+	}
+	if len(result.Segments) != 0 {
+		t.Errorf("expected no segments for a too-large diff, got %d", len(result.Segments))
+	}
+	if result.ALen == 0 || result.BLen == 0 {
+		t.Errorf("expected ALen/BLen to be populated on a too-large result, got %+v", result)
+	}
+}
@@ -0,0 +1,10 @@
+// Package docs embeds the generated OpenAPI 3 specification served at
+// GET /api/docs/openapi.json. Regenerate via `go generate ./...` (see
+// cmd/gendocs); docs/generate_test.go fails CI if the committed spec
+// has drifted from the swag annotations on the handlers.
+package docs
+
+import _ "embed"
+
+//go:embed openapi.json
+var OpenAPISpec []byte
@@ -0,0 +1,38 @@
+package docs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/1055373165/blog/gendocs"
+)
+
+// TestSpecUpToDate regenerates the OpenAPI spec from the current swag
+// annotations and fails if it differs from the committed
+// docs/openapi.json, so the spec can't silently drift from the handlers.
+func TestSpecUpToDate(t *testing.T) {
+	searchDir, err := filepath.Abs("..")
+	if err != nil {
+		t.Fatalf("resolve module root: %v", err)
+	}
+
+	outDir := t.TempDir()
+	if err := gendocs.Run(searchDir, outDir); err != nil {
+		t.Fatalf("regenerate spec: %v", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(outDir, "openapi.json"))
+	if err != nil {
+		t.Fatalf("read regenerated spec: %v", err)
+	}
+
+	want, err := os.ReadFile("openapi.json")
+	if err != nil {
+		t.Fatalf("read committed spec: %v", err)
+	}
+
+	if string(got) != string(want) {
+		t.Fatal("docs/openapi.json is out of date; run `go generate ./...` and commit the result")
+	}
+}
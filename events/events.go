@@ -0,0 +1,78 @@
+// Package events implements a small in-process pub/sub hub for streaming
+// typed events to connected admin clients (see handler.AdminEvents). Like
+// package presence, it is entirely in-memory and best-effort: nothing is
+// persisted, and a subscriber that falls behind simply loses its oldest
+// unsent events rather than blocking publishers.
+package events
+
+import "sync"
+
+// Event is a single typed message published to the hub.
+type Event struct {
+	Name string `json:"name"`
+	Data any    `json:"data"`
+}
+
+// bufferSize is how many unsent events a subscriber's channel holds
+// before Publish starts dropping the oldest to make room, so one slow
+// consumer can't block delivery to the rest.
+const bufferSize = 32
+
+// Hub fans out published events to every currently connected subscriber.
+type Hub struct {
+	mu          sync.Mutex
+	subscribers map[chan Event]struct{}
+}
+
+// NewHub creates an empty Hub.
+func NewHub() *Hub {
+	return &Hub{subscribers: make(map[chan Event]struct{})}
+}
+
+// Subscribe registers a new subscriber and returns its event channel plus
+// an unsubscribe function the caller must call once done (typically via
+// defer on disconnect) so the hub stops fanning out to it.
+func (h *Hub) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, bufferSize)
+	h.mu.Lock()
+	h.subscribers[ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		delete(h.subscribers, ch)
+		h.mu.Unlock()
+	}
+}
+
+// Publish fans event out to every subscriber. A subscriber whose buffer
+// is full has its oldest queued event dropped to make room for this one,
+// rather than blocking Publish or starving the other subscribers.
+func (h *Hub) Publish(event Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subscribers {
+		select {
+		case ch <- event:
+		default:
+			select {
+			case <-ch:
+			default:
+			}
+			select {
+			case ch <- event:
+			default:
+			}
+		}
+	}
+}
+
+// Default is the hub used by handler.AdminEvents and every event
+// publisher, set up by Init.
+var Default *Hub
+
+// Init creates Default. Call once at startup, mirroring presence.Init and
+// search.Init.
+func Init() {
+	Default = NewHub()
+}
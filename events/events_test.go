@@ -0,0 +1,77 @@
+package events
+
+import "testing"
+
+func TestSubscribeReceivesPublishedEvent(t *testing.T) {
+	h := NewHub()
+	ch, unsubscribe := h.Subscribe()
+	defer unsubscribe()
+
+	h.Publish(Event{Name: "submission.created", Data: 42})
+
+	select {
+	case got := <-ch:
+		if got.Name != "submission.created" || got.Data != 42 {
+			t.Fatalf("got unexpected event %+v", got)
+		}
+	default:
+		t.Fatal("expected event to be delivered synchronously")
+	}
+}
+
+func TestUnsubscribeStopsDelivery(t *testing.T) {
+	h := NewHub()
+	ch, unsubscribe := h.Subscribe()
+	unsubscribe()
+
+	h.Publish(Event{Name: "submission.created"})
+
+	select {
+	case got, ok := <-ch:
+		if ok {
+			t.Fatalf("expected no event after unsubscribe, got %+v", got)
+		}
+	default:
+	}
+}
+
+func TestPublishDropsOldestWhenSubscriberBufferFull(t *testing.T) {
+	h := NewHub()
+	ch, unsubscribe := h.Subscribe()
+	defer unsubscribe()
+
+	for i := 0; i < bufferSize+5; i++ {
+		h.Publish(Event{Name: "tick", Data: i})
+	}
+
+	var last Event
+	for {
+		select {
+		case e := <-ch:
+			last = e
+		default:
+			goto drained
+		}
+	}
+drained:
+	if last.Data != bufferSize+4 {
+		t.Fatalf("expected the most recent event to survive, got %+v", last)
+	}
+}
+
+func TestPublishFansOutToMultipleSubscribers(t *testing.T) {
+	h := NewHub()
+	ch1, unsub1 := h.Subscribe()
+	defer unsub1()
+	ch2, unsub2 := h.Subscribe()
+	defer unsub2()
+
+	h.Publish(Event{Name: "submission.created"})
+
+	if got := <-ch1; got.Name != "submission.created" {
+		t.Errorf("subscriber 1 got unexpected event %+v", got)
+	}
+	if got := <-ch2; got.Name != "submission.created" {
+		t.Errorf("subscriber 2 got unexpected event %+v", got)
+	}
+}
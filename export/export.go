@@ -0,0 +1,390 @@
+// Package export renders the entire public site (published articles,
+// blogs, series, tags, categories, and an archive index) to static HTML
+// plus a sitemap and an RSS feed, and packages the result as a
+// downloadable tar.gz. There is no Markdown-to-HTML renderer anywhere
+// in this tree (Article.Content is stored and served as raw Markdown
+// text for client-side rendering; see README "Known gaps"), so exported
+// pages embed content verbatim inside a <pre> block rather than
+// rendering it to HTML.
+package export
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/1055373165/blog/models"
+	"gorm.io/gorm"
+)
+
+// Status is the state of a static export run.
+type Status string
+
+const (
+	StatusRunning   Status = "running"
+	StatusDone      Status = "done"
+	StatusFailed    Status = "failed"
+	StatusCancelled Status = "cancelled"
+)
+
+// ErrAlreadyRunning is returned by Start when an export is already in
+// progress; only one export may run at a time.
+var ErrAlreadyRunning = errors.New("export: a static export is already running")
+
+// ErrNotRunning is returned by Cancel when no export is running.
+var ErrNotRunning = errors.New("export: no static export is running")
+
+// Progress reports a static export's state, polled via GET
+// /api/admin/export/static/status.
+type Progress struct {
+	Status       Status    `json:"status"`
+	PagesDone    int       `json:"pages_done"`
+	PagesTotal   int       `json:"pages_total"`
+	DownloadName string    `json:"download_name,omitempty"`
+	Error        string    `json:"error,omitempty"`
+	StartedAt    time.Time `json:"started_at"`
+	FinishedAt   time.Time `json:"finished_at,omitempty"`
+}
+
+// Manager tracks at most one in-flight export at a time.
+type Manager struct {
+	mu       sync.Mutex
+	progress Progress
+	cancel   context.CancelFunc
+}
+
+// NewManager returns an idle Manager.
+func NewManager() *Manager {
+	return &Manager{}
+}
+
+// Start kicks off a new export in the background, streaming its pages
+// to a temporary directory under exportDir and assembling them into a
+// tar.gz named in the resulting Progress.DownloadName. It returns
+// ErrAlreadyRunning if an export is already in progress.
+func (m *Manager) Start(db *gorm.DB, exportDir, uploadDir string) error {
+	m.mu.Lock()
+	if m.progress.Status == StatusRunning {
+		m.mu.Unlock()
+		return ErrAlreadyRunning
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	m.progress = Progress{Status: StatusRunning, StartedAt: time.Now()}
+	m.mu.Unlock()
+
+	go m.run(ctx, db, exportDir, uploadDir)
+	return nil
+}
+
+func (m *Manager) run(ctx context.Context, db *gorm.DB, exportDir, uploadDir string) {
+	name, err := runExport(ctx, db, exportDir, uploadDir, m.setProgress)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.progress.FinishedAt = time.Now()
+	switch {
+	case errors.Is(err, context.Canceled):
+		m.progress.Status = StatusCancelled
+	case err != nil:
+		m.progress.Status = StatusFailed
+		m.progress.Error = err.Error()
+	default:
+		m.progress.Status = StatusDone
+		m.progress.DownloadName = name
+	}
+}
+
+func (m *Manager) setProgress(done, total int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.progress.PagesDone = done
+	m.progress.PagesTotal = total
+}
+
+// Status returns the current or most recently finished export's progress.
+func (m *Manager) Status() Progress {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.progress
+}
+
+// Cancel stops the in-progress export, if any. The run's eventual
+// Status becomes StatusCancelled once its current page finishes.
+func (m *Manager) Cancel() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.progress.Status != StatusRunning {
+		return ErrNotRunning
+	}
+	m.cancel()
+	return nil
+}
+
+// Default is the package-level Manager used by handler.StartStaticExport
+// and friends, mirroring the cache.Default/presence.Default convention.
+var Default *Manager
+
+// Init sets Default to a fresh Manager. Call once at startup.
+func Init() {
+	Default = NewManager()
+}
+
+// assetRefPattern finds references to the flat upload pool
+// (/api/files/<name> or /api/media/<name>) inside stored content, so
+// their files can be copied into the export.
+var assetRefPattern = regexp.MustCompile(`/api/(?:files|media)/([A-Za-z0-9._-]+)`)
+
+func runExport(ctx context.Context, db *gorm.DB, exportDir, uploadDir string, onProgress func(done, total int)) (string, error) {
+	if err := os.MkdirAll(exportDir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to prepare export dir: %w", err)
+	}
+	tmpDir, err := os.MkdirTemp(exportDir, "export-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create staging dir: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	var articles []models.Article
+	if err := db.Where("status = ?", models.ArticleStatusPublished).Preload("Tags").Order("created_at desc").Find(&articles).Error; err != nil {
+		return "", err
+	}
+	var blogs []models.Blog
+	if err := db.Order("created_at desc").Find(&blogs).Error; err != nil {
+		return "", err
+	}
+	var series []models.Series
+	if err := db.Find(&series).Error; err != nil {
+		return "", err
+	}
+	var tags []models.Tag
+	if err := db.Find(&tags).Error; err != nil {
+		return "", err
+	}
+	var categories []models.Category
+	if err := db.Find(&categories).Error; err != nil {
+		return "", err
+	}
+
+	total := len(articles) + len(blogs) + len(series) + len(tags) + len(categories) + 2 // +archive index +sitemap/feed
+	done := 0
+	tick := func() error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		done++
+		onProgress(done, total)
+		return nil
+	}
+
+	assetNames := make(map[string]struct{})
+
+	for _, a := range articles {
+		collectAssetRefs(a.Content, assetNames)
+		if err := writePage(tmpDir, filepath.Join("articles", a.Slug, "index.html"), articlePageTemplate, a); err != nil {
+			return "", err
+		}
+		if err := tick(); err != nil {
+			return "", err
+		}
+	}
+	for _, b := range blogs {
+		if err := writePage(tmpDir, filepath.Join("blogs", b.Slug, "index.html"), blogPageTemplate, b); err != nil {
+			return "", err
+		}
+		if err := tick(); err != nil {
+			return "", err
+		}
+	}
+	for _, s := range series {
+		seriesArticles, err := articlesInSeries(db, s.ID)
+		if err != nil {
+			return "", err
+		}
+		page := seriesPage{Series: s, Articles: seriesArticles}
+		if err := writePage(tmpDir, filepath.Join("series", s.Slug, "index.html"), seriesPageTemplate, page); err != nil {
+			return "", err
+		}
+		if err := tick(); err != nil {
+			return "", err
+		}
+	}
+	for _, t := range tags {
+		tagArticles, err := articlesWithTag(db, t.ID)
+		if err != nil {
+			return "", err
+		}
+		page := tagPage{Tag: t, Articles: tagArticles}
+		if err := writePage(tmpDir, filepath.Join("tags", t.Slug, "index.html"), tagPageTemplate, page); err != nil {
+			return "", err
+		}
+		if err := tick(); err != nil {
+			return "", err
+		}
+	}
+	for _, cat := range categories {
+		// Articles have no relationship to categories in this tree (no
+		// Article.CategoryID; see README "Known gaps"), so the category
+		// page carries only its own metadata, never a post listing.
+		if err := writePage(tmpDir, filepath.Join("categories", cat.Slug, "index.html"), categoryPageTemplate, cat); err != nil {
+			return "", err
+		}
+		if err := tick(); err != nil {
+			return "", err
+		}
+	}
+
+	if err := writePage(tmpDir, filepath.Join("archive", "index.html"), archivePageTemplate, articles); err != nil {
+		return "", err
+	}
+	if err := tick(); err != nil {
+		return "", err
+	}
+
+	if err := writeSitemap(tmpDir, articles, blogs, series, tags, categories); err != nil {
+		return "", err
+	}
+	if err := writeFeed(tmpDir, articles); err != nil {
+		return "", err
+	}
+	if err := tick(); err != nil {
+		return "", err
+	}
+
+	if err := copyAssets(assetNames, uploadDir, filepath.Join(tmpDir, "files")); err != nil {
+		return "", err
+	}
+
+	archiveName := fmt.Sprintf("export-%s.tar.gz", time.Now().UTC().Format("20060102T150405Z"))
+	if err := tarGzDir(tmpDir, filepath.Join(exportDir, archiveName)); err != nil {
+		return "", err
+	}
+	return archiveName, nil
+}
+
+func collectAssetRefs(content string, out map[string]struct{}) {
+	for _, m := range assetRefPattern.FindAllStringSubmatch(content, -1) {
+		out[m[1]] = struct{}{}
+	}
+}
+
+func copyAssets(names map[string]struct{}, uploadDir, destDir string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(destDir, 0o755); err != nil {
+		return err
+	}
+	for name := range names {
+		safeName := filepath.Base(name)
+		src, err := os.Open(filepath.Join(uploadDir, safeName))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return err
+		}
+		dst, err := os.Create(filepath.Join(destDir, safeName))
+		if err != nil {
+			src.Close()
+			return err
+		}
+		_, copyErr := io.Copy(dst, src)
+		src.Close()
+		dst.Close()
+		if copyErr != nil {
+			return copyErr
+		}
+	}
+	return nil
+}
+
+func writePage(tmpDir, relPath string, tmpl *template.Template, data any) error {
+	fullPath := filepath.Join(tmpDir, relPath)
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(fullPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return tmpl.Execute(f, data)
+}
+
+func articlesInSeries(db *gorm.DB, seriesID uint) ([]models.Article, error) {
+	var articles []models.Article
+	err := db.Where("series_id = ? AND status = ?", seriesID, models.ArticleStatusPublished).
+		Order("series_order asc").Find(&articles).Error
+	return articles, err
+}
+
+func articlesWithTag(db *gorm.DB, tagID uint) ([]models.Article, error) {
+	var articles []models.Article
+	err := db.Joins("JOIN article_tags ON article_tags.article_id = articles.id").
+		Where("article_tags.tag_id = ? AND articles.status = ?", tagID, models.ArticleStatusPublished).
+		Order("articles.created_at desc").Find(&articles).Error
+	return articles, err
+}
+
+type seriesPage struct {
+	Series   models.Series
+	Articles []models.Article
+}
+
+type tagPage struct {
+	Tag      models.Tag
+	Articles []models.Article
+}
+
+func tarGzDir(srcDir, destFile string) error {
+	out, err := os.Create(destFile)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	gz := gzip.NewWriter(out)
+	defer gz.Close()
+	tw := tar.NewWriter(gz)
+	defer tw.Close()
+
+	return filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		relPath, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = relPath
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+}
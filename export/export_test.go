@@ -0,0 +1,160 @@
+package export
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/1055373165/blog/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupExportTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	conn, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	if err := conn.AutoMigrate(&models.Article{}, &models.Blog{}, &models.Series{}, &models.Tag{}, &models.Category{}); err != nil {
+		t.Fatalf("failed to migrate test db: %v", err)
+	}
+	return conn
+}
+
+func tarEntryNames(t *testing.T, path string) map[string]bool {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open archive: %v", err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("failed to open gzip reader: %v", err)
+	}
+	defer gz.Close()
+
+	names := make(map[string]bool)
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed to read tar entry: %v", err)
+		}
+		names[header.Name] = true
+	}
+	return names
+}
+
+func TestRunExportProducesAllExpectedPages(t *testing.T) {
+	db := setupExportTestDB(t)
+
+	tag := models.Tag{Name: "go", Slug: "go"}
+	db.Create(&tag)
+	series := models.Series{Title: "Tutorial", Slug: "tutorial"}
+	db.Create(&series)
+	category := models.Category{Name: "Notes", Slug: "notes"}
+	db.Create(&category)
+
+	article := models.Article{
+		AuthorID: 1, Title: "Hello", Slug: "hello", Status: models.ArticleStatusPublished,
+		Content: "body referencing /api/files/cover.png", Tags: []models.Tag{tag},
+	}
+	db.Create(&article)
+	draft := models.Article{AuthorID: 1, Title: "Draft", Slug: "draft-post", Status: models.ArticleStatusDraft}
+	db.Create(&draft)
+	db.Create(&models.Blog{AuthorID: 1, Title: "Episode 1", Slug: "episode-1", MediaURL: "episode1.mp3"})
+
+	exportDir := t.TempDir()
+	uploadDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(uploadDir, "cover.png"), []byte("fake-png"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var progressCalls int
+	name, err := runExport(context.Background(), db, exportDir, uploadDir, func(done, total int) { progressCalls++ })
+	if err != nil {
+		t.Fatalf("runExport returned error: %v", err)
+	}
+	if progressCalls == 0 {
+		t.Error("expected onProgress to be called at least once")
+	}
+
+	names := tarEntryNames(t, filepath.Join(exportDir, name))
+	for _, want := range []string{
+		"articles/hello/index.html",
+		"blogs/episode-1/index.html",
+		"series/tutorial/index.html",
+		"tags/go/index.html",
+		"categories/notes/index.html",
+		"archive/index.html",
+		"sitemap.xml",
+		"feed.xml",
+		"files/cover.png",
+	} {
+		if !names[want] {
+			t.Errorf("expected archive to contain %q, entries: %v", want, names)
+		}
+	}
+	if names["articles/draft-post/index.html"] {
+		t.Error("expected the unpublished draft to be excluded from the export")
+	}
+}
+
+func TestManagerRejectsConcurrentStarts(t *testing.T) {
+	db := setupExportTestDB(t)
+	m := NewManager()
+
+	if err := m.Start(db, t.TempDir(), t.TempDir()); err != nil {
+		t.Fatalf("first Start returned error: %v", err)
+	}
+	if err := m.Start(db, t.TempDir(), t.TempDir()); err != ErrAlreadyRunning {
+		t.Errorf("expected ErrAlreadyRunning for a second concurrent Start, got %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for m.Status().Status == StatusRunning && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := m.Status().Status; got != StatusDone {
+		t.Errorf("expected the export to finish as StatusDone, got %q", got)
+	}
+}
+
+func TestManagerCancel(t *testing.T) {
+	db := setupExportTestDB(t)
+	for i := 0; i < 200; i++ {
+		db.Create(&models.Article{AuthorID: 1, Title: "Post", Slug: "post-" + strconv.Itoa(i), Status: models.ArticleStatusPublished})
+	}
+
+	m := NewManager()
+	if err := m.Start(db, t.TempDir(), t.TempDir()); err != nil {
+		t.Fatalf("Start returned error: %v", err)
+	}
+	if err := m.Cancel(); err != nil {
+		t.Fatalf("Cancel returned error: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for m.Status().Status == StatusRunning && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := m.Status().Status; got != StatusCancelled && got != StatusDone {
+		t.Errorf("expected StatusCancelled (or StatusDone if it finished first), got %q", got)
+	}
+
+	if err := m.Cancel(); err != ErrNotRunning {
+		t.Errorf("expected ErrNotRunning for a second Cancel, got %v", err)
+	}
+}
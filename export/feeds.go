@@ -0,0 +1,92 @@
+package export
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/1055373165/blog/models"
+)
+
+type sitemapURL struct {
+	Loc string `xml:"loc"`
+}
+
+type sitemapIndex struct {
+	XMLName xml.Name     `xml:"urlset"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+// writeSitemap writes sitemap.xml listing every page this export
+// produces, relative to the site root.
+func writeSitemap(tmpDir string, articles []models.Article, blogs []models.Blog, series []models.Series, tags []models.Tag, categories []models.Category) error {
+	index := sitemapIndex{Xmlns: "http://www.sitemaps.org/schemas/sitemap/0.9"}
+	index.URLs = append(index.URLs, sitemapURL{Loc: "/archive/"})
+	for _, a := range articles {
+		index.URLs = append(index.URLs, sitemapURL{Loc: "/articles/" + a.Slug + "/"})
+	}
+	for _, b := range blogs {
+		index.URLs = append(index.URLs, sitemapURL{Loc: "/blogs/" + b.Slug + "/"})
+	}
+	for _, s := range series {
+		index.URLs = append(index.URLs, sitemapURL{Loc: "/series/" + s.Slug + "/"})
+	}
+	for _, t := range tags {
+		index.URLs = append(index.URLs, sitemapURL{Loc: "/tags/" + t.Slug + "/"})
+	}
+	for _, c := range categories {
+		index.URLs = append(index.URLs, sitemapURL{Loc: "/categories/" + c.Slug + "/"})
+	}
+
+	data, err := xml.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(tmpDir, "sitemap.xml"), append([]byte(xml.Header), data...), 0o644)
+}
+
+type rssItem struct {
+	Title   string `xml:"title"`
+	Link    string `xml:"link"`
+	GUID    string `xml:"guid"`
+	PubDate string `xml:"pubDate"`
+}
+
+type rssChannel struct {
+	Title string    `xml:"title"`
+	Link  string    `xml:"link"`
+	Items []rssItem `xml:"item"`
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+// writeFeed writes feed.xml, an RSS 2.0 feed of every exported article.
+func writeFeed(tmpDir string, articles []models.Article) error {
+	feed := rssFeed{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title: "Blog",
+			Link:  "/",
+		},
+	}
+	for _, a := range articles {
+		feed.Channel.Items = append(feed.Channel.Items, rssItem{
+			Title:   a.Title,
+			Link:    "/articles/" + a.Slug + "/",
+			GUID:    "/articles/" + a.Slug + "/",
+			PubDate: a.CreatedAt.Format(time.RFC1123Z),
+		})
+	}
+
+	data, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(tmpDir, "feed.xml"), append([]byte(xml.Header), data...), 0o644)
+}
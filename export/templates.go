@@ -0,0 +1,64 @@
+package export
+
+import "html/template"
+
+// These templates are deliberately minimal: there is no Markdown-to-HTML
+// renderer anywhere in this tree (see the package doc comment), so
+// Markdown bodies are embedded verbatim inside a <pre> block rather than
+// rendered to HTML.
+
+var articlePageTemplate = template.Must(template.New("article").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>{{.Title}}</title></head>
+<body>
+<h1>{{.Title}}</h1>
+<pre>{{.Content}}</pre>
+</body></html>
+`))
+
+var blogPageTemplate = template.Must(template.New("blog").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>{{.Title}}</title></head>
+<body>
+<h1>{{.Title}}</h1>
+<p>{{.Description}}</p>
+</body></html>
+`))
+
+var seriesPageTemplate = template.Must(template.New("series").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>{{.Series.Title}}</title></head>
+<body>
+<h1>{{.Series.Title}}</h1>
+<ul>
+{{range .Articles}}<li><a href="../../articles/{{.Slug}}/">{{.Title}}</a></li>
+{{end}}</ul>
+</body></html>
+`))
+
+var tagPageTemplate = template.Must(template.New("tag").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>{{.Tag.Name}}</title></head>
+<body>
+<h1>{{.Tag.Name}}</h1>
+<ul>
+{{range .Articles}}<li><a href="../../articles/{{.Slug}}/">{{.Title}}</a></li>
+{{end}}</ul>
+</body></html>
+`))
+
+// categoryPageTemplate renders only category metadata: Article has no
+// CategoryID in this tree, so there is no post listing to include (see
+// README "Known gaps").
+var categoryPageTemplate = template.Must(template.New("category").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>{{.Name}}</title></head>
+<body>
+<h1>{{.Name}}</h1>
+</body></html>
+`))
+
+var archivePageTemplate = template.Must(template.New("archive").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>Archive</title></head>
+<body>
+<h1>Archive</h1>
+<ul>
+{{range .}}<li><a href="../articles/{{.Slug}}/">{{.Title}}</a></li>
+{{end}}</ul>
+</body></html>
+`))
@@ -1,6 +0,0 @@
-package main
-
-func main() {
-    fmt.Println("this is a fix golang program")
-}
-
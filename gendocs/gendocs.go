@@ -0,0 +1,53 @@
+// Package gendocs builds the OpenAPI 3 specification served at
+// GET /api/docs/openapi.json from the swag annotations on handler
+// functions and request/response structs. It backs the `go generate`
+// target in cmd/gendocs and the drift check in docs/generate_test.go.
+package gendocs
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
+	"github.com/swaggo/swag/gen"
+)
+
+// Run parses main.go's swag annotations into a Swagger 2.0 document,
+// converts it to OpenAPI 3, and writes the result to
+// outDir/openapi.json. searchDir is the module root to parse.
+func Run(searchDir, outDir string) error {
+	if err := gen.New().Build(&gen.Config{
+		SearchDir:       searchDir,
+		MainAPIFile:     "main.go",
+		OutputDir:       outDir,
+		OutputTypes:     []string{"json"},
+		ParseDependency: true,
+	}); err != nil {
+		return err
+	}
+
+	swagger2, err := os.ReadFile(filepath.Join(outDir, "swagger.json"))
+	if err != nil {
+		return err
+	}
+
+	var doc2 openapi2.T
+	if err := json.Unmarshal(swagger2, &doc2); err != nil {
+		return err
+	}
+
+	doc3, err := openapi2conv.ToV3(&doc2)
+	if err != nil {
+		return err
+	}
+
+	out, err := json.MarshalIndent(doc3, "", "  ")
+	if err != nil {
+		return err
+	}
+	out = append(out, '\n')
+
+	return os.WriteFile(filepath.Join(outDir, "openapi.json"), out, 0o644)
+}
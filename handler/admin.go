@@ -0,0 +1,402 @@
+package handler
+
+import (
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/1055373165/blog/audit"
+	"github.com/1055373165/blog/cache"
+	"github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/middleware"
+	"github.com/1055373165/blog/models"
+	"github.com/1055373165/blog/searchstats"
+	"github.com/1055373165/blog/service"
+	"github.com/1055373165/blog/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// searchStatsDefaultLimit is how many popular/recent queries GetSearchStats
+// returns when the caller doesn't specify limit.
+const searchStatsDefaultLimit = 20
+
+// normalizeContentURLsBatchSize is the number of articles processed per
+// batch when backfilling content URLs.
+const normalizeContentURLsBatchSize = 100
+
+// NormalizeContentURLs rewrites every Article's stored Content field into
+// the canonical relative upload-URL form, for use after an environment or
+// domain migration left absolute upload URLs baked into stored content.
+//
+// @Summary      Normalize article content URLs
+// @Description  Rewrites every Article's stored Content field into the canonical relative upload-URL form. Admin only.
+// @Tags         admin
+// @Produce      json
+// @Success      200  {object}  utils.Response{data=map[string]int}
+// @Failure      500  {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /admin/normalize-content-urls [post]
+func NormalizeContentURLs(c *gin.Context) {
+	touched, err := service.NormalizeArticleContentURLs(db.DB, normalizeContentURLsBatchSize)
+	if err != nil {
+		slog.Error("failed to normalize article content urls", "request_id", middleware.RequestID(c), "error", err)
+		utils.Fail(c, 500, "failed to normalize content urls")
+		return
+	}
+
+	slog.Info("normalized article content urls", "request_id", middleware.RequestID(c), "articles_touched", len(touched))
+	audit.Record(c, "normalize_content_urls", "article", 0, nil, touched)
+	utils.OK(c, touched)
+}
+
+// BackfillExcerpts regenerates Excerpt for every Article and Submission
+// row where it's still auto-generated or empty, for use after a bulk
+// content import or once for rows created before Excerpt existed.
+// Hand-written excerpts are left untouched.
+//
+// @Summary      Backfill auto-generated excerpts
+// @Description  Regenerates Excerpt for every Article and Submission row where ExcerptAuto is true or Excerpt is empty, leaving hand-written excerpts untouched. Admin only.
+// @Tags         admin
+// @Produce      json
+// @Success      200  {object}  utils.Response{data=service.ExcerptBackfillResult}
+// @Failure      500  {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /admin/backfill-excerpts [post]
+func BackfillExcerpts(c *gin.Context) {
+	result, err := service.BackfillExcerpts(db.DB)
+	if err != nil {
+		slog.Error("failed to backfill excerpts", "request_id", middleware.RequestID(c), "error", err)
+		utils.Fail(c, 500, "failed to backfill excerpts")
+		return
+	}
+
+	slog.Info("backfilled excerpts", "request_id", middleware.RequestID(c), "articles_updated", result.ArticlesUpdated, "submissions_updated", result.SubmissionsUpdated)
+	audit.Record(c, "backfill_excerpts", "article", 0, nil, result)
+	utils.OK(c, result)
+}
+
+// defaultAuditLogPageSize and maxAuditLogPageSize bound the page_size query
+// parameter for GET /admin/audit-log.
+const (
+	defaultAuditLogPageSize = 50
+	maxAuditLogPageSize     = 200
+)
+
+// GetAuditLog returns audit log entries, most recent first, optionally
+// filtered by actor, action, entity type, and creation date range.
+//
+// @Summary      List audit log entries
+// @Description  Returns audit log entries, most recent first, filterable by actor, action, entity type, and date range. Admin only.
+// @Tags         admin
+// @Produce      json
+// @Param        actor_id     query     int     false  "Filter by actor user ID"
+// @Param        action       query     string  false  "Filter by action"
+// @Param        entity_type  query     string  false  "Filter by entity type"
+// @Param        since        query     string  false  "Only entries created at or after this RFC3339 timestamp"
+// @Param        until        query     string  false  "Only entries created at or before this RFC3339 timestamp"
+// @Param        page         query     int     false  "Page number, starting at 1"
+// @Param        page_size    query     int     false  "Entries per page, max 200"
+// @Success      200          {object}  utils.Response{data=[]models.AuditLog}
+// @Failure      400          {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /admin/audit-log [get]
+func GetAuditLog(c *gin.Context) {
+	query := db.DB.Model(&models.AuditLog{})
+
+	if v := c.Query("actor_id"); v != "" {
+		actorID, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			utils.Fail(c, 400, "invalid actor_id")
+			return
+		}
+		query = query.Where("actor_id = ?", actorID)
+	}
+	if v := c.Query("action"); v != "" {
+		query = query.Where("action = ?", v)
+	}
+	if v := c.Query("entity_type"); v != "" {
+		query = query.Where("entity_type = ?", v)
+	}
+	if v := c.Query("since"); v != "" {
+		since, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			utils.Fail(c, 400, "invalid since, expected RFC3339")
+			return
+		}
+		query = query.Where("created_at >= ?", since)
+	}
+	if v := c.Query("until"); v != "" {
+		until, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			utils.Fail(c, 400, "invalid until, expected RFC3339")
+			return
+		}
+		query = query.Where("created_at <= ?", until)
+	}
+
+	page := 1
+	if v := c.Query("page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			utils.Fail(c, 400, "invalid page")
+			return
+		}
+		page = n
+	}
+	pageSize := defaultAuditLogPageSize
+	if v := c.Query("page_size"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxAuditLogPageSize {
+			utils.Fail(c, 400, "invalid page_size")
+			return
+		}
+		pageSize = n
+	}
+
+	var entries []models.AuditLog
+	if err := query.Order("created_at desc").
+		Offset((page - 1) * pageSize).Limit(pageSize).
+		Find(&entries).Error; err != nil {
+		utils.Fail(c, 500, "failed to load audit log")
+		return
+	}
+
+	utils.OK(c, entries)
+}
+
+// GetDuplicateArticles scans every published article pairwise for
+// near-duplicate titles or content, for manual merge review.
+//
+// @Summary      Scan for duplicate articles
+// @Description  Scans every published article pairwise above a similarity threshold (default 0.6) and lists suspect pairs for manual merge. Admin only.
+// @Tags         admin
+// @Produce      json
+// @Param        threshold  query     number  false  "Similarity threshold, 0-1"
+// @Success      200        {object}  utils.Response{data=[]service.DuplicatePair}
+// @Failure      400        {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /admin/duplicates [get]
+func GetDuplicateArticles(c *gin.Context) {
+	threshold := service.DefaultDuplicateThreshold
+	if v := c.Query("threshold"); v != "" {
+		t, err := strconv.ParseFloat(v, 64)
+		if err != nil || t < 0 || t > 1 {
+			utils.Fail(c, 400, "invalid threshold, expected a number between 0 and 1")
+			return
+		}
+		threshold = t
+	}
+
+	pairs, err := service.ScanDuplicatePairs(db.DB, threshold)
+	if err != nil {
+		utils.Fail(c, 500, "failed to scan for duplicates")
+		return
+	}
+
+	utils.OK(c, pairs)
+}
+
+// GetEditorialCalendar returns a month-view of editorial activity bucketed
+// by date: submissions submitted or reviewed in range, and articles
+// published in range. The range is capped at 92 days.
+//
+// @Summary      Get the editorial calendar
+// @Description  Returns submission and article events bucketed by date over a range capped at 92 days. Admin only.
+// @Tags         admin
+// @Produce      json
+// @Param        from  query     string  true  "Range start, YYYY-MM-DD"
+// @Param        to    query     string  true  "Range end, YYYY-MM-DD"
+// @Success      200   {object}  utils.Response{data=object{}}
+// @Failure      400   {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /admin/editorial-calendar [get]
+func GetEditorialCalendar(c *gin.Context) {
+	from, err := time.Parse("2006-01-02", c.Query("from"))
+	if err != nil {
+		utils.Fail(c, 400, "invalid from, expected YYYY-MM-DD")
+		return
+	}
+	to, err := time.Parse("2006-01-02", c.Query("to"))
+	if err != nil {
+		utils.Fail(c, 400, "invalid to, expected YYYY-MM-DD")
+		return
+	}
+	to = to.Add(24*time.Hour - time.Nanosecond)
+
+	calendar, err := service.GetEditorialCalendar(db.DB, from, to)
+	if err != nil {
+		switch err {
+		case service.ErrEditorialCalendarRangeInvalid, service.ErrEditorialCalendarRangeTooLarge:
+			utils.Fail(c, 400, err.Error())
+		default:
+			utils.Fail(c, 500, "failed to load editorial calendar")
+		}
+		return
+	}
+
+	utils.OK(c, calendar)
+}
+
+// RecountLikes resynchronizes Article.Likes and Blog.Likes with the
+// actual number of "like" Reaction rows for each target, for use after
+// any drift is suspected (e.g. rows deleted out-of-band).
+//
+// @Summary      Resynchronize denormalized like counts
+// @Description  Resynchronizes Article.Likes and Blog.Likes with the actual number of "like" Reaction rows for each target, in batches. Admin only.
+// @Tags         admin
+// @Produce      json
+// @Success      200  {object}  utils.Response{data=service.LikesRecountResult}
+// @Failure      500  {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /admin/recount-likes [post]
+func RecountLikes(c *gin.Context) {
+	result, err := service.RecountLikes(db.DB)
+	if err != nil {
+		slog.Error("failed to recount likes", "request_id", middleware.RequestID(c), "error", err)
+		utils.Fail(c, 500, "failed to recount likes")
+		return
+	}
+
+	slog.Info("recounted likes", "request_id", middleware.RequestID(c), "articles_fixed", result.ArticlesFixed, "blogs_fixed", result.BlogsFixed)
+	audit.Record(c, "recount_likes", "article", 0, nil, result)
+	cache.Default.Invalidate(cache.KeyPopularArticles)
+	utils.OK(c, result)
+}
+
+// SearchStatsWindowResponse is GetSearchStats' payload when from/to is
+// given: the window's popular/recent queries and total query count, plus
+// - under compare=previous - the immediately preceding window's query
+// count and the delta between the two. Dropped isn't windowed - see
+// service.SearchStatsWindow.
+type SearchStatsWindowResponse struct {
+	From               time.Time                 `json:"from"`
+	To                 time.Time                 `json:"to"`
+	Popular            []service.PopularQuery    `json:"popular"`
+	Recent             []models.SearchStatistics `json:"recent"`
+	QueryCount         int64                     `json:"query_count"`
+	Dropped            uint64                    `json:"dropped"`
+	PreviousQueryCount *int64                    `json:"previous_query_count,omitempty"`
+	DeltaQueryCount    *int64                    `json:"delta_query_count,omitempty"`
+}
+
+// GetSearchStats returns the most popular and most recent search queries
+// recorded by searchstats.Recorder, plus how many queries it has had to
+// drop because its buffer was full. If from/to is given, popular/recent
+// and the total query count are instead restricted to that window.
+//
+// @Summary      Get search statistics
+// @Description  Returns the most popular and most recent search queries, plus the dropped-query count. If from/to (YYYY-MM-DD, capped at 366 days) is given, popular/recent and the query count are restricted to that window instead; compare=previous adds the preceding window's query count and the delta. Admin only.
+// @Tags         admin
+// @Produce      json
+// @Param        limit    query     int     false  "Max popular/recent queries to return, default 20"
+// @Param        from     query     string  false  "Window start, YYYY-MM-DD"
+// @Param        to       query     string  false  "Window end, YYYY-MM-DD"
+// @Param        compare  query     string  false  "Set to 'previous' to include the preceding window's query count and delta"
+// @Success      200    {object}  utils.Response{data=service.SearchStatsResult}
+// @Failure      400    {object}  utils.Response
+// @Failure      500    {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /admin/search-stats [get]
+func GetSearchStats(c *gin.Context) {
+	limit := searchStatsDefaultLimit
+	if v := c.Query("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			limit = n
+		}
+	}
+
+	var dropped uint64
+	if searchstats.Default != nil {
+		dropped = searchstats.Default.Dropped()
+	}
+
+	dr, hasRange, compare, ok := parseOptionalDateRange(c)
+	if !ok {
+		return
+	}
+	if !hasRange {
+		result, err := service.GetSearchStats(db.DB, limit, dropped)
+		if err != nil {
+			slog.Error("failed to load search stats", "request_id", middleware.RequestID(c), "error", err)
+			utils.Fail(c, 500, "failed to load search stats")
+			return
+		}
+		utils.OK(c, result)
+		return
+	}
+
+	window, err := service.GetSearchStatsWindow(db.DB, limit, dr)
+	if err != nil {
+		slog.Error("failed to load search stats", "request_id", middleware.RequestID(c), "error", err)
+		utils.Fail(c, 500, "failed to load search stats")
+		return
+	}
+	resp := SearchStatsWindowResponse{
+		From: dr.From, To: dr.To,
+		Popular: window.Popular, Recent: window.Recent, QueryCount: window.QueryCount,
+		Dropped: dropped,
+	}
+	if compare {
+		previous, err := service.GetSearchStatsWindow(db.DB, limit, dr.Previous())
+		if err != nil {
+			slog.Error("failed to load search stats", "request_id", middleware.RequestID(c), "error", err)
+			utils.Fail(c, 500, "failed to load search stats")
+			return
+		}
+		delta := window.QueryCount - previous.QueryCount
+		resp.PreviousQueryCount = &previous.QueryCount
+		resp.DeltaQueryCount = &delta
+	}
+	utils.OK(c, resp)
+}
+
+// GetSearchConsistency compares the Blog table against the search index
+// (the only content type search/index.go indexes - see README "Known
+// gaps") and reports Blog rows missing from the index, indexed
+// documents whose Blog has expired or no longer exists, and indexed
+// documents that predate their Blog's current content. It changes
+// nothing; see PostSearchRepair to fix what it finds.
+//
+// @Summary      Check search index consistency against the database
+// @Description  Compares the Blog table against the search index, reporting missing, orphaned, and stale documents with a bounded ID sample per bucket. Read-only. Admin only.
+// @Tags         admin
+// @Produce      json
+// @Success      200  {object}  utils.Response{data=service.SearchConsistencyReport}
+// @Failure      500  {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /admin/search/consistency [get]
+func GetSearchConsistency(c *gin.Context) {
+	report, err := service.CheckSearchConsistency(db.DB)
+	if err != nil {
+		slog.Error("failed to check search consistency", "request_id", middleware.RequestID(c), "error", err)
+		utils.Fail(c, 500, "failed to check search consistency")
+		return
+	}
+	utils.OK(c, report)
+}
+
+// PostSearchRepair fixes exactly the discrepancies GetSearchConsistency
+// would report: it indexes every missing Blog, deletes every orphaned
+// document, and reindexes every stale one, without rebuilding the index
+// from scratch.
+//
+// @Summary      Repair search index discrepancies
+// @Description  Indexes every Blog missing from the search index, deletes every orphaned document, and reindexes every stale one, without a full rebuild. Admin only.
+// @Tags         admin
+// @Produce      json
+// @Success      200  {object}  utils.Response{data=service.SearchConsistencyRepairResult}
+// @Failure      500  {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /admin/search/repair [post]
+func PostSearchRepair(c *gin.Context) {
+	result, err := service.RepairSearchConsistency(db.DB)
+	if err != nil {
+		slog.Error("failed to repair search consistency", "request_id", middleware.RequestID(c), "error", err)
+		utils.Fail(c, 500, "failed to repair search consistency")
+		return
+	}
+
+	slog.Info("repaired search consistency", "request_id", middleware.RequestID(c), "indexed", result.Indexed, "deleted", result.Deleted, "reindexed", result.Reindexed)
+	audit.Record(c, "repair_search_consistency", "blog", 0, nil, result)
+	utils.OK(c, result)
+}
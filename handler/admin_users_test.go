@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	blogdb "github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupAdminUsersTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	conn, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	if err := conn.AutoMigrate(&models.User{}, &models.Article{}, &models.Submission{}); err != nil {
+		t.Fatalf("failed to migrate test db: %v", err)
+	}
+	blogdb.DB = conn
+	return conn
+}
+
+func TestGetUsersReturnsArticleCountsAndTotal(t *testing.T) {
+	db := setupAdminUsersTestDB(t)
+
+	author := models.User{Username: "author", Email: "author@example.com"}
+	db.Create(&author)
+	db.Create(&models.Article{AuthorID: author.ID, Title: "a", Slug: "a"})
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/api/admin/users", GetUsers)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/users", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"article_count":1`) {
+		t.Errorf("expected article_count 1 in response, got %s", w.Body.String())
+	}
+}
+
+func TestGetUsersRejectsInvalidRole(t *testing.T) {
+	setupAdminUsersTestDB(t)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/api/admin/users", GetUsers)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/users?role=superuser", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an invalid role, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestExportUsersCSVStreamsHeaderAndMatchingRows(t *testing.T) {
+	db := setupAdminUsersTestDB(t)
+
+	db.Create(&models.User{Username: "alice", Email: "alice@example.com"})
+	db.Create(&models.User{Username: "bob", Email: "bob@example.com"})
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/api/admin/users/export.csv", ExportUsersCSV)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/users/export.csv?q=ali", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "text/csv" {
+		t.Errorf("expected Content-Type text/csv, got %q", ct)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(w.Body.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to parse CSV body: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected a header row plus one matching user, got %d rows: %v", len(records), records)
+	}
+	if records[0][0] != "id" {
+		t.Errorf("expected first column header \"id\", got %q", records[0][0])
+	}
+	if records[1][1] != "alice" {
+		t.Errorf("expected the matching row to be alice, got %v", records[1])
+	}
+}
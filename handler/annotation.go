@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/middleware"
+	"github.com/1055373165/blog/models"
+	"github.com/1055373165/blog/service"
+	"github.com/1055373165/blog/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateAnnotationRequest is the payload for POST /api/articles/:id/annotations.
+type CreateAnnotationRequest struct {
+	StudyItemID *uint  `json:"study_item_id"`
+	StartOffset int    `json:"start_offset" binding:"min=0"`
+	EndOffset   int    `json:"end_offset" binding:"required,gtfield=StartOffset"`
+	QuotedText  string `json:"quoted_text" binding:"required"`
+	Note        string `json:"note"`
+	Color       string `json:"color"`
+}
+
+// CreateAnnotation anchors a new highlight/note to an article for the
+// caller.
+//
+// @Summary      Create an annotation
+// @Description  Anchors a new highlight and optional note to a span of an article's content, owned by the caller.
+// @Tags         study
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                       true  "Article ID"
+// @Param        request  body      CreateAnnotationRequest  true  "Annotation fields"
+// @Success      200      {object}  utils.Response{data=models.Annotation}
+// @Failure      400      {object}  utils.Response{details=[]utils.FieldError}
+// @Security     BearerAuth
+// @Router       /articles/{id}/annotations [post]
+func CreateAnnotation(c *gin.Context) {
+	articleID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid article id")
+		return
+	}
+
+	var req CreateAnnotationRequest
+	if !utils.BindJSON(c, &req) {
+		return
+	}
+
+	annotation := models.Annotation{
+		UserID:      middleware.CurrentUserID(c),
+		ArticleID:   uint(articleID),
+		StudyItemID: req.StudyItemID,
+		StartOffset: req.StartOffset,
+		EndOffset:   req.EndOffset,
+		QuotedText:  req.QuotedText,
+		Note:        req.Note,
+		Color:       req.Color,
+	}
+	if err := service.CreateAnnotation(db.DB, &annotation); err != nil {
+		utils.Fail(c, 500, "failed to create annotation")
+		return
+	}
+
+	utils.OK(c, annotation)
+}
+
+// GetArticleAnnotations bulk-fetches all of the caller's annotations for an
+// article in one call, with anchors re-located against the article's
+// current content where the original offsets no longer match.
+//
+// @Summary      List my annotations for an article
+// @Description  Returns all of the caller's annotations for an article, with anchors re-located against current content, or flagged orphaned if the quoted text can no longer be found.
+// @Tags         study
+// @Produce      json
+// @Param        id   path      int  true  "Article ID"
+// @Success      200  {object}  utils.Response{data=[]service.AnnotationWithStatus}
+// @Failure      404  {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /articles/{id}/annotations [get]
+func GetArticleAnnotations(c *gin.Context) {
+	articleID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid article id")
+		return
+	}
+
+	var article models.Article
+	if err := db.DB.First(&article, articleID).Error; err != nil {
+		utils.Fail(c, 404, "article not found")
+		return
+	}
+
+	annotations, err := service.GetArticleAnnotations(db.DB, middleware.CurrentUserID(c), uint(articleID), article.Content)
+	if err != nil {
+		utils.Fail(c, 500, "failed to load annotations")
+		return
+	}
+	utils.OK(c, annotations)
+}
+
+// loadOwnedAnnotation fetches the :annotation_id annotation owned by the
+// caller, failing the request with 404 if it doesn't exist or isn't theirs.
+func loadOwnedAnnotation(c *gin.Context) (models.Annotation, bool) {
+	var annotation models.Annotation
+	id, err := strconv.ParseUint(c.Param("annotation_id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid annotation id")
+		return annotation, false
+	}
+	if err := db.DB.Where("id = ? AND user_id = ?", id, middleware.CurrentUserID(c)).
+		First(&annotation).Error; err != nil {
+		utils.Fail(c, 404, "annotation not found")
+		return annotation, false
+	}
+	return annotation, true
+}
+
+// UpdateAnnotationRequest is the payload for PUT
+// /api/articles/:id/annotations/:annotation_id. The anchor itself isn't
+// updatable; re-anchoring after a content edit happens automatically on
+// read (see GetArticleAnnotations), not through this endpoint.
+type UpdateAnnotationRequest struct {
+	Note  *string `json:"note"`
+	Color *string `json:"color"`
+}
+
+// UpdateAnnotation updates the caller's annotation's note and/or color.
+//
+// @Summary      Update an annotation
+// @Description  Updates the note and/or color of the caller's annotation.
+// @Tags         study
+// @Accept       json
+// @Produce      json
+// @Param        id             path      int                       true  "Article ID"
+// @Param        annotation_id  path      int                       true  "Annotation ID"
+// @Param        request        body      UpdateAnnotationRequest  true  "Fields to update"
+// @Success      200            {object}  utils.Response{data=models.Annotation}
+// @Failure      400            {object}  utils.Response{details=[]utils.FieldError}
+// @Failure      404            {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /articles/{id}/annotations/{annotation_id} [put]
+func UpdateAnnotation(c *gin.Context) {
+	annotation, ok := loadOwnedAnnotation(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateAnnotationRequest
+	if !utils.BindJSON(c, &req) {
+		return
+	}
+
+	updates := map[string]any{}
+	if req.Note != nil {
+		updates["note"] = *req.Note
+	}
+	if req.Color != nil {
+		updates["color"] = *req.Color
+	}
+
+	updated, err := service.UpdateAnnotation(db.DB, annotation.ID, middleware.CurrentUserID(c), updates)
+	if err != nil {
+		utils.Fail(c, 500, "failed to update annotation")
+		return
+	}
+	utils.OK(c, updated)
+}
+
+// DeleteAnnotation deletes the caller's annotation.
+//
+// @Summary      Delete an annotation
+// @Description  Deletes the caller's annotation.
+// @Tags         study
+// @Produce      json
+// @Param        id             path      int  true  "Article ID"
+// @Param        annotation_id  path      int  true  "Annotation ID"
+// @Success      200            {object}  utils.Response
+// @Failure      404            {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /articles/{id}/annotations/{annotation_id} [delete]
+func DeleteAnnotation(c *gin.Context) {
+	annotation, ok := loadOwnedAnnotation(c)
+	if !ok {
+		return
+	}
+
+	if err := service.DeleteAnnotation(db.DB, annotation.ID, middleware.CurrentUserID(c)); err != nil {
+		utils.Fail(c, 500, "failed to delete annotation")
+		return
+	}
+	utils.OK(c, nil)
+}
@@ -0,0 +1,1010 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/1055373165/blog/cache"
+	"github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/middleware"
+	"github.com/1055373165/blog/models"
+	"github.com/1055373165/blog/service"
+	"github.com/1055373165/blog/siteconfig"
+	"github.com/1055373165/blog/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// canViewArticle reports whether the caller may see article, given its
+// publication status and expiry. Published, unexpired articles are
+// visible to anyone; drafts and expired articles are visible only to
+// their author or an admin, so a guessed ID or slug can't be used to
+// read unpublished or expired content.
+func canViewArticle(c *gin.Context, article models.Article) bool {
+	expired := article.ExpiresAt != nil && article.ExpiresAt.Before(time.Now())
+	if article.Status == models.ArticleStatusPublished && !expired {
+		return true
+	}
+	uid := middleware.CurrentUserID(c)
+	return uid != 0 && (uid == article.AuthorID || middleware.CurrentRole(c) == string(models.RoleAdmin))
+}
+
+// GetArticle returns a single article by ID. Unpublished drafts 404 for
+// anyone but their author or an admin. It no longer increments the view
+// counter itself - POST /api/articles/:id/view does, so prerendering,
+// admin dashboards, and bots that merely read an article don't inflate
+// its count just by doing so.
+//
+// @Summary      Get an article by ID
+// @Description  Returns a single article. Unpublished drafts 404 for anyone but their author or an admin.
+// @Tags         articles
+// @Produce      json
+// @Param        id   path      int  true  "Article ID"
+// @Success      200  {object}  utils.Response{data=object{contributors=[]models.ArticleContributor}}
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /articles/{id} [get]
+func GetArticle(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid article id")
+		return
+	}
+
+	var article models.Article
+	if err := db.DB.First(&article, id).Error; err != nil {
+		utils.RespondNotFoundOrForbidden(c, false, "article not found", "")
+		return
+	}
+	if !canViewArticle(c, article) {
+		utils.RespondNotFoundOrForbidden(c, false, "article not found", "")
+		return
+	}
+
+	article.Content = utils.ExpandContentURLs(article.Content, utils.ResolvePublicBase(c))
+	respondWithArticle(c, article)
+}
+
+// RecordArticleView increments an article's view counter. It requires a
+// same-origin request (see utils.IsSameOriginRequest) so a curl-level
+// bot can't trivially inflate counts, and - matching GetArticle's old
+// behavior of only counting authenticated views - it's a no-op for
+// anonymous callers and for the article's own author, so an author
+// previewing their own draft doesn't pollute the ranking
+// GetPopularArticles sorts by. It reports success in every no-op case:
+// the caller asked for a view to be recorded, and from their
+// perspective nothing went wrong.
+//
+// @Summary      Record an article view
+// @Description  Increments an article's view counter. No-ops for anonymous callers and the article's own author. Requires a same-origin request.
+// @Tags         articles
+// @Produce      json
+// @Param        id   path      int  true  "Article ID"
+// @Success      200  {object}  utils.Response
+// @Failure      400  {object}  utils.Response
+// @Failure      403  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /articles/{id}/view [post]
+func RecordArticleView(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid article id")
+		return
+	}
+	if !utils.IsSameOriginRequest(c) {
+		utils.Fail(c, 403, "cross-origin view recording is not allowed")
+		return
+	}
+
+	var article models.Article
+	if err := db.DB.First(&article, id).Error; err != nil {
+		utils.RespondNotFoundOrForbidden(c, false, "article not found", "")
+		return
+	}
+	if !canViewArticle(c, article) {
+		utils.RespondNotFoundOrForbidden(c, false, "article not found", "")
+		return
+	}
+
+	uid := middleware.CurrentUserID(c)
+	if uid != 0 && uid != article.AuthorID {
+		if err := service.IncrementArticleViews(db.DB, article.ID); err != nil {
+			utils.Fail(c, 500, "failed to record view")
+			return
+		}
+	}
+	utils.OK(c, nil)
+}
+
+// GetArticleStats returns an article's current views/likes counters
+// without loading its Content or any other column, so admin dashboards
+// and prerendering can poll it cheaply and without the write side
+// effect GetArticle used to have. Subject to the same draft visibility
+// rule as GetArticle.
+//
+// @Summary      Get an article's counters
+// @Description  Returns an article's current views and likes without loading the full article. Unpublished drafts 404 for anyone but their author or an admin.
+// @Tags         articles
+// @Produce      json
+// @Param        id   path      int  true  "Article ID"
+// @Success      200  {object}  utils.Response{data=service.ArticleStats}
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /articles/{id}/stats [get]
+func GetArticleStats(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid article id")
+		return
+	}
+
+	var article models.Article
+	err = db.WithCtx(c.Request.Context()).
+		Select("id", "author_id", "status", "expires_at", "views", "likes").
+		First(&article, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.RespondNotFoundOrForbidden(c, false, "article not found", "")
+			return
+		}
+		utils.FailFromDBError(c, err, "failed to load article stats")
+		return
+	}
+	if !canViewArticle(c, article) {
+		utils.RespondNotFoundOrForbidden(c, false, "article not found", "")
+		return
+	}
+
+	utils.OK(c, service.ArticleStats{Views: article.Views, Likes: article.Likes})
+}
+
+// gatedArticleView is GetArticle/GetArticleBySlug's response when the
+// caller doesn't meet article.AccessLevel: Content is omitted and
+// replaced by a short Excerpt, flagged via ContentLocked so the UI can
+// show an upsell instead of the full post.
+type gatedArticleView struct {
+	articleWithOGImage
+	Content       string `json:"content,omitempty"`
+	Excerpt       string `json:"excerpt"`
+	ContentLocked bool   `json:"content_locked"`
+}
+
+// respondWithArticle writes article as the response, gating Content
+// behind its AccessLevel. A gated response varies by caller rather than
+// by article state alone, so it bypasses ConditionalJSON (see that
+// function's doc comment on per-viewer state) and is never cached.
+func respondWithArticle(c *gin.Context, article models.Article) {
+	allowed, err := service.CanAccessArticleContent(db.DB, middleware.CurrentUserID(c), middleware.CurrentRole(c), article)
+	if err != nil {
+		utils.Fail(c, 500, "failed to check article access")
+		return
+	}
+	if allowed {
+		utils.ConditionalJSON(c, articleWithContributors(c, article))
+		return
+	}
+
+	excerpt := article.Excerpt
+	if excerpt == "" {
+		excerpt = utils.Excerpt(article.Content)
+	}
+	article.Content = ""
+	// Matches ConditionalJSON's unwrapped body shape (see its doc
+	// comment) rather than utils.OK's Response envelope, so the locked
+	// and unlocked branches return the same top-level shape.
+	c.JSON(200, gatedArticleView{
+		articleWithOGImage: articleWithContributors(c, article).(articleWithOGImage),
+		Excerpt:            excerpt,
+		ContentLocked:      true,
+	})
+}
+
+// articleWithContributors loads article's ordered contributor list and
+// wraps it alongside article for the JSON response. Contributors are
+// viewer-independent, so it's safe alongside ConditionalJSON.
+func articleWithContributors(c *gin.Context, article models.Article) any {
+	contributors, err := service.GetArticleContributors(db.DB, article.ID)
+	if err != nil {
+		contributors = nil
+	}
+	translations, err := service.GetTranslations(db.DB, article)
+	if err != nil {
+		translations = nil
+	}
+	reactions, err := service.GetArticleReactionCounts(db.DB, article.ID)
+	if err != nil {
+		reactions = nil
+	}
+	latestChangelog, err := service.GetLatestArticleChangelog(db.DB, article.ID)
+	if err != nil {
+		latestChangelog = nil
+	}
+	return articleWithOGImage{
+		Article:         article,
+		OGImageURL:      articleOGImageURL(c, article),
+		Contributors:    contributors,
+		Translations:    translations,
+		Reactions:       reactions,
+		LatestChangelog: latestChangelog,
+	}
+}
+
+// defaultArticleLanguage is used when CreateArticleRequest omits Language.
+const defaultArticleLanguage = "zh-CN"
+
+// CreateArticleRequest is the payload for POST /api/articles.
+type CreateArticleRequest struct {
+	Title           string                    `json:"title" binding:"required"`
+	Slug            string                    `json:"slug" binding:"required"`
+	Content         string                    `json:"content"`
+	Excerpt         string                    `json:"excerpt"`
+	Language        string                    `json:"language"`
+	AccessLevel     models.ArticleAccessLevel `json:"access_level" binding:"omitempty,oneof=public members supporters"`
+	ExpiresAt       *time.Time                `json:"expires_at"`
+	Contributors    []ContributorRequest      `json:"contributors"`
+	CoverImage      string                    `json:"cover_image"`
+	CoverFocalPoint *CoverFocalPointRequest   `json:"cover_focal_point"`
+}
+
+// CoverFocalPointRequest is CreateArticleRequest's optional
+// cover_focal_point field: normalized (0-1) coordinates of the point
+// GetArticleCover keeps inside any crop of CoverImage.
+type CoverFocalPointRequest struct {
+	X float64 `json:"x" binding:"gte=0,lte=1"`
+	Y float64 `json:"y" binding:"gte=0,lte=1"`
+}
+
+// resolveCoverImage validates coverImage (if non-empty) against this
+// site's own upload storage and decodes its pixel dimensions. An empty
+// coverImage is valid and simply means no cover.
+func resolveCoverImage(coverImage string) (width, height int, err error) {
+	if coverImage == "" {
+		return 0, 0, nil
+	}
+	path, err := service.ResolveCoverImagePath(coverImage)
+	if err != nil {
+		return 0, 0, err
+	}
+	return service.DecodeImageDimensions(path)
+}
+
+// ContributorRequest attributes a co-author, translator, or editor on a
+// created or updated article. AuthorID remains the primary owner and is
+// set separately from the caller's identity.
+type ContributorRequest struct {
+	UserID uint                   `json:"user_id" binding:"required"`
+	Role   models.ContributorRole `json:"role" binding:"required"`
+}
+
+// CreateArticle creates a new draft article authored by the caller,
+// optionally attributing co-authors, translators, or editors. If the
+// title or content closely resembles an existing published article,
+// creation still succeeds but the response includes possible_duplicates
+// so the UI can warn the author.
+//
+// @Summary      Create an article
+// @Description  Creates a new draft article authored by the caller. Warns via possible_duplicates if it resembles an existing published article.
+// @Tags         articles
+// @Accept       json
+// @Produce      json
+// @Param        request  body      CreateArticleRequest  true  "Article fields"
+// @Success      200      {object}  utils.Response{data=object{contributors=[]models.ArticleContributor,possible_duplicates=[]service.DuplicateCandidate}}
+// @Failure      400      {object}  utils.Response{details=[]utils.FieldError}
+// @Failure      409      {object}  utils.Response
+// @Failure      422      {object}  utils.Response{details=utils.ContentTooLargeDetail}
+// @Security     BearerAuth
+// @Router       /articles [post]
+func CreateArticle(c *gin.Context) {
+	var req CreateArticleRequest
+	if !utils.BindJSON(c, &req) {
+		return
+	}
+	if !utils.EnforceContentLength(c, "content", req.Content, siteconfig.KeyContentMaxBytes) {
+		return
+	}
+
+	language := req.Language
+	if language == "" {
+		language = defaultArticleLanguage
+	}
+
+	content, _ := utils.NormalizeContentURLs(req.Content)
+
+	duplicates, err := service.FindPossibleDuplicates(db.DB, req.Title, content, service.DefaultDuplicateThreshold)
+	if err != nil {
+		utils.Fail(c, 500, "failed to check for duplicates")
+		return
+	}
+
+	accessLevel := req.AccessLevel
+	if accessLevel == "" {
+		accessLevel = models.ArticleAccessPublic
+	}
+
+	coverWidth, coverHeight, err := resolveCoverImage(req.CoverImage)
+	if err != nil {
+		utils.Fail(c, 400, err.Error())
+		return
+	}
+	focalX, focalY := 0.5, 0.5
+	if req.CoverFocalPoint != nil {
+		focalX, focalY = req.CoverFocalPoint.X, req.CoverFocalPoint.Y
+	}
+
+	excerpt := req.Excerpt
+	excerptAuto := excerpt == ""
+	if excerptAuto {
+		excerpt = utils.Excerpt(content)
+	}
+
+	article := models.Article{
+		AuthorID:    middleware.CurrentUserID(c),
+		Title:       req.Title,
+		Slug:        req.Slug,
+		Content:     content,
+		Status:      models.ArticleStatusDraft,
+		Language:    language,
+		AccessLevel: accessLevel,
+		ExpiresAt:   req.ExpiresAt,
+		CoverImage:  req.CoverImage,
+		CoverWidth:  coverWidth,
+		CoverHeight: coverHeight,
+		CoverFocalX: focalX,
+		CoverFocalY: focalY,
+		Excerpt:     excerpt,
+		ExcerptAuto: excerptAuto,
+	}
+	if err := db.DB.Create(&article).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			utils.FailWithCode(c, 409, utils.ErrCodeSlugConflict)
+			return
+		}
+		utils.Fail(c, 500, "failed to create article")
+		return
+	}
+
+	if len(req.Contributors) > 0 {
+		contributors := make([]models.ArticleContributor, len(req.Contributors))
+		for i, cr := range req.Contributors {
+			contributors[i] = models.ArticleContributor{UserID: cr.UserID, Role: cr.Role}
+		}
+		if err := service.SetArticleContributors(db.DB, article.ID, contributors); err != nil {
+			utils.Fail(c, 500, "failed to save contributors")
+			return
+		}
+	}
+
+	cache.Default.Invalidate(cache.KeyPopularArticles)
+	cache.Default.Invalidate(cache.KeyStatsSummary)
+	utils.OK(c, articleCreationResponse{
+		articleWithOGImage: articleWithContributors(c, article).(articleWithOGImage),
+		PossibleDuplicates: duplicates,
+	})
+}
+
+// articleCreationResponse adds possible_duplicates to the usual article
+// response, only populated on creation.
+type articleCreationResponse struct {
+	articleWithOGImage
+	PossibleDuplicates []service.DuplicateCandidate `json:"possible_duplicates,omitempty"`
+}
+
+// UpdateArticleRequest is the payload for updating an Article. Every
+// field leaves the corresponding column untouched when omitted, mirroring
+// UpdateBlogRequest. ChangelogSummary isn't a column: a non-empty value
+// asks service.UpdateArticle to also record a public ArticleChangelog
+// entry and bump UpdatedSignificantlyAt, surfaced via GetArticleChangelog
+// and LatestChangelog on the usual article response.
+type UpdateArticleRequest struct {
+	Title            *string                    `json:"title"`
+	Slug             *string                    `json:"slug"`
+	Content          *string                    `json:"content"`
+	Excerpt          *string                    `json:"excerpt"`
+	AccessLevel      *models.ArticleAccessLevel `json:"access_level" binding:"omitempty,oneof=public members supporters"`
+	ChangelogSummary string                     `json:"changelog_summary"`
+}
+
+// UpdateArticle updates an existing Article. Only the author or an admin
+// may update it.
+//
+// @Summary      Update an article
+// @Description  Updates fields on an existing Article. Supplying a non-empty changelog_summary also records a public, timestamped changelog entry - see GetArticleChangelog.
+// @Tags         articles
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                   true  "Article ID"
+// @Param        request  body      UpdateArticleRequest  true  "Fields to update"
+// @Success      200      {object}  utils.Response{data=object{contributors=[]models.ArticleContributor}}
+// @Failure      400      {object}  utils.Response{details=[]utils.FieldError}
+// @Failure      403      {object}  utils.Response
+// @Failure      404      {object}  utils.Response
+// @Failure      409      {object}  utils.Response
+// @Failure      422      {object}  utils.Response{details=utils.ContentTooLargeDetail}
+// @Security     BearerAuth
+// @Router       /articles/{id} [put]
+func UpdateArticle(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid article id")
+		return
+	}
+
+	var article models.Article
+	if err := db.DB.First(&article, id).Error; err != nil {
+		utils.RespondNotFoundOrForbidden(c, false, "article not found", "")
+		return
+	}
+
+	uid := middleware.CurrentUserID(c)
+	if uid != article.AuthorID && middleware.CurrentRole(c) != string(models.RoleAdmin) {
+		utils.RespondNotFoundOrForbidden(c, canViewArticle(c, article), "article not found", "you don't have permission to update this article")
+		return
+	}
+
+	var req UpdateArticleRequest
+	if !utils.BindJSON(c, &req) {
+		return
+	}
+	if req.Content != nil && !utils.EnforceContentLength(c, "content", *req.Content, siteconfig.KeyContentMaxBytes) {
+		return
+	}
+
+	oldSlug := article.Slug
+	if req.Slug != nil && *req.Slug != oldSlug {
+		if err := service.RecordSlugRedirect(db.DB, "article", oldSlug, *req.Slug); err != nil {
+			utils.Fail(c, 409, "slug change would create a redirect cycle")
+			return
+		}
+	}
+
+	updates := map[string]any{}
+	if req.Title != nil {
+		updates["title"] = *req.Title
+	}
+	if req.Slug != nil {
+		updates["slug"] = *req.Slug
+	}
+	if req.Content != nil {
+		content, _ := utils.NormalizeContentURLs(*req.Content)
+		updates["content"] = content
+	}
+	if req.Excerpt != nil {
+		updates["excerpt"] = *req.Excerpt
+		updates["excerpt_auto"] = false
+	}
+	if req.AccessLevel != nil {
+		updates["access_level"] = *req.AccessLevel
+	}
+
+	if err := service.UpdateArticle(db.DB, &article, updates, req.ChangelogSummary); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			utils.FailWithCode(c, 409, utils.ErrCodeSlugConflict)
+			return
+		}
+		utils.Fail(c, 500, "failed to update article")
+		return
+	}
+
+	utils.OK(c, articleWithContributors(c, article))
+}
+
+// GetArticleChangelog returns an article's changelog entries, newest
+// first. Gated the same way as the article itself, so a draft or expired
+// article's changelog isn't visible to anyone but its author or an admin.
+//
+// @Summary      Get an article's changelog
+// @Description  Returns an article's changelog entries, newest first.
+// @Tags         articles
+// @Produce      json
+// @Param        id   path      int  true  "Article ID"
+// @Success      200  {object}  utils.Response{data=[]models.ArticleChangelog}
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /articles/{id}/changelog [get]
+func GetArticleChangelog(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid article id")
+		return
+	}
+
+	var article models.Article
+	if err := db.DB.First(&article, id).Error; err != nil {
+		utils.RespondNotFoundOrForbidden(c, false, "article not found", "")
+		return
+	}
+	if !canViewArticle(c, article) {
+		utils.RespondNotFoundOrForbidden(c, false, "article not found", "")
+		return
+	}
+
+	entries, err := service.GetArticleChangelog(db.DB, uint(id))
+	if err != nil {
+		utils.Fail(c, 500, "failed to load changelog")
+		return
+	}
+	utils.OK(c, entries)
+}
+
+// ToggleArticleLike flips the caller's like on an article. This is a
+// thin, pre-reactions alias for ToggleArticleReaction with
+// models.ReactionLike, kept so existing callers of this route don't
+// break; it shares that function's toggle-on-the-reaction-table logic
+// rather than incrementing Article.Likes directly, so the two routes
+// can't drift apart or double-count the same like.
+//
+// @Summary      Toggle a like on an article
+// @Description  Toggles the caller's like on a published article, keeping the legacy likes counter in sync. Equivalent to POST /articles/:id/reactions with reaction_type "like".
+// @Tags         articles
+// @Produce      json
+// @Param        id   path      int  true  "Article ID"
+// @Success      200  {object}  utils.Response{data=object{liked=bool}}
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /articles/{id}/like [post]
+func ToggleArticleLike(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid article id")
+		return
+	}
+
+	liked, err := service.ToggleArticleReaction(db.DB, middleware.CurrentUserID(c), uint(id), models.ReactionLike)
+	if err != nil {
+		if err == service.ErrArticleNotFound {
+			utils.FailWithCode(c, 404, utils.ErrCodeArticleNotFound)
+			return
+		}
+		utils.Fail(c, 500, "failed to toggle like")
+		return
+	}
+
+	cache.Default.Invalidate(cache.KeyPopularArticles)
+	utils.OK(c, gin.H{"liked": liked})
+}
+
+// ToggleReactionRequest is the payload for POST /api/articles/:id/reactions
+// and POST /api/blogs/:id/reactions.
+type ToggleReactionRequest struct {
+	ReactionType models.ReactionType `json:"reaction_type" binding:"required,oneof=like heart celebrate thinking"`
+}
+
+// reactionsResponse is GetArticleReactions/GetBlogReactions's response:
+// per-type counts plus the caller's own reactions.
+type reactionsResponse struct {
+	Counts service.ReactionCounts `json:"counts"`
+	Mine   []models.ReactionType  `json:"mine"`
+}
+
+// ToggleArticleReaction toggles the caller's reaction of a given type on
+// a published article, mapping the "like" type onto the legacy
+// likes counter for backward compatibility.
+//
+// @Summary      Toggle a reaction on an article
+// @Description  Toggles the caller's reaction (like, heart, celebrate, or thinking) on a published article. Toggling "like" keeps the legacy likes counter in sync.
+// @Tags         articles
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                   true  "Article ID"
+// @Param        request  body      ToggleReactionRequest  true  "Reaction type"
+// @Success      200      {object}  utils.Response{data=object{reacted=bool}}
+// @Failure      400      {object}  utils.Response
+// @Failure      404      {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /articles/{id}/reactions [post]
+func ToggleArticleReaction(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid article id")
+		return
+	}
+	var req ToggleReactionRequest
+	if !utils.BindJSON(c, &req) {
+		return
+	}
+
+	reacted, err := service.ToggleArticleReaction(db.DB, middleware.CurrentUserID(c), uint(id), req.ReactionType)
+	if err != nil {
+		if err == service.ErrArticleNotFound {
+			utils.FailWithCode(c, 404, utils.ErrCodeArticleNotFound)
+			return
+		}
+		utils.Fail(c, 500, "failed to toggle reaction")
+		return
+	}
+
+	cache.Default.Invalidate(cache.KeyPopularArticles)
+	utils.OK(c, gin.H{"reacted": reacted})
+}
+
+// GetArticleReactions returns an article's per-type reaction counts plus
+// the caller's own reactions, or an empty "mine" list if unauthenticated.
+//
+// @Summary      Get an article's reactions
+// @Description  Returns an article's per-type reaction counts plus the caller's own reactions.
+// @Tags         articles
+// @Produce      json
+// @Param        id   path      int  true  "Article ID"
+// @Success      200  {object}  utils.Response{data=reactionsResponse}
+// @Failure      400  {object}  utils.Response
+// @Router       /articles/{id}/reactions [get]
+func GetArticleReactions(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid article id")
+		return
+	}
+
+	counts, mine, err := service.GetArticleReactions(db.DB, uint(id), middleware.CurrentUserID(c))
+	if err != nil {
+		utils.Fail(c, 500, "failed to load reactions")
+		return
+	}
+	utils.OK(c, reactionsResponse{Counts: counts, Mine: mine})
+}
+
+// ToggleArticleFavorite flips the caller's favorite on a published
+// article, keeping its favorites_count accurate. Unfavoriting an article
+// that wasn't favorited is a no-op, so the UI toggle can be optimistic.
+//
+// @Summary      Favorite or unfavorite an article
+// @Description  Toggles the caller's favorite on a published article.
+// @Tags         articles
+// @Produce      json
+// @Param        id   path      int  true  "Article ID"
+// @Success      200  {object}  utils.Response{data=object{favorited=bool}}
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /articles/{id}/favorite [post]
+func ToggleArticleFavorite(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid article id")
+		return
+	}
+
+	favorited, err := service.ToggleFavorite(db.DB, middleware.CurrentUserID(c), uint(id))
+	if err != nil {
+		if err == service.ErrArticleNotFound {
+			utils.FailWithCode(c, 404, utils.ErrCodeArticleNotFound)
+			return
+		}
+		utils.Fail(c, 500, "failed to toggle favorite")
+		return
+	}
+
+	utils.OK(c, gin.H{"favorited": favorited})
+}
+
+// GetArticleBySlug returns a single article by slug, with conditional
+// caching via ETag. Unpublished drafts 404 for anyone but their author
+// or an admin.
+//
+// @Summary      Get an article by slug
+// @Description  Returns a single article by its slug. Supports If-None-Match for 304 responses. Unpublished drafts 404 for anyone but their author or an admin.
+// @Tags         articles
+// @Produce      json
+// @Param        slug  path      string  true  "Article slug"
+// @Success      200   {object}  utils.Response{data=object{og_image_url=string,contributors=[]models.ArticleContributor,translations=[]service.TranslationInfo}}
+// @Failure      404   {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /articles/slug/{slug} [get]
+func GetArticleBySlug(c *gin.Context) {
+	slug := c.Param("slug")
+	var article models.Article
+	if err := db.DB.Where("slug = ?", slug).First(&article).Error; err != nil {
+		if newSlug, ok := service.ResolveSlugRedirect(db.DB, "article", slug); ok {
+			utils.SlugRedirect(c, "/api/articles/slug/"+newSlug, newSlug)
+			return
+		}
+		utils.RespondNotFoundOrForbidden(c, false, "article not found", "")
+		return
+	}
+	if !canViewArticle(c, article) {
+		utils.RespondNotFoundOrForbidden(c, false, "article not found", "")
+		return
+	}
+	article.Content = utils.ExpandContentURLs(article.Content, utils.ResolvePublicBase(c))
+	respondWithArticle(c, article)
+}
+
+// LinkArticleTranslationRequest is the payload for POST
+// /api/articles/:id/translations.
+type LinkArticleTranslationRequest struct {
+	ArticleID uint `json:"article_id" binding:"required"`
+}
+
+// LinkArticleTranslation marks the article at :id and ArticleID as
+// translations of each other, merging their translation groups.
+//
+// @Summary      Link an article translation
+// @Description  Marks the given article and :id as translations of each other.
+// @Tags         articles
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                            true  "Article ID"
+// @Param        request  body      LinkArticleTranslationRequest  true  "Target article"
+// @Success      200      {object}  utils.Response
+// @Failure      400      {object}  utils.Response
+// @Failure      404      {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /articles/{id}/translations [post]
+func LinkArticleTranslation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid article id")
+		return
+	}
+
+	var req LinkArticleTranslationRequest
+	if !utils.BindJSON(c, &req) {
+		return
+	}
+
+	if err := service.LinkTranslation(db.DB, uint(id), req.ArticleID); err != nil {
+		switch {
+		case err == service.ErrSameArticle:
+			utils.Fail(c, 400, err.Error())
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			utils.FailWithCode(c, 404, utils.ErrCodeArticleNotFound)
+		default:
+			utils.Fail(c, 500, "failed to link translation")
+		}
+		return
+	}
+
+	utils.OK(c, nil)
+}
+
+// SetArticlePinnedRequest is the payload for POST /api/articles/:id/pin.
+type SetArticlePinnedRequest struct {
+	Pinned bool `json:"pinned"`
+}
+
+// PinArticle pins or unpins an article on the home feed. At most three
+// articles may be pinned at once.
+//
+// @Summary      Pin or unpin an article
+// @Description  Pins or unpins an article on the home feed. At most three articles may be pinned at once.
+// @Tags         articles
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                      true  "Article ID"
+// @Param        request  body      SetArticlePinnedRequest  true  "Desired pinned state"
+// @Success      200      {object}  utils.Response{data=models.Article}
+// @Failure      400      {object}  utils.Response
+// @Failure      404      {object}  utils.Response
+// @Failure      409      {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /articles/{id}/pin [post]
+func PinArticle(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid article id")
+		return
+	}
+
+	var req SetArticlePinnedRequest
+	if !utils.BindJSON(c, &req) {
+		return
+	}
+
+	article, err := service.SetArticlePinned(db.DB, uint(id), req.Pinned)
+	if err != nil {
+		switch {
+		case err == service.ErrArticleNotFound:
+			utils.FailWithCode(c, 404, utils.ErrCodeArticleNotFound)
+		case err == service.ErrPinLimitReached:
+			utils.Fail(c, 409, err.Error())
+		default:
+			utils.Fail(c, 500, "failed to update pinned state")
+		}
+		return
+	}
+
+	cache.Default.Invalidate(cache.KeyHomeFeed)
+	utils.OK(c, article)
+}
+
+// SetArticleFeaturedRequest is the payload for POST /api/articles/:id/feature.
+type SetArticleFeaturedRequest struct {
+	Featured bool `json:"featured"`
+}
+
+// FeatureArticle adds or removes an article from the home feed's featured
+// carousel.
+//
+// @Summary      Feature or unfeature an article
+// @Description  Adds or removes an article from the home feed's featured carousel.
+// @Tags         articles
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                        true  "Article ID"
+// @Param        request  body      SetArticleFeaturedRequest  true  "Desired featured state"
+// @Success      200      {object}  utils.Response{data=models.Article}
+// @Failure      400      {object}  utils.Response
+// @Failure      404      {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /articles/{id}/feature [post]
+func FeatureArticle(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid article id")
+		return
+	}
+
+	var req SetArticleFeaturedRequest
+	if !utils.BindJSON(c, &req) {
+		return
+	}
+
+	article, err := service.SetArticleFeatured(db.DB, uint(id), req.Featured)
+	if err != nil {
+		if err == service.ErrArticleNotFound {
+			utils.FailWithCode(c, 404, utils.ErrCodeArticleNotFound)
+			return
+		}
+		utils.Fail(c, 500, "failed to update featured state")
+		return
+	}
+
+	cache.Default.Invalidate(cache.KeyHomeFeed)
+	utils.OK(c, article)
+}
+
+// GetHomeFeed returns the composed home feed: pinned articles, the
+// featured carousel, then the latest published articles. Cached under
+// cache.KeyHomeFeed since it changes only when an article is pinned,
+// featured, or published.
+//
+// @Summary      Get the home feed
+// @Description  Returns pinned articles, a featured carousel, and the latest published articles, cached until one of those changes.
+// @Tags         articles
+// @Produce      json
+// @Success      200  {object}  utils.Response{data=service.HomeFeed}
+// @Router       /home-feed [get]
+func GetHomeFeed(c *gin.Context) {
+	feed, err := cache.Default.GetOrCompute(cache.KeyHomeFeed, func() (any, error) {
+		return service.GetHomeFeed(db.WithCtx(c.Request.Context()))
+	})
+	if err != nil {
+		utils.FailFromDBError(c, err, "failed to load home feed")
+		return
+	}
+	utils.ConditionalJSON(c, feed)
+}
+
+// articleWithOGImage adds the computed og_image_url, ordered contributor
+// list, available translations, and per-type reaction counts to an
+// Article response. All are viewer-independent, so they're safe
+// alongside ConditionalJSON; the caller's own reactions are viewer-
+// dependent and so are only exposed via GetArticleReactions.
+type articleWithOGImage struct {
+	models.Article
+	OGImageURL      string                      `json:"og_image_url"`
+	Contributors    []models.ArticleContributor `json:"contributors"`
+	Translations    []service.TranslationInfo   `json:"translations"`
+	Reactions       service.ReactionCounts      `json:"reactions"`
+	LatestChangelog *models.ArticleChangelog    `json:"latest_changelog,omitempty"`
+}
+
+// seriesWithCounts adds membership counts to a Series response, split by
+// content type so the frontend can label a series as a podcast vs
+// article series.
+type seriesWithCounts struct {
+	models.Series
+	ArticlesCount  int64 `json:"articles_count"`
+	BlogsCount     int64 `json:"blogs_count"`
+	FollowersCount int64 `json:"followers_count"`
+}
+
+// GetSeriesList returns every Series with its article/blog counts, with
+// conditional caching via ETag.
+//
+// @Summary      List series
+// @Description  Returns every series, with article and blog counts. Supports If-None-Match for 304 responses.
+// @Tags         series
+// @Produce      json
+// @Success      200  {object}  utils.Response{data=[]seriesWithCounts}
+// @Router       /series [get]
+func GetSeriesList(c *gin.Context) {
+	qdb := db.WithCtx(c.Request.Context())
+	var series []models.Series
+	if err := qdb.Order("id asc").Find(&series).Error; err != nil {
+		utils.FailFromDBError(c, err, "failed to load series")
+		return
+	}
+
+	seriesIDs := make([]uint, len(series))
+	for i, s := range series {
+		seriesIDs[i] = s.ID
+	}
+	followerCounts, err := service.GetFollowerCounts(qdb, models.FollowEntitySeries, seriesIDs)
+	if err != nil {
+		utils.FailFromDBError(c, err, "failed to count series followers")
+		return
+	}
+
+	result := make([]seriesWithCounts, len(series))
+	for i, s := range series {
+		var articlesCount, blogsCount int64
+		if err := qdb.Model(&models.Article{}).Where("series_id = ?", s.ID).Count(&articlesCount).Error; err != nil {
+			utils.FailFromDBError(c, err, "failed to count series articles")
+			return
+		}
+		if err := qdb.Model(&models.Blog{}).Where("series_id = ?", s.ID).Count(&blogsCount).Error; err != nil {
+			utils.FailFromDBError(c, err, "failed to count series blogs")
+			return
+		}
+		result[i] = seriesWithCounts{Series: s, ArticlesCount: articlesCount, BlogsCount: blogsCount, FollowersCount: followerCounts[s.ID]}
+	}
+	utils.ConditionalJSON(c, result)
+}
+
+// ReorderSeriesRequest is the payload for PUT /api/series/:id/reorder.
+type ReorderSeriesRequest struct {
+	ArticleIDs []uint `json:"article_ids" binding:"required"`
+}
+
+// ReorderSeries reassigns sequential series_order values to a series'
+// articles. Callers must be an admin or the author of every article in
+// the series.
+//
+// @Summary      Reorder a series
+// @Description  Reassigns sequential series_order values to the given articles. Caller must be an admin or author of every article in the series.
+// @Tags         series
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                   true  "Series ID"
+// @Param        request  body      ReorderSeriesRequest  true  "New article order"
+// @Success      200      {object}  utils.Response{data=[]models.Article}
+// @Failure      400      {object}  utils.Response{details=[]utils.FieldError}
+// @Failure      403      {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /series/{id}/reorder [put]
+func ReorderSeries(c *gin.Context) {
+	seriesID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid series id")
+		return
+	}
+
+	var req ReorderSeriesRequest
+	if !utils.BindJSON(c, &req) {
+		return
+	}
+
+	if middleware.CurrentRole(c) != string(models.RoleAdmin) {
+		var articles []models.Article
+		if err := db.DB.Where("series_id = ?", seriesID).Find(&articles).Error; err != nil {
+			utils.Fail(c, 500, "failed to load series")
+			return
+		}
+		uid := middleware.CurrentUserID(c)
+		for _, a := range articles {
+			if a.AuthorID != uid {
+				utils.Fail(c, 403, "not an author of this series")
+				return
+			}
+		}
+	}
+
+	articles, err := service.ReorderSeries(db.DB, uint(seriesID), req.ArticleIDs)
+	if err != nil {
+		if err == service.ErrSeriesOrderMismatch {
+			utils.Fail(c, 400, err.Error())
+			return
+		}
+		utils.Fail(c, 500, "failed to reorder series")
+		return
+	}
+
+	utils.OK(c, articles)
+}
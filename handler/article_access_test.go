@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/1055373165/blog/config"
+	"github.com/1055373165/blog/models"
+)
+
+func TestGetArticleGatesContentByAccessLevel(t *testing.T) {
+	db := setupArticleVisibilityTestDB(t)
+	config.App = &config.Config{JWTSecret: "test-secret"}
+
+	author := models.User{Username: "author", Email: "author@example.com"}
+	db.Create(&author)
+	member := models.User{Username: "member", Email: "member@example.com"}
+	db.Create(&member)
+	supporter := models.User{Username: "supporter", Email: "supporter@example.com", Supporter: true}
+	db.Create(&supporter)
+	admin := models.User{Username: "admin", Email: "admin@example.com", Role: models.RoleAdmin}
+	db.Create(&admin)
+
+	article := models.Article{
+		AuthorID:    author.ID,
+		Title:       "Deep Dive",
+		Slug:        "deep-dive",
+		Content:     strings.Repeat("x", 400),
+		Status:      models.ArticleStatusPublished,
+		AccessLevel: models.ArticleAccessSupporters,
+	}
+	db.Create(&article)
+
+	r := articleVisibilityRouter()
+	path := "/api/articles/" + strconv.FormatUint(uint64(article.ID), 10)
+
+	cases := []struct {
+		name       string
+		header     string
+		wantLocked bool
+	}{
+		{"anonymous", "", true},
+		{"member", bearerToken(t, member.ID, string(models.RoleUser)), true},
+		{"supporter", bearerToken(t, supporter.ID, string(models.RoleUser)), false},
+		{"admin", bearerToken(t, admin.ID, string(models.RoleAdmin)), false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+			}
+
+			var resp struct {
+				Content       string `json:"content"`
+				Excerpt       string `json:"excerpt"`
+				ContentLocked bool   `json:"content_locked"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+
+			if resp.ContentLocked != tc.wantLocked {
+				t.Errorf("got content_locked=%v, want %v", resp.ContentLocked, tc.wantLocked)
+			}
+			if tc.wantLocked {
+				if resp.Content != "" {
+					t.Error("expected content to be omitted for a locked response")
+				}
+				if resp.Excerpt == "" || resp.Excerpt == article.Content {
+					t.Errorf("expected a truncated excerpt, got %q", resp.Excerpt)
+				}
+			} else if resp.Content != article.Content {
+				t.Errorf("expected full content for an unlocked caller, got %q", resp.Content)
+			}
+		})
+	}
+}
+
+func TestGetArticleAllowsPublicAccessLevelForAnyone(t *testing.T) {
+	db := setupArticleVisibilityTestDB(t)
+	config.App = &config.Config{JWTSecret: "test-secret"}
+
+	author := models.User{Username: "author2", Email: "author2@example.com"}
+	db.Create(&author)
+	article := models.Article{
+		AuthorID: author.ID, Title: "Open Post", Slug: "open-post",
+		Content: "hello world", Status: models.ArticleStatusPublished,
+		AccessLevel: models.ArticleAccessPublic,
+	}
+	db.Create(&article)
+
+	r := articleVisibilityRouter()
+	req := httptest.NewRequest(http.MethodGet, "/api/articles/"+strconv.FormatUint(uint64(article.ID), 10), nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "hello world") {
+		t.Errorf("expected full content for a public article, got %s", w.Body.String())
+	}
+}
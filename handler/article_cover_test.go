@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/1055373165/blog/cache"
+	"github.com/1055373165/blog/config"
+	blogdb "github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/middleware"
+	"github.com/1055373165/blog/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupCoverTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	conn, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	if err := conn.AutoMigrate(&models.Article{}, &models.User{}); err != nil {
+		t.Fatalf("failed to migrate test db: %v", err)
+	}
+	blogdb.DB = conn
+	cache.Default = cache.New(time.Minute)
+	return conn
+}
+
+func TestCreateArticleRejectsExternalCoverImageHotlink(t *testing.T) {
+	db := setupCoverTestDB(t)
+	config.App = &config.Config{JWTSecret: "test-secret", UploadDir: t.TempDir()}
+
+	author := models.User{Username: "author", Email: "author@example.com"}
+	db.Create(&author)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/articles", middleware.RequireAuth(), CreateArticle)
+
+	body := `{"title":"New","slug":"new-article","cover_image":"https://example.com/cat.png"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/articles", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", bearerToken(t, author.ID, string(models.RoleUser)))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an external cover_image hotlink, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestCreateArticleRecordsCoverDimensionsForUploadedFile(t *testing.T) {
+	db := setupCoverTestDB(t)
+	uploadDir := t.TempDir()
+	config.App = &config.Config{JWTSecret: "test-secret", UploadDir: uploadDir}
+
+	pngBytes := []byte{
+		0x89, 0x50, 0x4e, 0x47, 0x0d, 0x0a, 0x1a, 0x0a, 0x00, 0x00, 0x00, 0x0d, 0x49, 0x48, 0x44, 0x52,
+		0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00, 0x02, 0x08, 0x06, 0x00, 0x00, 0x00, 0x72, 0xb6, 0x0d,
+		0x24, 0x00, 0x00, 0x00, 0x17, 0x49, 0x44, 0x41, 0x54, 0x78, 0x9c, 0x62, 0xfa, 0xcf, 0xc0, 0xf0,
+		0x9f, 0x01, 0x0e, 0xfe, 0x33, 0xfc, 0x07, 0x04, 0x00, 0x00, 0xff, 0xff, 0x23, 0x13, 0x03, 0xff,
+		0x86, 0x1f, 0xa1, 0x88, 0x00, 0x00, 0x00, 0x00, 0x49, 0x45, 0x4e, 0x44, 0xae, 0x42, 0x60, 0x82,
+	}
+	if err := os.WriteFile(filepath.Join(uploadDir, "cover.png"), pngBytes, 0o644); err != nil {
+		t.Fatalf("failed to seed upload dir: %v", err)
+	}
+
+	author := models.User{Username: "author", Email: "author@example.com"}
+	db.Create(&author)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/articles", middleware.RequireAuth(), CreateArticle)
+
+	body := `{"title":"New","slug":"new-article","cover_image":"/api/files/cover.png","cover_focal_point":{"x":0.25,"y":0.75}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/articles", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", bearerToken(t, author.ID, string(models.RoleUser)))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 for a valid cover_image, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var article models.Article
+	if err := db.Where("slug = ?", "new-article").First(&article).Error; err != nil {
+		t.Fatalf("failed to load created article: %v", err)
+	}
+	if article.CoverWidth != 2 || article.CoverHeight != 2 {
+		t.Errorf("expected cover dimensions 2x2, got %dx%d", article.CoverWidth, article.CoverHeight)
+	}
+	if article.CoverFocalX != 0.25 || article.CoverFocalY != 0.75 {
+		t.Errorf("expected focal point (0.25, 0.75), got (%v, %v)", article.CoverFocalX, article.CoverFocalY)
+	}
+}
+
+func TestGetArticleCoverServesRequestedDimensions(t *testing.T) {
+	db := setupCoverTestDB(t)
+	uploadDir := t.TempDir()
+	config.App = &config.Config{UploadDir: uploadDir}
+
+	pngBytes := []byte{
+		0x89, 0x50, 0x4e, 0x47, 0x0d, 0x0a, 0x1a, 0x0a, 0x00, 0x00, 0x00, 0x0d, 0x49, 0x48, 0x44, 0x52,
+		0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00, 0x02, 0x08, 0x06, 0x00, 0x00, 0x00, 0x72, 0xb6, 0x0d,
+		0x24, 0x00, 0x00, 0x00, 0x17, 0x49, 0x44, 0x41, 0x54, 0x78, 0x9c, 0x62, 0xfa, 0xcf, 0xc0, 0xf0,
+		0x9f, 0x01, 0x0e, 0xfe, 0x33, 0xfc, 0x07, 0x04, 0x00, 0x00, 0xff, 0xff, 0x23, 0x13, 0x03, 0xff,
+		0x86, 0x1f, 0xa1, 0x88, 0x00, 0x00, 0x00, 0x00, 0x49, 0x45, 0x4e, 0x44, 0xae, 0x42, 0x60, 0x82,
+	}
+	if err := os.WriteFile(filepath.Join(uploadDir, "cover.png"), pngBytes, 0o644); err != nil {
+		t.Fatalf("failed to seed upload dir: %v", err)
+	}
+
+	author := models.User{Username: "author", Email: "author@example.com"}
+	db.Create(&author)
+	article := models.Article{
+		AuthorID: author.ID, Title: "Covered", Slug: "covered",
+		CoverImage: "/api/files/cover.png", CoverFocalX: 0.5, CoverFocalY: 0.5,
+	}
+	db.Create(&article)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/api/articles/:id/cover", GetArticleCover)
+
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/articles/%d/cover?w=40&h=20", article.ID), nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "image/png" {
+		t.Errorf("expected Content-Type image/png, got %q", ct)
+	}
+}
+
+func TestGetArticleCoverReturns404WithoutCoverImage(t *testing.T) {
+	db := setupCoverTestDB(t)
+	config.App = &config.Config{UploadDir: t.TempDir()}
+
+	author := models.User{Username: "author", Email: "author@example.com"}
+	db.Create(&author)
+	article := models.Article{AuthorID: author.ID, Title: "No Cover", Slug: "no-cover"}
+	db.Create(&article)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/api/articles/:id/cover", GetArticleCover)
+
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/articles/%d/cover", article.ID), nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for an article with no cover image, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/1055373165/blog/cache"
+	"github.com/1055373165/blog/config"
+	blogdb "github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/middleware"
+	"github.com/1055373165/blog/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestCreateArticleRejectsDuplicateSlugWithConflictInsteadOf500(t *testing.T) {
+	conn, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	if err := conn.AutoMigrate(&models.Article{}, &models.User{}); err != nil {
+		t.Fatalf("failed to migrate test db: %v", err)
+	}
+	blogdb.DB = conn
+	db := conn
+	config.App = &config.Config{JWTSecret: "test-secret"}
+
+	author := models.User{Username: "author", Email: "author@example.com"}
+	db.Create(&author)
+	db.Create(&models.Article{AuthorID: author.ID, Title: "Existing", Slug: "dup-slug"})
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/articles", middleware.RequireAuth(), CreateArticle)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/articles", strings.NewReader(`{"title":"New","slug":"dup-slug"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", bearerToken(t, author.ID, string(models.RoleUser)))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for a duplicate slug, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func setupArticleCreateTestDB(t *testing.T) (*gorm.DB, models.User) {
+	t.Helper()
+	conn, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	if err := conn.AutoMigrate(&models.Article{}, &models.User{}, &models.ArticleContributor{}, &models.Reaction{}); err != nil {
+		t.Fatalf("failed to migrate test db: %v", err)
+	}
+	blogdb.DB = conn
+	config.App = &config.Config{JWTSecret: "test-secret"}
+	cache.Init(time.Minute)
+
+	author := models.User{Username: "author", Email: "author@example.com"}
+	conn.Create(&author)
+	return conn, author
+}
+
+func articleCreateRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/articles", middleware.RequireAuth(), CreateArticle)
+	return r
+}
+
+func TestCreateArticleAutoGeneratesExcerptFromMarkdown(t *testing.T) {
+	db, author := setupArticleCreateTestDB(t)
+	r := articleCreateRouter()
+
+	body := `{"title":"New","slug":"new","content":"# Heading\n\nSome **bold** body text."}`
+	req := httptest.NewRequest(http.MethodPost, "/api/articles", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", bearerToken(t, author.ID, string(models.RoleUser)))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var created models.Article
+	db.First(&created, "slug = ?", "new")
+	if created.Excerpt != "Heading Some bold body text." {
+		t.Errorf("got excerpt %q", created.Excerpt)
+	}
+	if !created.ExcerptAuto {
+		t.Error("expected ExcerptAuto to be true for an auto-generated excerpt")
+	}
+}
+
+func TestCreateArticlePreservesHandWrittenExcerpt(t *testing.T) {
+	db, author := setupArticleCreateTestDB(t)
+	r := articleCreateRouter()
+
+	body := `{"title":"New","slug":"new","content":"# Heading\n\nBody.","excerpt":"A hand-written teaser."}`
+	req := httptest.NewRequest(http.MethodPost, "/api/articles", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", bearerToken(t, author.ID, string(models.RoleUser)))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var created models.Article
+	db.First(&created, "slug = ?", "new")
+	if created.Excerpt != "A hand-written teaser." {
+		t.Errorf("got excerpt %q, want hand-written excerpt preserved", created.Excerpt)
+	}
+	if created.ExcerptAuto {
+		t.Error("expected ExcerptAuto to be false when the request supplies its own excerpt")
+	}
+}
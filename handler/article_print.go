@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/1055373165/blog/config"
+	"github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/models"
+	"github.com/1055373165/blog/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// printCacheDir is where rendered print-view HTML is cached, under the
+// configured upload directory, mirroring ogImageCacheDir.
+const printCacheDir = "print-cache"
+
+// printPageTemplate renders a minimal, standalone, print-friendly HTML
+// document. There is no Markdown-to-HTML renderer anywhere in this
+// tree (see the export package doc comment and README "Known gaps"),
+// so Content is embedded verbatim inside a <pre> block rather than
+// rendered, the same tradeoff export.runExport already made.
+var printPageTemplate = template.Must(template.New("print").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+  body { font-family: Georgia, serif; max-width: 40rem; margin: 2rem auto; color: #111; }
+  h1 { font-size: 1.8rem; margin-bottom: 0.2rem; }
+  .meta { color: #555; font-size: 0.9rem; margin-bottom: 1.5rem; }
+  pre { white-space: pre-wrap; word-wrap: break-word; font-family: inherit; font-size: 1rem; line-height: 1.6; }
+  .draft-watermark { color: #b00; border: 1px solid #b00; display: inline-block; padding: 0.1rem 0.5rem; font-weight: bold; margin-bottom: 1rem; }
+  footer { margin-top: 2rem; padding-top: 1rem; border-top: 1px solid #ccc; color: #777; font-size: 0.85rem; }
+  @media print {
+    body { margin: 0; max-width: none; }
+    footer { position: fixed; bottom: 0; }
+  }
+</style>
+</head>
+<body>
+{{if .IsDraft}}<div class="draft-watermark">DRAFT</div>{{end}}
+<h1>{{.Title}}</h1>
+<div class="meta">By {{.AuthorName}} &middot; {{.Date}}</div>
+<pre>{{.Content}}</pre>
+<footer>{{.CanonicalURL}}</footer>
+</body>
+</html>
+`))
+
+type printPageData struct {
+	Title        string
+	AuthorName   string
+	Date         string
+	Content      string
+	CanonicalURL string
+	IsDraft      bool
+}
+
+// printCachePath returns where article's rendered print-view HTML is
+// (or would be) cached. It's keyed by ID and updated_at, so an edit
+// invalidates the cache by simply changing the path - the same scheme
+// ogImageCachePath uses for social cards. Draft renders (the
+// author/admin-only "DRAFT" watermark variant) are never cached,
+// since they're only ever requested by the one person who can see
+// them and caching them would risk leaking a draft's cached path to
+// a later anonymous request for the same, still-unpublished, article.
+func printCachePath(article models.Article) string {
+	name := fmt.Sprintf("%d-%d.html", article.ID, article.UpdatedAt.UnixNano())
+	return filepath.Join(config.App.UploadDir, printCacheDir, name)
+}
+
+// GetArticlePrintView server-renders a minimal standalone HTML
+// document for an article - title, author, date, content, and a
+// canonical-URL footer - suited for printing or archival scraping
+// without the SPA chrome. It is registered outside /api since it's a
+// document response, not a JSON API call.
+//
+// Unpublished or expired articles 404 for anyone but their author or
+// an admin, who instead see the same page with a "DRAFT" watermark.
+// Published renders are cached to disk keyed by article ID and
+// updated_at; draft renders are always generated fresh.
+func GetArticlePrintView(c *gin.Context) {
+	slug := c.Param("slug")
+	var article models.Article
+	if err := db.DB.Where("slug = ?", slug).First(&article).Error; err != nil {
+		c.String(http.StatusNotFound, "article not found")
+		return
+	}
+	if !canViewArticle(c, article) {
+		c.String(http.StatusNotFound, "article not found")
+		return
+	}
+
+	isDraft := article.Status != models.ArticleStatusPublished
+	if !isDraft {
+		if data, err := os.ReadFile(printCachePath(article)); err == nil {
+			c.Data(http.StatusOK, "text/html; charset=utf-8", data)
+			return
+		}
+	}
+
+	html, err := renderArticlePrintPage(c, article, isDraft)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "failed to render article")
+		return
+	}
+
+	if !isDraft {
+		path := printCachePath(article)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err == nil {
+			_ = os.WriteFile(path, html, 0o644)
+		}
+	}
+
+	c.Data(http.StatusOK, "text/html; charset=utf-8", html)
+}
+
+func renderArticlePrintPage(c *gin.Context, article models.Article, isDraft bool) ([]byte, error) {
+	var author models.User
+	authorName := "Unknown"
+	if err := db.DB.First(&author, article.AuthorID).Error; err == nil {
+		authorName = author.Username
+	}
+
+	date := article.CreatedAt
+	if article.PublishedAt != nil {
+		date = *article.PublishedAt
+	}
+
+	baseURL := utils.ResolvePublicBase(c)
+	data := printPageData{
+		Title:        article.Title,
+		AuthorName:   authorName,
+		Date:         date.Format("2006-01-02"),
+		Content:      utils.ExpandContentURLs(article.Content, baseURL),
+		CanonicalURL: baseURL + "/articles/" + article.Slug + "/",
+		IsDraft:      isDraft,
+	}
+
+	var buf strings.Builder
+	if err := printPageTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return []byte(buf.String()), nil
+}
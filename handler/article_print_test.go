@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/1055373165/blog/config"
+	blogdb "github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/middleware"
+	"github.com/1055373165/blog/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupArticlePrintTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	conn, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	if err := conn.AutoMigrate(&models.Article{}, &models.User{}); err != nil {
+		t.Fatalf("failed to migrate test db: %v", err)
+	}
+	blogdb.DB = conn
+	config.App = &config.Config{UploadDir: t.TempDir()}
+	return conn
+}
+
+func articlePrintRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/articles/:slug/print", middleware.OptionalAuth(), GetArticlePrintView)
+	return r
+}
+
+func TestGetArticlePrintViewRendersContentWithAbsoluteImageURLsAndNoScripts(t *testing.T) {
+	db := setupArticlePrintTestDB(t)
+
+	author := models.User{Username: "jane", Email: "jane@example.com"}
+	db.Create(&author)
+	article := models.Article{
+		AuthorID: author.ID,
+		Title:    "Hello World",
+		Slug:     "hello",
+		Content:  "See ![cover](/api/files/cover.png) for details.",
+		Status:   models.ArticleStatusPublished,
+	}
+	db.Create(&article)
+
+	r := articlePrintRouter()
+	req := httptest.NewRequest(http.MethodGet, "/articles/hello/print", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	body := w.Body.String()
+	if strings.Contains(strings.ToLower(body), "<script") {
+		t.Errorf("expected no script tags in print view, got %s", body)
+	}
+	if !strings.Contains(body, "http://example.com/api/files/cover.png") {
+		t.Errorf("expected the image reference rewritten to an absolute URL, got %s", body)
+	}
+	if strings.Contains(body, "DRAFT") {
+		t.Errorf("expected no draft watermark on a published article, got %s", body)
+	}
+}
+
+func TestGetArticlePrintViewCachesPublishedRenderToDisk(t *testing.T) {
+	db := setupArticlePrintTestDB(t)
+
+	author := models.User{Username: "jane", Email: "jane@example.com"}
+	db.Create(&author)
+	article := models.Article{AuthorID: author.ID, Title: "Hello", Slug: "hello", Content: "body", Status: models.ArticleStatusPublished}
+	db.Create(&article)
+
+	r := articlePrintRouter()
+	path := printCachePath(article)
+	if _, err := os.Stat(path); err == nil {
+		t.Fatal("expected no cached render before the first request")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/articles/hello/print", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("expected render to be cached at %q: %v", path, err)
+	}
+}
+
+func TestGetArticlePrintViewHidesUnpublishedDraftFromAnonymous(t *testing.T) {
+	db := setupArticlePrintTestDB(t)
+
+	author := models.User{Username: "jane", Email: "jane@example.com"}
+	db.Create(&author)
+	draft := models.Article{AuthorID: author.ID, Title: "WIP", Slug: "wip", Content: "body", Status: models.ArticleStatusDraft}
+	db.Create(&draft)
+
+	r := articlePrintRouter()
+	req := httptest.NewRequest(http.MethodGet, "/articles/wip/print", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestGetArticlePrintViewShowsDraftWatermarkToAuthor(t *testing.T) {
+	db := setupArticlePrintTestDB(t)
+	config.App.JWTSecret = "test-secret"
+
+	author := models.User{Username: "jane", Email: "jane@example.com"}
+	db.Create(&author)
+	draft := models.Article{AuthorID: author.ID, Title: "WIP", Slug: "wip", Content: "body", Status: models.ArticleStatusDraft}
+	db.Create(&draft)
+
+	r := articlePrintRouter()
+	req := httptest.NewRequest(http.MethodGet, "/articles/wip/print", nil)
+	req.Header.Set("Authorization", bearerToken(t, author.ID, string(models.RoleUser)))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "DRAFT") {
+		t.Errorf("expected a DRAFT watermark for the author, got %s", w.Body.String())
+	}
+}
@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/1055373165/blog/config"
+	blogdb "github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/middleware"
+	"github.com/1055373165/blog/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupArticleUpdateTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	conn, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	if err := conn.AutoMigrate(&models.Article{}, &models.User{}, &models.ArticleChangelog{}, &models.SlugRedirect{}); err != nil {
+		t.Fatalf("failed to migrate test db: %v", err)
+	}
+	blogdb.DB = conn
+	return conn
+}
+
+func articleUpdateRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.PUT("/api/articles/:id", middleware.RequireAuth(), UpdateArticle)
+	r.GET("/api/articles/:id/changelog", middleware.OptionalAuth(), GetArticleChangelog)
+	return r
+}
+
+func TestUpdateArticleWithChangelogSummaryRecordsAChangelogEntry(t *testing.T) {
+	db := setupArticleUpdateTestDB(t)
+	config.App = &config.Config{JWTSecret: "test-secret"}
+
+	author := models.User{Username: "author", Email: "author@example.com"}
+	db.Create(&author)
+	article := models.Article{AuthorID: author.ID, Title: "original", Slug: "original", Status: models.ArticleStatusPublished}
+	db.Create(&article)
+
+	r := articleUpdateRouter()
+	path := "/api/articles/" + strconv.FormatUint(uint64(article.ID), 10)
+
+	body := `{"title":"revised","changelog_summary":"fixed benchmark numbers"}`
+	req := httptest.NewRequest(http.MethodPut, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", bearerToken(t, author.ID, string(models.RoleUser)))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"title":"revised"`) {
+		t.Errorf("expected updated title in response, got %s", w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"updated_significantly_at"`) {
+		t.Errorf("expected updated_significantly_at to be set, got %s", w.Body.String())
+	}
+
+	var entries []models.ArticleChangelog
+	db.Where("article_id = ?", article.ID).Find(&entries)
+	if len(entries) != 1 || entries[0].Summary != "fixed benchmark numbers" {
+		t.Errorf("expected one changelog entry recording the summary, got %+v", entries)
+	}
+}
+
+func TestUpdateArticleWithoutChangelogSummaryRecordsNoEntry(t *testing.T) {
+	db := setupArticleUpdateTestDB(t)
+	config.App = &config.Config{JWTSecret: "test-secret"}
+
+	author := models.User{Username: "author", Email: "author@example.com"}
+	db.Create(&author)
+	article := models.Article{AuthorID: author.ID, Title: "original", Slug: "original", Status: models.ArticleStatusPublished}
+	db.Create(&article)
+
+	r := articleUpdateRouter()
+	path := "/api/articles/" + strconv.FormatUint(uint64(article.ID), 10)
+
+	body := `{"title":"minor typo fix"}`
+	req := httptest.NewRequest(http.MethodPut, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", bearerToken(t, author.ID, string(models.RoleUser)))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var count int64
+	db.Model(&models.ArticleChangelog{}).Where("article_id = ?", article.ID).Count(&count)
+	if count != 0 {
+		t.Errorf("expected no changelog entry for a routine edit, got %d", count)
+	}
+}
+
+func TestUpdateArticleRejectsNonAuthorNonAdmin(t *testing.T) {
+	db := setupArticleUpdateTestDB(t)
+	config.App = &config.Config{JWTSecret: "test-secret"}
+
+	author := models.User{Username: "author", Email: "author@example.com"}
+	db.Create(&author)
+	other := models.User{Username: "other", Email: "other@example.com"}
+	db.Create(&other)
+	article := models.Article{AuthorID: author.ID, Title: "original", Slug: "original", Status: models.ArticleStatusPublished}
+	db.Create(&article)
+
+	r := articleUpdateRouter()
+	path := "/api/articles/" + strconv.FormatUint(uint64(article.ID), 10)
+
+	body := `{"title":"hijacked"}`
+	req := httptest.NewRequest(http.MethodPut, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", bearerToken(t, other.ID, string(models.RoleUser)))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != 403 {
+		t.Fatalf("expected 403, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestUpdateArticleRejectsDuplicateSlugWithConflict(t *testing.T) {
+	db := setupArticleUpdateTestDB(t)
+	config.App = &config.Config{JWTSecret: "test-secret"}
+
+	author := models.User{Username: "author", Email: "author@example.com"}
+	db.Create(&author)
+	db.Create(&models.Article{AuthorID: author.ID, Title: "existing", Slug: "taken", Status: models.ArticleStatusPublished})
+	article := models.Article{AuthorID: author.ID, Title: "mine", Slug: "mine", Status: models.ArticleStatusPublished}
+	db.Create(&article)
+
+	r := articleUpdateRouter()
+	path := "/api/articles/" + strconv.FormatUint(uint64(article.ID), 10)
+
+	body := `{"slug":"taken"}`
+	req := httptest.NewRequest(http.MethodPut, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", bearerToken(t, author.ID, string(models.RoleUser)))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for a duplicate slug, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestGetArticleChangelogReturnsEntriesNewestFirst(t *testing.T) {
+	db := setupArticleUpdateTestDB(t)
+	config.App = &config.Config{JWTSecret: "test-secret"}
+
+	author := models.User{Username: "author", Email: "author@example.com"}
+	db.Create(&author)
+	article := models.Article{AuthorID: author.ID, Title: "original", Slug: "original", Status: models.ArticleStatusPublished}
+	db.Create(&article)
+
+	r := articleUpdateRouter()
+	updatePath := "/api/articles/" + strconv.FormatUint(uint64(article.ID), 10)
+
+	for _, summary := range []string{"first fix", "second fix"} {
+		body := `{"changelog_summary":"` + summary + `"}`
+		req := httptest.NewRequest(http.MethodPut, updatePath, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", bearerToken(t, author.ID, string(models.RoleUser)))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != 200 {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, updatePath+"/changelog", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	firstIdx := strings.Index(w.Body.String(), "first fix")
+	secondIdx := strings.Index(w.Body.String(), "second fix")
+	if firstIdx == -1 || secondIdx == -1 || secondIdx > firstIdx {
+		t.Errorf("expected the newest entry first, got %s", w.Body.String())
+	}
+}
+
+func TestGetArticleChangelogHidesUnpublishedDraftFromOtherUsers(t *testing.T) {
+	db := setupArticleUpdateTestDB(t)
+	config.App = &config.Config{JWTSecret: "test-secret"}
+
+	author := models.User{Username: "author", Email: "author@example.com"}
+	db.Create(&author)
+	draft := models.Article{AuthorID: author.ID, Title: "wip", Slug: "wip", Status: models.ArticleStatusDraft}
+	db.Create(&draft)
+
+	r := articleUpdateRouter()
+	req := httptest.NewRequest(http.MethodGet, "/api/articles/"+strconv.FormatUint(uint64(draft.ID), 10)+"/changelog", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != 404 {
+		t.Fatalf("expected 404, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,236 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/1055373165/blog/config"
+	"github.com/1055373165/blog/middleware"
+	"github.com/1055373165/blog/models"
+	"github.com/gin-gonic/gin"
+)
+
+func articleViewRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/api/articles/:id", middleware.OptionalAuth(), GetArticle)
+	r.GET("/api/articles/:id/stats", middleware.OptionalAuth(), GetArticleStats)
+	r.POST("/api/articles/:id/view", middleware.OptionalAuth(), RecordArticleView)
+	return r
+}
+
+func TestGetArticleNoLongerIncrementsViewsOnRead(t *testing.T) {
+	db := setupArticleVisibilityTestDB(t)
+	config.App = &config.Config{JWTSecret: "test-secret"}
+
+	author := models.User{Username: "author", Email: "author@example.com"}
+	db.Create(&author)
+	article := models.Article{AuthorID: author.ID, Title: "live", Slug: "live", Status: models.ArticleStatusPublished, Views: 5}
+	db.Create(&article)
+
+	r := articleViewRouter()
+	path := "/api/articles/" + strconv.FormatUint(uint64(article.ID), 10)
+
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", bearerToken(t, author.ID, string(models.RoleUser)))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != 200 {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+
+	var got models.Article
+	db.First(&got, article.ID)
+	if got.Views != 5 {
+		t.Errorf("expected views to stay 5 after reads, got %d", got.Views)
+	}
+}
+
+func TestRecordArticleViewIncrementsForOtherAuthenticatedUsers(t *testing.T) {
+	db := setupArticleVisibilityTestDB(t)
+	config.App = &config.Config{JWTSecret: "test-secret"}
+
+	author := models.User{Username: "author", Email: "author@example.com"}
+	db.Create(&author)
+	reader := models.User{Username: "reader", Email: "reader@example.com"}
+	db.Create(&reader)
+	article := models.Article{AuthorID: author.ID, Title: "live", Slug: "live", Status: models.ArticleStatusPublished}
+	db.Create(&article)
+
+	r := articleViewRouter()
+	path := "/api/articles/" + strconv.FormatUint(uint64(article.ID), 10) + "/view"
+
+	req := httptest.NewRequest(http.MethodPost, path, nil)
+	req.Header.Set("Authorization", bearerToken(t, reader.ID, string(models.RoleUser)))
+	req.Header.Set("Origin", "http://example.com")
+	req.Host = "example.com"
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var got models.Article
+	db.First(&got, article.ID)
+	if got.Views != 1 {
+		t.Errorf("expected views to be 1, got %d", got.Views)
+	}
+}
+
+func TestRecordArticleViewSkipsTheArticlesOwnAuthor(t *testing.T) {
+	db := setupArticleVisibilityTestDB(t)
+	config.App = &config.Config{JWTSecret: "test-secret"}
+
+	author := models.User{Username: "author", Email: "author@example.com"}
+	db.Create(&author)
+	article := models.Article{AuthorID: author.ID, Title: "live", Slug: "live", Status: models.ArticleStatusPublished}
+	db.Create(&article)
+
+	r := articleViewRouter()
+	path := "/api/articles/" + strconv.FormatUint(uint64(article.ID), 10) + "/view"
+
+	req := httptest.NewRequest(http.MethodPost, path, nil)
+	req.Header.Set("Authorization", bearerToken(t, author.ID, string(models.RoleUser)))
+	req.Header.Set("Origin", "http://example.com")
+	req.Host = "example.com"
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var got models.Article
+	db.First(&got, article.ID)
+	if got.Views != 0 {
+		t.Errorf("expected the author's own view not to be recorded, got %d", got.Views)
+	}
+}
+
+func TestRecordArticleViewRejectsCrossOriginRequest(t *testing.T) {
+	db := setupArticleVisibilityTestDB(t)
+	config.App = &config.Config{JWTSecret: "test-secret"}
+
+	author := models.User{Username: "author", Email: "author@example.com"}
+	db.Create(&author)
+	reader := models.User{Username: "reader", Email: "reader@example.com"}
+	db.Create(&reader)
+	article := models.Article{AuthorID: author.ID, Title: "live", Slug: "live", Status: models.ArticleStatusPublished}
+	db.Create(&article)
+
+	r := articleViewRouter()
+	path := "/api/articles/" + strconv.FormatUint(uint64(article.ID), 10) + "/view"
+
+	req := httptest.NewRequest(http.MethodPost, path, nil)
+	req.Header.Set("Authorization", bearerToken(t, reader.ID, string(models.RoleUser)))
+	req.Header.Set("Origin", "http://evil.example")
+	req.Host = "example.com"
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != 403 {
+		t.Fatalf("expected 403, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var got models.Article
+	db.First(&got, article.ID)
+	if got.Views != 0 {
+		t.Errorf("expected the cross-origin request not to record a view, got %d", got.Views)
+	}
+}
+
+func TestGetArticleStatsReturnsCountersWithoutContent(t *testing.T) {
+	db := setupArticleVisibilityTestDB(t)
+	config.App = &config.Config{JWTSecret: "test-secret"}
+
+	author := models.User{Username: "author", Email: "author@example.com"}
+	db.Create(&author)
+	article := models.Article{AuthorID: author.ID, Title: "live", Slug: "live", Content: "secret content", Status: models.ArticleStatusPublished, Views: 42, Likes: 7}
+	db.Create(&article)
+
+	r := articleViewRouter()
+	path := "/api/articles/" + strconv.FormatUint(uint64(article.ID), 10) + "/stats"
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if body := w.Body.String(); !contains(body, `"views":42`) || !contains(body, `"likes":7`) {
+		t.Errorf("expected body to carry views/likes counters, got %s", body)
+	}
+	if contains(w.Body.String(), "secret content") {
+		t.Errorf("expected stats response not to include article content, got %s", w.Body.String())
+	}
+}
+
+func TestGetArticleStatsHidesUnpublishedDraftFromOtherUsers(t *testing.T) {
+	db := setupArticleVisibilityTestDB(t)
+	config.App = &config.Config{JWTSecret: "test-secret"}
+
+	author := models.User{Username: "author", Email: "author@example.com"}
+	db.Create(&author)
+	draft := models.Article{AuthorID: author.ID, Title: "wip", Slug: "wip", Status: models.ArticleStatusDraft}
+	db.Create(&draft)
+
+	r := articleViewRouter()
+	path := "/api/articles/" + strconv.FormatUint(uint64(draft.ID), 10) + "/stats"
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != 404 {
+		t.Fatalf("expected 404, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestGetArticleStatsAllowsAuthorAndAdminToSeeOwnDraft(t *testing.T) {
+	db := setupArticleVisibilityTestDB(t)
+	config.App = &config.Config{JWTSecret: "test-secret"}
+
+	author := models.User{Username: "author", Email: "author@example.com"}
+	db.Create(&author)
+	admin := models.User{Username: "admin", Email: "admin@example.com", Role: models.RoleAdmin}
+	db.Create(&admin)
+	draft := models.Article{AuthorID: author.ID, Title: "wip", Slug: "wip", Status: models.ArticleStatusDraft, Views: 3, Likes: 1}
+	db.Create(&draft)
+
+	r := articleViewRouter()
+	path := "/api/articles/" + strconv.FormatUint(uint64(draft.ID), 10) + "/stats"
+
+	cases := []struct {
+		name   string
+		header string
+	}{
+		{"owner", bearerToken(t, author.ID, string(models.RoleUser))},
+		{"admin", bearerToken(t, admin.ID, string(models.RoleAdmin))},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			req.Header.Set("Authorization", tc.header)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != 200 {
+				t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+			}
+			if !contains(w.Body.String(), `"views":3`) {
+				t.Errorf("expected own draft's stats to be visible, got %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func contains(haystack, needle string) bool {
+	return len(haystack) >= len(needle) && (func() bool {
+		for i := 0; i+len(needle) <= len(haystack); i++ {
+			if haystack[i:i+len(needle)] == needle {
+				return true
+			}
+		}
+		return false
+	})()
+}
@@ -0,0 +1,218 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/1055373165/blog/config"
+	blogdb "github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/middleware"
+	"github.com/1055373165/blog/models"
+	"github.com/1055373165/blog/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupArticleVisibilityTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	conn, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	if err := conn.AutoMigrate(&models.Article{}, &models.User{}); err != nil {
+		t.Fatalf("failed to migrate test db: %v", err)
+	}
+	blogdb.DB = conn
+	return conn
+}
+
+func articleVisibilityRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/api/articles/:id", middleware.OptionalAuth(), GetArticle)
+	r.GET("/api/articles/slug/:slug", middleware.OptionalAuth(), GetArticleBySlug)
+	return r
+}
+
+func bearerToken(t *testing.T, userID uint, role string) string {
+	t.Helper()
+	token, err := utils.GenerateToken(userID, role)
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+	return "Bearer " + token
+}
+
+func TestGetArticleHidesUnpublishedDraftFromAnonymousAndOtherUsers(t *testing.T) {
+	db := setupArticleVisibilityTestDB(t)
+	config.App = &config.Config{JWTSecret: "test-secret"}
+
+	author := models.User{Username: "author", Email: "author@example.com"}
+	db.Create(&author)
+	other := models.User{Username: "other", Email: "other@example.com"}
+	db.Create(&other)
+	admin := models.User{Username: "admin", Email: "admin@example.com", Role: models.RoleAdmin}
+	db.Create(&admin)
+
+	draft := models.Article{AuthorID: author.ID, Title: "wip", Slug: "wip", Status: models.ArticleStatusDraft}
+	db.Create(&draft)
+
+	r := articleVisibilityRouter()
+	path := "/api/articles/" + strconv.FormatUint(uint64(draft.ID), 10)
+
+	cases := []struct {
+		name   string
+		header string
+		want   int
+	}{
+		{"anonymous", "", 404},
+		{"owner", bearerToken(t, author.ID, string(models.RoleUser)), 200},
+		{"other user", bearerToken(t, other.ID, string(models.RoleUser)), 404},
+		{"admin", bearerToken(t, admin.ID, string(models.RoleAdmin)), 200},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tc.want {
+				t.Errorf("expected %d, got %d: %s", tc.want, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetArticleBySlugHidesUnpublishedDraftFromAnonymousAndOtherUsers(t *testing.T) {
+	db := setupArticleVisibilityTestDB(t)
+	config.App = &config.Config{JWTSecret: "test-secret"}
+
+	author := models.User{Username: "author", Email: "author@example.com"}
+	db.Create(&author)
+	other := models.User{Username: "other", Email: "other@example.com"}
+	db.Create(&other)
+	admin := models.User{Username: "admin", Email: "admin@example.com", Role: models.RoleAdmin}
+	db.Create(&admin)
+
+	draft := models.Article{AuthorID: author.ID, Title: "wip", Slug: "wip-slug", Status: models.ArticleStatusDraft}
+	db.Create(&draft)
+
+	r := articleVisibilityRouter()
+	path := "/api/articles/slug/wip-slug"
+
+	cases := []struct {
+		name   string
+		header string
+		want   int
+	}{
+		{"anonymous", "", 404},
+		{"owner", bearerToken(t, author.ID, string(models.RoleUser)), 200},
+		{"other user", bearerToken(t, other.ID, string(models.RoleUser)), 404},
+		{"admin", bearerToken(t, admin.ID, string(models.RoleAdmin)), 200},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tc.want {
+				t.Errorf("expected %d, got %d: %s", tc.want, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetArticleAllowsAnonymousAccessToPublished(t *testing.T) {
+	db := setupArticleVisibilityTestDB(t)
+	config.App = &config.Config{JWTSecret: "test-secret"}
+
+	author := models.User{Username: "author", Email: "author@example.com"}
+	db.Create(&author)
+	published := models.Article{AuthorID: author.ID, Title: "live", Slug: "live", Status: models.ArticleStatusPublished}
+	db.Create(&published)
+
+	r := articleVisibilityRouter()
+	req := httptest.NewRequest(http.MethodGet, "/api/articles/"+strconv.FormatUint(uint64(published.ID), 10), nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected 200 for a published article, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestGetArticleHidesExpiredArticleFromAnonymousAndOtherUsers(t *testing.T) {
+	db := setupArticleVisibilityTestDB(t)
+	config.App = &config.Config{JWTSecret: "test-secret"}
+
+	author := models.User{Username: "author", Email: "author@example.com"}
+	db.Create(&author)
+	other := models.User{Username: "other", Email: "other@example.com"}
+	db.Create(&other)
+	admin := models.User{Username: "admin", Email: "admin@example.com", Role: models.RoleAdmin}
+	db.Create(&admin)
+
+	past := time.Now().Add(-time.Hour)
+	expired := models.Article{
+		AuthorID: author.ID, Title: "gone", Slug: "gone",
+		Status: models.ArticleStatusPublished, ExpiresAt: &past,
+	}
+	db.Create(&expired)
+
+	r := articleVisibilityRouter()
+	path := "/api/articles/" + strconv.FormatUint(uint64(expired.ID), 10)
+
+	cases := []struct {
+		name   string
+		header string
+		want   int
+	}{
+		{"anonymous", "", 404},
+		{"owner", bearerToken(t, author.ID, string(models.RoleUser)), 200},
+		{"other user", bearerToken(t, other.ID, string(models.RoleUser)), 404},
+		{"admin", bearerToken(t, admin.ID, string(models.RoleAdmin)), 200},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tc.want {
+				t.Errorf("expected %d, got %d: %s", tc.want, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetArticleAllowsUnexpiredArticleForAnyone(t *testing.T) {
+	db := setupArticleVisibilityTestDB(t)
+	config.App = &config.Config{JWTSecret: "test-secret"}
+
+	author := models.User{Username: "author", Email: "author@example.com"}
+	db.Create(&author)
+	future := time.Now().Add(time.Hour)
+	article := models.Article{
+		AuthorID: author.ID, Title: "still live", Slug: "still-live",
+		Status: models.ArticleStatusPublished, ExpiresAt: &future,
+	}
+	db.Create(&article)
+
+	r := articleVisibilityRouter()
+	req := httptest.NewRequest(http.MethodGet, "/api/articles/"+strconv.FormatUint(uint64(article.ID), 10), nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected 200 for an unexpired article, got %d: %s", w.Code, w.Body.String())
+	}
+}
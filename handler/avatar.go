@@ -0,0 +1,258 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/1055373165/blog/avatar"
+	"github.com/1055373165/blog/config"
+	"github.com/1055373165/blog/covercrop"
+	"github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/metrics"
+	"github.com/1055373165/blog/middleware"
+	"github.com/1055373165/blog/models"
+	"github.com/1055373165/blog/service"
+	"github.com/1055373165/blog/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// avatarLargeSize and avatarSmallSize are the two square variants
+// UploadAvatar generates, matching a detail-view size and a list/comment
+// thumbnail size.
+const (
+	avatarLargeSize = 256
+	avatarSmallSize = 64
+)
+
+// defaultAvatarSize and maxAvatarSize bound GetDefaultAvatar's size
+// query parameter.
+const (
+	defaultAvatarSize = 256
+	maxAvatarSize     = 1024
+)
+
+// avatarUserHash returns a stable, non-sequential per-user directory
+// name to store avatar files under, so an avatar URL doesn't expose the
+// user's numeric ID the way a plain uploads/avatars/<id>/ path would.
+func avatarUserHash(userID uint) string {
+	sum := sha256.Sum256([]byte(strconv.FormatUint(uint64(userID), 10)))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// avatarDir returns where userID's avatar files are stored.
+func avatarDir(userID uint) string {
+	return filepath.Join(config.App.UploadDir, "avatars", avatarUserHash(userID))
+}
+
+// UploadAvatar accepts a multipart image upload, center-crops and
+// resizes it to 256px and 64px square variants with the same focal-crop
+// pipeline article covers use (covercrop.Crop, centered since an avatar
+// has no configurable focal point), and stores both under
+// uploads/avatars/<user-hash>/, replacing the caller's avatar and
+// deleting whatever files were stored there before.
+//
+// @Summary      Upload an avatar
+// @Description  Accepts an image upload, center-crops and resizes it to 256px and 64px square variants, and replaces the caller's avatar, deleting their previous avatar files.
+// @Tags         users
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        file  formData  file  true  "Image to upload"
+// @Success      200   {object}  utils.Response{data=models.User}
+// @Failure      400   {object}  utils.Response
+// @Failure      413   {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /users/me/avatar [post]
+func UploadAvatar(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, config.App.UploadMaxBytes)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		if utils.IsBodyTooLarge(err) {
+			utils.Fail(c, 413, "file exceeds the upload size limit")
+			return
+		}
+		utils.Fail(c, 400, "missing file field")
+		return
+	}
+
+	src, err := header.Open()
+	if err != nil {
+		utils.Fail(c, 400, "missing file field")
+		return
+	}
+	defer src.Close()
+	data, err := io.ReadAll(src)
+	if err != nil {
+		if utils.IsBodyTooLarge(err) {
+			utils.Fail(c, 413, "file exceeds the upload size limit")
+			return
+		}
+		utils.Fail(c, 400, "failed to read uploaded file")
+		return
+	}
+
+	sniffN := len(data)
+	if sniffN > 512 {
+		sniffN = 512
+	}
+	ext := filepath.Ext(header.Filename)
+	if err := utils.VerifyUploadContentType(data[:sniffN], ext, bytes.NewReader(data), int64(len(data))); err != nil {
+		utils.Fail(c, 400, "file content does not match its extension")
+		return
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		utils.Fail(c, 400, "uploaded file is not a valid image")
+		return
+	}
+
+	userID := middleware.CurrentUserID(c)
+	dir := avatarDir(userID)
+	if err := os.RemoveAll(dir); err != nil {
+		utils.Fail(c, 500, "failed to store avatar")
+		return
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		utils.Fail(c, 500, "failed to store avatar")
+		return
+	}
+
+	largeName := randomFilename() + ".png"
+	smallName := randomFilename() + ".png"
+	if err := writeAvatarVariant(decoded, filepath.Join(dir, largeName), avatarLargeSize); err != nil {
+		utils.Fail(c, 500, "failed to store avatar")
+		return
+	}
+	if err := writeAvatarVariant(decoded, filepath.Join(dir, smallName), avatarSmallSize); err != nil {
+		utils.Fail(c, 500, "failed to store avatar")
+		return
+	}
+
+	hash := avatarUserHash(userID)
+	avatarURL := "/api/avatars/u/" + hash + "/" + largeName
+	thumbnailURL := "/api/avatars/u/" + hash + "/" + smallName
+
+	user, err := service.SetUserAvatar(db.DB, userID, avatarURL, thumbnailURL)
+	if err != nil {
+		utils.Fail(c, 500, "failed to update avatar")
+		return
+	}
+
+	metrics.UploadsBytesTotal.Add(float64(len(data)))
+	utils.OK(c, user)
+}
+
+// writeAvatarVariant center-crops src to size x size and writes it as a
+// PNG to path.
+func writeAvatarVariant(src image.Image, path string, size int) error {
+	cropped := covercrop.Crop(src, 0.5, 0.5, size, size)
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	return png.Encode(out, cropped)
+}
+
+// DeleteAvatar clears the caller's Avatar and AvatarThumbnail back to
+// "" and deletes their uploaded avatar files, reverting them to
+// GetDefaultAvatar's generated identicon - the same state as a user who
+// never uploaded one.
+//
+// @Summary      Reset avatar to the generated default
+// @Description  Deletes the caller's uploaded avatar files and clears Avatar/AvatarThumbnail, reverting to the generated default identicon.
+// @Tags         users
+// @Produce      json
+// @Success      200  {object}  utils.Response{data=models.User}
+// @Failure      500  {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /users/me/avatar [delete]
+func DeleteAvatar(c *gin.Context) {
+	userID := middleware.CurrentUserID(c)
+	if err := os.RemoveAll(avatarDir(userID)); err != nil {
+		utils.Fail(c, 500, "failed to reset avatar")
+		return
+	}
+
+	user, err := service.ClearUserAvatar(db.DB, userID)
+	if err != nil {
+		utils.Fail(c, 500, "failed to update avatar")
+		return
+	}
+	utils.OK(c, user)
+}
+
+// GetDefaultAvatar renders a deterministic identicon PNG for a user:
+// a background color chosen from a hash of their username, with its
+// first letter centered in white (see package avatar). This is what a
+// caller should fall back to rendering whenever a User's Avatar is "".
+//
+// @Summary      Get a user's default generated avatar
+// @Description  Renders a deterministic identicon PNG from a hash of the user's username - the fallback for any user whose Avatar is empty.
+// @Tags         users
+// @Produce      image/png
+// @Param        user_id  path   int  true   "User ID"
+// @Param        size     query  int  false  "Image size in pixels, default 256"
+// @Success      200      {file}    file
+// @Failure      400      {object}  utils.Response
+// @Failure      404      {object}  utils.Response
+// @Router       /avatars/default/{user_id} [get]
+func GetDefaultAvatar(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid user id")
+		return
+	}
+
+	size := defaultAvatarSize
+	if v := c.Query("size"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxAvatarSize {
+			utils.Fail(c, 400, fmt.Sprintf("size must be an integer between 1 and %d", maxAvatarSize))
+			return
+		}
+		size = n
+	}
+
+	var user models.User
+	if err := db.DB.First(&user, userID).Error; err != nil {
+		utils.Fail(c, 404, "user not found")
+		return
+	}
+
+	data, err := avatar.Generate(user.Username, size)
+	if err != nil {
+		utils.Fail(c, 500, "failed to generate avatar")
+		return
+	}
+	c.Data(http.StatusOK, "image/png", data)
+}
+
+// GetAvatarFile serves a stored uploaded avatar image the same way as
+// GetFile, scoped to its own per-user subdirectory.
+//
+// @Summary      Download an avatar file
+// @Description  Serves a stored uploaded avatar image the same way as GetFile, scoped to its own per-user subdirectory.
+// @Tags         users
+// @Produce      application/octet-stream
+// @Param        hash      path  string  true  "Per-user avatar directory hash"
+// @Param        filename  path  string  true  "Stored filename"
+// @Success      200       {file}    file
+// @Failure      403       {object}  utils.Response
+// @Failure      404       {object}  utils.Response
+// @Router       /avatars/u/{hash}/{filename} [get]
+func GetAvatarFile(c *gin.Context) {
+	serveUploadedFile(c, filepath.Join("avatars", c.Param("hash")), c.Param("filename"))
+}
@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/1055373165/blog/config"
+	blogdb "github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/middleware"
+	"github.com/1055373165/blog/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupAvatarTestDB(t *testing.T) (*gorm.DB, models.User) {
+	t.Helper()
+	conn, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	if err := conn.AutoMigrate(&models.User{}); err != nil {
+		t.Fatalf("failed to migrate test db: %v", err)
+	}
+	blogdb.DB = conn
+
+	user := models.User{Username: "alice", Email: "alice@example.com"}
+	if err := conn.Create(&user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	return conn, user
+}
+
+func avatarRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/users/me/avatar", middleware.RequireAuth(), UploadAvatar)
+	r.DELETE("/api/users/me/avatar", middleware.RequireAuth(), DeleteAvatar)
+	return r
+}
+
+func testPNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 0, A: 0xff})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func avatarUploadRequest(t *testing.T, token string, content []byte) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, err := w.CreateFormFile("file", "photo.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := part.Write(content); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/users/me/avatar", &body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	req.Header.Set("Authorization", token)
+	return req
+}
+
+func TestUploadAvatarGeneratesSquareVariantsAndReplacesThePrevious(t *testing.T) {
+	conn, user := setupAvatarTestDB(t)
+	config.App = &config.Config{UploadDir: t.TempDir(), UploadMaxBytes: 1 << 20, JWTSecret: "test-secret"}
+	r := avatarRouter()
+	token := bearerToken(t, user.ID, string(models.RoleUser))
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, avatarUploadRequest(t, token, testPNG(t, 400, 300)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp struct {
+		Data models.User `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Data.Avatar == "" || resp.Data.AvatarThumbnail == "" {
+		t.Fatalf("expected avatar URLs to be set, got %+v", resp.Data)
+	}
+
+	dir := avatarDir(user.ID)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read avatar dir: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 avatar files, got %d", len(entries))
+	}
+
+	firstAvatar := resp.Data.Avatar
+	rec2 := httptest.NewRecorder()
+	r.ServeHTTP(rec2, avatarUploadRequest(t, token, testPNG(t, 100, 100)))
+	if rec2.Code != http.StatusOK {
+		t.Fatalf("expected 200 on re-upload, got %d: %s", rec2.Code, rec2.Body.String())
+	}
+
+	var resp2 struct {
+		Data models.User `json:"data"`
+	}
+	json.Unmarshal(rec2.Body.Bytes(), &resp2)
+	if resp2.Data.Avatar == firstAvatar {
+		t.Errorf("expected a fresh avatar URL on re-upload, got the same one")
+	}
+
+	entries, err = os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read avatar dir: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected the previous upload's files to be deleted, found %d files", len(entries))
+	}
+
+	var reloaded models.User
+	conn.First(&reloaded, user.ID)
+	if reloaded.Avatar != resp2.Data.Avatar {
+		t.Errorf("expected the stored user record to carry the latest avatar URL")
+	}
+}
+
+func TestUploadAvatarRejectsNonImageContent(t *testing.T) {
+	_, user := setupAvatarTestDB(t)
+	config.App = &config.Config{UploadDir: t.TempDir(), UploadMaxBytes: 1 << 20, JWTSecret: "test-secret"}
+	r := avatarRouter()
+	token := bearerToken(t, user.ID, string(models.RoleUser))
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, avatarUploadRequest(t, token, []byte("not an image")))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for non-image content, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestDeleteAvatarClearsFieldsAndRemovesFiles(t *testing.T) {
+	conn, user := setupAvatarTestDB(t)
+	config.App = &config.Config{UploadDir: t.TempDir(), UploadMaxBytes: 1 << 20, JWTSecret: "test-secret"}
+	r := avatarRouter()
+	token := bearerToken(t, user.ID, string(models.RoleUser))
+
+	r.ServeHTTP(httptest.NewRecorder(), avatarUploadRequest(t, token, testPNG(t, 200, 200)))
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/users/me/avatar", nil)
+	req.Header.Set("Authorization", token)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var reloaded models.User
+	conn.First(&reloaded, user.ID)
+	if reloaded.Avatar != "" || reloaded.AvatarThumbnail != "" {
+		t.Errorf("expected avatar fields cleared, got %+v", reloaded)
+	}
+
+	if _, err := os.Stat(avatarDir(user.ID)); !os.IsNotExist(err) {
+		t.Errorf("expected avatar directory removed, stat err: %v", err)
+	}
+}
+
+func TestGetDefaultAvatarRendersAConsistentPNGPerUser(t *testing.T) {
+	_, user := setupAvatarTestDB(t)
+	config.App = &config.Config{UploadDir: t.TempDir(), UploadMaxBytes: 1 << 20}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/api/avatars/default/:user_id", GetDefaultAvatar)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/avatars/default/"+strconv.FormatUint(uint64(user.ID), 10), nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec.Header().Get("Content-Type") != "image/png" {
+		t.Errorf("expected image/png content type, got %q", rec.Header().Get("Content-Type"))
+	}
+	if _, err := png.Decode(bytes.NewReader(rec.Body.Bytes())); err != nil {
+		t.Errorf("expected a valid PNG, decode failed: %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/1055373165/blog/backup"
+	"github.com/1055373165/blog/config"
+	"github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateBackup runs a logical dump of every database table to a new
+// timestamped, gzip-compressed JSON file under config.App.BackupDir.
+//
+// @Summary      Create a database backup
+// @Description  Runs a logical dump of every database table to a timestamped, gzip-compressed JSON file. Admin only.
+// @Tags         admin
+// @Produce      json
+// @Success      200  {object}  utils.Response{data=backup.Manifest}
+// @Failure      500  {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /admin/backups [post]
+func CreateBackup(c *gin.Context) {
+	manifest, err := backup.Run(db.DB, config.App.BackupDir, time.Now())
+	if err != nil {
+		utils.Fail(c, 500, "failed to create backup")
+		return
+	}
+	utils.OK(c, manifest)
+}
+
+// GetBackups lists every backup under config.App.BackupDir, newest
+// first.
+//
+// @Summary      List database backups
+// @Description  Returns every backup's manifest, newest first. Admin only.
+// @Tags         admin
+// @Produce      json
+// @Success      200  {object}  utils.Response{data=[]backup.Manifest}
+// @Failure      500  {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /admin/backups [get]
+func GetBackups(c *gin.Context) {
+	manifests, err := backup.List(config.App.BackupDir)
+	if err != nil {
+		utils.Fail(c, 500, "failed to list backups")
+		return
+	}
+	utils.OK(c, manifests)
+}
+
+// DownloadBackup streams a backup's dump file.
+//
+// @Summary      Download a database backup
+// @Description  Streams a backup's gzip-compressed JSON dump. Admin only.
+// @Tags         admin
+// @Produce      application/gzip
+// @Param        name  path  string  true  "Backup name"
+// @Success      200   {file}    file
+// @Failure      404   {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /admin/backups/{name}/download [get]
+func DownloadBackup(c *gin.Context) {
+	path, err := backup.DumpFile(config.App.BackupDir, c.Param("name"))
+	if err != nil {
+		utils.Fail(c, 404, "backup not found")
+		return
+	}
+	c.FileAttachment(path, c.Param("name")+".json.gz")
+}
@@ -0,0 +1,523 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/middleware"
+	"github.com/1055373165/blog/models"
+	"github.com/1055373165/blog/service"
+	"github.com/1055373165/blog/siteconfig"
+	"github.com/1055373165/blog/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// blogWithEpisodeNav adds previous/next episode links to a Blog response
+// when it belongs to an ordered Series.
+type blogWithEpisodeNav struct {
+	models.Blog
+	PreviousEpisode *service.BlogEpisodeRef `json:"previous_episode,omitempty"`
+	NextEpisode     *service.BlogEpisodeRef `json:"next_episode,omitempty"`
+}
+
+// canViewBlog reports whether the caller may see blog. Blog has no
+// draft/published Status like Article, so an unexpired blog is always
+// visible; once ExpiresAt passes, it's visible only to its author or an
+// admin, mirroring canViewArticle.
+func canViewBlog(c *gin.Context, blog *models.Blog) bool {
+	if blog.ExpiresAt == nil || blog.ExpiresAt.After(time.Now()) {
+		return true
+	}
+	uid := middleware.CurrentUserID(c)
+	return uid != 0 && (uid == blog.AuthorID || middleware.CurrentRole(c) == string(models.RoleAdmin))
+}
+
+// GetBlogBySlug returns a Blog (with chapters) by its slug.
+//
+// @Summary      Get a blog by slug
+// @Description  Returns a Blog, including its chapters and series episode navigation, by slug. 404s once ExpiresAt has passed, unless the caller is its author or an admin.
+// @Tags         blogs
+// @Produce      json
+// @Param        slug  path      string  true  "Blog slug"
+// @Success      200   {object}  utils.Response{data=blogWithEpisodeNav}
+// @Failure      404   {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /blogs/slug/{slug} [get]
+func GetBlogBySlug(c *gin.Context) {
+	slug := c.Param("slug")
+	blog, err := service.GetBlogBySlug(db.DB, slug)
+	if err != nil {
+		if newSlug, ok := service.ResolveSlugRedirect(db.DB, "blog", slug); ok {
+			utils.SlugRedirect(c, "/api/blogs/slug/"+newSlug, newSlug)
+			return
+		}
+		utils.Fail(c, 404, "blog not found")
+		return
+	}
+	if !canViewBlog(c, blog) {
+		utils.Fail(c, 404, "blog not found")
+		return
+	}
+	blog.Description = utils.ExpandContentURLs(blog.Description, utils.ResolvePublicBase(c))
+
+	previous, next, err := service.GetBlogEpisodeNav(db.DB, blog)
+	if err != nil {
+		utils.Fail(c, 500, "failed to load episode navigation")
+		return
+	}
+	utils.OK(c, blogWithEpisodeNav{Blog: *blog, PreviousEpisode: previous, NextEpisode: next})
+}
+
+// RecordBlogView increments a blog's view counter, mirroring
+// RecordArticleView: it requires a same-origin request and no-ops for
+// anonymous callers and the blog's own author, so previewing your own
+// episode doesn't pollute its view count.
+//
+// @Summary      Record a blog view
+// @Description  Increments a blog's view counter. No-ops for anonymous callers and the blog's own author. Requires a same-origin request.
+// @Tags         blogs
+// @Produce      json
+// @Param        id   path      int  true  "Blog ID"
+// @Success      200  {object}  utils.Response
+// @Failure      400  {object}  utils.Response
+// @Failure      403  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /blogs/{id}/view [post]
+func RecordBlogView(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid blog id")
+		return
+	}
+	if !utils.IsSameOriginRequest(c) {
+		utils.Fail(c, 403, "cross-origin view recording is not allowed")
+		return
+	}
+
+	var blog models.Blog
+	if err := db.DB.First(&blog, id).Error; err != nil {
+		utils.Fail(c, 404, "blog not found")
+		return
+	}
+	if !canViewBlog(c, &blog) {
+		utils.Fail(c, 404, "blog not found")
+		return
+	}
+
+	uid := middleware.CurrentUserID(c)
+	if uid != 0 && uid != blog.AuthorID {
+		if err := service.IncrementBlogViews(db.DB, blog.ID); err != nil {
+			utils.Fail(c, 500, "failed to record view")
+			return
+		}
+	}
+	utils.OK(c, nil)
+}
+
+// GetBlogStats returns a blog's current views/likes counters without
+// loading its Transcript, Chapters, or any other column, mirroring
+// GetArticleStats.
+//
+// @Summary      Get a blog's counters
+// @Description  Returns a blog's current views and likes without loading the full blog. 404s once ExpiresAt has passed, unless the caller is its author or an admin.
+// @Tags         blogs
+// @Produce      json
+// @Param        id   path      int  true  "Blog ID"
+// @Success      200  {object}  utils.Response{data=service.BlogStats}
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /blogs/{id}/stats [get]
+func GetBlogStats(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid blog id")
+		return
+	}
+
+	var blog models.Blog
+	err = db.WithCtx(c.Request.Context()).
+		Select("id", "author_id", "expires_at", "views", "likes").
+		First(&blog, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.Fail(c, 404, "blog not found")
+			return
+		}
+		utils.FailFromDBError(c, err, "failed to load blog stats")
+		return
+	}
+	if !canViewBlog(c, &blog) {
+		utils.Fail(c, 404, "blog not found")
+		return
+	}
+
+	utils.OK(c, service.BlogStats{Views: blog.Views, Likes: blog.Likes})
+}
+
+// GetBlogsBySeries returns every blog in a series, ordered by episode
+// number.
+//
+// @Summary      List a series' blogs
+// @Description  Returns every blog episode in a series, ordered by series_order.
+// @Tags         series
+// @Produce      json
+// @Param        id  path      int  true  "Series ID"
+// @Success      200  {object}  utils.Response{data=[]models.Blog}
+// @Failure      400  {object}  utils.Response
+// @Router       /series/{id}/blogs [get]
+func GetBlogsBySeries(c *gin.Context) {
+	seriesID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid series id")
+		return
+	}
+
+	blogs, err := service.GetBlogsBySeries(db.DB, uint(seriesID))
+	if err != nil {
+		utils.Fail(c, 500, "failed to load series blogs")
+		return
+	}
+	utils.OK(c, blogs)
+}
+
+// CreateBlogRequest is the payload for creating an audio/video Blog.
+type CreateBlogRequest struct {
+	Title       string           `json:"title" binding:"required"`
+	Slug        string           `json:"slug" binding:"required"`
+	Description string           `json:"description"`
+	MediaURL    string           `json:"media_url" binding:"required"`
+	Duration    float64          `json:"duration" binding:"required,min=0.001"`
+	Transcript  string           `json:"transcript"`
+	Chapters    []models.Chapter `json:"chapters"`
+	SeriesID    *uint            `json:"series_id"`
+	SeriesOrder *int             `json:"series_order"`
+	ExpiresAt   *time.Time       `json:"expires_at"`
+}
+
+// CreateBlog creates a new Blog.
+//
+// @Summary      Create a blog
+// @Description  Creates a new audio/video Blog.
+// @Tags         blogs
+// @Accept       json
+// @Produce      json
+// @Param        request  body      CreateBlogRequest  true  "Blog fields"
+// @Success      200      {object}  utils.Response{data=models.Blog}
+// @Failure      400      {object}  utils.Response{details=[]utils.FieldError}
+// @Failure      422      {object}  utils.Response{details=utils.ContentTooLargeDetail}
+// @Security     BearerAuth
+// @Router       /blogs [post]
+func CreateBlog(c *gin.Context) {
+	var req CreateBlogRequest
+	if !utils.BindJSON(c, &req) {
+		return
+	}
+	if !utils.EnforceContentLength(c, "transcript", req.Transcript, siteconfig.KeyContentMaxBytes) {
+		return
+	}
+
+	blog := models.Blog{
+		AuthorID:    middleware.CurrentUserID(c),
+		Title:       req.Title,
+		Slug:        req.Slug,
+		Description: req.Description,
+		MediaURL:    req.MediaURL,
+		Duration:    req.Duration,
+		Transcript:  req.Transcript,
+		Chapters:    models.Chapters(req.Chapters),
+		SeriesID:    req.SeriesID,
+		SeriesOrder: req.SeriesOrder,
+		ExpiresAt:   req.ExpiresAt,
+	}
+
+	blog.WaveformStatus = models.WaveformStatusPending
+
+	if err := service.CreateBlog(db.DB, &blog); err != nil {
+		if err == service.ErrInvalidChapters {
+			utils.Fail(c, 400, err.Error())
+			return
+		}
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			utils.FailWithCode(c, 409, utils.ErrCodeSlugConflict)
+			return
+		}
+		utils.Fail(c, 500, "failed to create blog")
+		return
+	}
+	service.TriggerBlogWaveformGeneration(db.DB, blog.ID)
+
+	utils.OK(c, blog)
+}
+
+// UpdateBlogRequest is the payload for updating a Blog. Every field
+// leaves the corresponding column untouched when omitted. SeriesID and
+// SeriesOrder are the two fields a caller can null out (to pull an
+// episode out of its series), so they use utils.OptionalUint/OptionalInt
+// to distinguish an omitted key from an explicit null - a plain
+// `*uint`/`*int` can't tell those apart, since both decode to nil. The
+// *string fields don't need this: `*string` already distinguishes an
+// omitted key (nil) from an explicit empty string.
+type UpdateBlogRequest struct {
+	Title       *string            `json:"title"`
+	Slug        *string            `json:"slug"`
+	Description *string            `json:"description"`
+	Duration    *float64           `json:"duration" binding:"omitempty,min=0.001"`
+	Transcript  *string            `json:"transcript"`
+	Chapters    []models.Chapter   `json:"chapters"`
+	SeriesID    utils.OptionalUint `json:"series_id"`
+	SeriesOrder utils.OptionalInt  `json:"series_order"`
+}
+
+// UpdateBlog updates an existing Blog.
+//
+// @Summary      Update a blog
+// @Description  Updates fields on an existing Blog.
+// @Tags         blogs
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                true  "Blog ID"
+// @Param        request  body      UpdateBlogRequest  true  "Fields to update"
+// @Success      200      {object}  utils.Response{data=models.Blog}
+// @Failure      400      {object}  utils.Response{details=[]utils.FieldError}
+// @Failure      404      {object}  utils.Response
+// @Failure      409      {object}  utils.Response
+// @Failure      422      {object}  utils.Response{details=utils.ContentTooLargeDetail}
+// @Security     BearerAuth
+// @Router       /blogs/{id} [put]
+func UpdateBlog(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid blog id")
+		return
+	}
+
+	var blog models.Blog
+	if err := db.DB.First(&blog, id).Error; err != nil {
+		utils.Fail(c, 404, "blog not found")
+		return
+	}
+
+	var req UpdateBlogRequest
+	if !utils.BindJSON(c, &req) {
+		return
+	}
+	if req.Transcript != nil && !utils.EnforceContentLength(c, "transcript", *req.Transcript, siteconfig.KeyContentMaxBytes) {
+		return
+	}
+
+	oldSlug := blog.Slug
+	if req.Slug != nil && *req.Slug != oldSlug {
+		if err := service.RecordSlugRedirect(db.DB, "blog", oldSlug, *req.Slug); err != nil {
+			utils.Fail(c, 409, "slug change would create a redirect cycle")
+			return
+		}
+	}
+
+	updates := map[string]any{}
+	if req.Title != nil {
+		updates["title"] = *req.Title
+	}
+	if req.Slug != nil {
+		updates["slug"] = *req.Slug
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if req.Duration != nil {
+		updates["duration"] = *req.Duration
+	}
+	if req.Transcript != nil {
+		updates["transcript"] = *req.Transcript
+	}
+	if req.Chapters != nil {
+		updates["chapters"] = models.Chapters(req.Chapters)
+	}
+	if req.SeriesID.Set {
+		updates["series_id"] = req.SeriesID.Value
+	}
+	if req.SeriesOrder.Set {
+		updates["series_order"] = req.SeriesOrder.Value
+	}
+
+	if err := service.UpdateBlog(db.DB, &blog, updates); err != nil {
+		if err == service.ErrInvalidChapters {
+			utils.Fail(c, 400, err.Error())
+			return
+		}
+		utils.Fail(c, 500, "failed to update blog")
+		return
+	}
+
+	utils.OK(c, blog)
+}
+
+// GenerateBlogWaveform (re)starts asynchronous waveform peak generation
+// for an existing Blog, e.g. if it failed or predates this feature.
+//
+// @Summary      Generate a blog's waveform
+// @Description  Starts asynchronous waveform peak generation for a blog's media, marking it pending immediately.
+// @Tags         blogs
+// @Produce      json
+// @Param        id  path      int  true  "Blog ID"
+// @Success      200  {object}  utils.Response{data=models.Blog}
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /blogs/{id}/generate-waveform [post]
+func GenerateBlogWaveform(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid blog id")
+		return
+	}
+
+	var blog models.Blog
+	if err := db.DB.First(&blog, id).Error; err != nil {
+		utils.Fail(c, 404, "blog not found")
+		return
+	}
+
+	if err := db.DB.Model(&blog).Update("waveform_status", models.WaveformStatusPending).Error; err != nil {
+		utils.Fail(c, 500, "failed to start waveform generation")
+		return
+	}
+	service.TriggerBlogWaveformGeneration(db.DB, blog.ID)
+
+	blog.WaveformStatus = models.WaveformStatusPending
+	utils.OK(c, blog)
+}
+
+// GetBlogTranscriptVTT serves a Blog's transcript as WebVTT, chaptered by
+// its stored Chapters when timings are present.
+//
+// @Summary      Get a blog's transcript as WebVTT
+// @Description  Serves a Blog's transcript as WebVTT, chaptered by its stored Chapters when timings are present.
+// @Tags         blogs
+// @Produce      text/vtt
+// @Param        id   path  int  true  "Blog ID"
+// @Success      200  {string}  string  "WebVTT transcript"
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Router       /blogs/{id}/transcript.vtt [get]
+func GetBlogTranscriptVTT(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid blog id")
+		return
+	}
+
+	var blog models.Blog
+	if err := db.DB.First(&blog, id).Error; err != nil {
+		utils.Fail(c, 404, "blog not found")
+		return
+	}
+	if blog.Transcript == "" {
+		utils.Fail(c, 404, "no transcript available")
+		return
+	}
+
+	c.Header("Content-Type", "text/vtt; charset=utf-8")
+	c.String(200, transcriptToVTT(blog))
+}
+
+func transcriptToVTT(blog models.Blog) string {
+	var b strings.Builder
+	b.WriteString("WEBVTT\n\n")
+
+	if len(blog.Chapters) == 0 {
+		b.WriteString("00:00:00.000 --> ")
+		b.WriteString(formatVTTTimestamp(blog.Duration))
+		b.WriteString("\n")
+		b.WriteString(blog.Transcript)
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	for i, ch := range blog.Chapters {
+		end := blog.Duration
+		if i+1 < len(blog.Chapters) {
+			end = blog.Chapters[i+1].StartSeconds
+		}
+		fmt.Fprintf(&b, "%s --> %s\n%s\n\n", formatVTTTimestamp(ch.StartSeconds), formatVTTTimestamp(end), ch.Title)
+	}
+	return b.String()
+}
+
+func formatVTTTimestamp(seconds float64) string {
+	total := int(seconds)
+	h := total / 3600
+	m := (total % 3600) / 60
+	s := total % 60
+	ms := int((seconds - float64(total)) * 1000)
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", h, m, s, ms)
+}
+
+// ToggleBlogReaction is ToggleArticleReaction for a Blog.
+//
+// @Summary      Toggle a reaction on a blog
+// @Description  Toggles the caller's reaction (like, heart, celebrate, or thinking) on a blog. Toggling "like" keeps the legacy likes counter in sync.
+// @Tags         blogs
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                   true  "Blog ID"
+// @Param        request  body      ToggleReactionRequest  true  "Reaction type"
+// @Success      200      {object}  utils.Response{data=object{reacted=bool}}
+// @Failure      400      {object}  utils.Response
+// @Failure      404      {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /blogs/{id}/reactions [post]
+func ToggleBlogReaction(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid blog id")
+		return
+	}
+	var req ToggleReactionRequest
+	if !utils.BindJSON(c, &req) {
+		return
+	}
+
+	reacted, err := service.ToggleBlogReaction(db.DB, middleware.CurrentUserID(c), uint(id), req.ReactionType)
+	if err != nil {
+		if err == service.ErrBlogNotFound {
+			utils.Fail(c, 404, "blog not found")
+			return
+		}
+		utils.Fail(c, 500, "failed to toggle reaction")
+		return
+	}
+
+	utils.OK(c, gin.H{"reacted": reacted})
+}
+
+// GetBlogReactions is GetArticleReactions for a Blog.
+//
+// @Summary      Get a blog's reactions
+// @Description  Returns a blog's per-type reaction counts plus the caller's own reactions.
+// @Tags         blogs
+// @Produce      json
+// @Param        id   path      int  true  "Blog ID"
+// @Success      200  {object}  utils.Response{data=reactionsResponse}
+// @Failure      400  {object}  utils.Response
+// @Router       /blogs/{id}/reactions [get]
+func GetBlogReactions(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid blog id")
+		return
+	}
+
+	counts, mine, err := service.GetBlogReactions(db.DB, uint(id), middleware.CurrentUserID(c))
+	if err != nil {
+		utils.Fail(c, 500, "failed to load reactions")
+		return
+	}
+	utils.OK(c, reactionsResponse{Counts: counts, Mine: mine})
+}
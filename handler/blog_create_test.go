@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/1055373165/blog/config"
+	blogdb "github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestCreateBlogRejectsDuplicateSlugWithConflictInsteadOf500(t *testing.T) {
+	conn, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	if err := conn.AutoMigrate(&models.Blog{}, &models.Series{}); err != nil {
+		t.Fatalf("failed to migrate test db: %v", err)
+	}
+	blogdb.DB = conn
+	config.App = &config.Config{UploadDir: t.TempDir()}
+
+	conn.Create(&models.Blog{AuthorID: 1, Title: "Existing", Slug: "dup-slug", MediaURL: "http://example.com/a.mp3", Duration: 10})
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/blogs", CreateBlog)
+
+	body := `{"title":"New","slug":"dup-slug","media_url":"http://example.com/b.mp3","duration":10}`
+	req := httptest.NewRequest(http.MethodPost, "/api/blogs", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for a duplicate slug, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+// TestConcurrentCreateBlogWithSameSlugNeverProduces500 fires two
+// simultaneous creates with the same slug and asserts exactly one
+// succeeds and the other gets a clean 409 - the check-then-insert race
+// this is guarding against would otherwise let both pass a SELECT-based
+// existence check and have the loser's INSERT die as a raw 500.
+func TestConcurrentCreateBlogWithSameSlugNeverProduces500(t *testing.T) {
+	conn, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	if err := conn.AutoMigrate(&models.Blog{}, &models.Series{}); err != nil {
+		t.Fatalf("failed to migrate test db: %v", err)
+	}
+	blogdb.DB = conn
+	config.App = &config.Config{UploadDir: t.TempDir()}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/blogs", CreateBlog)
+
+	body := `{"title":"Race","slug":"race-slug","media_url":"http://example.com/race.mp3","duration":10}`
+
+	var wg sync.WaitGroup
+	codes := make([]int, 2)
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req := httptest.NewRequest(http.MethodPost, "/api/blogs", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			codes[i] = w.Code
+		}(i)
+	}
+	wg.Wait()
+
+	var successes, conflicts int
+	for _, code := range codes {
+		switch code {
+		case http.StatusOK:
+			successes++
+		case http.StatusConflict:
+			conflicts++
+		default:
+			t.Errorf("unexpected status code %d, neither 200 nor 409", code)
+		}
+	}
+	if successes != 1 || conflicts != 1 {
+		t.Errorf("expected exactly one 200 and one 409, got codes %v", codes)
+	}
+
+	var count int64
+	conn.Model(&models.Blog{}).Where("slug = ?", "race-slug").Count(&count)
+	if count != 1 {
+		t.Errorf("expected exactly one blog with slug race-slug, got %d", count)
+	}
+}
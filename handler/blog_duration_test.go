@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/1055373165/blog/models"
+	"github.com/gin-gonic/gin"
+)
+
+func TestCreateAndUpdateBlogPreserveFractionalDuration(t *testing.T) {
+	db := setupBlogUpdateTestDB(t)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/blogs", CreateBlog)
+	r.PUT("/api/blogs/:id", UpdateBlog)
+
+	body := `{"title":"Ep1","slug":"ep1","media_url":"http://example.com/ep1.mp3","duration":312.7}`
+	req := httptest.NewRequest(http.MethodPost, "/api/blogs", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var created models.Blog
+	db.Where("slug = ?", "ep1").First(&created)
+	if created.Duration != 312.7 {
+		t.Errorf("expected stored duration 312.7, got %v", created.Duration)
+	}
+
+	var resp struct {
+		Data models.Blog `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode create response: %v", err)
+	}
+	if resp.Data.Duration != 312.7 {
+		t.Errorf("expected response duration 312.7, got %v", resp.Data.Duration)
+	}
+
+	putReq := httptest.NewRequest(http.MethodPut, "/api/blogs/"+strconv.Itoa(int(created.ID)), strings.NewReader(`{"duration":45.25}`))
+	putReq.Header.Set("Content-Type", "application/json")
+	putW := httptest.NewRecorder()
+	r.ServeHTTP(putW, putReq)
+	if putW.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", putW.Code, putW.Body.String())
+	}
+
+	var reloaded models.Blog
+	db.First(&reloaded, created.ID)
+	if reloaded.Duration != 45.25 {
+		t.Errorf("expected updated duration 45.25, got %v", reloaded.Duration)
+	}
+}
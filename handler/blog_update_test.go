@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	blogdb "github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupBlogUpdateTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	conn, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	if err := conn.AutoMigrate(&models.Blog{}, &models.Series{}); err != nil {
+		t.Fatalf("failed to migrate test db: %v", err)
+	}
+	blogdb.DB = conn
+	return conn
+}
+
+func TestUpdateBlogSeriesIDAndOrderTriState(t *testing.T) {
+	db := setupBlogUpdateTestDB(t)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.PUT("/api/blogs/:id", UpdateBlog)
+
+	series := models.Series{Title: "S1", Slug: "s1"}
+	db.Create(&series)
+	seriesOrder := 1
+	blog := models.Blog{AuthorID: 1, Title: "Ep1", Slug: "ep1", MediaURL: "http://example.com/ep1.mp3", Duration: 60, SeriesID: &series.ID, SeriesOrder: &seriesOrder}
+	db.Create(&blog)
+
+	put := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/api/blogs/"+strconv.Itoa(int(blog.ID)), strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// Omitted series_id/series_order leaves them unchanged.
+	if w := put(`{"title":"Ep1 Renamed"}`); w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var reloaded models.Blog
+	db.First(&reloaded, blog.ID)
+	if reloaded.SeriesID == nil || *reloaded.SeriesID != series.ID {
+		t.Errorf("expected series_id to stay %d when omitted, got %v", series.ID, reloaded.SeriesID)
+	}
+	if reloaded.SeriesOrder == nil || *reloaded.SeriesOrder != 1 {
+		t.Errorf("expected series_order to stay 1 when omitted, got %v", reloaded.SeriesOrder)
+	}
+
+	// Explicit null pulls the episode out of its series.
+	if w := put(`{"series_id":null,"series_order":null}`); w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	db.First(&reloaded, blog.ID)
+	if reloaded.SeriesID != nil {
+		t.Errorf("expected series_id cleared, got %v", *reloaded.SeriesID)
+	}
+	if reloaded.SeriesOrder != nil {
+		t.Errorf("expected series_order cleared, got %v", *reloaded.SeriesOrder)
+	}
+
+	// A real value sets them again.
+	if w := put(`{"series_id":` + strconv.Itoa(int(series.ID)) + `,"series_order":2}`); w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	db.First(&reloaded, blog.ID)
+	if reloaded.SeriesID == nil || *reloaded.SeriesID != series.ID {
+		t.Errorf("expected series_id set to %d, got %v", series.ID, reloaded.SeriesID)
+	}
+	if reloaded.SeriesOrder == nil || *reloaded.SeriesOrder != 2 {
+		t.Errorf("expected series_order set to 2, got %v", reloaded.SeriesOrder)
+	}
+}
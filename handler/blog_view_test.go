@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/1055373165/blog/config"
+	"github.com/1055373165/blog/middleware"
+	"github.com/1055373165/blog/models"
+	"github.com/gin-gonic/gin"
+)
+
+func blogViewRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/api/blogs/:id/stats", middleware.OptionalAuth(), GetBlogStats)
+	r.POST("/api/blogs/:id/view", middleware.OptionalAuth(), RecordBlogView)
+	return r
+}
+
+func TestRecordBlogViewIncrementsForOtherAuthenticatedUsers(t *testing.T) {
+	db := setupBlogVisibilityTestDB(t)
+	config.App = &config.Config{JWTSecret: "test-secret"}
+
+	author := models.User{Username: "author", Email: "author@example.com"}
+	db.Create(&author)
+	reader := models.User{Username: "reader", Email: "reader@example.com"}
+	db.Create(&reader)
+	blog := models.Blog{AuthorID: author.ID, Title: "live", Slug: "live", MediaURL: "http://example.com/a.mp3"}
+	db.Create(&blog)
+
+	r := blogViewRouter()
+	path := "/api/blogs/" + strconv.FormatUint(uint64(blog.ID), 10) + "/view"
+
+	req := httptest.NewRequest(http.MethodPost, path, nil)
+	req.Header.Set("Authorization", bearerToken(t, reader.ID, string(models.RoleUser)))
+	req.Header.Set("Origin", "http://example.com")
+	req.Host = "example.com"
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var got models.Blog
+	db.First(&got, blog.ID)
+	if got.Views != 1 {
+		t.Errorf("expected views to be 1, got %d", got.Views)
+	}
+}
+
+func TestRecordBlogViewSkipsTheBlogsOwnAuthor(t *testing.T) {
+	db := setupBlogVisibilityTestDB(t)
+	config.App = &config.Config{JWTSecret: "test-secret"}
+
+	author := models.User{Username: "author", Email: "author@example.com"}
+	db.Create(&author)
+	blog := models.Blog{AuthorID: author.ID, Title: "live", Slug: "live", MediaURL: "http://example.com/a.mp3"}
+	db.Create(&blog)
+
+	r := blogViewRouter()
+	path := "/api/blogs/" + strconv.FormatUint(uint64(blog.ID), 10) + "/view"
+
+	req := httptest.NewRequest(http.MethodPost, path, nil)
+	req.Header.Set("Authorization", bearerToken(t, author.ID, string(models.RoleUser)))
+	req.Header.Set("Origin", "http://example.com")
+	req.Host = "example.com"
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var got models.Blog
+	db.First(&got, blog.ID)
+	if got.Views != 0 {
+		t.Errorf("expected the author's own view not to be recorded, got %d", got.Views)
+	}
+}
+
+func TestRecordBlogViewRejectsCrossOriginRequest(t *testing.T) {
+	db := setupBlogVisibilityTestDB(t)
+	config.App = &config.Config{JWTSecret: "test-secret"}
+
+	author := models.User{Username: "author", Email: "author@example.com"}
+	db.Create(&author)
+	reader := models.User{Username: "reader", Email: "reader@example.com"}
+	db.Create(&reader)
+	blog := models.Blog{AuthorID: author.ID, Title: "live", Slug: "live", MediaURL: "http://example.com/a.mp3"}
+	db.Create(&blog)
+
+	r := blogViewRouter()
+	path := "/api/blogs/" + strconv.FormatUint(uint64(blog.ID), 10) + "/view"
+
+	req := httptest.NewRequest(http.MethodPost, path, nil)
+	req.Header.Set("Authorization", bearerToken(t, reader.ID, string(models.RoleUser)))
+	req.Header.Set("Origin", "http://evil.example")
+	req.Host = "example.com"
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != 403 {
+		t.Fatalf("expected 403, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var got models.Blog
+	db.First(&got, blog.ID)
+	if got.Views != 0 {
+		t.Errorf("expected the cross-origin request not to record a view, got %d", got.Views)
+	}
+}
+
+func TestGetBlogStatsReturnsCountersWithoutTranscript(t *testing.T) {
+	db := setupBlogVisibilityTestDB(t)
+	config.App = &config.Config{JWTSecret: "test-secret"}
+
+	author := models.User{Username: "author", Email: "author@example.com"}
+	db.Create(&author)
+	blog := models.Blog{AuthorID: author.ID, Title: "live", Slug: "live", MediaURL: "http://example.com/a.mp3", Views: 42, Likes: 7}
+	db.Create(&blog)
+
+	r := blogViewRouter()
+	path := "/api/blogs/" + strconv.FormatUint(uint64(blog.ID), 10) + "/stats"
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if body := w.Body.String(); !contains(body, `"views":42`) || !contains(body, `"likes":7`) {
+		t.Errorf("expected body to carry views/likes counters, got %s", body)
+	}
+}
+
+func TestGetBlogStatsAllowsAuthorAndAdminToSeeOwnExpiredBlog(t *testing.T) {
+	db := setupBlogVisibilityTestDB(t)
+	config.App = &config.Config{JWTSecret: "test-secret"}
+
+	author := models.User{Username: "author", Email: "author@example.com"}
+	db.Create(&author)
+	admin := models.User{Username: "admin", Email: "admin@example.com", Role: models.RoleAdmin}
+	db.Create(&admin)
+	past := time.Now().Add(-time.Hour)
+	blog := models.Blog{
+		AuthorID: author.ID, Title: "gone", Slug: "gone-episode",
+		MediaURL: "http://example.com/a.mp3", ExpiresAt: &past, Views: 3, Likes: 1,
+	}
+	db.Create(&blog)
+
+	r := blogViewRouter()
+	path := "/api/blogs/" + strconv.FormatUint(uint64(blog.ID), 10) + "/stats"
+
+	cases := []struct {
+		name   string
+		header string
+	}{
+		{"owner", bearerToken(t, author.ID, string(models.RoleUser))},
+		{"admin", bearerToken(t, admin.ID, string(models.RoleAdmin))},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			req.Header.Set("Authorization", tc.header)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != 200 {
+				t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+			}
+			if !contains(w.Body.String(), `"views":3`) {
+				t.Errorf("expected own expired blog's stats to be visible, got %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetBlogStatsReturns404ForMissingBlog(t *testing.T) {
+	setupBlogVisibilityTestDB(t)
+	config.App = &config.Config{JWTSecret: "test-secret"}
+
+	r := blogViewRouter()
+	req := httptest.NewRequest(http.MethodGet, "/api/blogs/999/stats", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != 404 {
+		t.Fatalf("expected 404, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/1055373165/blog/config"
+	blogdb "github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/middleware"
+	"github.com/1055373165/blog/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupBlogVisibilityTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	conn, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	if err := conn.AutoMigrate(&models.Blog{}, &models.User{}); err != nil {
+		t.Fatalf("failed to migrate test db: %v", err)
+	}
+	blogdb.DB = conn
+	return conn
+}
+
+func blogVisibilityRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/api/blogs/slug/:slug", middleware.OptionalAuth(), GetBlogBySlug)
+	return r
+}
+
+func TestGetBlogBySlugHidesExpiredBlogFromAnonymousAndOtherUsers(t *testing.T) {
+	db := setupBlogVisibilityTestDB(t)
+	config.App = &config.Config{JWTSecret: "test-secret"}
+
+	author := models.User{Username: "author", Email: "author@example.com"}
+	db.Create(&author)
+	other := models.User{Username: "other", Email: "other@example.com"}
+	db.Create(&other)
+	admin := models.User{Username: "admin", Email: "admin@example.com", Role: models.RoleAdmin}
+	db.Create(&admin)
+
+	past := time.Now().Add(-time.Hour)
+	blog := models.Blog{
+		AuthorID: author.ID, Title: "gone", Slug: "gone-episode",
+		MediaURL: "http://example.com/a.mp3", ExpiresAt: &past,
+	}
+	db.Create(&blog)
+
+	r := blogVisibilityRouter()
+	path := "/api/blogs/slug/gone-episode"
+
+	cases := []struct {
+		name   string
+		header string
+		want   int
+	}{
+		{"anonymous", "", 404},
+		{"owner", bearerToken(t, author.ID, string(models.RoleUser)), 200},
+		{"other user", bearerToken(t, other.ID, string(models.RoleUser)), 404},
+		{"admin", bearerToken(t, admin.ID, string(models.RoleAdmin)), 200},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tc.want {
+				t.Errorf("expected %d, got %d: %s", tc.want, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetBlogBySlugAllowsUnexpiredBlogForAnyone(t *testing.T) {
+	db := setupBlogVisibilityTestDB(t)
+	config.App = &config.Config{JWTSecret: "test-secret"}
+
+	author := models.User{Username: "author", Email: "author@example.com"}
+	db.Create(&author)
+	blog := models.Blog{
+		AuthorID: author.ID, Title: "live", Slug: "live-episode",
+		MediaURL: "http://example.com/b.mp3",
+	}
+	db.Create(&blog)
+
+	r := blogVisibilityRouter()
+	req := httptest.NewRequest(http.MethodGet, "/api/blogs/slug/live-episode", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected 200 for an unexpired blog, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/1055373165/blog/config"
+	"github.com/1055373165/blog/middleware"
+	"github.com/1055373165/blog/models"
+	"github.com/gin-gonic/gin"
+)
+
+func TestCreateArticleRejectsOversizedBodyWith413(t *testing.T) {
+	db := setupArticleVisibilityTestDB(t)
+	config.App = &config.Config{JWTSecret: "test-secret"}
+
+	author := models.User{Username: "author", Email: "author@example.com"}
+	db.Create(&author)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/articles", middleware.MaxBodyBytes(16), middleware.RequireAuth(), CreateArticle)
+
+	body := `{"title":"New","slug":"new-article","content":"far more than sixteen bytes of content"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/articles", bytes.NewReader([]byte(body)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", bearerToken(t, author.ID, string(models.RoleUser)))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413 for a body over the limit, got %d: %s", w.Code, w.Body.String())
+	}
+	if !bytes.Contains(w.Body.Bytes(), []byte(`"code":413`)) {
+		t.Errorf("expected the standard Response envelope with code 413, got %s", w.Body.String())
+	}
+}
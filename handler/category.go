@@ -0,0 +1,430 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/1055373165/blog/audit"
+	"github.com/1055373165/blog/cache"
+	"github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/middleware"
+	"github.com/1055373165/blog/models"
+	"github.com/1055373165/blog/service"
+	"github.com/1055373165/blog/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// isCurrentUserAdmin reports whether the caller is an authenticated
+// admin, for the GetCategories/GetCategoryTree is_visible filter. These
+// routes are public (OptionalAuth), so anonymous and non-admin callers
+// alike get the filtered view.
+func isCurrentUserAdmin(c *gin.Context) bool {
+	return middleware.CurrentRole(c) == string(models.RoleAdmin)
+}
+
+// GetCategories returns every category. Categories with IsVisible false
+// are omitted for non-admin callers.
+//
+// @Summary      List categories
+// @Description  Returns every category; internal categories with is_visible=false are omitted unless the caller is an admin. Supports If-None-Match for 304 responses.
+// @Tags         categories
+// @Produce      json
+// @Success      200  {object}  utils.Response{data=[]models.Category}
+// @Router       /categories [get]
+// categoryWithFollowers adds the follow feature's follower_count to a
+// Category response.
+type categoryWithFollowers struct {
+	models.Category
+	FollowersCount int64 `json:"followers_count"`
+}
+
+func GetCategories(c *gin.Context) {
+	qdb := db.WithCtx(c.Request.Context())
+	categories, err := service.GetCategories(qdb)
+	if err != nil {
+		utils.FailFromDBError(c, err, "failed to load categories")
+		return
+	}
+	if !isCurrentUserAdmin(c) {
+		categories = service.FilterVisibleCategories(categories)
+	}
+
+	categoryIDs := make([]uint, len(categories))
+	for i, cat := range categories {
+		categoryIDs[i] = cat.ID
+	}
+	followerCounts, err := service.GetFollowerCounts(qdb, models.FollowEntityCategory, categoryIDs)
+	if err != nil {
+		utils.FailFromDBError(c, err, "failed to count category followers")
+		return
+	}
+
+	result := make([]categoryWithFollowers, len(categories))
+	for i, cat := range categories {
+		result[i] = categoryWithFollowers{Category: cat, FollowersCount: followerCounts[cat.ID]}
+	}
+	utils.ConditionalJSON(c, result)
+}
+
+// GetCategoryTree returns the category hierarchy. The assembled tree is
+// cached under cache.KeyCategoryTree since it changes only when a
+// category is created, renamed, or deleted. Nodes with IsVisible false,
+// and everything nested beneath them, are omitted for non-admin callers.
+//
+// @Summary      Get the category tree
+// @Description  Returns the category hierarchy, cached until a category is created, renamed, or deleted; internal categories with is_visible=false (and their children) are omitted unless the caller is an admin.
+// @Tags         categories
+// @Produce      json
+// @Success      200  {object}  utils.Response{data=[]service.CategoryNode}
+// @Router       /categories/tree [get]
+func GetCategoryTree(c *gin.Context) {
+	tree, err := cache.Default.GetOrCompute(cache.KeyCategoryTree, func() (any, error) {
+		return service.GetCategoryTree(db.WithCtx(c.Request.Context()))
+	})
+	if err != nil {
+		utils.FailFromDBError(c, err, "failed to load category tree")
+		return
+	}
+	if !isCurrentUserAdmin(c) {
+		tree = service.FilterVisibleCategoryTree(tree.([]*service.CategoryNode))
+	}
+	utils.ConditionalJSON(c, tree)
+}
+
+// CreateCategoryRequest is the payload for POST /api/categories.
+type CreateCategoryRequest struct {
+	Name       string `json:"name" binding:"required"`
+	Slug       string `json:"slug" binding:"required"`
+	ParentID   *uint  `json:"parent_id"`
+	Content    string `json:"content"`
+	CoverImage string `json:"cover_image"`
+	IsVisible  *bool  `json:"is_visible"`
+}
+
+// CreateCategory creates a new category and invalidates the cached tree.
+//
+// @Summary      Create a category
+// @Description  Creates a new category and invalidates the cached category tree.
+// @Tags         categories
+// @Accept       json
+// @Produce      json
+// @Param        request  body      CreateCategoryRequest  true  "Category fields"
+// @Success      200      {object}  utils.Response{data=models.Category}
+// @Failure      400      {object}  utils.Response{details=[]utils.FieldError}
+// @Security     BearerAuth
+// @Router       /categories [post]
+func CreateCategory(c *gin.Context) {
+	var req CreateCategoryRequest
+	if !utils.BindJSON(c, &req) {
+		return
+	}
+
+	isVisible := true
+	if req.IsVisible != nil {
+		isVisible = *req.IsVisible
+	}
+	category := models.Category{
+		Name:       req.Name,
+		Slug:       req.Slug,
+		ParentID:   req.ParentID,
+		Content:    req.Content,
+		CoverImage: req.CoverImage,
+		IsVisible:  isVisible,
+	}
+	if err := db.DB.Create(&category).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			utils.Fail(c, 409, "slug already in use")
+			return
+		}
+		utils.Fail(c, 500, "failed to create category")
+		return
+	}
+
+	cache.Default.Invalidate(cache.KeyCategoryTree)
+	utils.OK(c, category)
+}
+
+// UpdateCategoryRequest is the payload for PUT /api/categories/:id.
+// Every field leaves the corresponding column untouched when omitted.
+// ParentID is the one field that can be nulled out (making the category
+// top-level again), so it uses utils.OptionalUint to distinguish an
+// omitted key from an explicit `"parent_id": null` - a plain `*uint`
+// can't tell those apart. The *string fields don't need this: `*string`
+// already distinguishes an omitted key (nil) from an explicit empty
+// string, which is how a caller blanks e.g. cover_image.
+type UpdateCategoryRequest struct {
+	Name       *string            `json:"name"`
+	Slug       *string            `json:"slug"`
+	ParentID   utils.OptionalUint `json:"parent_id"`
+	Content    *string            `json:"content"`
+	CoverImage *string            `json:"cover_image"`
+	IsVisible  *bool              `json:"is_visible"`
+}
+
+// UpdateCategory updates a category and invalidates the cached tree.
+//
+// @Summary      Update a category
+// @Description  Updates a category's fields and invalidates the cached category tree.
+// @Tags         categories
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                    true  "Category ID"
+// @Param        request  body      UpdateCategoryRequest  true  "Fields to update"
+// @Success      200      {object}  utils.Response{data=models.Category}
+// @Failure      400      {object}  utils.Response{details=[]utils.FieldError}
+// @Failure      404      {object}  utils.Response
+// @Failure      409      {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /categories/{id} [put]
+func UpdateCategory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid category id")
+		return
+	}
+
+	var category models.Category
+	if err := db.DB.First(&category, id).Error; err != nil {
+		utils.Fail(c, 404, "category not found")
+		return
+	}
+
+	var req UpdateCategoryRequest
+	if !utils.BindJSON(c, &req) {
+		return
+	}
+
+	oldSlug := category.Slug
+	if req.Slug != nil && *req.Slug != oldSlug {
+		if err := service.RecordSlugRedirect(db.DB, "category", oldSlug, *req.Slug); err != nil {
+			utils.Fail(c, 409, "slug change would create a redirect cycle")
+			return
+		}
+	}
+
+	updates := map[string]any{}
+	if req.Name != nil {
+		updates["name"] = *req.Name
+	}
+	if req.Slug != nil {
+		updates["slug"] = *req.Slug
+	}
+	if req.ParentID.Set {
+		updates["parent_id"] = req.ParentID.Value
+	}
+	if req.Content != nil {
+		updates["content"] = *req.Content
+	}
+	if req.CoverImage != nil {
+		updates["cover_image"] = *req.CoverImage
+	}
+	if req.IsVisible != nil {
+		updates["is_visible"] = *req.IsVisible
+	}
+	if len(updates) > 0 {
+		if err := db.DB.Model(&category).Updates(updates).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				utils.Fail(c, 409, "slug already in use")
+				return
+			}
+			utils.Fail(c, 500, "failed to update category")
+			return
+		}
+	}
+
+	cache.Default.Invalidate(cache.KeyCategoryTree)
+	utils.OK(c, category)
+}
+
+// DeleteCategory deletes a category and invalidates the cached tree.
+//
+// @Summary      Delete a category
+// @Description  Deletes a category and invalidates the cached category tree.
+// @Tags         categories
+// @Produce      json
+// @Param        id   path      int  true  "Category ID"
+// @Success      200  {object}  utils.Response
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /categories/{id} [delete]
+func DeleteCategory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid category id")
+		return
+	}
+
+	var category models.Category
+	if err := db.DB.First(&category, id).Error; err != nil {
+		utils.Fail(c, 404, "category not found")
+		return
+	}
+
+	if err := db.DB.Delete(&models.Category{}, id).Error; err != nil {
+		utils.Fail(c, 500, "failed to delete category")
+		return
+	}
+
+	audit.Record(c, "delete", "category", category.ID, category, nil)
+	cache.Default.Invalidate(cache.KeyCategoryTree)
+	utils.OK(c, nil)
+}
+
+// PinArticleToCategory pins an article as a category's "start here" post.
+// At most two articles may be pinned per category.
+//
+// @Summary      Pin an article to a category
+// @Description  Pins an article as a category's "start here" post. At most two articles may be pinned per category.
+// @Tags         categories
+// @Produce      json
+// @Param        id          path      int  true  "Category ID"
+// @Param        article_id  path      int  true  "Article ID"
+// @Success      200         {object}  utils.Response{data=models.CategoryPin}
+// @Failure      400         {object}  utils.Response
+// @Failure      404         {object}  utils.Response
+// @Failure      409         {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /categories/{id}/pin/{article_id} [post]
+func PinArticleToCategory(c *gin.Context) {
+	categoryID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid category id")
+		return
+	}
+	articleID, err := strconv.ParseUint(c.Param("article_id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid article id")
+		return
+	}
+
+	pin, err := service.PinArticleToCategory(db.DB, uint(categoryID), uint(articleID))
+	if err != nil {
+		switch {
+		case err == service.ErrCategoryNotFound:
+			utils.Fail(c, 404, "category not found")
+		case err == service.ErrArticleNotFound:
+			utils.Fail(c, 404, "article not found")
+		case err == service.ErrCategoryPinLimitReached:
+			utils.Fail(c, 409, err.Error())
+		default:
+			utils.Fail(c, 500, "failed to pin article")
+		}
+		return
+	}
+
+	utils.OK(c, pin)
+}
+
+// UnpinArticleFromCategory removes an article's pin from a category.
+//
+// @Summary      Unpin an article from a category
+// @Description  Removes an article's pin from a category, if it was pinned.
+// @Tags         categories
+// @Produce      json
+// @Param        id          path      int  true  "Category ID"
+// @Param        article_id  path      int  true  "Article ID"
+// @Success      200         {object}  utils.Response
+// @Failure      400         {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /categories/{id}/pin/{article_id} [delete]
+func UnpinArticleFromCategory(c *gin.Context) {
+	categoryID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid category id")
+		return
+	}
+	articleID, err := strconv.ParseUint(c.Param("article_id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid article id")
+		return
+	}
+
+	if err := service.UnpinArticleFromCategory(db.DB, uint(categoryID), uint(articleID)); err != nil {
+		utils.Fail(c, 500, "failed to unpin article")
+		return
+	}
+	utils.OK(c, nil)
+}
+
+// GetTags returns every tag.
+//
+// @Summary      List tags
+// @Description  Returns every tag. Supports If-None-Match for 304 responses.
+// @Tags         tags
+// @Produce      json
+// @Success      200  {object}  utils.Response{data=[]models.Tag}
+// @Router       /tags [get]
+// tagWithFollowers adds the follow feature's follower_count to a Tag
+// response.
+type tagWithFollowers struct {
+	models.Tag
+	FollowersCount int64 `json:"followers_count"`
+}
+
+func GetTags(c *gin.Context) {
+	qdb := db.WithCtx(c.Request.Context())
+	tags, err := service.GetTags(qdb)
+	if err != nil {
+		utils.FailFromDBError(c, err, "failed to load tags")
+		return
+	}
+
+	tagIDs := make([]uint, len(tags))
+	for i, tag := range tags {
+		tagIDs[i] = tag.ID
+	}
+	followerCounts, err := service.GetFollowerCounts(qdb, models.FollowEntityTag, tagIDs)
+	if err != nil {
+		utils.FailFromDBError(c, err, "failed to count tag followers")
+		return
+	}
+
+	result := make([]tagWithFollowers, len(tags))
+	for i, tag := range tags {
+		result[i] = tagWithFollowers{Tag: tag, FollowersCount: followerCounts[tag.ID]}
+	}
+	utils.ConditionalJSON(c, result)
+}
+
+// defaultTagStatsMonths is the window used when the months query
+// parameter is omitted from GET /tags/:id/stats.
+const defaultTagStatsMonths = 12
+
+// GetTagStats returns a monthly series of published-article counts for a
+// tag over the last months months (default 12).
+//
+// @Summary      Get a tag's usage stats over time
+// @Description  Returns a monthly series of published-article counts for a tag over the last N months (default 12). Views and likes aren't tracked historically, so only article counts are covered.
+// @Tags         tags
+// @Produce      json
+// @Param        id      path      int  true   "Tag ID"
+// @Param        months  query     int  false  "Window size in months"
+// @Success      200     {object}  utils.Response{data=[]service.MonthlyTagStat}
+// @Failure      400     {object}  utils.Response
+// @Router       /tags/{id}/stats [get]
+func GetTagStats(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid tag id")
+		return
+	}
+
+	months := defaultTagStatsMonths
+	if v := c.Query("months"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			utils.Fail(c, 400, "invalid months")
+			return
+		}
+		months = n
+	}
+
+	stats, err := service.GetTagMonthlyStats(db.WithCtx(c.Request.Context()), uint(id), months)
+	if err != nil {
+		utils.FailFromDBError(c, err, "failed to load tag stats")
+		return
+	}
+	utils.OK(c, stats)
+}
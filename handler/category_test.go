@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/1055373165/blog/cache"
+	"github.com/1055373165/blog/config"
+	blogdb "github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/middleware"
+	"github.com/1055373165/blog/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupCategoryTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	conn, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	if err := conn.AutoMigrate(&models.Category{}, &models.User{}, &models.AuditLog{}, &models.Follow{}); err != nil {
+		t.Fatalf("failed to migrate test db: %v", err)
+	}
+	blogdb.DB = conn
+	cache.Init(time.Minute)
+	return conn
+}
+
+func categoryRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/api/categories", middleware.OptionalAuth(), GetCategories)
+	return r
+}
+
+func TestGetCategoriesHidesInvisibleCategoryFromNonAdmins(t *testing.T) {
+	db := setupCategoryTestDB(t)
+	config.App = &config.Config{JWTSecret: "test-secret"}
+
+	admin := models.User{Username: "admin", Email: "admin@example.com", Role: models.RoleAdmin}
+	db.Create(&admin)
+
+	db.Create(&models.Category{Name: "Public", Slug: "public", IsVisible: true})
+	db.Create(&models.Category{Name: "Internal", Slug: "internal", IsVisible: false})
+
+	r := categoryRouter()
+
+	cases := []struct {
+		name     string
+		header   string
+		wantBody string
+	}{
+		{"anonymous", "", "public"},
+		{"admin", bearerToken(t, admin.ID, string(models.RoleAdmin)), "internal"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/categories", nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+			}
+			if got := w.Body.String(); !strings.Contains(got, tc.wantBody) {
+				t.Errorf("expected response to contain %q, got %s", tc.wantBody, got)
+			}
+		})
+	}
+}
+
+func TestCreateCategoryDefaultsIsVisibleToTrue(t *testing.T) {
+	setupCategoryTestDB(t)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/categories", CreateCategory)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/categories", strings.NewReader(`{"name":"Guides","slug":"guides","content":"# Guides","cover_image":"/api/files/guides.png"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var created models.Category
+	blogdb.DB.First(&created, "slug = ?", "guides")
+	if !created.IsVisible {
+		t.Error("expected IsVisible to default to true")
+	}
+	if created.Content != "# Guides" || created.CoverImage != "/api/files/guides.png" {
+		t.Errorf("got %+v, want content and cover image persisted", created)
+	}
+}
+
+func TestUpdateCategoryParentIDTriState(t *testing.T) {
+	db := setupCategoryTestDB(t)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.PUT("/api/categories/:id", UpdateCategory)
+
+	parent := models.Category{Name: "Parent", Slug: "parent", IsVisible: true}
+	db.Create(&parent)
+	child := models.Category{Name: "Child", Slug: "child", ParentID: &parent.ID, IsVisible: true}
+	db.Create(&child)
+
+	put := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/api/categories/"+strconv.Itoa(int(child.ID)), strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// Omitted parent_id leaves it unchanged.
+	if w := put(`{"name":"Child Renamed"}`); w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var reloaded models.Category
+	db.First(&reloaded, child.ID)
+	if reloaded.ParentID == nil || *reloaded.ParentID != parent.ID {
+		t.Errorf("expected parent_id to stay %d when omitted, got %v", parent.ID, reloaded.ParentID)
+	}
+
+	// Explicit null clears it.
+	if w := put(`{"parent_id":null}`); w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	db.First(&reloaded, child.ID)
+	if reloaded.ParentID != nil {
+		t.Errorf("expected parent_id cleared, got %v", *reloaded.ParentID)
+	}
+
+	// A real value sets it again.
+	other := models.Category{Name: "Other", Slug: "other", IsVisible: true}
+	db.Create(&other)
+	if w := put(`{"parent_id":` + strconv.Itoa(int(other.ID)) + `}`); w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	db.First(&reloaded, child.ID)
+	if reloaded.ParentID == nil || *reloaded.ParentID != other.ID {
+		t.Errorf("expected parent_id set to %d, got %v", other.ID, reloaded.ParentID)
+	}
+}
+
+func TestCreateCategoryRejectsDuplicateSlugWith409(t *testing.T) {
+	db := setupCategoryTestDB(t)
+	db.Create(&models.Category{Name: "Guides", Slug: "guides", IsVisible: true})
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/categories", CreateCategory)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/categories", strings.NewReader(`{"name":"Guides Again","slug":"guides"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+// Category has no soft-delete field (no gorm.DeletedAt anywhere in this
+// tree), so DeleteCategory already performs a real DELETE and the slug
+// is immediately free - this just pins that behavior down.
+func TestDeleteCategoryFreesSlugForImmediateReuse(t *testing.T) {
+	db := setupCategoryTestDB(t)
+	category := models.Category{Name: "Guides", Slug: "guides", IsVisible: true}
+	db.Create(&category)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.DELETE("/api/categories/:id", DeleteCategory)
+	r.POST("/api/categories", CreateCategory)
+
+	delReq := httptest.NewRequest(http.MethodDelete, "/api/categories/"+strconv.Itoa(int(category.ID)), nil)
+	delW := httptest.NewRecorder()
+	r.ServeHTTP(delW, delReq)
+	if delW.Code != http.StatusOK {
+		t.Fatalf("expected 200 deleting category, got %d: %s", delW.Code, delW.Body.String())
+	}
+
+	createReq := httptest.NewRequest(http.MethodPost, "/api/categories", strings.NewReader(`{"name":"Guides Reborn","slug":"guides"}`))
+	createReq.Header.Set("Content-Type", "application/json")
+	createW := httptest.NewRecorder()
+	r.ServeHTTP(createW, createReq)
+	if createW.Code != http.StatusOK {
+		t.Fatalf("expected 200 recreating the same slug after delete, got %d: %s", createW.Code, createW.Body.String())
+	}
+}
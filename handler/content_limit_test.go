@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/1055373165/blog/config"
+	blogdb "github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/middleware"
+	"github.com/1055373165/blog/models"
+	"github.com/1055373165/blog/siteconfig"
+	"github.com/1055373165/blog/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// setupContentLimitTestDB migrates every model the content-limit tests
+// touch and loads siteconfig with its compiled-in defaults, then lowers
+// content_max_bytes/note_max_bytes to small, test-friendly ceilings so
+// the boundary cases don't require generating megabytes of body text.
+func setupContentLimitTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	conn, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	if err := conn.AutoMigrate(
+		&models.User{}, &models.Article{}, &models.Submission{},
+		&models.SubmissionComment{}, &models.Blog{}, &models.SiteConfig{},
+	); err != nil {
+		t.Fatalf("failed to migrate test db: %v", err)
+	}
+	blogdb.DB = conn
+	config.App = &config.Config{JWTSecret: "test-secret"}
+
+	if err := siteconfig.Load(conn); err != nil {
+		t.Fatalf("failed to load siteconfig: %v", err)
+	}
+	if _, err := siteconfig.Set(conn, siteconfig.KeyContentMaxBytes, []byte("10")); err != nil {
+		t.Fatalf("failed to set content_max_bytes: %v", err)
+	}
+	if _, err := siteconfig.Set(conn, siteconfig.KeyNoteMaxBytes, []byte("5")); err != nil {
+		t.Fatalf("failed to set note_max_bytes: %v", err)
+	}
+	return conn
+}
+
+func assertContentTooLarge(t *testing.T, w *httptest.ResponseRecorder, field string, limit int) {
+	t.Helper()
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), string(utils.ErrCodeContentTooLarge)) {
+		t.Errorf("expected error_code %s in body, got %s", utils.ErrCodeContentTooLarge, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), fmt.Sprintf(`"field":"%s"`, field)) {
+		t.Errorf("expected details field %q in body, got %s", field, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), fmt.Sprintf(`"limit_bytes":%d`, limit)) {
+		t.Errorf("expected limit_bytes %d in body, got %s", limit, w.Body.String())
+	}
+}
+
+func TestCreateArticleAcceptsContentAtTheByteLimitAndRejectsOneByteOver(t *testing.T) {
+	setupContentLimitTestDB(t)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/articles", middleware.RequireAuth(), CreateArticle)
+
+	author := models.User{Username: "author", Email: "author@example.com"}
+	blogdb.DB.Create(&author)
+	token := bearerToken(t, author.ID, string(models.RoleUser))
+
+	atLimit := `{"title":"t","slug":"at-limit","content":"0123456789"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/articles", strings.NewReader(atLimit))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", token)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 for content exactly at the limit, got %d: %s", w.Code, w.Body.String())
+	}
+
+	overLimit := `{"title":"t","slug":"over-limit","content":"01234567890"}`
+	req = httptest.NewRequest(http.MethodPost, "/api/articles", strings.NewReader(overLimit))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", token)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assertContentTooLarge(t, w, "content", 10)
+}
+
+func TestUpdateArticleRejectsContentOverTheByteLimit(t *testing.T) {
+	setupContentLimitTestDB(t)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.PUT("/api/articles/:id", middleware.RequireAuth(), UpdateArticle)
+
+	author := models.User{Username: "author", Email: "author@example.com"}
+	blogdb.DB.Create(&author)
+	article := models.Article{AuthorID: author.ID, Title: "t", Slug: "s", Content: "short"}
+	blogdb.DB.Create(&article)
+
+	req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/articles/%d", article.ID), strings.NewReader(`{"content":"01234567890"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", bearerToken(t, author.ID, string(models.RoleUser)))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assertContentTooLarge(t, w, "content", 10)
+}
+
+func TestCreateSubmissionRejectsContentOverTheByteLimit(t *testing.T) {
+	setupContentLimitTestDB(t)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/submissions", middleware.RequireAuth(), CreateSubmission)
+
+	author := models.User{Username: "author", Email: "author@example.com"}
+	blogdb.DB.Create(&author)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/submissions", strings.NewReader(`{"title":"t","content":"01234567890"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", bearerToken(t, author.ID, string(models.RoleUser)))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assertContentTooLarge(t, w, "content", 10)
+}
+
+func TestReviewSubmissionRejectsNotesOverTheByteLimit(t *testing.T) {
+	setupContentLimitTestDB(t)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/submissions/:id/review", middleware.RequireAuth(), ReviewSubmission)
+
+	author := models.User{Username: "author", Email: "author@example.com"}
+	blogdb.DB.Create(&author)
+	submission := models.Submission{AuthorID: author.ID, Title: "t", Status: models.SubmissionStatusPending}
+	blogdb.DB.Create(&submission)
+
+	reviewer := models.User{Username: "admin", Email: "admin@example.com"}
+	blogdb.DB.Create(&reviewer)
+
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/submissions/%d/review", submission.ID), strings.NewReader(`{"status":"approved","notes":"too long"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", bearerToken(t, reviewer.ID, string(models.RoleAdmin)))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assertContentTooLarge(t, w, "notes", 5)
+}
+
+func TestCreateSubmissionCommentRejectsContentOverTheByteLimit(t *testing.T) {
+	setupContentLimitTestDB(t)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/submissions/:id/comments", middleware.RequireAuth(), CreateSubmissionComment)
+
+	author := models.User{Username: "author", Email: "author@example.com"}
+	blogdb.DB.Create(&author)
+	submission := models.Submission{AuthorID: author.ID, Title: "t", Status: models.SubmissionStatusDraft}
+	blogdb.DB.Create(&submission)
+
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/submissions/%d/comments", submission.ID), strings.NewReader(`{"content":"too long"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", bearerToken(t, author.ID, string(models.RoleUser)))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assertContentTooLarge(t, w, "content", 5)
+}
+
+func TestCreateBlogRejectsTranscriptOverTheByteLimit(t *testing.T) {
+	setupContentLimitTestDB(t)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/blogs", middleware.RequireAuth(), CreateBlog)
+
+	author := models.User{Username: "author", Email: "author@example.com"}
+	blogdb.DB.Create(&author)
+
+	body := `{"title":"t","slug":"s","media_url":"/api/files/a.mp3","duration":1,"transcript":"01234567890"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/blogs", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", bearerToken(t, author.ID, string(models.RoleUser)))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assertContentTooLarge(t, w, "transcript", 10)
+}
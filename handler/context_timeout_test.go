@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	blogdb "github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// slowCountQuery takes long enough in SQLite to observe the handler
+// returning before it finishes, without needing a SLEEP builtin (SQLite
+// has none).
+const slowCountQuery = "WITH RECURSIVE cnt(x) AS (SELECT 1 UNION ALL SELECT x+1 FROM cnt WHERE x<500000000) SELECT count(*) FROM cnt"
+
+// slowQueryHandler exercises the same db.WithCtx/utils.FailFromDBError
+// pattern every migrated handler in this package uses, against a query
+// slow enough to still be running when the client disconnects.
+func slowQueryHandler(c *gin.Context) {
+	var n int
+	err := blogdb.WithCtx(c.Request.Context()).Raw(slowCountQuery).Scan(&n).Error
+	if err != nil {
+		utils.FailFromDBError(c, err, "failed to load count")
+		return
+	}
+	utils.OK(c, n)
+}
+
+func TestHandlerReturns499PromptlyWhenRequestContextIsCanceled(t *testing.T) {
+	conn, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	blogdb.DB = conn
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/slow", slowQueryHandler)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodGet, "/slow", nil).WithContext(ctx)
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+
+	start := time.Now()
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	elapsed := time.Since(start)
+
+	if w.Code != 499 {
+		t.Fatalf("got status %d, want 499: %s", w.Code, w.Body.String())
+	}
+	if elapsed > 2*time.Second {
+		t.Errorf("expected the handler to return promptly after the client disconnected, took %v", elapsed)
+	}
+}
@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/1055373165/blog/config"
+	"github.com/1055373165/blog/covercrop"
+	"github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/models"
+	"github.com/1055373165/blog/service"
+	"github.com/1055373165/blog/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// coverCropCacheDir is where generated cover crop PNGs are cached, under
+// the configured upload directory.
+const coverCropCacheDir = "cover-cache"
+
+// defaultCoverCropWidth and defaultCoverCropHeight are used when w/h
+// aren't given, matching a common card thumbnail size.
+const (
+	defaultCoverCropWidth  = 800
+	defaultCoverCropHeight = 450
+)
+
+// maxCoverCropDimension bounds w/h to keep a generated crop request
+// cheap; it's well above any size this site's templates actually use.
+const maxCoverCropDimension = 4000
+
+// GetArticleCover renders a focal-point crop of an article's cover image
+// at the requested size, caching the output keyed by article ID,
+// updated_at, and dimensions so it regenerates only when the article or
+// the request changes.
+//
+// @Summary      Get an article's cover image crop
+// @Description  Renders (or serves a cached) focal-point crop of an article's cover image, scaled to w x h (default 800x450).
+// @Tags         articles
+// @Produce      image/png
+// @Param        id  path      int  true   "Article ID"
+// @Param        w   query     int  false  "Crop width in pixels"
+// @Param        h   query     int  false  "Crop height in pixels"
+// @Success      200  {file}    file
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Router       /articles/{id}/cover [get]
+func GetArticleCover(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid article id")
+		return
+	}
+
+	width, height, err := parseCoverCropSize(c)
+	if err != nil {
+		utils.Fail(c, 400, err.Error())
+		return
+	}
+
+	var article models.Article
+	if err := db.DB.First(&article, id).Error; err != nil {
+		utils.Fail(c, 404, "article not found")
+		return
+	}
+	if article.CoverImage == "" {
+		utils.Fail(c, 404, "article has no cover image")
+		return
+	}
+
+	path := coverCropCachePath(article, width, height)
+	if _, err := os.Stat(path); err != nil {
+		srcPath, err := service.ResolveCoverImagePath(article.CoverImage)
+		if err != nil {
+			utils.Fail(c, 404, err.Error())
+			return
+		}
+		if err := generateCoverCrop(srcPath, path, article, width, height); err != nil {
+			utils.Fail(c, 500, "failed to generate cover crop")
+			return
+		}
+	}
+
+	c.Header("Content-Type", "image/png")
+	http.ServeFile(c.Writer, c.Request, path)
+}
+
+// parseCoverCropSize reads and validates the w/h query params, defaulting
+// to defaultCoverCropWidth x defaultCoverCropHeight.
+func parseCoverCropSize(c *gin.Context) (width, height int, err error) {
+	width, height = defaultCoverCropWidth, defaultCoverCropHeight
+	if v := c.Query("w"); v != "" {
+		width, err = strconv.Atoi(v)
+		if err != nil || width < 1 || width > maxCoverCropDimension {
+			return 0, 0, fmt.Errorf("w must be an integer between 1 and %d", maxCoverCropDimension)
+		}
+	}
+	if v := c.Query("h"); v != "" {
+		height, err = strconv.Atoi(v)
+		if err != nil || height < 1 || height > maxCoverCropDimension {
+			return 0, 0, fmt.Errorf("h must be an integer between 1 and %d", maxCoverCropDimension)
+		}
+	}
+	return width, height, nil
+}
+
+// coverCropCachePath returns where article's generated cover crop PNG at
+// width x height is (or would be) cached. It's keyed by ID, updated_at,
+// and the requested dimensions so an edit to the article - or a
+// different size request - invalidates the cache by simply changing the
+// path.
+func coverCropCachePath(article models.Article, width, height int) string {
+	name := fmt.Sprintf("%d-%d-%dx%d.png", article.ID, article.UpdatedAt.UnixNano(), width, height)
+	return filepath.Join(config.App.UploadDir, coverCropCacheDir, name)
+}
+
+// generateCoverCrop decodes srcPath, crops it around article's focal
+// point to width x height, and writes the result as a PNG to destPath.
+func generateCoverCrop(srcPath, destPath string, article models.Article, width, height int) error {
+	f, err := os.Open(srcPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	src, _, err := image.Decode(f)
+	if err != nil {
+		return err
+	}
+	cropped := covercrop.Crop(src, article.CoverFocalX, article.CoverFocalY, width, height)
+
+	if err := os.MkdirAll(filepath.Dir(destPath), 0o755); err != nil {
+		return err
+	}
+	out, err := os.Create(destPath)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	return png.Encode(out, cropped)
+}
+
+// GetBrokenCoverReport lists every article whose cover_image can no
+// longer be resolved in upload storage - e.g. the file was deleted, or
+// BLOG_UPLOAD_DIR changed - for manual cleanup.
+//
+// @Summary      Scan for broken article covers
+// @Description  Scans every article with a cover_image and lists those whose file is missing from upload storage. Admin only.
+// @Tags         admin
+// @Produce      json
+// @Success      200  {object}  utils.Response{data=[]service.BrokenCover}
+// @Failure      500  {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /admin/covers/broken [get]
+func GetBrokenCoverReport(c *gin.Context) {
+	broken, err := service.GetArticlesWithBrokenCovers(db.DB)
+	if err != nil {
+		utils.Fail(c, 500, "failed to scan for broken covers")
+		return
+	}
+	utils.OK(c, broken)
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/1055373165/blog/service"
+	"github.com/1055373165/blog/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// parseOptionalDateRange reads the from/to/compare query params shared by
+// GetPopularArticles, GetStats, and GetSearchStats. from/to follow
+// GetEditorialCalendar's YYYY-MM-DD convention, with to extended to the
+// end of its day. Neither param given is a valid "no window" request:
+// hasRange is false and ok is true, so the caller falls back to its
+// existing unwindowed behavior. Either one given without the other, or an
+// invalid/inverted/too-large range, writes a 400 and returns ok=false.
+func parseOptionalDateRange(c *gin.Context) (r service.DateRange, hasRange bool, compare bool, ok bool) {
+	fromStr, toStr := c.Query("from"), c.Query("to")
+	if fromStr == "" && toStr == "" {
+		return service.DateRange{}, false, false, true
+	}
+	if fromStr == "" || toStr == "" {
+		utils.Fail(c, 400, "from and to must both be given")
+		return service.DateRange{}, false, false, false
+	}
+
+	from, err := time.Parse("2006-01-02", fromStr)
+	if err != nil {
+		utils.Fail(c, 400, "invalid from, expected YYYY-MM-DD")
+		return service.DateRange{}, false, false, false
+	}
+	to, err := time.Parse("2006-01-02", toStr)
+	if err != nil {
+		utils.Fail(c, 400, "invalid to, expected YYYY-MM-DD")
+		return service.DateRange{}, false, false, false
+	}
+	to = to.Add(24*time.Hour - time.Nanosecond)
+
+	dr, err := service.NewDateRange(from, to)
+	if err != nil {
+		switch err {
+		case service.ErrDateRangeInvalid, service.ErrDateRangeTooLarge:
+			utils.Fail(c, 400, err.Error())
+		default:
+			utils.Fail(c, 500, "failed to validate date range")
+		}
+		return service.DateRange{}, false, false, false
+	}
+
+	return dr, true, c.Query("compare") == "previous", true
+}
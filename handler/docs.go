@@ -0,0 +1,19 @@
+package handler
+
+import (
+	"github.com/1055373165/blog/docs"
+	"github.com/gin-gonic/gin"
+)
+
+// GetOpenAPISpec serves the generated OpenAPI 3 document backing the
+// Swagger UI at /api/docs.
+//
+// @Summary      Get the OpenAPI specification
+// @Description  Serves the generated OpenAPI 3 document backing the Swagger UI at /api/docs.
+// @Tags         docs
+// @Produce      json
+// @Success      200  {object}  object
+// @Router       /docs/openapi.json [get]
+func GetOpenAPISpec(c *gin.Context) {
+	c.Data(200, "application/json; charset=utf-8", docs.OpenAPISpec)
+}
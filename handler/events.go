@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/1055373165/blog/events"
+	"github.com/gin-gonic/gin"
+)
+
+// heartbeatInterval is how often AdminEvents writes an SSE comment to keep
+// the connection alive through proxies that close idle streams.
+const heartbeatInterval = 30 * time.Second
+
+// AdminEvents streams events.Default's published events to the caller as
+// Server-Sent Events, for a live admin notifications feed. Authenticates via
+// ?token= rather than the Authorization header since browsers' EventSource
+// cannot set custom headers.
+//
+// @Summary      Stream admin events
+// @Description  Streams published events (e.g. submission.created) as Server-Sent Events until the client disconnects. Admin only.
+// @Tags         admin
+// @Produce      text/event-stream
+// @Param        token  query  string  true  "Admin JWT"
+// @Success      200
+// @Failure      401  {object}  utils.Response
+// @Failure      403  {object}  utils.Response
+// @Router       /admin/events [get]
+func AdminEvents(c *gin.Context) {
+	ch, unsubscribe := events.Default.Subscribe()
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event := <-ch:
+			data, err := json.Marshal(event.Data)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(c.Writer, "event: %s\ndata: %s\n\n", event.Name, data)
+			c.Writer.Flush()
+		case <-ticker.C:
+			fmt.Fprint(c.Writer, ": heartbeat\n\n")
+			c.Writer.Flush()
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/1055373165/blog/events"
+	"github.com/gin-gonic/gin"
+)
+
+func TestAdminEventsStreamsPublishedEvent(t *testing.T) {
+	events.Init()
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	req := httptest.NewRequest("GET", "/api/admin/events", nil).WithContext(ctx)
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	done := make(chan struct{})
+	go func() {
+		AdminEvents(c)
+		close(done)
+	}()
+
+	// give AdminEvents a moment to subscribe before publishing.
+	time.Sleep(10 * time.Millisecond)
+	events.Default.Publish(events.Event{Name: "submission.created", Data: map[string]any{"id": 1}})
+	time.Sleep(10 * time.Millisecond)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("AdminEvents did not return after context cancellation")
+	}
+
+	body := w.Body.String()
+	if !strings.Contains(body, "event: submission.created") {
+		t.Fatalf("expected stream to contain the published event, got %q", body)
+	}
+	if !strings.Contains(body, `"id":1`) {
+		t.Fatalf("expected stream to contain the event payload, got %q", body)
+	}
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"path/filepath"
+
+	"github.com/1055373165/blog/config"
+	"github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/export"
+	"github.com/1055373165/blog/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// StartStaticExport kicks off a background job that renders the entire
+// public site to static HTML, packaged as a downloadable tar.gz. Only
+// one export may run at a time; poll GetStaticExportStatus for progress
+// and the download link.
+//
+// @Summary      Start a static site export
+// @Description  Kicks off a background job rendering the entire public site to static HTML and packaging it as a tar.gz. Admin only.
+// @Tags         admin
+// @Produce      json
+// @Success      200  {object}  utils.Response{data=export.Progress}
+// @Failure      409  {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /admin/export/static [post]
+func StartStaticExport(c *gin.Context) {
+	if err := export.Default.Start(db.DB, config.App.ExportDir, config.App.UploadDir); err != nil {
+		utils.Fail(c, 409, "an export is already running")
+		return
+	}
+	utils.OK(c, export.Default.Status())
+}
+
+// GetStaticExportStatus reports the current or most recently finished
+// static export's progress.
+//
+// @Summary      Get static export status
+// @Description  Returns the current or most recently finished static export's progress (pages done/total, and the download link once finished). Admin only.
+// @Tags         admin
+// @Produce      json
+// @Success      200  {object}  utils.Response{data=export.Progress}
+// @Security     BearerAuth
+// @Router       /admin/export/static/status [get]
+func GetStaticExportStatus(c *gin.Context) {
+	utils.OK(c, export.Default.Status())
+}
+
+// CancelStaticExport cancels the in-progress static export, if any.
+//
+// @Summary      Cancel the running static export
+// @Description  Cancels the in-progress static export. Admin only.
+// @Tags         admin
+// @Produce      json
+// @Success      200  {object}  utils.Response
+// @Failure      409  {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /admin/export/static/cancel [post]
+func CancelStaticExport(c *gin.Context) {
+	if err := export.Default.Cancel(); err != nil {
+		utils.Fail(c, 409, "no export is running")
+		return
+	}
+	utils.OK(c, nil)
+}
+
+// DownloadStaticExport streams a finished export's tar.gz.
+//
+// @Summary      Download the finished static export
+// @Description  Streams the most recently finished export's tar.gz. Admin only.
+// @Tags         admin
+// @Produce      application/gzip
+// @Success      200  {file}    file
+// @Failure      404  {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /admin/export/static/download [get]
+func DownloadStaticExport(c *gin.Context) {
+	progress := export.Default.Status()
+	if progress.Status != export.StatusDone || progress.DownloadName == "" {
+		utils.Fail(c, 404, "no finished export available")
+		return
+	}
+	c.FileAttachment(filepath.Join(config.App.ExportDir, progress.DownloadName), progress.DownloadName)
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/1055373165/blog/config"
+	"github.com/gin-gonic/gin"
+)
+
+func TestGetFileRangeRequest(t *testing.T) {
+	gin := setupTestRouter(t)
+
+	content := []byte("0123456789")
+	if err := os.WriteFile(filepath.Join(config.App.UploadDir, "sample.mp3"), content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/files/sample.mp3", nil)
+	req.Header.Set("Range", "bytes=2-5")
+	w := httptest.NewRecorder()
+	gin.ServeHTTP(w, req)
+
+	if w.Code != http.StatusPartialContent {
+		t.Fatalf("expected 206 Partial Content, got %d", w.Code)
+	}
+	if got := w.Body.String(); got != "2345" {
+		t.Errorf("expected partial body %q, got %q", "2345", got)
+	}
+	if disp := w.Header().Get("Content-Disposition"); disp == "" || disp[:6] != "inline" {
+		t.Errorf("expected inline disposition for audio, got %q", disp)
+	}
+	if got := w.Header().Get("X-Content-Type-Options"); got != "nosniff" {
+		t.Errorf("expected X-Content-Type-Options: nosniff, got %q", got)
+	}
+}
+
+func TestGetFileRejectsTraversal(t *testing.T) {
+	gin := setupTestRouter(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/files/..", nil)
+	w := httptest.NewRecorder()
+	gin.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 Forbidden, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestGetFileRejectsSymlinkEscapingUploadDir(t *testing.T) {
+	gin, dir := setupTestRouterWithDir(t)
+
+	outside := t.TempDir()
+	secret := filepath.Join(outside, "secret.txt")
+	if err := os.WriteFile(secret, []byte("top secret"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "escape.txt")
+	if err := os.Symlink(secret, link); err != nil {
+		t.Skipf("symlinks not supported in this environment: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/files/escape.txt", nil)
+	w := httptest.NewRecorder()
+	gin.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 Forbidden, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestGetMediaServesFileFromUploadDir(t *testing.T) {
+	gin, dir := setupTestRouterWithDir(t)
+
+	if err := os.WriteFile(filepath.Join(dir, "cover.png"), []byte("png-bytes"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/media/cover.png", nil)
+	w := httptest.NewRecorder()
+	gin.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 OK, got %d: %s", w.Code, w.Body.String())
+	}
+	if w.Body.String() != "png-bytes" {
+		t.Errorf("got body %q, want %q", w.Body.String(), "png-bytes")
+	}
+}
+
+func TestGetFileNotFoundForMissingFile(t *testing.T) {
+	gin := setupTestRouter(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/files/does-not-exist.txt", nil)
+	w := httptest.NewRecorder()
+	gin.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 Not Found, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func setupTestRouterWithDir(t *testing.T) (http.Handler, string) {
+	t.Helper()
+	dir := t.TempDir()
+	config.App = &config.Config{UploadDir: dir, JWTSecret: "test-secret"}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/api/files/:filename", GetFile)
+	r.GET("/api/media/:filename", GetMedia)
+	return r, dir
+}
+
+func setupTestRouter(t *testing.T) http.Handler {
+	t.Helper()
+	r, _ := setupTestRouterWithDir(t)
+	return r
+}
@@ -0,0 +1,234 @@
+package handler
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/middleware"
+	"github.com/1055373165/blog/models"
+	"github.com/1055373165/blog/service"
+	"github.com/1055373165/blog/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateFlashcardRequest is the payload for POST /api/study/items/:item_id/cards.
+type CreateFlashcardRequest struct {
+	Front        string `json:"front" binding:"required"`
+	Back         string `json:"back" binding:"required"`
+	SourceAnchor string `json:"source_anchor"`
+}
+
+func failFlashcardErr(c *gin.Context, err error, fallback string) {
+	switch err {
+	case service.ErrStudyItemNotFound:
+		utils.Fail(c, 404, "study item not found")
+	case service.ErrFlashcardNotFound:
+		utils.Fail(c, 404, "flashcard not found")
+	default:
+		utils.Fail(c, 500, fallback)
+	}
+}
+
+// CreateFlashcard adds a new flashcard under a study item owned by the
+// caller.
+//
+// @Summary      Create a flashcard
+// @Description  Adds a new flashcard under a study item owned by the caller.
+// @Tags         study
+// @Accept       json
+// @Produce      json
+// @Param        item_id  path      int                      true  "Study item ID"
+// @Param        request  body      CreateFlashcardRequest  true  "Flashcard fields"
+// @Success      200      {object}  utils.Response{data=models.Flashcard}
+// @Failure      400      {object}  utils.Response{details=[]utils.FieldError}
+// @Failure      404      {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /study/items/{item_id}/cards [post]
+func CreateFlashcard(c *gin.Context) {
+	itemID, err := strconv.ParseUint(c.Param("item_id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid study item id")
+		return
+	}
+
+	var req CreateFlashcardRequest
+	if !utils.BindJSON(c, &req) {
+		return
+	}
+
+	card := models.Flashcard{Front: req.Front, Back: req.Back, SourceAnchor: req.SourceAnchor}
+	if err := service.CreateFlashcard(db.DB, uint(itemID), middleware.CurrentUserID(c), &card); err != nil {
+		failFlashcardErr(c, err, "failed to create flashcard")
+		return
+	}
+	utils.OK(c, card)
+}
+
+// GetItemFlashcards lists the flashcards under a study item owned by the
+// caller.
+//
+// @Summary      List a study item's flashcards
+// @Description  Returns the flashcards under a study item owned by the caller.
+// @Tags         study
+// @Produce      json
+// @Param        item_id  path      int  true  "Study item ID"
+// @Success      200      {object}  utils.Response{data=[]models.Flashcard}
+// @Failure      404      {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /study/items/{item_id}/cards [get]
+func GetItemFlashcards(c *gin.Context) {
+	itemID, err := strconv.ParseUint(c.Param("item_id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid study item id")
+		return
+	}
+
+	cards, err := service.GetItemFlashcards(db.DB, uint(itemID), middleware.CurrentUserID(c))
+	if err != nil {
+		failFlashcardErr(c, err, "failed to load flashcards")
+		return
+	}
+	utils.OK(c, cards)
+}
+
+// UpdateFlashcardRequest is the payload for PUT
+// /api/study/items/:item_id/cards/:card_id.
+type UpdateFlashcardRequest struct {
+	Front        *string `json:"front"`
+	Back         *string `json:"back"`
+	SourceAnchor *string `json:"source_anchor"`
+}
+
+// UpdateFlashcard updates the caller's flashcard's front/back/source_anchor.
+//
+// @Summary      Update a flashcard
+// @Description  Updates the front, back, and/or source_anchor of the caller's flashcard.
+// @Tags         study
+// @Accept       json
+// @Produce      json
+// @Param        item_id  path      int                      true  "Study item ID"
+// @Param        card_id  path      int                      true  "Flashcard ID"
+// @Param        request  body      UpdateFlashcardRequest  true  "Fields to update"
+// @Success      200      {object}  utils.Response{data=models.Flashcard}
+// @Failure      400      {object}  utils.Response{details=[]utils.FieldError}
+// @Failure      404      {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /study/items/{item_id}/cards/{card_id} [put]
+func UpdateFlashcard(c *gin.Context) {
+	cardID, err := strconv.ParseUint(c.Param("card_id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid flashcard id")
+		return
+	}
+
+	var req UpdateFlashcardRequest
+	if !utils.BindJSON(c, &req) {
+		return
+	}
+
+	updates := map[string]any{}
+	if req.Front != nil {
+		updates["front"] = *req.Front
+	}
+	if req.Back != nil {
+		updates["back"] = *req.Back
+	}
+	if req.SourceAnchor != nil {
+		updates["source_anchor"] = *req.SourceAnchor
+	}
+
+	card, err := service.UpdateFlashcard(db.DB, uint(cardID), middleware.CurrentUserID(c), updates)
+	if err != nil {
+		failFlashcardErr(c, err, "failed to update flashcard")
+		return
+	}
+	utils.OK(c, card)
+}
+
+// DeleteFlashcard deletes the caller's flashcard.
+//
+// @Summary      Delete a flashcard
+// @Description  Deletes the caller's flashcard.
+// @Tags         study
+// @Produce      json
+// @Param        item_id  path      int  true  "Study item ID"
+// @Param        card_id  path      int  true  "Flashcard ID"
+// @Success      200      {object}  utils.Response
+// @Failure      404      {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /study/items/{item_id}/cards/{card_id} [delete]
+func DeleteFlashcard(c *gin.Context) {
+	cardID, err := strconv.ParseUint(c.Param("card_id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid flashcard id")
+		return
+	}
+
+	if err := service.DeleteFlashcard(db.DB, uint(cardID), middleware.CurrentUserID(c)); err != nil {
+		failFlashcardErr(c, err, "failed to delete flashcard")
+		return
+	}
+	utils.OK(c, nil)
+}
+
+// GetDueFlashcards returns the caller's due flashcards across all of their
+// study plans.
+//
+// @Summary      List due flashcards
+// @Description  Returns the caller's due flashcards across all of their study plans.
+// @Tags         study
+// @Produce      json
+// @Success      200  {object}  utils.Response{data=[]models.Flashcard}
+// @Security     BearerAuth
+// @Router       /study/due-cards [get]
+func GetDueFlashcards(c *gin.Context) {
+	cards, err := service.GetDueFlashcards(db.DB, middleware.CurrentUserID(c), time.Now())
+	if err != nil {
+		utils.Fail(c, 500, "failed to load due flashcards")
+		return
+	}
+	utils.OK(c, cards)
+}
+
+// ReviewFlashcardRequest is the payload for POST /api/study/cards/:id/review.
+type ReviewFlashcardRequest struct {
+	Rating         int `json:"rating" binding:"min=0,max=5"`
+	DurationSecond int `json:"duration_seconds" binding:"min=0"`
+}
+
+// ReviewFlashcard records a review of the caller's flashcard and advances
+// its schedule. Lighter weight than a study item review: just the rating
+// and elapsed time, with no separate log entry.
+//
+// @Summary      Review a flashcard
+// @Description  Records a review of the caller's flashcard (rating and elapsed time) and advances its interval/ease/next_review_at.
+// @Tags         study
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                       true  "Flashcard ID"
+// @Param        request  body      ReviewFlashcardRequest  true  "Review rating"
+// @Success      200      {object}  utils.Response{data=models.Flashcard}
+// @Failure      400      {object}  utils.Response{details=[]utils.FieldError}
+// @Failure      404      {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /study/cards/{id}/review [post]
+func ReviewFlashcard(c *gin.Context) {
+	cardID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid flashcard id")
+		return
+	}
+
+	var req ReviewFlashcardRequest
+	if !utils.BindJSON(c, &req) {
+		return
+	}
+
+	card, err := service.ReviewFlashcard(db.DB, uint(cardID), middleware.CurrentUserID(c), req.Rating, time.Now())
+	if err != nil {
+		failFlashcardErr(c, err, "failed to review flashcard")
+		return
+	}
+	utils.OK(c, card)
+}
@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/middleware"
+	"github.com/1055373165/blog/models"
+	"github.com/1055373165/blog/service"
+	"github.com/1055373165/blog/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// defaultFeedPageSize and maxFeedPageSize bound the page_size query
+// parameter for GET /users/me/feed, mirroring defaultFavoritesPageSize.
+const (
+	defaultFeedPageSize = 20
+	maxFeedPageSize     = 100
+)
+
+// toggleFollow is shared by ToggleTagFollow/ToggleCategoryFollow/
+// ToggleSeriesFollow/ToggleAuthorFollow, which differ only in which
+// models.FollowEntityType they pass, mirroring ToggleArticleReaction/
+// ToggleBlogReaction's shared helper.
+func toggleFollow(c *gin.Context, entityType models.FollowEntityType) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid id")
+		return
+	}
+
+	followed, err := service.ToggleFollow(db.DB, middleware.CurrentUserID(c), entityType, uint(id))
+	if err != nil {
+		if err == service.ErrInvalidFollowEntity {
+			utils.Fail(c, 404, err.Error())
+			return
+		}
+		utils.Fail(c, 500, "failed to toggle follow")
+		return
+	}
+	utils.OK(c, gin.H{"followed": followed})
+}
+
+// ToggleTagFollow toggles the caller's follow of a tag.
+//
+// @Summary      Toggle following a tag
+// @Description  Toggles the caller's follow of a tag; followed articles appear in GET /users/me/feed.
+// @Tags         tags
+// @Produce      json
+// @Param        id  path      int  true  "Tag ID"
+// @Success      200  {object}  utils.Response{data=object{followed=bool}}
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /tags/{id}/follow [post]
+func ToggleTagFollow(c *gin.Context) { toggleFollow(c, models.FollowEntityTag) }
+
+// ToggleCategoryFollow toggles the caller's follow of a category.
+//
+// @Summary      Toggle following a category
+// @Description  Toggles the caller's follow of a category; followed articles appear in GET /users/me/feed. Matching is approximated via CategoryPin, since articles have no direct category assignment in this schema.
+// @Tags         categories
+// @Produce      json
+// @Param        id  path      int  true  "Category ID"
+// @Success      200  {object}  utils.Response{data=object{followed=bool}}
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /categories/{id}/follow [post]
+func ToggleCategoryFollow(c *gin.Context) { toggleFollow(c, models.FollowEntityCategory) }
+
+// ToggleSeriesFollow toggles the caller's follow of a series.
+//
+// @Summary      Toggle following a series
+// @Description  Toggles the caller's follow of a series; followed articles appear in GET /users/me/feed.
+// @Tags         series
+// @Produce      json
+// @Param        id  path      int  true  "Series ID"
+// @Success      200  {object}  utils.Response{data=object{followed=bool}}
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /series/{id}/follow [post]
+func ToggleSeriesFollow(c *gin.Context) { toggleFollow(c, models.FollowEntitySeries) }
+
+// ToggleAuthorFollow toggles the caller's follow of another user's
+// authored articles.
+//
+// @Summary      Toggle following an author
+// @Description  Toggles the caller's follow of a user as an author; followed articles appear in GET /users/me/feed.
+// @Tags         users
+// @Produce      json
+// @Param        id  path      int  true  "User ID"
+// @Success      200  {object}  utils.Response{data=object{followed=bool}}
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /users/{id}/follow [post]
+func ToggleAuthorFollow(c *gin.Context) { toggleFollow(c, models.FollowEntityAuthor) }
+
+// GetMyFollows returns every entity the caller follows.
+//
+// @Summary      List my follows
+// @Description  Returns every tag, category, series, and author the caller follows.
+// @Tags         users
+// @Produce      json
+// @Success      200  {object}  utils.Response{data=[]models.Follow}
+// @Security     BearerAuth
+// @Router       /users/me/follows [get]
+func GetMyFollows(c *gin.Context) {
+	follows, err := service.GetUserFollows(db.DB, middleware.CurrentUserID(c))
+	if err != nil {
+		utils.Fail(c, 500, "failed to load follows")
+		return
+	}
+	utils.OK(c, follows)
+}
+
+// GetMyFeed returns published articles matching anything the caller
+// follows, newest-published first, paginated.
+//
+// @Summary      Get my personalized feed
+// @Description  Returns published articles matching any tag, category, series, or author the caller follows, deduplicated, newest-published first, paginated.
+// @Tags         users
+// @Produce      json
+// @Param        page       query     int  false  "Page number, starting at 1"
+// @Param        page_size  query     int  false  "Articles per page, max 100"
+// @Success      200        {object}  utils.Response{data=[]models.Article}
+// @Failure      400        {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /users/me/feed [get]
+func GetMyFeed(c *gin.Context) {
+	page := 1
+	if v := c.Query("page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			utils.Fail(c, 400, "invalid page")
+			return
+		}
+		page = n
+	}
+	pageSize := defaultFeedPageSize
+	if v := c.Query("page_size"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxFeedPageSize {
+			utils.Fail(c, 400, "invalid page_size")
+			return
+		}
+		pageSize = n
+	}
+
+	articles, err := service.GetFeedArticles(db.DB, middleware.CurrentUserID(c), page, pageSize)
+	if err != nil {
+		utils.Fail(c, 500, "failed to load feed")
+		return
+	}
+	utils.OK(c, articles)
+}
@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/1055373165/blog/config"
+	blogdb "github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/middleware"
+	"github.com/1055373165/blog/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupFollowTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	conn, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	if err := conn.AutoMigrate(&models.Follow{}, &models.Tag{}, &models.Category{}, &models.Series{}, &models.Article{}, &models.User{}, &models.CategoryPin{}); err != nil {
+		t.Fatalf("failed to migrate test db: %v", err)
+	}
+	blogdb.DB = conn
+	return conn
+}
+
+func followRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/tags/:id/follow", middleware.RequireAuth(), ToggleTagFollow)
+	r.POST("/api/categories/:id/follow", middleware.RequireAuth(), ToggleCategoryFollow)
+	r.POST("/api/series/:id/follow", middleware.RequireAuth(), ToggleSeriesFollow)
+	r.POST("/api/users/:id/follow", middleware.RequireAuth(), ToggleAuthorFollow)
+	r.GET("/api/users/me/follows", middleware.RequireAuth(), GetMyFollows)
+	r.GET("/api/users/me/feed", middleware.RequireAuth(), GetMyFeed)
+	return r
+}
+
+func TestToggleTagFollowTogglesOnThenOff(t *testing.T) {
+	db := setupFollowTestDB(t)
+	config.App = &config.Config{JWTSecret: "test-secret"}
+
+	user := models.User{Username: "reader", Email: "reader@example.com"}
+	db.Create(&user)
+	tag := models.Tag{Name: "Go", Slug: "go"}
+	db.Create(&tag)
+
+	r := followRouter()
+	path := "/api/tags/" + strconv.FormatUint(uint64(tag.ID), 10) + "/follow"
+
+	req := httptest.NewRequest(http.MethodPost, path, nil)
+	req.Header.Set("Authorization", bearerToken(t, user.ID, string(models.RoleUser)))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"followed":true`) {
+		t.Errorf("expected followed=true on first toggle, got %s", w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodPost, path, nil)
+	req.Header.Set("Authorization", bearerToken(t, user.ID, string(models.RoleUser)))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if !strings.Contains(w.Body.String(), `"followed":false`) {
+		t.Errorf("expected followed=false on second toggle, got %s", w.Body.String())
+	}
+}
+
+func TestToggleCategoryFollowRejectsNonexistentCategoryWith404(t *testing.T) {
+	db := setupFollowTestDB(t)
+	config.App = &config.Config{JWTSecret: "test-secret"}
+
+	user := models.User{Username: "reader", Email: "reader@example.com"}
+	db.Create(&user)
+
+	r := followRouter()
+	req := httptest.NewRequest(http.MethodPost, "/api/categories/999/follow", nil)
+	req.Header.Set("Authorization", bearerToken(t, user.ID, string(models.RoleUser)))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != 404 {
+		t.Fatalf("expected 404, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestGetMyFollowsReturnsEverythingTheCallerFollows(t *testing.T) {
+	db := setupFollowTestDB(t)
+	config.App = &config.Config{JWTSecret: "test-secret"}
+
+	user := models.User{Username: "reader", Email: "reader@example.com"}
+	db.Create(&user)
+	tag := models.Tag{Name: "Go", Slug: "go"}
+	db.Create(&tag)
+	series := models.Series{Title: "chapters", Slug: "chapters"}
+	db.Create(&series)
+
+	r := followRouter()
+	for _, path := range []string{
+		"/api/tags/" + strconv.FormatUint(uint64(tag.ID), 10) + "/follow",
+		"/api/series/" + strconv.FormatUint(uint64(series.ID), 10) + "/follow",
+	} {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.Header.Set("Authorization", bearerToken(t, user.ID, string(models.RoleUser)))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != 200 {
+			t.Fatalf("expected 200 following %s, got %d: %s", path, w.Code, w.Body.String())
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/users/me/follows", nil)
+	req.Header.Set("Authorization", bearerToken(t, user.ID, string(models.RoleUser)))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"entity_type":"tag"`) || !strings.Contains(w.Body.String(), `"entity_type":"series"`) {
+		t.Errorf("expected both follows listed, got %s", w.Body.String())
+	}
+}
+
+func TestGetMyFeedReturnsPublishedArticlesMatchingFollowedTag(t *testing.T) {
+	db := setupFollowTestDB(t)
+	config.App = &config.Config{JWTSecret: "test-secret"}
+
+	user := models.User{Username: "reader", Email: "reader@example.com"}
+	db.Create(&user)
+	author := models.User{Username: "author", Email: "author@example.com"}
+	db.Create(&author)
+	tag := models.Tag{Name: "Go", Slug: "go"}
+	db.Create(&tag)
+	matching := models.Article{AuthorID: author.ID, Title: "matching", Slug: "matching", Status: models.ArticleStatusPublished, Tags: []models.Tag{tag}}
+	db.Create(&matching)
+	unrelated := models.Article{AuthorID: author.ID, Title: "unrelated", Slug: "unrelated", Status: models.ArticleStatusPublished}
+	db.Create(&unrelated)
+
+	r := followRouter()
+	followPath := "/api/tags/" + strconv.FormatUint(uint64(tag.ID), 10) + "/follow"
+	req := httptest.NewRequest(http.MethodPost, followPath, nil)
+	req.Header.Set("Authorization", bearerToken(t, user.ID, string(models.RoleUser)))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected 200 following tag, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/users/me/feed", nil)
+	req.Header.Set("Authorization", bearerToken(t, user.ID, string(models.RoleUser)))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"matching"`) {
+		t.Errorf("expected matching article in feed, got %s", w.Body.String())
+	}
+	if strings.Contains(w.Body.String(), `"unrelated"`) {
+		t.Errorf("expected unrelated article excluded from feed, got %s", w.Body.String())
+	}
+}
+
+func TestGetMyFeedRejectsInvalidPageSize(t *testing.T) {
+	db := setupFollowTestDB(t)
+	config.App = &config.Config{JWTSecret: "test-secret"}
+
+	user := models.User{Username: "reader", Email: "reader@example.com"}
+	db.Create(&user)
+
+	r := followRouter()
+	req := httptest.NewRequest(http.MethodGet, "/api/users/me/feed?page_size=1000", nil)
+	req.Header.Set("Authorization", bearerToken(t, user.ID, string(models.RoleUser)))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != 400 {
+		t.Fatalf("expected 400, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,262 @@
+package handler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/1055373165/blog/config"
+	"github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/search"
+	"github.com/1055373165/blog/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// ComponentStatus is the health of a single dependency, as computed
+// internally. It always carries the error text; callers that expose it
+// publicly must sanitize via publicStatus first.
+type ComponentStatus struct {
+	Status    string `json:"status"` // ok, degraded, down
+	LatencyMS int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// publicComponentStatus is what unauthenticated callers see: a status
+// enum only, no error text and no latency, so a component's failure
+// mode (e.g. a MySQL DSN with embedded credentials) can never leak
+// through GET /health/ready.
+type publicComponentStatus struct {
+	Status string `json:"status"` // ok, degraded, down
+}
+
+func publicStatus(comp ComponentStatus) publicComponentStatus {
+	return publicComponentStatus{Status: comp.Status}
+}
+
+// ReadinessReport is the internally computed health report, shared by
+// the public and admin endpoints; only Readiness's response is
+// sanitized before being written out.
+type ReadinessReport struct {
+	Status     string                     `json:"status"` // ok, degraded, down
+	Components map[string]ComponentStatus `json:"components"`
+}
+
+// PublicReadinessReport is the body of GET /health/ready: the same
+// shape as ReadinessReport with every component's error text and
+// latency stripped.
+type PublicReadinessReport struct {
+	Status     string                           `json:"status"` // ok, degraded, down
+	Components map[string]publicComponentStatus `json:"components"`
+}
+
+func (r ReadinessReport) sanitized() PublicReadinessReport {
+	components := make(map[string]publicComponentStatus, len(r.Components))
+	for name, comp := range r.Components {
+		components[name] = publicStatus(comp)
+	}
+	return PublicReadinessReport{Status: r.Status, Components: components}
+}
+
+// Liveness answers GET /health/live: the process is up. It always
+// returns 200 unless the process is shutting down.
+func Liveness(c *gin.Context) {
+	c.JSON(200, gin.H{"status": "ok"})
+}
+
+var (
+	readinessMu     sync.Mutex
+	readinessCached ReadinessReport
+	readinessAt     time.Time
+)
+
+const readinessCacheTTL = 2 * time.Second
+
+// Readiness answers GET /health/ready: it checks the database, search
+// engine, and upload directory individually and reports an overall
+// status of ok/degraded/down. Results are cached briefly so repeated
+// health probes don't hammer the database.
+func Readiness(c *gin.Context) {
+	report := cachedReadiness()
+	c.JSON(statusCode(report.Status), report.sanitized())
+}
+
+// cachedReadiness returns the cached report if it's still fresh,
+// otherwise recomputes it and refreshes the cache. Shared by Readiness
+// and AdminHealth so the admin variant doesn't double the probe load.
+func cachedReadiness() ReadinessReport {
+	readinessMu.Lock()
+	if time.Since(readinessAt) < readinessCacheTTL {
+		report := readinessCached
+		readinessMu.Unlock()
+		return report
+	}
+	readinessMu.Unlock()
+
+	report := computeReadiness()
+
+	readinessMu.Lock()
+	readinessCached = report
+	readinessAt = time.Now()
+	readinessMu.Unlock()
+
+	return report
+}
+
+func computeReadiness() ReadinessReport {
+	components := map[string]ComponentStatus{
+		"database": checkDatabase(),
+		// The search engine being down is degraded, not unready: reads
+		// still work because search falls back to the database.
+		"search":  checkSearch(),
+		"uploads": checkUploads(),
+	}
+
+	overall := "ok"
+	for _, comp := range components {
+		if comp.Status == "down" && overall != "down" {
+			overall = "down"
+		}
+		if comp.Status == "degraded" && overall == "ok" {
+			overall = "degraded"
+		}
+	}
+	// The database being down makes the service unready; everything else
+	// degrades without blocking traffic.
+	if components["database"].Status == "down" {
+		overall = "down"
+	} else if overall == "down" {
+		overall = "degraded"
+	}
+
+	return ReadinessReport{Status: overall, Components: components}
+}
+
+func checkDatabase() ComponentStatus {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	sqlDB, err := db.DB.DB()
+	if err == nil {
+		err = sqlDB.PingContext(ctx)
+	}
+	latency := time.Since(start).Milliseconds()
+	if err != nil {
+		return ComponentStatus{Status: "down", LatencyMS: latency, Error: err.Error()}
+	}
+	return ComponentStatus{Status: "ok", LatencyMS: latency}
+}
+
+func checkSearch() ComponentStatus {
+	start := time.Now()
+	if !search.Ready() {
+		return ComponentStatus{Status: "degraded", LatencyMS: time.Since(start).Milliseconds(), Error: "search index not initialized"}
+	}
+	return ComponentStatus{Status: "ok", LatencyMS: time.Since(start).Milliseconds()}
+}
+
+func checkUploads() ComponentStatus {
+	start := time.Now()
+	probe := filepath.Join(config.App.UploadDir, ".health-check")
+	err := os.WriteFile(probe, []byte("ok"), 0o644)
+	if err == nil {
+		os.Remove(probe)
+	}
+	latency := time.Since(start).Milliseconds()
+	if err != nil {
+		return ComponentStatus{Status: "degraded", LatencyMS: latency, Error: err.Error()}
+	}
+	return ComponentStatus{Status: "ok", LatencyMS: latency}
+}
+
+func statusCode(status string) int {
+	if status == "down" {
+		return 503
+	}
+	return 200
+}
+
+// processStartedAt is recorded at package init so AdminHealth can report
+// uptime without main.go needing to thread a start time through.
+var processStartedAt = time.Now()
+
+// DBPoolStats mirrors the subset of sql.DBStats worth exposing.
+type DBPoolStats struct {
+	OpenConnections int `json:"open_connections"`
+	InUse           int `json:"in_use"`
+	Idle            int `json:"idle"`
+}
+
+// SearchIndexStats is the search engine's own self-reported health.
+type SearchIndexStats struct {
+	Ready    bool   `json:"ready"`
+	DocCount uint64 `json:"doc_count"`
+}
+
+// DiskStats is free/total space on the volume backing config.UploadDir.
+type DiskStats struct {
+	FreeBytes  uint64 `json:"free_bytes"`
+	TotalBytes uint64 `json:"total_bytes"`
+}
+
+// AdminHealthReport is the body of GET /api/admin/health: everything
+// Readiness checks, but with full error text, plus pool/search/disk
+// stats and process uptime that the public endpoints never expose.
+type AdminHealthReport struct {
+	Status      string                     `json:"status"`
+	Components  map[string]ComponentStatus `json:"components"`
+	DBPool      DBPoolStats                `json:"db_pool"`
+	SearchIndex SearchIndexStats           `json:"search_index"`
+	Disk        DiskStats                  `json:"disk"`
+	UptimeS     int64                      `json:"uptime_seconds"`
+}
+
+// AdminHealth answers GET /api/admin/health: the same component checks
+// as GET /health/ready, but with the full error text and pool/search/
+// disk/uptime detail that would be unsafe to hand to an unauthenticated
+// caller. Admin only.
+//
+// @Summary      Get detailed health report
+// @Description  Returns component health with full error text, connection pool stats, search index stats, upload volume disk space, and process uptime. Admin only.
+// @Tags         admin
+// @Produce      json
+// @Success      200  {object}  utils.Response{data=AdminHealthReport}
+// @Security     BearerAuth
+// @Router       /admin/health [get]
+func AdminHealth(c *gin.Context) {
+	report := cachedReadiness()
+
+	var pool DBPoolStats
+	if sqlDB, err := db.DB.DB(); err == nil {
+		stats := sqlDB.Stats()
+		pool = DBPoolStats{OpenConnections: stats.OpenConnections, InUse: stats.InUse, Idle: stats.Idle}
+	}
+
+	disk := diskStats(config.App.UploadDir)
+
+	utils.OK(c, AdminHealthReport{
+		Status:     report.Status,
+		Components: report.Components,
+		DBPool:     pool,
+		SearchIndex: SearchIndexStats{
+			Ready:    search.Ready(),
+			DocCount: search.DocCount(),
+		},
+		Disk:    disk,
+		UptimeS: int64(time.Since(processStartedAt).Seconds()),
+	})
+}
+
+func diskStats(path string) DiskStats {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return DiskStats{}
+	}
+	return DiskStats{
+		FreeBytes:  stat.Bavail * uint64(stat.Bsize),
+		TotalBytes: stat.Blocks * uint64(stat.Bsize),
+	}
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/1055373165/blog/config"
+	blogdb "github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupHealthTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	conn, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	if err := conn.AutoMigrate(&models.User{}); err != nil {
+		t.Fatalf("failed to migrate test db: %v", err)
+	}
+	blogdb.DB = conn
+	config.App = &config.Config{UploadDir: t.TempDir()}
+	// Force the readiness cache to recompute against this test's DB
+	// rather than returning a stale report from a previous test.
+	readinessAt = time.Time{}
+	return conn
+}
+
+func healthRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/health/ready", Readiness)
+	r.GET("/api/admin/health", AdminHealth)
+	return r
+}
+
+func TestReadinessNeverExposesComponentErrorText(t *testing.T) {
+	setupHealthTestDB(t)
+	sqlDB, _ := blogdb.DB.DB()
+	sqlDB.Close() // force the database check to fail with an error string
+
+	r := healthRouter()
+	req := httptest.NewRequest(http.MethodGet, "/health/ready", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d: %s", w.Code, w.Body.String())
+	}
+	if strings.Contains(w.Body.String(), `"error"`) {
+		t.Errorf("expected no error text in public response, got %s", w.Body.String())
+	}
+	if strings.Contains(w.Body.String(), `"latency_ms"`) {
+		t.Errorf("expected no latency detail in public response, got %s", w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"status":"down"`) {
+		t.Errorf("expected overall status down, got %s", w.Body.String())
+	}
+}
+
+func TestAdminHealthIncludesErrorTextAndStats(t *testing.T) {
+	setupHealthTestDB(t)
+	sqlDB, _ := blogdb.DB.DB()
+	sqlDB.Close()
+
+	r := healthRouter()
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/health", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, `"error"`) {
+		t.Errorf("expected the admin report to include error text, got %s", body)
+	}
+	for _, field := range []string{`"db_pool"`, `"search_index"`, `"disk"`, `"uptime_seconds"`} {
+		if !strings.Contains(body, field) {
+			t.Errorf("expected admin report to include %s, got %s", field, body)
+		}
+	}
+}
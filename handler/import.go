@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"io"
+	"log/slog"
+
+	"github.com/1055373165/blog/audit"
+	"github.com/1055373165/blog/config"
+	"github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/middleware"
+	"github.com/1055373165/blog/service"
+	"github.com/1055373165/blog/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// ImportMarkdown imports a zip of Markdown files (e.g. exported from an
+// existing Hugo or Hexo blog) as Articles. Front-matter tags are mapped
+// to existing or newly created Tags and attached to each Article; front-
+// matter categories are mapped to existing or newly created Categories,
+// though they can't yet be linked to the Article itself (see README
+// "Known gaps"). With dry_run=true, every file is validated but nothing
+// is written to the database or upload directory.
+//
+// @Summary      Import Markdown files as articles
+// @Description  Imports a zip of Markdown files (e.g. exported from Hugo or Hexo) as Articles, mapping front-matter tags/categories by name. Admin only.
+// @Tags         admin
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        file     formData  file    true   "Zip archive of .md files"
+// @Param        dry_run  formData  bool    false  "Validate without writing to the database or upload directory"
+// @Success      200  {object}  utils.Response{data=service.ImportMarkdownResult}
+// @Failure      400  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /admin/import/markdown [post]
+func ImportMarkdown(c *gin.Context) {
+	header, err := c.FormFile("file")
+	if err != nil {
+		utils.Fail(c, 400, "missing file field")
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		utils.Fail(c, 400, "failed to read uploaded file")
+		return
+	}
+	defer file.Close()
+
+	zipData, err := io.ReadAll(file)
+	if err != nil {
+		utils.Fail(c, 400, "failed to read uploaded file")
+		return
+	}
+
+	dryRun := c.PostForm("dry_run") == "true"
+
+	result, err := service.ImportMarkdownZip(db.DB, zipData, middleware.CurrentUserID(c), config.App.UploadDir, dryRun)
+	if err != nil {
+		utils.Fail(c, 400, "failed to import markdown archive")
+		return
+	}
+
+	slog.Info("imported markdown archive", "request_id", middleware.RequestID(c), "files", len(result.Files), "dry_run", dryRun)
+	if !dryRun {
+		audit.Record(c, "import_markdown", "article", 0, nil, result)
+	}
+	utils.OK(c, result)
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"log/slog"
+
+	"github.com/1055373165/blog/jobs"
+	"github.com/1055373165/blog/middleware"
+	"github.com/1055373165/blog/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// GetJobs lists every registered background job's schedule and most
+// recent run outcome.
+//
+// @Summary      List background jobs
+// @Description  Returns every registered job's interval, last run, next run, and last error. Admin only.
+// @Tags         admin
+// @Produce      json
+// @Success      200  {object}  utils.Response{data=[]jobs.Status}
+// @Failure      500  {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /admin/jobs [get]
+func GetJobs(c *gin.Context) {
+	statuses, err := jobs.Default.List()
+	if err != nil {
+		slog.Error("failed to list jobs", "request_id", middleware.RequestID(c), "error", err)
+		utils.Fail(c, 500, "failed to list jobs")
+		return
+	}
+	utils.OK(c, statuses)
+}
+
+// TriggerJobRequest is the payload for POST /api/admin/jobs.
+type TriggerJobRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// TriggerJob runs a registered job immediately, outside its regular
+// schedule, and reports its outcome. It returns 409 rather than
+// blocking if the job's previous run hasn't finished yet.
+//
+// @Summary      Trigger a background job
+// @Description  Runs a registered job immediately, outside its schedule. 409 if the job's previous run hasn't finished. Admin only.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        request  body      TriggerJobRequest  true  "Job to trigger"
+// @Success      200      {object}  utils.Response{data=jobs.Status}
+// @Failure      404      {object}  utils.Response
+// @Failure      409      {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /admin/jobs [post]
+func TriggerJob(c *gin.Context) {
+	var req TriggerJobRequest
+	if !utils.BindJSON(c, &req) {
+		return
+	}
+
+	status, err := jobs.Default.Trigger(req.Name)
+	if err != nil {
+		switch err {
+		case jobs.ErrJobNotFound:
+			utils.Fail(c, 404, "unknown job")
+		case jobs.ErrJobAlreadyRunning:
+			utils.Fail(c, 409, "job is already running")
+		default:
+			slog.Error("failed to trigger job", "request_id", middleware.RequestID(c), "name", req.Name, "error", err)
+			utils.Fail(c, 500, "failed to trigger job")
+		}
+		return
+	}
+
+	utils.OK(c, status)
+}
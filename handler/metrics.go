@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"github.com/1055373165/blog/config"
+	"github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/metrics"
+	"github.com/1055373165/blog/search"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// MetricsGuard restricts /metrics to callers bearing config.App.MetricsToken,
+// since traffic patterns are sensitive. An empty configured token denies
+// every request rather than leaving the endpoint open by accident.
+func MetricsGuard(c *gin.Context) {
+	token := config.App.MetricsToken
+	if token == "" || c.GetHeader("Authorization") != "Bearer "+token {
+		c.AbortWithStatus(404)
+		return
+	}
+	c.Next()
+}
+
+var promHandler = promhttp.Handler()
+
+// Metrics refreshes gauges sourced from live state (DB pool, search
+// index size) and serves the Prometheus exposition format.
+func Metrics(c *gin.Context) {
+	if sqlDB, err := db.DB.DB(); err == nil {
+		stats := sqlDB.Stats()
+		metrics.DBOpenConnections.Set(float64(stats.OpenConnections))
+		metrics.DBIdleConnections.Set(float64(stats.Idle))
+		metrics.DBWaitCount.Set(float64(stats.WaitCount))
+	}
+	metrics.SearchIndexDocs.Set(float64(search.DocCount()))
+
+	promHandler.ServeHTTP(c.Writer, c.Request)
+}
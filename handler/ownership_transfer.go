@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/1055373165/blog/audit"
+	"github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/service"
+	"github.com/1055373165/blog/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// TransferOwnerRequest is the payload for the article/blog transfer
+// endpoints: the ID of the user content should move to.
+type TransferOwnerRequest struct {
+	NewOwnerID uint `json:"new_owner_id" binding:"required"`
+}
+
+func transferOwnerNotFoundStatus(err error) (int, string) {
+	switch err {
+	case service.ErrArticleNotFound:
+		return 404, "article not found"
+	case service.ErrBlogNotFound:
+		return 404, "blog not found"
+	case service.ErrUserNotFound:
+		return 400, "target user not found"
+	case service.ErrSameOwner:
+		return 400, "target user already owns this content"
+	default:
+		return 500, "failed to transfer ownership"
+	}
+}
+
+// TransferArticle reassigns an article's authorship to another user.
+//
+// @Summary      Transfer an article's ownership
+// @Description  Reassigns an article's AuthorID to another user, audited and notifying both parties. Admin only.
+// @Tags         admin
+// @Produce      json
+// @Param        id       path      int                    true  "Article ID"
+// @Param        request  body      TransferOwnerRequest   true  "Target owner"
+// @Success      200      {object}  utils.Response{data=models.Article}
+// @Failure      400      {object}  utils.Response
+// @Failure      404      {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /articles/{id}/transfer [post]
+func TransferArticle(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid article id")
+		return
+	}
+
+	var req TransferOwnerRequest
+	if !utils.BindJSON(c, &req) {
+		return
+	}
+
+	oldOwnerID, err := service.TransferArticleOwner(db.DB, uint(id), req.NewOwnerID)
+	if err != nil {
+		status, message := transferOwnerNotFoundStatus(err)
+		utils.Fail(c, status, message)
+		return
+	}
+
+	audit.Record(c, "transfer", "article", uint(id), gin.H{"author_id": oldOwnerID}, gin.H{"author_id": req.NewOwnerID})
+	notifyOwnershipTransfer(db.DB, "article", oldOwnerID, req.NewOwnerID)
+	utils.OK(c, gin.H{"id": id, "old_owner_id": oldOwnerID, "new_owner_id": req.NewOwnerID})
+}
+
+// TransferBlog reassigns a blog's authorship to another user.
+//
+// @Summary      Transfer a blog's ownership
+// @Description  Reassigns a blog's AuthorID to another user, audited and notifying both parties. Admin only.
+// @Tags         admin
+// @Produce      json
+// @Param        id       path      int                    true  "Blog ID"
+// @Param        request  body      TransferOwnerRequest   true  "Target owner"
+// @Success      200      {object}  utils.Response{data=models.Blog}
+// @Failure      400      {object}  utils.Response
+// @Failure      404      {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /blogs/{id}/transfer [post]
+func TransferBlog(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid blog id")
+		return
+	}
+
+	var req TransferOwnerRequest
+	if !utils.BindJSON(c, &req) {
+		return
+	}
+
+	oldOwnerID, err := service.TransferBlogOwner(db.DB, uint(id), req.NewOwnerID)
+	if err != nil {
+		status, message := transferOwnerNotFoundStatus(err)
+		utils.Fail(c, status, message)
+		return
+	}
+
+	audit.Record(c, "transfer", "blog", uint(id), gin.H{"author_id": oldOwnerID}, gin.H{"author_id": req.NewOwnerID})
+	notifyOwnershipTransfer(db.DB, "blog", oldOwnerID, req.NewOwnerID)
+	utils.OK(c, gin.H{"id": id, "old_owner_id": oldOwnerID, "new_owner_id": req.NewOwnerID})
+}
+
+// notifyOwnershipTransfer notifies both the old and new owner that a
+// piece of content moved between their accounts. Like every other
+// notification in this codebase, a failure here never fails the
+// transfer itself - CreateNotification's error is ignored.
+func notifyOwnershipTransfer(tx *gorm.DB, kind string, oldOwnerID, newOwnerID uint) {
+	service.CreateNotification(tx, oldOwnerID, "content_transferred",
+		fmt.Sprintf("Your %s was transferred to another account", kind))
+	service.CreateNotification(tx, newOwnerID, "content_transferred",
+		fmt.Sprintf("You received a new %s", kind))
+}
+
+// TransferUserContentRequest is the payload for
+// POST /api/admin/users/:id/transfer-content: the ID of the user
+// everything should move to.
+type TransferUserContentRequest struct {
+	NewOwnerID uint `json:"new_owner_id" binding:"required"`
+}
+
+// TransferUserContent moves every article, blog, and draft submission
+// owned by the path user to NewOwnerID, for use right before
+// deactivating that user's account.
+//
+// @Summary      Bulk-transfer a user's content
+// @Description  Moves every article, blog, and draft-status submission owned by the path user to new_owner_id, in one transaction. Used right before deactivating an account. Admin only.
+// @Tags         admin
+// @Produce      json
+// @Param        id       path      int                          true  "User ID to move content away from"
+// @Param        request  body      TransferUserContentRequest  true  "Target owner"
+// @Success      200      {object}  utils.Response{data=service.ContentTransferCounts}
+// @Failure      400      {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /admin/users/{id}/transfer-content [post]
+func TransferUserContent(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid user id")
+		return
+	}
+
+	var req TransferUserContentRequest
+	if !utils.BindJSON(c, &req) {
+		return
+	}
+
+	counts, err := service.TransferAllContent(db.DB, uint(id), req.NewOwnerID)
+	if err != nil {
+		status, message := transferOwnerNotFoundStatus(err)
+		utils.Fail(c, status, message)
+		return
+	}
+
+	audit.Record(c, "transfer_all_content", "user", uint(id), nil, gin.H{"new_owner_id": req.NewOwnerID, "counts": counts})
+	service.CreateNotification(db.DB, uint(id), "content_transferred", fmt.Sprintf("Your content was transferred to user %d", req.NewOwnerID))
+	service.CreateNotification(db.DB, req.NewOwnerID, "content_transferred", fmt.Sprintf("Content from user %d was transferred to you", id))
+	utils.OK(c, counts)
+}
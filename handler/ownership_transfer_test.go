@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	blogdb "github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupOwnershipTransferTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	conn, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	if err := conn.AutoMigrate(&models.User{}, &models.Article{}, &models.Blog{}, &models.Submission{}, &models.AuditLog{}, &models.Notification{}); err != nil {
+		t.Fatalf("failed to migrate test db: %v", err)
+	}
+	blogdb.DB = conn
+	return conn
+}
+
+func TestTransferArticleMovesAuthorAndRecordsAudit(t *testing.T) {
+	db := setupOwnershipTransferTestDB(t)
+
+	oldOwner := models.User{Username: "old", Email: "old@example.com"}
+	newOwner := models.User{Username: "new", Email: "new@example.com"}
+	db.Create(&oldOwner)
+	db.Create(&newOwner)
+	article := models.Article{AuthorID: oldOwner.ID, Title: "A", Slug: "a"}
+	db.Create(&article)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/articles/:id/transfer", TransferArticle)
+
+	body := `{"new_owner_id":` + itoa(newOwner.ID) + `}`
+	req := httptest.NewRequest(http.MethodPost, "/api/articles/"+itoa(article.ID)+"/transfer", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var reloaded models.Article
+	db.First(&reloaded, article.ID)
+	if reloaded.AuthorID != newOwner.ID {
+		t.Errorf("expected author_id %d, got %d", newOwner.ID, reloaded.AuthorID)
+	}
+
+	var auditLog models.AuditLog
+	if err := db.Where("entity_type = ? AND entity_id = ?", "article", article.ID).First(&auditLog).Error; err != nil {
+		t.Fatalf("expected an audit log entry for the transfer: %v", err)
+	}
+
+	var notifications []models.Notification
+	db.Find(&notifications)
+	if len(notifications) != 2 {
+		t.Errorf("expected both parties notified, got %d notifications", len(notifications))
+	}
+}
+
+func TestTransferArticleRejectsUnknownTargetUser(t *testing.T) {
+	db := setupOwnershipTransferTestDB(t)
+
+	owner := models.User{Username: "owner", Email: "owner@example.com"}
+	db.Create(&owner)
+	article := models.Article{AuthorID: owner.ID, Title: "A", Slug: "a"}
+	db.Create(&article)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/articles/:id/transfer", TransferArticle)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/articles/"+itoa(article.ID)+"/transfer", strings.NewReader(`{"new_owner_id":999}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an unknown target user, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestTransferUserContentMovesArticlesBlogsAndDraftsAndReturnsCounts(t *testing.T) {
+	db := setupOwnershipTransferTestDB(t)
+
+	from := models.User{Username: "leaving", Email: "leaving@example.com"}
+	to := models.User{Username: "staying", Email: "staying@example.com"}
+	db.Create(&from)
+	db.Create(&to)
+	db.Create(&models.Article{AuthorID: from.ID, Title: "a1", Slug: "a1"})
+	db.Create(&models.Blog{AuthorID: from.ID, Title: "b1"})
+	db.Create(&models.Submission{AuthorID: from.ID, Title: "s1", Status: models.SubmissionStatusDraft})
+	db.Create(&models.Submission{AuthorID: from.ID, Title: "s2", Status: models.SubmissionStatusPending})
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/admin/users/:id/transfer-content", TransferUserContent)
+
+	body := `{"new_owner_id":` + itoa(to.ID) + `}`
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/users/"+itoa(from.ID)+"/transfer-content", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Data struct {
+			ArticlesMoved    int64 `json:"articles_moved"`
+			BlogsMoved       int64 `json:"blogs_moved"`
+			SubmissionsMoved int64 `json:"submissions_moved"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if resp.Data.ArticlesMoved != 1 || resp.Data.BlogsMoved != 1 || resp.Data.SubmissionsMoved != 1 {
+		t.Fatalf("expected 1/1/1 moved, got %+v", resp.Data)
+	}
+}
+
+func itoa(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/1055373165/blog/config"
+	"github.com/1055373165/blog/metrics"
+	"github.com/1055373165/blog/middleware"
+	"github.com/1055373165/blog/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// UploadFile accepts a multipart file upload and stores it under the
+// configured upload directory, returning its generated filename.
+//
+// The request body is capped at config.App.UploadMaxBytes via
+// http.MaxBytesReader before the multipart form is even parsed, so an
+// oversized upload is rejected without fully reading it off the wire -
+// the multipart header's own Size field is client-supplied and not
+// trustworthy for this. The file itself is streamed straight from the
+// multipart part to disk (saveUploadStream), so memory use stays
+// constant regardless of file size.
+//
+// @Summary      Upload a file
+// @Description  Accepts a multipart file upload and stores it under the configured upload directory.
+// @Tags         files
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        file  formData  file  true  "File to upload"
+// @Success      200   {object}  utils.Response{data=object{filename=string,url=string,content_type=string,size=int}}
+// @Failure      400   {object}  utils.Response
+// @Failure      413   {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /upload [post]
+func UploadFile(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, config.App.UploadMaxBytes)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		if utils.IsBodyTooLarge(err) {
+			utils.Fail(c, 413, "file exceeds the upload size limit")
+			return
+		}
+		utils.Fail(c, 400, "missing file field")
+		return
+	}
+
+	if err := os.MkdirAll(config.App.UploadDir, 0o755); err != nil {
+		slog.Error("failed to prepare upload dir", "request_id", middleware.RequestID(c), "error", err)
+		utils.Fail(c, 500, "failed to store file")
+		return
+	}
+
+	name, contentType, size, err := saveUploadStream(header, config.App.UploadDir)
+	if err != nil {
+		if utils.IsBodyTooLarge(err) {
+			utils.Fail(c, 413, "file exceeds the upload size limit")
+			return
+		}
+		if errors.Is(err, utils.ErrUploadContentMismatch) {
+			utils.Fail(c, 400, "file content does not match its extension")
+			return
+		}
+		slog.Error("failed to save uploaded file", "request_id", middleware.RequestID(c), "error", err)
+		utils.Fail(c, 500, "failed to store file")
+		return
+	}
+
+	metrics.UploadsBytesTotal.Add(float64(size))
+	slog.Debug("file uploaded", "request_id", middleware.RequestID(c), "filename", name, "size", size, "content_type", contentType)
+	url := utils.ResolvePublicBase(c) + "/api/files/" + name
+	utils.OK(c, gin.H{"filename": name, "url": url, "content_type": contentType, "size": size})
+}
+
+// saveUploadStream streams header's content straight to a file under
+// dir, hashing it incrementally along the way via io.MultiWriter so the
+// whole file never needs to sit in memory at once. The destination
+// filename is derived from the content hash (content-addressed, like a
+// git blob) rather than a random name: an identical re-upload lands on
+// the same file instead of writing a duplicate copy.
+//
+// The name can't be chosen until the content has been read, so it
+// writes to a temp file first and renames into place once the hash is
+// known. Content type is sniffed from the file's own first 512 bytes
+// (http.DetectContentType) rather than trusted from the multipart part's
+// Content-Type header, which a client can set to anything. The sniffed
+// content is further checked against the upload's extension
+// (utils.VerifyUploadContentType) so e.g. an executable renamed to end
+// in ".pdf" is rejected (ErrUploadContentMismatch) rather than stored
+// and later served back out under a trusted-looking content type.
+func saveUploadStream(header *multipart.FileHeader, dir string) (filename, contentType string, size int64, err error) {
+	src, err := header.Open()
+	if err != nil {
+		return "", "", 0, err
+	}
+	defer src.Close()
+
+	tmp, err := os.CreateTemp(dir, "upload-*.tmp")
+	if err != nil {
+		return "", "", 0, err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // no-op once successfully renamed below
+
+	hasher := sha256.New()
+	n, err := io.Copy(io.MultiWriter(tmp, hasher), src)
+	if err != nil {
+		tmp.Close()
+		return "", "", 0, err
+	}
+
+	sniff := make([]byte, 512)
+	sniffN, _ := tmp.ReadAt(sniff, 0)
+	contentType = http.DetectContentType(sniff[:sniffN])
+
+	ext := filepath.Ext(header.Filename)
+	if verr := utils.VerifyUploadContentType(sniff[:sniffN], ext, tmp, n); verr != nil {
+		tmp.Close()
+		return "", "", 0, verr
+	}
+
+	if err := tmp.Close(); err != nil {
+		return "", "", 0, err
+	}
+
+	name := hex.EncodeToString(hasher.Sum(nil)) + ext
+	dest := filepath.Join(dir, name)
+	if _, err := os.Stat(dest); err == nil {
+		// Identical content already stored under this name; drop the temp
+		// copy and reuse it rather than writing a duplicate.
+		return name, contentType, n, nil
+	}
+	if err := os.Rename(tmpPath, dest); err != nil {
+		return "", "", 0, err
+	}
+	return name, contentType, n, nil
+}
+
+func randomFilename() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/1055373165/blog/config"
+	"github.com/gin-gonic/gin"
+)
+
+func uploadRequest(t *testing.T) *http.Request {
+	t.Helper()
+	return uploadRequestWithContent(t, []byte("hello"))
+}
+
+func uploadRequestWithContent(t *testing.T, content []byte) *http.Request {
+	t.Helper()
+	return uploadRequestWithFilename(t, "sample.txt", content)
+}
+
+func uploadRequestWithFilename(t *testing.T, filename string, content []byte) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, err := w.CreateFormFile("file", filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := part.Write(content); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/upload", &body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	return req
+}
+
+func TestUploadFileURLUsesConfiguredPublicBase(t *testing.T) {
+	bases := []string{"https://blog.example.com", "http://staging.internal:8080", "https://cdn.example.org/blog"}
+
+	for _, base := range bases {
+		dir := t.TempDir()
+		config.App = &config.Config{UploadDir: dir, PublicBase: base, UploadMaxBytes: 1 << 20}
+
+		gin.SetMode(gin.TestMode)
+		r := gin.New()
+		r.POST("/api/upload", UploadFile)
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, uploadRequest(t))
+
+		var resp struct {
+			Data struct {
+				URL string `json:"url"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if want := base + "/api/files/"; len(resp.Data.URL) <= len(want) || resp.Data.URL[:len(want)] != want {
+			t.Errorf("base %q: expected url to start with %q, got %q", base, want, resp.Data.URL)
+		}
+	}
+}
+
+func TestUploadFileURLFallsBackToRequestHost(t *testing.T) {
+	dir := t.TempDir()
+	config.App = &config.Config{UploadDir: dir, PublicBase: "", UploadMaxBytes: 1 << 20}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/upload", UploadFile)
+
+	req := uploadRequest(t)
+	req.Host = "example.test"
+	req.Header.Set("X-Forwarded-Proto", "https")
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	var resp struct {
+		Data struct {
+			URL string `json:"url"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if want := "https://example.test/api/files/"; len(resp.Data.URL) <= len(want) || resp.Data.URL[:len(want)] != want {
+		t.Errorf("expected url to start with %q, got %q", want, resp.Data.URL)
+	}
+}
+
+func TestUploadFileRejectsFileOverSizeLimitWithoutStoringIt(t *testing.T) {
+	dir := t.TempDir()
+	config.App = &config.Config{UploadDir: dir, UploadMaxBytes: 10}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/upload", UploadFile)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, uploadRequestWithContent(t, bytes.Repeat([]byte("x"), 1024)))
+
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected an oversized upload to be rejected with 413, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read upload dir: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected nothing written to disk for a rejected upload, got %v", entries)
+	}
+}
+
+func TestUploadFileDeduplicatesIdenticalContent(t *testing.T) {
+	dir := t.TempDir()
+	config.App = &config.Config{UploadDir: dir, UploadMaxBytes: 1 << 20}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/upload", UploadFile)
+
+	upload := func() string {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, uploadRequestWithContent(t, []byte("identical content")))
+		var resp struct {
+			Data struct {
+				Filename    string `json:"filename"`
+				ContentType string `json:"content_type"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if resp.Data.ContentType == "" {
+			t.Error("expected a sniffed content_type in the response")
+		}
+		return resp.Data.Filename
+	}
+
+	first := upload()
+	second := upload()
+	if first == "" || first != second {
+		t.Errorf("expected two uploads of identical content to resolve to the same filename, got %q and %q", first, second)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read upload dir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected exactly one stored file for two identical uploads, got %v", entries)
+	}
+}
+
+func TestUploadFileRejectsExecutableRenamedWithSafeExtension(t *testing.T) {
+	dir := t.TempDir()
+	config.App = &config.Config{UploadDir: dir, UploadMaxBytes: 1 << 20}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/upload", UploadFile)
+
+	elfHeader := append([]byte("\x7fELF"), bytes.Repeat([]byte{0}, 60)...)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, uploadRequestWithFilename(t, "report.pdf", elfHeader))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected a mislabeled executable to be rejected with 400, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read upload dir: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected nothing left on disk for a rejected upload, got %v", entries)
+	}
+}
+
+func TestUploadFileRejectsHTMLRenamedAsImage(t *testing.T) {
+	dir := t.TempDir()
+	config.App = &config.Config{UploadDir: dir, UploadMaxBytes: 1 << 20}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/upload", UploadFile)
+
+	html := []byte("<!DOCTYPE html><html><body><script>alert(1)</script></body></html>")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, uploadRequestWithFilename(t, "photo.png", html))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected an HTML file renamed as an image to be rejected with 400, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestUploadFileAllowsExecutableUnderItsOwnExtension(t *testing.T) {
+	dir := t.TempDir()
+	config.App = &config.Config{UploadDir: dir, UploadMaxBytes: 1 << 20}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/upload", UploadFile)
+
+	elfHeader := append([]byte("\x7fELF"), bytes.Repeat([]byte{0}, 60)...)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, uploadRequestWithFilename(t, "tool.bin", elfHeader))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected a binary under its own extension to be accepted, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
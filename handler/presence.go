@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/1055373165/blog/presence"
+	"github.com/1055373165/blog/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// PingArticlePresenceRequest is the payload for POST
+// /api/articles/:id/presence.
+type PingArticlePresenceRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// PingArticlePresence records that the caller's anonymous client token
+// is currently reading an article. The frontend is expected to call this
+// roughly every 30 seconds while the article is open; no database write
+// happens here.
+//
+// @Summary      Ping article presence
+// @Description  Records that an anonymous client token is currently reading an article, for a live reader count. Call roughly every 30 seconds while the article is open.
+// @Tags         articles
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                          true  "Article ID"
+// @Param        request  body      PingArticlePresenceRequest  true  "Client token"
+// @Success      200      {object}  utils.Response
+// @Failure      400      {object}  utils.Response
+// @Router       /articles/{id}/presence [post]
+func PingArticlePresence(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid article id")
+		return
+	}
+
+	var req PingArticlePresenceRequest
+	if !utils.BindJSON(c, &req) {
+		return
+	}
+
+	presence.Default.Ping(uint(id), req.Token)
+	utils.OK(c, nil)
+}
+
+// GetArticlePresence returns how many distinct client tokens are
+// currently reading an article, fuzzed to 0 below a small threshold so
+// individual readers can't be singled out.
+//
+// @Summary      Get article presence
+// @Description  Returns the number of distinct client tokens currently reading an article, fuzzed to 0 below a small threshold.
+// @Tags         articles
+// @Produce      json
+// @Param        id   path      int  true  "Article ID"
+// @Success      200  {object}  utils.Response{data=object{count=int}}
+// @Failure      400  {object}  utils.Response
+// @Router       /articles/{id}/presence [get]
+func GetArticlePresence(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid article id")
+		return
+	}
+
+	utils.OK(c, gin.H{"count": presence.Default.Count(uint(id))})
+}
@@ -0,0 +1,202 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/middleware"
+	"github.com/1055373165/blog/models"
+	"github.com/1055373165/blog/service"
+	"github.com/1055373165/blog/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateSavedSearchRequest is the payload for POST /api/search/saved.
+type CreateSavedSearchRequest struct {
+	Name    string               `json:"name" binding:"required"`
+	Query   string               `json:"query" binding:"required"`
+	Filters models.SearchFilters `json:"filters"`
+	Notify  bool                 `json:"notify"`
+}
+
+// CreateSavedSearch saves a new search for the caller, capped at
+// service.MaxSavedSearchesPerUser.
+//
+// @Summary      Save a search
+// @Description  Saves a new search for the caller, capped at 20 per user.
+// @Tags         search
+// @Accept       json
+// @Produce      json
+// @Param        request  body      CreateSavedSearchRequest  true  "Saved search fields"
+// @Success      200      {object}  utils.Response{data=models.SavedSearch}
+// @Failure      400      {object}  utils.Response{details=[]utils.FieldError}
+// @Security     BearerAuth
+// @Router       /search/saved [post]
+func CreateSavedSearch(c *gin.Context) {
+	var req CreateSavedSearchRequest
+	if !utils.BindJSON(c, &req) {
+		return
+	}
+
+	saved := models.SavedSearch{
+		UserID:  middleware.CurrentUserID(c),
+		Name:    req.Name,
+		Query:   req.Query,
+		Filters: req.Filters,
+		Notify:  req.Notify,
+	}
+	if err := service.CreateSavedSearch(db.DB, &saved); err != nil {
+		if err == service.ErrSavedSearchLimitReached {
+			utils.Fail(c, 400, err.Error())
+			return
+		}
+		utils.Fail(c, 500, "failed to save search")
+		return
+	}
+
+	utils.OK(c, saved)
+}
+
+// GetMySavedSearches returns the caller's saved searches.
+//
+// @Summary      List my saved searches
+// @Description  Returns the caller's saved searches.
+// @Tags         search
+// @Produce      json
+// @Success      200  {object}  utils.Response{data=[]models.SavedSearch}
+// @Security     BearerAuth
+// @Router       /search/saved [get]
+func GetMySavedSearches(c *gin.Context) {
+	var searches []models.SavedSearch
+	if err := db.DB.Where("user_id = ?", middleware.CurrentUserID(c)).
+		Order("created_at desc").Find(&searches).Error; err != nil {
+		utils.Fail(c, 500, "failed to load saved searches")
+		return
+	}
+	utils.OK(c, searches)
+}
+
+// loadOwnedSavedSearch fetches the :id saved search owned by the caller,
+// failing the request with 404 if it doesn't exist or isn't theirs.
+func loadOwnedSavedSearch(c *gin.Context) (models.SavedSearch, bool) {
+	var saved models.SavedSearch
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid saved search id")
+		return saved, false
+	}
+	if err := db.DB.Where("id = ? AND user_id = ?", id, middleware.CurrentUserID(c)).
+		First(&saved).Error; err != nil {
+		utils.Fail(c, 404, "saved search not found")
+		return saved, false
+	}
+	return saved, true
+}
+
+// UpdateSavedSearchRequest is the payload for PUT /api/search/saved/:id.
+type UpdateSavedSearchRequest struct {
+	Name    *string               `json:"name"`
+	Query   *string               `json:"query"`
+	Filters *models.SearchFilters `json:"filters"`
+	Notify  *bool                 `json:"notify"`
+}
+
+// UpdateSavedSearch updates the caller's saved search.
+//
+// @Summary      Update a saved search
+// @Description  Updates the caller's saved search.
+// @Tags         search
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                       true  "Saved search ID"
+// @Param        request  body      UpdateSavedSearchRequest  true  "Fields to update"
+// @Success      200      {object}  utils.Response{data=models.SavedSearch}
+// @Failure      400      {object}  utils.Response{details=[]utils.FieldError}
+// @Failure      404      {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /search/saved/{id} [put]
+func UpdateSavedSearch(c *gin.Context) {
+	saved, ok := loadOwnedSavedSearch(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateSavedSearchRequest
+	if !utils.BindJSON(c, &req) {
+		return
+	}
+
+	updates := map[string]any{}
+	if req.Name != nil {
+		updates["name"] = *req.Name
+	}
+	if req.Query != nil {
+		updates["query"] = *req.Query
+	}
+	if req.Filters != nil {
+		updates["filters"] = *req.Filters
+	}
+	if req.Notify != nil {
+		updates["notify"] = *req.Notify
+	}
+	if len(updates) > 0 {
+		if err := db.DB.Model(&saved).Updates(updates).Error; err != nil {
+			utils.Fail(c, 500, "failed to update saved search")
+			return
+		}
+	}
+
+	utils.OK(c, saved)
+}
+
+// DeleteSavedSearch deletes the caller's saved search.
+//
+// @Summary      Delete a saved search
+// @Description  Deletes the caller's saved search.
+// @Tags         search
+// @Produce      json
+// @Param        id   path      int  true  "Saved search ID"
+// @Success      200  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /search/saved/{id} [delete]
+func DeleteSavedSearch(c *gin.Context) {
+	saved, ok := loadOwnedSavedSearch(c)
+	if !ok {
+		return
+	}
+
+	if err := db.DB.Delete(&saved).Error; err != nil {
+		utils.Fail(c, 500, "failed to delete saved search")
+		return
+	}
+
+	utils.OK(c, nil)
+}
+
+// RunSavedSearch executes the caller's saved search's stored query
+// through the normal search pipeline and returns standard results.
+//
+// @Summary      Run a saved search
+// @Description  Executes the saved search's stored query and returns standard search results.
+// @Tags         search
+// @Produce      json
+// @Param        id   path      int  true  "Saved search ID"
+// @Success      200  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /search/saved/{id}/run [get]
+func RunSavedSearch(c *gin.Context) {
+	saved, ok := loadOwnedSavedSearch(c)
+	if !ok {
+		return
+	}
+
+	resp, err := runSearch(c, saved.Query)
+	if err != nil {
+		utils.FailFromDBError(c, err, err.Error())
+		return
+	}
+	utils.OK(c, resp)
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/search"
+	"github.com/1055373165/blog/searchstats"
+	"github.com/1055373165/blog/service"
+	"github.com/1055373165/blog/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// maxFacetValues caps how many values each search facet returns.
+const maxFacetValues = 15
+
+// searchFacets are refinement counts alongside a search's hit list.
+// They are computed over all published articles, not the search hits
+// themselves: the Bleve index only covers Blog transcripts/titles, which
+// carry no tag/series/category metadata to facet on, so these counts are
+// not yet scoped to the active query or filters.
+type searchFacets struct {
+	Tags   []service.TagFacet    `json:"tags"`
+	Series []service.SeriesFacet `json:"series"`
+	Years  []service.YearFacet   `json:"years"`
+}
+
+// searchResponse is the payload returned by GET /api/search.
+type searchResponse struct {
+	Hits   any          `json:"hits"`
+	Facets searchFacets `json:"facets"`
+}
+
+// SearchBlogsAndArticles handles GET /api/search?q=...
+//
+// @Summary      Search blogs and articles
+// @Description  Full-text searches across blogs and articles, with tag/series/year facet counts.
+// @Tags         search
+// @Produce      json
+// @Param        q    query     string  true  "Search query"
+// @Success      200  {object}  utils.Response{data=object{hits=search.Result,facets=object{tags=[]service.TagFacet,series=[]service.SeriesFacet,years=[]service.YearFacet}}}
+// @Failure      400  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /search [get]
+func SearchBlogsAndArticles(c *gin.Context) {
+	query := c.Query("q")
+	if query == "" {
+		utils.Fail(c, 400, "missing query parameter q")
+		return
+	}
+
+	resp, err := runSearch(c, query)
+	if err != nil {
+		utils.FailFromDBError(c, err, err.Error())
+		return
+	}
+	if searchstats.Default != nil {
+		resultCount := 0
+		if hits, ok := resp.Hits.(*search.Result); ok {
+			resultCount = int(hits.Total)
+		}
+		searchstats.Default.Record(c.ClientIP(), query, resultCount)
+	}
+	utils.OK(c, resp)
+}
+
+// runSearch executes query through the full search pipeline (hits plus
+// facet counts), shared by SearchBlogsAndArticles and RunSavedSearch so
+// a saved search re-run returns the same response shape as a live one.
+func runSearch(c *gin.Context, query string) (searchResponse, error) {
+	hits, err := search.Search(query, 20)
+	if err != nil {
+		return searchResponse{}, errors.New("search failed")
+	}
+
+	qdb := db.WithCtx(c.Request.Context())
+	tags, err := service.GetTagFacets(qdb, maxFacetValues)
+	if err != nil {
+		return searchResponse{}, fmt.Errorf("failed to load facets: %w", err)
+	}
+	seriesFacets, err := service.GetSeriesFacets(qdb, maxFacetValues)
+	if err != nil {
+		return searchResponse{}, fmt.Errorf("failed to load facets: %w", err)
+	}
+	years, err := service.GetYearFacets(qdb, maxFacetValues)
+	if err != nil {
+		return searchResponse{}, fmt.Errorf("failed to load facets: %w", err)
+	}
+
+	return searchResponse{
+		Hits:   hits,
+		Facets: searchFacets{Tags: tags, Series: seriesFacets, Years: years},
+	}, nil
+}
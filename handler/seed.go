@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"github.com/1055373165/blog/config"
+	"github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/seed"
+	"github.com/1055373165/blog/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// SeedDemo creates the deterministic demo dataset described by
+// seed.Run, for quickly populating a dev environment with something
+// to click through. Refuses to run when BLOG_ENV is "production", so
+// it can never be pointed at real data by mistake.
+//
+// @Summary      Seed demo data
+// @Description  Creates a deterministic demo dataset (users, categories, tags, articles, blogs, submissions, a study plan). Idempotent - a second call is a no-op. Refuses in production. Admin only.
+// @Tags         admin
+// @Produce      json
+// @Success      200  {object}  utils.Response{data=seed.Summary}
+// @Failure      403  {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /admin/seed-demo [post]
+func SeedDemo(c *gin.Context) {
+	if config.App.Env == "production" {
+		utils.Fail(c, 403, "demo seeding is disabled in production")
+		return
+	}
+
+	summary, err := seed.Run(db.DB)
+	if err != nil {
+		utils.Fail(c, 500, "failed to seed demo data")
+		return
+	}
+	utils.OK(c, summary)
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/1055373165/blog/config"
+	blogdb "github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupSeedHandlerTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	conn, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	if err := conn.AutoMigrate(
+		&models.User{}, &models.Category{}, &models.Tag{}, &models.Series{},
+		&models.Article{}, &models.Blog{}, &models.Reaction{}, &models.Submission{},
+		&models.SubmissionComment{}, &models.StudyPlan{}, &models.StudyItem{}, &models.StudyLog{},
+	); err != nil {
+		t.Fatalf("failed to migrate test db: %v", err)
+	}
+	blogdb.DB = conn
+	return conn
+}
+
+func TestSeedDemoCreatesDataset(t *testing.T) {
+	setupSeedHandlerTestDB(t)
+	config.App = &config.Config{Env: "development"}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/admin/seed-demo", SeedDemo)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/seed-demo", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSeedDemoRefusesInProduction(t *testing.T) {
+	setupSeedHandlerTestDB(t)
+	config.App = &config.Config{Env: "production"}
+	defer func() { config.App = &config.Config{Env: "development"} }()
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/admin/seed-demo", SeedDemo)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/seed-demo", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 in production, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+
+	"github.com/1055373165/blog/audit"
+	"github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/middleware"
+	"github.com/1055373165/blog/siteconfig"
+	"github.com/1055373165/blog/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// GetSiteConfig returns every known configuration key's current value,
+// type, and description. Sensitive keys (e.g. smtp_password) are
+// write-only: their value is masked rather than returned.
+//
+// @Summary      List site configuration
+// @Description  Returns every known config key's current value, type, and description. Sensitive keys are masked. Admin only.
+// @Tags         admin
+// @Produce      json
+// @Success      200  {object}  utils.Response{data=[]siteconfig.Entry}
+// @Security     BearerAuth
+// @Router       /admin/config [get]
+func GetSiteConfig(c *gin.Context) {
+	utils.OK(c, siteconfig.Snapshot())
+}
+
+// UpdateSiteConfigRequest is the payload for PUT /api/admin/config/:key.
+// Value is validated against the key's declared type (string, int, bool,
+// or json) before it's stored.
+type UpdateSiteConfigRequest struct {
+	Value json.RawMessage `json:"value" binding:"required"`
+}
+
+// UpdateSiteConfig validates and persists a new value for a known config
+// key, updating the in-process cache so it takes effect immediately for
+// every subsequent read, with no restart.
+//
+// @Summary      Update a site configuration key
+// @Description  Validates value against the key's declared type and persists it, taking effect immediately with no restart. Admin only.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        key      path      string                          true  "Config key"
+// @Param        request  body      UpdateSiteConfigRequest  true  "New value"
+// @Success      200      {object}  utils.Response{data=siteconfig.Entry}
+// @Failure      400      {object}  utils.Response
+// @Failure      404      {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /admin/config/{key} [put]
+func UpdateSiteConfig(c *gin.Context) {
+	key := c.Param("key")
+
+	var req UpdateSiteConfigRequest
+	if !utils.BindJSON(c, &req) {
+		return
+	}
+
+	entry, err := siteconfig.Set(db.DB, key, req.Value)
+	if err != nil {
+		switch err {
+		case siteconfig.ErrUnknownKey:
+			utils.Fail(c, 404, "unknown config key")
+		case siteconfig.ErrInvalidValue:
+			utils.Fail(c, 400, "value does not match the key's declared type")
+		default:
+			slog.Error("failed to update site config", "request_id", middleware.RequestID(c), "key", key, "error", err)
+			utils.Fail(c, 500, "failed to update config")
+		}
+		return
+	}
+
+	audit.Record(c, "update_config", "site_config", 0, nil, gin.H{"key": key})
+	utils.OK(c, entry)
+}
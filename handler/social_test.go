@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/1055373165/blog/config"
+	blogdb "github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupSocialTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	conn, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	if err := conn.AutoMigrate(&models.Article{}, &models.User{}); err != nil {
+		t.Fatalf("failed to migrate test db: %v", err)
+	}
+	blogdb.DB = conn
+	return conn
+}
+
+func TestGetArticleOGImageCachesGeneratedPNG(t *testing.T) {
+	db := setupSocialTestDB(t)
+	dir := t.TempDir()
+	config.App = &config.Config{UploadDir: dir}
+
+	author := models.User{Username: "jane", Email: "jane@example.com"}
+	db.Create(&author)
+	article := models.Article{AuthorID: author.ID, Title: "Hello World", Slug: "hello", Status: models.ArticleStatusPublished}
+	db.Create(&article)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/api/articles/:id/og-image.png", GetArticleOGImage)
+
+	path := ogImageCachePath(article)
+	if _, err := os.Stat(path); err == nil {
+		t.Fatal("expected no cached card before first request")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/articles/"+strconv.FormatUint(uint64(article.ID), 10)+"/og-image.png", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "image/png" {
+		t.Errorf("expected image/png content type, got %q", ct)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("expected card to be cached at %q: %v", path, err)
+	}
+
+	modTime1, _ := os.Stat(path)
+	req2 := httptest.NewRequest(http.MethodGet, "/api/articles/"+strconv.FormatUint(uint64(article.ID), 10)+"/og-image.png", nil)
+	w2 := httptest.NewRecorder()
+	r.ServeHTTP(w2, req2)
+	modTime2, _ := os.Stat(path)
+	if modTime1.ModTime() != modTime2.ModTime() {
+		t.Error("expected second request to reuse the cached file, not regenerate it")
+	}
+}
@@ -0,0 +1,228 @@
+package handler
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/1055373165/blog/cache"
+	"github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/service"
+	"github.com/1055373165/blog/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// popularArticlesWindowLimit bounds how many articles GetPopularArticles
+// returns when from/to is given, matching the unwindowed default.
+const popularArticlesWindowLimit = 10
+
+// PopularArticlesWindowResponse is GetPopularArticles' payload when
+// from/to is given: the window's most-liked published articles, plus -
+// under compare=previous - each one's like count from the immediately
+// preceding window of equal length and the delta between the two.
+type PopularArticlesWindowResponse struct {
+	From     time.Time                      `json:"from"`
+	To       time.Time                      `json:"to"`
+	Articles []service.PopularArticleWindow `json:"articles"`
+	Previous map[uint]int64                 `json:"previous_likes,omitempty"`
+	Delta    map[uint]int64                 `json:"delta_likes,omitempty"`
+}
+
+// StatsWindowResponse is GetStats' payload when from/to is given: the
+// window's totals, plus - under compare=previous - the immediately
+// preceding window's totals and the delta between the two.
+type StatsWindowResponse struct {
+	From     time.Time            `json:"from"`
+	To       time.Time            `json:"to"`
+	Window   service.StatsWindow  `json:"window"`
+	Previous *service.StatsWindow `json:"previous,omitempty"`
+	Delta    *service.StatsWindow `json:"delta,omitempty"`
+}
+
+// defaultTaxonomyTrendsMonths is the window used when the months query
+// parameter is omitted from GET /stats/taxonomy-trends.
+const defaultTaxonomyTrendsMonths = 6
+
+// GetPopularTags returns tags ranked by published-article usage, cached
+// under cache.KeyPopularTags.
+//
+// @Summary      List popular tags
+// @Description  Returns tags ranked by published-article usage, cached until invalidated.
+// @Tags         tags
+// @Produce      json
+// @Success      200  {object}  utils.Response{data=[]service.TagCount}
+// @Router       /tags/popular [get]
+func GetPopularTags(c *gin.Context) {
+	tags, err := cache.Default.GetOrCompute(cache.KeyPopularTags, func() (any, error) {
+		return service.GetPopularTags(db.WithCtx(c.Request.Context()))
+	})
+	if err != nil {
+		utils.FailFromDBError(c, err, "failed to load popular tags")
+		return
+	}
+	utils.ConditionalJSON(c, tags)
+}
+
+// GetPopularArticles returns the most-viewed published articles, cached
+// under cache.KeyPopularArticles. If from/to is given, it instead returns
+// the window's most-liked published articles - Article.Views has no
+// per-event timestamp log to window on, so the windowed ranking uses
+// "like" Reactions, the only timestamped per-article engagement signal in
+// this tree (see service.PopularArticleWindow) - uncached, since the
+// result varies by window.
+//
+// @Summary      List popular articles
+// @Description  Returns the most-viewed published articles, cached until invalidated. If from/to (YYYY-MM-DD, capped at 366 days) is given, returns the window's most-liked published articles instead; compare=previous adds each article's immediately preceding window's like count and the delta.
+// @Tags         articles
+// @Produce      json
+// @Param        from     query     string  false  "Window start, YYYY-MM-DD"
+// @Param        to       query     string  false  "Window end, YYYY-MM-DD"
+// @Param        compare  query     string  false  "Set to 'previous' to include the preceding window's totals and deltas"
+// @Success      200  {object}  utils.Response{data=[]models.Article}
+// @Failure      400  {object}  utils.Response
+// @Router       /articles/popular [get]
+func GetPopularArticles(c *gin.Context) {
+	dr, hasRange, compare, ok := parseOptionalDateRange(c)
+	if !ok {
+		return
+	}
+	if !hasRange {
+		articles, err := cache.Default.GetOrCompute(cache.KeyPopularArticles, func() (any, error) {
+			return service.GetPopularArticles(db.WithCtx(c.Request.Context()), 10)
+		})
+		if err != nil {
+			utils.FailFromDBError(c, err, "failed to load popular articles")
+			return
+		}
+		utils.ConditionalJSON(c, articles)
+		return
+	}
+
+	conn := db.WithCtx(c.Request.Context())
+	articles, err := service.GetPopularArticlesWindow(conn, popularArticlesWindowLimit, dr)
+	if err != nil {
+		utils.FailFromDBError(c, err, "failed to load popular articles")
+		return
+	}
+
+	resp := PopularArticlesWindowResponse{From: dr.From, To: dr.To, Articles: articles}
+	if compare {
+		ids := make([]uint, len(articles))
+		for i, a := range articles {
+			ids[i] = a.ID
+		}
+		previous, err := service.GetArticleLikeCounts(conn, ids, dr.Previous())
+		if err != nil {
+			utils.FailFromDBError(c, err, "failed to load popular articles")
+			return
+		}
+		resp.Previous = previous
+		resp.Delta = make(map[uint]int64, len(articles))
+		for _, a := range articles {
+			resp.Delta[a.ID] = a.WindowLikes - previous[a.ID]
+		}
+	}
+	utils.OK(c, resp)
+}
+
+// GetStats returns site-wide counts, cached under cache.KeyStatsSummary.
+// If from/to is given, it instead returns how much happened during that
+// window - new articles/blogs/users and "like" Reactions created within
+// it - uncached, since the result varies by window.
+//
+// @Summary      Get site stats
+// @Description  Returns site-wide counts, cached until invalidated. If from/to (YYYY-MM-DD, capped at 366 days) is given, returns activity within the window instead; compare=previous adds the preceding window's totals and the delta.
+// @Tags         stats
+// @Produce      json
+// @Param        from     query     string  false  "Window start, YYYY-MM-DD"
+// @Param        to       query     string  false  "Window end, YYYY-MM-DD"
+// @Param        compare  query     string  false  "Set to 'previous' to include the preceding window's totals and deltas"
+// @Success      200  {object}  utils.Response{data=service.Stats}
+// @Failure      400  {object}  utils.Response
+// @Router       /stats [get]
+func GetStats(c *gin.Context) {
+	dr, hasRange, compare, ok := parseOptionalDateRange(c)
+	if !ok {
+		return
+	}
+	if !hasRange {
+		stats, err := cache.Default.GetOrCompute(cache.KeyStatsSummary, func() (any, error) {
+			return service.GetStats(db.WithCtx(c.Request.Context()))
+		})
+		if err != nil {
+			utils.FailFromDBError(c, err, "failed to load stats")
+			return
+		}
+		utils.ConditionalJSON(c, stats)
+		return
+	}
+
+	conn := db.WithCtx(c.Request.Context())
+	window, err := service.GetStatsWindow(conn, dr)
+	if err != nil {
+		utils.FailFromDBError(c, err, "failed to load stats")
+		return
+	}
+
+	resp := StatsWindowResponse{From: dr.From, To: dr.To, Window: window}
+	if compare {
+		previous, err := service.GetStatsWindow(conn, dr.Previous())
+		if err != nil {
+			utils.FailFromDBError(c, err, "failed to load stats")
+			return
+		}
+		delta := service.StatsWindow{
+			NewArticles: window.NewArticles - previous.NewArticles,
+			NewBlogs:    window.NewBlogs - previous.NewBlogs,
+			NewUsers:    window.NewUsers - previous.NewUsers,
+			Likes:       window.Likes - previous.Likes,
+		}
+		resp.Previous = &previous
+		resp.Delta = &delta
+	}
+	utils.OK(c, resp)
+}
+
+// GetTaxonomyTrends returns the top 10 tags ranked by article-count
+// growth rate over the given window, cached since it's expensive to
+// compute and changes slowly.
+//
+// @Summary      Get trending tags
+// @Description  Returns the top 10 tags ranked by article-count growth rate over the last N months (default 6), cached until the TTL expires.
+// @Tags         stats
+// @Produce      json
+// @Param        months  query     int  false  "Window size in months"
+// @Success      200     {object}  utils.Response{data=[]service.TagTrend}
+// @Failure      400     {object}  utils.Response
+// @Router       /stats/taxonomy-trends [get]
+func GetTaxonomyTrends(c *gin.Context) {
+	months := defaultTaxonomyTrendsMonths
+	if v := c.Query("months"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 2 {
+			utils.Fail(c, 400, "invalid months")
+			return
+		}
+		months = n
+	}
+
+	trends, err := cache.Default.GetOrCompute(cache.TaxonomyTrendsKey(months), func() (any, error) {
+		return service.GetTaxonomyTrends(db.WithCtx(c.Request.Context()), months)
+	})
+	if err != nil {
+		utils.FailFromDBError(c, err, "failed to load taxonomy trends")
+		return
+	}
+	utils.OK(c, trends)
+}
+
+// GetCacheDebug exposes hit/miss counters for the hot-read cache.
+//
+// @Summary      Get cache debug stats
+// @Description  Exposes hit/miss counters for the in-process hot-read cache.
+// @Tags         stats
+// @Produce      json
+// @Success      200  {object}  utils.Response{data=cache.Stats}
+// @Router       /debug/cache [get]
+func GetCacheDebug(c *gin.Context) {
+	utils.OK(c, cache.Default.Stats())
+}
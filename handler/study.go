@@ -0,0 +1,367 @@
+package handler
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/middleware"
+	"github.com/1055373165/blog/models"
+	"github.com/1055373165/blog/service"
+	"github.com/1055373165/blog/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// UpdateStudyPlanRequest is the payload for PUT /api/study/plans/:id.
+type UpdateStudyPlanRequest struct {
+	Name                  *string `json:"name"`
+	MasteryReviewInterval *int    `json:"mastery_review_interval" binding:"omitempty,min=0"`
+}
+
+// UpdateStudyPlan updates a study plan owned by the caller, including the
+// mastery_review_interval knob that controls mastered-item decay.
+//
+// @Summary      Update a study plan
+// @Description  Updates a study plan owned by the caller, including its mastery_review_interval decay knob.
+// @Tags         study
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                     true  "Study plan ID"
+// @Param        request  body      UpdateStudyPlanRequest  true  "Fields to update"
+// @Success      200      {object}  utils.Response{data=models.StudyPlan}
+// @Failure      400      {object}  utils.Response{details=[]utils.FieldError}
+// @Failure      404      {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /study/plans/{id} [put]
+func UpdateStudyPlan(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid plan id")
+		return
+	}
+
+	var plan models.StudyPlan
+	if err := db.DB.First(&plan, id).Error; err != nil {
+		utils.RespondNotFoundOrForbidden(c, false, "study plan not found", "")
+		return
+	}
+	if plan.UserID != middleware.CurrentUserID(c) {
+		utils.RespondNotFoundOrForbidden(c, false, "study plan not found", "")
+		return
+	}
+
+	var req UpdateStudyPlanRequest
+	if !utils.BindJSON(c, &req) {
+		return
+	}
+
+	updates := map[string]any{}
+	if req.Name != nil {
+		updates["name"] = *req.Name
+	}
+	if req.MasteryReviewInterval != nil {
+		updates["mastery_review_interval"] = *req.MasteryReviewInterval
+	}
+	if len(updates) > 0 {
+		if err := db.DB.Model(&plan).Updates(updates).Error; err != nil {
+			utils.Fail(c, 500, "failed to update study plan")
+			return
+		}
+	}
+
+	utils.OK(c, plan)
+}
+
+// defaultDueStudyItemsPageSize and maxDueStudyItemsPageSize bound the
+// page_size query parameter for GET /api/study/plans/:id/due.
+const (
+	defaultDueStudyItemsPageSize = 20
+	maxDueStudyItemsPageSize     = 100
+)
+
+// dueStudyItemsResponse is the payload returned by
+// GET /api/study/plans/:id/due.
+type dueStudyItemsResponse struct {
+	Items    []models.StudyItem `json:"items"`
+	Total    int64              `json:"total"`
+	Page     int                `json:"page"`
+	PageSize int                `json:"page_size"`
+}
+
+// GetDueStudyItems returns a page of the caller's due items for a plan,
+// most overdue first, along with the total due count.
+//
+// @Summary      List due study items
+// @Description  Returns a page of the caller's due items for a study plan, most overdue first, along with the total due count.
+// @Tags         study
+// @Produce      json
+// @Param        id         path      int     true   "Study plan ID"
+// @Param        status     query     string  false  "Restrict to one status (new or review); defaults to both"
+// @Param        page       query     int     false  "Page number, starting at 1"
+// @Param        page_size  query     int     false  "Items per page, max 100"
+// @Success      200        {object}  utils.Response{data=dueStudyItemsResponse}
+// @Failure      400        {object}  utils.Response
+// @Failure      404        {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /study/plans/{id}/due [get]
+func GetDueStudyItems(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid plan id")
+		return
+	}
+
+	var plan models.StudyPlan
+	if err := db.DB.First(&plan, id).Error; err != nil {
+		utils.RespondNotFoundOrForbidden(c, false, "study plan not found", "")
+		return
+	}
+	if plan.UserID != middleware.CurrentUserID(c) {
+		utils.RespondNotFoundOrForbidden(c, false, "study plan not found", "")
+		return
+	}
+
+	var status *models.StudyItemStatus
+	if v := c.Query("status"); v != "" {
+		s := models.StudyItemStatus(v)
+		if s != models.StudyItemStatusNew && s != models.StudyItemStatusReview {
+			utils.Fail(c, 400, "invalid status, expected new or review")
+			return
+		}
+		status = &s
+	}
+
+	page := 1
+	if v := c.Query("page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			utils.Fail(c, 400, "invalid page")
+			return
+		}
+		page = n
+	}
+	pageSize := defaultDueStudyItemsPageSize
+	if v := c.Query("page_size"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxDueStudyItemsPageSize {
+			utils.Fail(c, 400, "invalid page_size")
+			return
+		}
+		pageSize = n
+	}
+
+	items, total, err := service.GetDueStudyItems(db.WithCtx(c.Request.Context()), uint(id), status, time.Now(), page, pageSize)
+	if err != nil {
+		utils.FailFromDBError(c, err, "failed to load due items")
+		return
+	}
+	utils.OK(c, dueStudyItemsResponse{Items: items, Total: total, Page: page, PageSize: pageSize})
+}
+
+// ShareStudyPlan marks a study plan owned by the caller public and issues
+// it a fresh share slug.
+//
+// @Summary      Share a study plan
+// @Description  Marks a study plan owned by the caller public and issues it a fresh share slug, invalidating any previous one.
+// @Tags         study
+// @Produce      json
+// @Param        id   path      int  true  "Study plan ID"
+// @Success      200  {object}  utils.Response{data=models.StudyPlan}
+// @Failure      404  {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /study/plans/{id}/share [post]
+func ShareStudyPlan(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid plan id")
+		return
+	}
+
+	plan, err := service.ShareStudyPlan(db.DB, uint(id), middleware.CurrentUserID(c))
+	if err != nil {
+		if err == service.ErrStudyPlanNotFound {
+			utils.RespondNotFoundOrForbidden(c, false, "study plan not found", "")
+			return
+		}
+		utils.Fail(c, 500, "failed to share study plan")
+		return
+	}
+	utils.OK(c, plan)
+}
+
+// UnshareStudyPlan makes a study plan owned by the caller private again,
+// invalidating its share slug.
+//
+// @Summary      Unshare a study plan
+// @Description  Makes a study plan owned by the caller private again, invalidating its share slug.
+// @Tags         study
+// @Produce      json
+// @Param        id   path      int  true  "Study plan ID"
+// @Success      200  {object}  utils.Response{data=models.StudyPlan}
+// @Failure      404  {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /study/plans/{id}/unshare [post]
+func UnshareStudyPlan(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid plan id")
+		return
+	}
+
+	plan, err := service.UnshareStudyPlan(db.DB, uint(id), middleware.CurrentUserID(c))
+	if err != nil {
+		if err == service.ErrStudyPlanNotFound {
+			utils.RespondNotFoundOrForbidden(c, false, "study plan not found", "")
+			return
+		}
+		utils.Fail(c, 500, "failed to unshare study plan")
+		return
+	}
+	utils.OK(c, plan)
+}
+
+// GetSharedStudyPlan returns the public preview of a shared study plan by
+// its share slug.
+//
+// @Summary      Get a shared study plan
+// @Description  Returns a shared study plan's name and item article titles/slugs, with no personal notes or progress. 404s if the plan was never shared or has since been unshared.
+// @Tags         study
+// @Produce      json
+// @Param        slug  path      string  true  "Share slug"
+// @Success      200   {object}  utils.Response{data=service.SharedStudyPlan}
+// @Failure      404   {object}  utils.Response
+// @Router       /study/plans/shared/{slug} [get]
+func GetSharedStudyPlan(c *gin.Context) {
+	plan, err := service.GetSharedStudyPlan(db.DB, c.Param("slug"))
+	if err != nil {
+		if err == service.ErrStudyPlanNotShared {
+			utils.Fail(c, 404, "study plan not found")
+			return
+		}
+		utils.Fail(c, 500, "failed to load shared study plan")
+		return
+	}
+	utils.OK(c, plan)
+}
+
+// CloneSharedStudyPlan creates a fresh study plan for the caller by
+// copying a shared plan's items.
+//
+// @Summary      Clone a shared study plan
+// @Description  Creates a fresh study plan for the caller by copying a shared plan's items, reset to status new with personal fields blanked. Items whose article is unpublished are skipped.
+// @Tags         study
+// @Produce      json
+// @Param        slug  path      string  true  "Share slug"
+// @Success      200   {object}  utils.Response{data=object{plan=models.StudyPlan,skipped=int}}
+// @Failure      404   {object}  utils.Response
+// @Failure      400   {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /study/plans/shared/{slug}/clone [post]
+func CloneSharedStudyPlan(c *gin.Context) {
+	plan, skipped, err := service.CloneSharedStudyPlan(db.DB, c.Param("slug"), middleware.CurrentUserID(c))
+	if err != nil {
+		switch err {
+		case service.ErrStudyPlanNotShared:
+			utils.Fail(c, 404, "study plan not found")
+		case service.ErrStudyPlanCloneTooLarge:
+			utils.Fail(c, 400, err.Error())
+		default:
+			utils.Fail(c, 500, "failed to clone study plan")
+		}
+		return
+	}
+	utils.OK(c, gin.H{"plan": plan, "skipped": skipped})
+}
+
+// AutoLogReadRequest is the payload for POST /api/study/auto-log.
+type AutoLogReadRequest struct {
+	ArticleID      uint `json:"article_id" binding:"required"`
+	DurationSecond int  `json:"duration_seconds" binding:"min=0"`
+}
+
+// AutoLogRead records a passive reading session against the caller's
+// matching study item, if any, without affecting the review schedule.
+//
+// @Summary      Log a passive article read
+// @Description  Records a passive reading session against the caller's matching study item, if any, without affecting the review schedule.
+// @Tags         study
+// @Accept       json
+// @Produce      json
+// @Param        request  body      AutoLogReadRequest  true  "Read session"
+// @Success      200      {object}  utils.Response
+// @Failure      400      {object}  utils.Response{details=[]utils.FieldError}
+// @Security     BearerAuth
+// @Router       /study/auto-log [post]
+func AutoLogRead(c *gin.Context) {
+	var req AutoLogReadRequest
+	if !utils.BindJSON(c, &req) {
+		return
+	}
+
+	err := service.LogArticleRead(db.DB, middleware.CurrentUserID(c), req.ArticleID, time.Now(),
+		time.Duration(req.DurationSecond)*time.Second)
+	if err != nil {
+		utils.Fail(c, 500, "failed to log read")
+		return
+	}
+	utils.OK(c, nil)
+}
+
+// GetStudyPlanAnalyticsContext returns a breakdown of a study plan owned
+// by the caller's sessions by time of day and by review method, optionally
+// restricted to a from/to date range, to help a learner notice when and
+// how they study best.
+//
+// @Summary      Get a study plan's session analytics context
+// @Description  Breaks down a study plan's sessions by time of day and review method, optionally restricted to a from/to date range, plus a one-line summary naming the best-rated time of day.
+// @Tags         study
+// @Produce      json
+// @Param        id    path      int     true   "Study plan ID"
+// @Param        from  query     string  false  "Range start, YYYY-MM-DD"
+// @Param        to    query     string  false  "Range end, YYYY-MM-DD"
+// @Success      200   {object}  utils.Response{data=service.StudySessionAnalyticsContext}
+// @Failure      400   {object}  utils.Response
+// @Failure      404   {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /study/plans/{id}/analytics/context [get]
+func GetStudyPlanAnalyticsContext(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid plan id")
+		return
+	}
+
+	var plan models.StudyPlan
+	if err := db.DB.First(&plan, id).Error; err != nil {
+		utils.RespondNotFoundOrForbidden(c, false, "study plan not found", "")
+		return
+	}
+	if plan.UserID != middleware.CurrentUserID(c) {
+		utils.RespondNotFoundOrForbidden(c, false, "study plan not found", "")
+		return
+	}
+
+	var from, to time.Time
+	if v := c.Query("from"); v != "" {
+		from, err = time.Parse("2006-01-02", v)
+		if err != nil {
+			utils.Fail(c, 400, "invalid from, expected YYYY-MM-DD")
+			return
+		}
+	}
+	if v := c.Query("to"); v != "" {
+		to, err = time.Parse("2006-01-02", v)
+		if err != nil {
+			utils.Fail(c, 400, "invalid to, expected YYYY-MM-DD")
+			return
+		}
+		to = to.Add(24*time.Hour - time.Nanosecond)
+	}
+
+	context, err := service.GetStudySessionAnalyticsContext(db.WithCtx(c.Request.Context()), uint(id), from, to)
+	if err != nil {
+		utils.FailFromDBError(c, err, "failed to load session analytics")
+		return
+	}
+	utils.OK(c, context)
+}
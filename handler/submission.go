@@ -0,0 +1,789 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/1055373165/blog/config"
+	"github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/events"
+	"github.com/1055373165/blog/metrics"
+	"github.com/1055373165/blog/middleware"
+	"github.com/1055373165/blog/models"
+	"github.com/1055373165/blog/service"
+	"github.com/1055373165/blog/siteconfig"
+	"github.com/1055373165/blog/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CreateSubmissionRequest is the payload for POST /api/submissions.
+type CreateSubmissionRequest struct {
+	Title    string `json:"title" binding:"required"`
+	Content  string `json:"content"`
+	Excerpt  string `json:"excerpt"`
+	SeriesID *uint  `json:"series_id"`
+}
+
+// createSubmissionResponse adds possible_duplicates to the usual
+// Submission response, only populated on creation.
+type createSubmissionResponse struct {
+	models.Submission
+	PossibleDuplicates []service.DuplicateCandidate `json:"possible_duplicates,omitempty"`
+}
+
+// CreateSubmission creates a draft Submission for the caller. If the
+// title or content closely resembles an existing published article,
+// creation still succeeds but the response includes possible_duplicates
+// so the UI can warn the contributor.
+//
+// @Summary      Create a submission
+// @Description  Creates a draft Submission awaiting editorial review for the caller. Warns via possible_duplicates if it resembles an existing published article.
+// @Tags         submissions
+// @Accept       json
+// @Produce      json
+// @Param        request  body      CreateSubmissionRequest  true  "Submission fields"
+// @Success      200      {object}  utils.Response{data=createSubmissionResponse}
+// @Failure      400      {object}  utils.Response{details=[]utils.FieldError}
+// @Failure      422      {object}  utils.Response{details=utils.ContentTooLargeDetail}
+// @Security     BearerAuth
+// @Router       /submissions [post]
+func CreateSubmission(c *gin.Context) {
+	var req CreateSubmissionRequest
+	if !utils.BindJSON(c, &req) {
+		return
+	}
+	if !utils.EnforceContentLength(c, "content", req.Content, siteconfig.KeyContentMaxBytes) {
+		return
+	}
+
+	if err := service.ValidateSeriesID(db.DB, req.SeriesID); err != nil {
+		utils.Fail(c, 400, err.Error())
+		return
+	}
+
+	content, _ := utils.NormalizeContentURLs(req.Content)
+
+	duplicates, err := service.FindPossibleDuplicates(db.DB, req.Title, content, service.DefaultDuplicateThreshold)
+	if err != nil {
+		utils.Fail(c, 500, "failed to check for duplicates")
+		return
+	}
+
+	excerpt := req.Excerpt
+	excerptAuto := excerpt == ""
+	if excerptAuto {
+		excerpt = utils.Excerpt(content)
+	}
+
+	submission := models.Submission{
+		AuthorID:    middleware.CurrentUserID(c),
+		Title:       req.Title,
+		Content:     content,
+		Status:      models.SubmissionStatusDraft,
+		SubmittedAt: time.Now(),
+		Excerpt:     excerpt,
+		ExcerptAuto: excerptAuto,
+		SeriesID:    req.SeriesID,
+	}
+	if err := db.DB.Create(&submission).Error; err != nil {
+		slog.Error("failed to create submission", "request_id", middleware.RequestID(c), "error", err)
+		utils.Fail(c, 500, "failed to create submission")
+		return
+	}
+
+	slog.Debug("submission created", "request_id", middleware.RequestID(c), "submission_id", submission.ID)
+	events.Default.Publish(events.Event{Name: "submission.created", Data: submission})
+	utils.OK(c, createSubmissionResponse{Submission: submission, PossibleDuplicates: duplicates})
+}
+
+// GetAllSubmissions lists submissions for the admin queue, newest
+// first, filterable by status and assignment.
+//
+// @Summary      List submissions
+// @Description  Returns submissions for the admin queue, newest first, filterable by status and assignment. Admin only.
+// @Tags         submissions
+// @Produce      json
+// @Param        status       query     string  false  "Filter by status"
+// @Param        assigned_to  query     string  false  "me, unassigned, or a numeric user ID"
+// @Success      200          {object}  utils.Response{data=[]models.Submission}
+// @Failure      400          {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /submissions/admin [get]
+func GetAllSubmissions(c *gin.Context) {
+	assignedTo := c.Query("assigned_to")
+	var assignedToUserID uint
+	switch assignedTo {
+	case "", "unassigned":
+	case "me":
+		assignedToUserID = middleware.CurrentUserID(c)
+	default:
+		id, err := strconv.ParseUint(assignedTo, 10, 64)
+		if err != nil {
+			utils.Fail(c, 400, "invalid assigned_to, expected me, unassigned, or a numeric user id")
+			return
+		}
+		assignedToUserID = uint(id)
+	}
+
+	submissions, err := service.GetAllSubmissions(db.DB, models.SubmissionStatus(c.Query("status")), assignedTo, assignedToUserID)
+	if err != nil {
+		utils.Fail(c, 500, "failed to load submissions")
+		return
+	}
+	utils.OK(c, submissions)
+}
+
+// AssignSubmissionReviewerRequest is the payload for
+// POST /api/submissions/:id/assign.
+type AssignSubmissionReviewerRequest struct {
+	ReviewerID uint `json:"reviewer_id" binding:"required"`
+}
+
+// AssignSubmissionReviewer sets a submission's assigned reviewer.
+//
+// @Summary      Assign a submission reviewer
+// @Description  Sets a submission's assigned reviewer. The reviewer must be an admin user.
+// @Tags         submissions
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                              true  "Submission ID"
+// @Param        request  body      AssignSubmissionReviewerRequest  true  "Reviewer to assign"
+// @Success      200      {object}  utils.Response
+// @Failure      400      {object}  utils.Response{details=[]utils.FieldError}
+// @Failure      404      {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /submissions/{id}/assign [post]
+func AssignSubmissionReviewer(c *gin.Context) {
+	submissionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid submission id")
+		return
+	}
+
+	var req AssignSubmissionReviewerRequest
+	if !utils.BindJSON(c, &req) {
+		return
+	}
+
+	if err := service.AssignReviewer(db.DB, uint(submissionID), req.ReviewerID); err != nil {
+		switch err {
+		case service.ErrSubmissionNotFound:
+			utils.Fail(c, 404, "submission not found")
+		case service.ErrInvalidReviewerID:
+			utils.Fail(c, 400, "reviewer_id must belong to an admin user")
+		default:
+			utils.Fail(c, 500, "failed to assign reviewer")
+		}
+		return
+	}
+
+	utils.OK(c, nil)
+}
+
+// ClaimSubmission lets the caller self-assign an unassigned submission.
+//
+// @Summary      Claim a submission
+// @Description  Self-assigns the caller as reviewer of an unassigned submission.
+// @Tags         submissions
+// @Produce      json
+// @Param        id  path      int  true  "Submission ID"
+// @Success      200  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Failure      409  {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /submissions/{id}/claim [post]
+func ClaimSubmission(c *gin.Context) {
+	submissionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid submission id")
+		return
+	}
+
+	if err := service.ClaimSubmission(db.DB, uint(submissionID), middleware.CurrentUserID(c)); err != nil {
+		switch err {
+		case service.ErrSubmissionNotFound:
+			utils.Fail(c, 404, "submission not found")
+		case service.ErrSubmissionAlreadyAssigned:
+			utils.Fail(c, 409, "submission already assigned")
+		default:
+			utils.Fail(c, 500, "failed to claim submission")
+		}
+		return
+	}
+
+	utils.OK(c, nil)
+}
+
+// ReviewSubmissionRequest is the payload for POST /api/submissions/:id/review.
+type ReviewSubmissionRequest struct {
+	Status   models.SubmissionStatus `json:"status" binding:"required,oneof=approved rejected changes_requested"`
+	Notes    string                  `json:"notes"`
+	Override bool                    `json:"override"`
+}
+
+// ReviewSubmission approves, rejects, or requests changes on a
+// submission. If it's assigned to a different reviewer than the
+// caller, it returns 409 unless override is set.
+//
+// @Summary      Review a submission
+// @Description  Approves, rejects, or requests changes on a submission. Warns with 409 if assigned to a different reviewer, unless override is set.
+// @Tags         submissions
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                       true  "Submission ID"
+// @Param        request  body      ReviewSubmissionRequest  true  "Review decision"
+// @Success      200      {object}  utils.Response{data=models.Submission}
+// @Failure      400      {object}  utils.Response{details=[]utils.FieldError}
+// @Failure      404      {object}  utils.Response
+// @Failure      409      {object}  utils.Response
+// @Failure      422      {object}  utils.Response{details=utils.ContentTooLargeDetail}
+// @Security     BearerAuth
+// @Router       /submissions/{id}/review [post]
+func ReviewSubmission(c *gin.Context) {
+	submissionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid submission id")
+		return
+	}
+
+	var req ReviewSubmissionRequest
+	if !utils.BindJSON(c, &req) {
+		return
+	}
+	if !utils.EnforceContentLength(c, "notes", req.Notes, siteconfig.KeyNoteMaxBytes) {
+		return
+	}
+
+	submission, err := service.ReviewSubmission(db.DB, uint(submissionID), middleware.CurrentUserID(c), req.Status, req.Notes, req.Override)
+	if err != nil {
+		switch err {
+		case service.ErrSubmissionNotFound:
+			utils.Fail(c, 404, "submission not found")
+		case service.ErrSubmissionReviewerMismatch:
+			utils.Fail(c, 409, "submission is assigned to a different reviewer, pass override to review anyway")
+		default:
+			utils.Fail(c, 500, "failed to review submission")
+		}
+		return
+	}
+
+	utils.OK(c, submission)
+}
+
+// PublishSubmissionRequest is the payload for
+// POST /api/submissions/:id/publish.
+type PublishSubmissionRequest struct {
+	Slug        string                    `json:"slug" binding:"required"`
+	SeriesID    *uint                     `json:"series_id"`
+	SeriesOrder *int                      `json:"series_order"`
+	AccessLevel models.ArticleAccessLevel `json:"access_level" binding:"omitempty,oneof=public members supporters"`
+}
+
+// PublishSubmission turns an approved submission into a published
+// Article and links the two. SeriesID/SeriesOrder, if given, override
+// the submission's own series placement; if the requested order slot
+// is already taken, later articles in the series shift down to make
+// room rather than the request failing.
+//
+// @Summary      Publish a submission
+// @Description  Creates a published Article from an approved submission and links the two. Admin only.
+// @Tags         submissions
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                       true  "Submission ID"
+// @Param        request  body      PublishSubmissionRequest  true  "Publish options"
+// @Success      200      {object}  utils.Response{data=models.Article}
+// @Failure      400      {object}  utils.Response{details=[]utils.FieldError}
+// @Failure      404      {object}  utils.Response
+// @Failure      409      {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /submissions/{id}/publish [post]
+func PublishSubmission(c *gin.Context) {
+	submissionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid submission id")
+		return
+	}
+
+	var req PublishSubmissionRequest
+	if !utils.BindJSON(c, &req) {
+		return
+	}
+
+	article, err := service.PublishSubmission(db.DB, uint(submissionID), service.PublishSubmissionRequest{
+		Slug:        req.Slug,
+		SeriesID:    req.SeriesID,
+		SeriesOrder: req.SeriesOrder,
+		AccessLevel: req.AccessLevel,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrSubmissionNotFound):
+			utils.Fail(c, 404, "submission not found")
+		case errors.Is(err, service.ErrSubmissionNotApproved):
+			utils.Fail(c, 409, "submission must be approved before publishing")
+		case errors.Is(err, service.ErrInvalidSeriesID):
+			utils.Fail(c, 400, err.Error())
+		case errors.Is(err, gorm.ErrDuplicatedKey):
+			utils.FailWithCode(c, 409, utils.ErrCodeSlugConflict)
+		default:
+			slog.Error("failed to publish submission", "request_id", middleware.RequestID(c), "error", err)
+			utils.Fail(c, 500, "failed to publish submission")
+		}
+		return
+	}
+
+	slog.Debug("submission published", "request_id", middleware.RequestID(c), "submission_id", submissionID, "article_id", article.ID)
+	events.Default.Publish(events.Event{Name: "submission.published", Data: article})
+	utils.OK(c, article)
+}
+
+// GetSubmissionQueueStats returns per-reviewer open counts and average
+// time-to-review, for load balancing the editorial queue.
+//
+// @Summary      Reviewer queue stats
+// @Description  Returns per-reviewer open submission counts and average time-to-review. Admin only.
+// @Tags         submissions
+// @Produce      json
+// @Success      200  {object}  utils.Response{data=[]service.ReviewerQueueStat}
+// @Failure      500  {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /submissions/admin/queue-stats [get]
+func GetSubmissionQueueStats(c *gin.Context) {
+	stats, err := service.GetReviewerQueueStats(db.DB)
+	if err != nil {
+		utils.Fail(c, 500, "failed to load queue stats")
+		return
+	}
+	utils.OK(c, stats)
+}
+
+// LinkSubmissionArticleRequest is the payload for
+// POST /api/submissions/:id/link-article.
+type LinkSubmissionArticleRequest struct {
+	ArticleID uint `json:"article_id" binding:"required"`
+}
+
+// LinkSubmissionArticle records the Article a submission became once
+// approved, so its content can later be diffed against the published
+// version.
+//
+// @Summary      Link a submission to its published article
+// @Description  Records the Article a submission became once approved. Admin only.
+// @Tags         submissions
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                            true  "Submission ID"
+// @Param        request  body      LinkSubmissionArticleRequest  true  "Article to link"
+// @Success      200      {object}  utils.Response
+// @Failure      400      {object}  utils.Response{details=[]utils.FieldError}
+// @Failure      404      {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /submissions/{id}/link-article [post]
+func LinkSubmissionArticle(c *gin.Context) {
+	submissionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid submission id")
+		return
+	}
+
+	var req LinkSubmissionArticleRequest
+	if !utils.BindJSON(c, &req) {
+		return
+	}
+
+	if err := service.LinkSubmissionToArticle(db.DB, uint(submissionID), req.ArticleID); err != nil {
+		if err == service.ErrSubmissionNotFound {
+			utils.Fail(c, 404, "submission not found")
+			return
+		}
+		utils.Fail(c, 500, "failed to link submission to article")
+		return
+	}
+
+	utils.OK(c, nil)
+}
+
+// GetSubmissionDiffAgainstPublished returns a word-level diff between
+// a submission's current content and its linked article's content.
+//
+// @Summary      Diff a submission against its published article
+// @Description  Returns a word-level diff between a submission's content and its linked article's content. Visible only to the submission's author and admins.
+// @Tags         submissions
+// @Produce      json
+// @Param        id  path      int  true  "Submission ID"
+// @Success      200  {object}  utils.Response{data=diff.Result}
+// @Failure      404  {object}  utils.Response
+// @Failure      409  {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /submissions/{id}/diff-published [get]
+func GetSubmissionDiffAgainstPublished(c *gin.Context) {
+	submissionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid submission id")
+		return
+	}
+
+	if _, err := checkSubmissionVisible(c, uint(submissionID)); err != nil {
+		return
+	}
+
+	result, err := service.DiffSubmissionAgainstPublished(db.DB, uint(submissionID))
+	if err != nil {
+		switch err {
+		case service.ErrSubmissionNotFound:
+			utils.Fail(c, 404, "submission not found")
+		case service.ErrSubmissionNotLinked:
+			utils.Fail(c, 409, "submission has no linked article yet")
+		default:
+			utils.Fail(c, 500, "failed to diff submission")
+		}
+		return
+	}
+
+	utils.OK(c, result)
+}
+
+// checkSubmissionVisible 404s and returns a non-nil error if the caller
+// may not see submissionID, mirroring service.checkSubmissionAccess's
+// author-or-admin rule for handlers that need the check before calling
+// into a different service function. A submission someone else owns
+// always 404s, the same as one that doesn't exist, so guessing IDs
+// can't be used to enumerate other people's drafts.
+func checkSubmissionVisible(c *gin.Context, submissionID uint) (*models.Submission, error) {
+	var submission models.Submission
+	if err := db.DB.First(&submission, submissionID).Error; err != nil {
+		utils.RespondNotFoundOrForbidden(c, false, "submission not found", "")
+		return nil, err
+	}
+	if !isSubmissionAdmin(c) && submission.AuthorID != middleware.CurrentUserID(c) {
+		utils.RespondNotFoundOrForbidden(c, false, "submission not found", "")
+		return nil, errSubmissionForbidden
+	}
+	return &submission, nil
+}
+
+var errSubmissionForbidden = errors.New("submission access denied")
+
+// CreateSubmissionCommentRequest is the payload for
+// POST /api/submissions/:id/comments.
+type CreateSubmissionCommentRequest struct {
+	Content  string `json:"content" binding:"required"`
+	Anchor   string `json:"anchor"`
+	ParentID *uint  `json:"parent_id"`
+}
+
+// isSubmissionAdmin is the shared request.CurrentRole -> isAdmin bool
+// conversion used by the submission comment handlers.
+func isSubmissionAdmin(c *gin.Context) bool {
+	return middleware.CurrentRole(c) == string(models.RoleAdmin)
+}
+
+// submissionCommentResponse adds a mentions array to the usual
+// SubmissionComment response, resolved from any @mentions in Content.
+type submissionCommentResponse struct {
+	models.SubmissionComment
+	Mentions []service.MentionedUser `json:"mentions,omitempty"`
+}
+
+// CreateSubmissionComment adds a comment, or a threaded reply if
+// parent_id is set, to a submission. Visible only to the submission's
+// author and admins (there is no reviewer-assignment concept in this
+// tree yet; see README "Known gaps"). Any @mentions in content notify
+// the mentioned users and are returned so the frontend can linkify them.
+//
+// @Summary      Comment on a submission
+// @Description  Adds an editorial comment, or threaded reply, to a submission, notifying any @mentioned users. Visible only to the author and admins.
+// @Tags         submissions
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                             true  "Submission ID"
+// @Param        request  body      CreateSubmissionCommentRequest  true  "Comment fields"
+// @Success      200      {object}  utils.Response{data=submissionCommentResponse}
+// @Failure      400      {object}  utils.Response{details=[]utils.FieldError}
+// @Failure      404      {object}  utils.Response
+// @Failure      422      {object}  utils.Response{details=utils.ContentTooLargeDetail}
+// @Security     BearerAuth
+// @Router       /submissions/{id}/comments [post]
+func CreateSubmissionComment(c *gin.Context) {
+	submissionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid submission id")
+		return
+	}
+
+	var req CreateSubmissionCommentRequest
+	if !utils.BindJSON(c, &req) {
+		return
+	}
+	if !utils.EnforceContentLength(c, "content", req.Content, siteconfig.KeyNoteMaxBytes) {
+		return
+	}
+
+	comment, mentions, err := service.CreateSubmissionComment(db.DB, uint(submissionID), middleware.CurrentUserID(c), isSubmissionAdmin(c), req.Content, req.Anchor, req.ParentID)
+	if err != nil {
+		switch err {
+		case service.ErrSubmissionNotFound, service.ErrSubmissionAccessDenied:
+			utils.RespondNotFoundOrForbidden(c, false, "submission not found", "")
+		default:
+			utils.Fail(c, 500, "failed to create comment")
+		}
+		return
+	}
+
+	utils.OK(c, submissionCommentResponse{SubmissionComment: *comment, Mentions: mentions})
+}
+
+// GetSubmissionComments lists a submission's comments, oldest first.
+// Visible only to the submission's author and admins.
+//
+// @Summary      List submission comments
+// @Description  Returns a submission's editorial comments, oldest first. Visible only to the author and admins.
+// @Tags         submissions
+// @Produce      json
+// @Param        id  path      int  true  "Submission ID"
+// @Success      200  {object}  utils.Response{data=[]models.SubmissionComment}
+// @Failure      404  {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /submissions/{id}/comments [get]
+func GetSubmissionComments(c *gin.Context) {
+	submissionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid submission id")
+		return
+	}
+
+	comments, err := service.GetSubmissionComments(db.DB, uint(submissionID), middleware.CurrentUserID(c), isSubmissionAdmin(c))
+	if err != nil {
+		switch err {
+		case service.ErrSubmissionNotFound, service.ErrSubmissionAccessDenied:
+			utils.RespondNotFoundOrForbidden(c, false, "submission not found", "")
+		default:
+			utils.Fail(c, 500, "failed to load comments")
+		}
+		return
+	}
+
+	utils.OK(c, comments)
+}
+
+// ResolveSubmissionComment marks a submission comment as resolved.
+// Visible only to the submission's author and admins.
+//
+// @Summary      Resolve a submission comment
+// @Description  Marks an editorial comment on a submission as resolved. Visible only to the author and admins.
+// @Tags         submissions
+// @Produce      json
+// @Param        id          path      int  true  "Submission ID"
+// @Param        comment_id  path      int  true  "Comment ID"
+// @Success      200         {object}  utils.Response
+// @Failure      404         {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /submissions/{id}/comments/{comment_id}/resolve [post]
+func ResolveSubmissionComment(c *gin.Context) {
+	submissionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid submission id")
+		return
+	}
+	commentID, err := strconv.ParseUint(c.Param("comment_id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid comment id")
+		return
+	}
+
+	err = service.ResolveSubmissionComment(db.DB, uint(submissionID), uint(commentID), middleware.CurrentUserID(c), isSubmissionAdmin(c))
+	if err != nil {
+		switch err {
+		case service.ErrSubmissionNotFound, service.ErrSubmissionAccessDenied:
+			utils.RespondNotFoundOrForbidden(c, false, "submission not found", "")
+		case service.ErrSubmissionCommentNotFound:
+			utils.Fail(c, 404, "comment not found")
+		default:
+			utils.Fail(c, 500, "failed to resolve comment")
+		}
+		return
+	}
+
+	utils.OK(c, nil)
+}
+
+// CreateSubmissionAttachment accepts a multipart file upload and attaches
+// it to a submission, scoped under a per-submission upload directory so
+// review previews keep working even if the file is later deleted from
+// the general upload pool. Only the submission's author or an admin may
+// attach files.
+//
+// @Summary      Attach a file to a submission
+// @Description  Accepts a multipart file upload and attaches it to a submission. Author or admin only.
+// @Tags         submissions
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id    path      int   true  "Submission ID"
+// @Param        file  formData  file  true  "File to upload"
+// @Success      200   {object}  utils.Response{data=models.SubmissionAttachment}
+// @Failure      400   {object}  utils.Response
+// @Failure      404   {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /submissions/{id}/attachments [post]
+func CreateSubmissionAttachment(c *gin.Context) {
+	submissionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid submission id")
+		return
+	}
+	if _, err := checkSubmissionVisible(c, uint(submissionID)); err != nil {
+		return
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		utils.Fail(c, 400, "missing file field")
+		return
+	}
+
+	name := randomFilename() + filepath.Ext(header.Filename)
+	dir := filepath.Join(config.App.UploadDir, "submissions", strconv.FormatUint(submissionID, 10))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		slog.Error("failed to prepare submission attachment dir", "request_id", middleware.RequestID(c), "error", err)
+		utils.Fail(c, 500, "failed to store file")
+		return
+	}
+	if err := c.SaveUploadedFile(header, filepath.Join(dir, name)); err != nil {
+		slog.Error("failed to save submission attachment", "request_id", middleware.RequestID(c), "error", err)
+		utils.Fail(c, 500, "failed to store file")
+		return
+	}
+
+	url := utils.ResolvePublicBase(c) + "/api/files/submissions/" + strconv.FormatUint(submissionID, 10) + "/" + name
+	attachment, err := service.CreateSubmissionAttachment(db.DB, uint(submissionID), header.Filename, url, header.Header.Get("Content-Type"), header.Size)
+	if err != nil {
+		utils.Fail(c, 500, "failed to save attachment")
+		return
+	}
+
+	metrics.UploadsBytesTotal.Add(float64(header.Size))
+	utils.OK(c, attachment)
+}
+
+// GetSubmissionAttachments lists a submission's attachments, oldest
+// first. There is no GetSubmission endpoint yet (see README "Known
+// gaps"), so this is exposed as its own endpoint rather than a field on
+// one.
+//
+// @Summary      List a submission's attachments
+// @Description  Returns a submission's attached files, oldest first. Author or admin only.
+// @Tags         submissions
+// @Produce      json
+// @Param        id   path      int  true  "Submission ID"
+// @Success      200  {object}  utils.Response{data=[]models.SubmissionAttachment}
+// @Failure      404  {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /submissions/{id}/attachments [get]
+func GetSubmissionAttachments(c *gin.Context) {
+	submissionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid submission id")
+		return
+	}
+	if _, err := checkSubmissionVisible(c, uint(submissionID)); err != nil {
+		return
+	}
+
+	attachments, err := service.GetSubmissionAttachments(db.DB, uint(submissionID))
+	if err != nil {
+		utils.Fail(c, 500, "failed to load attachments")
+		return
+	}
+	utils.OK(c, attachments)
+}
+
+// DeleteSubmissionAttachment removes an attachment, refusing if it's
+// still referenced in the submission's content.
+//
+// @Summary      Delete a submission attachment
+// @Description  Removes an attachment, refusing if it's still referenced in the submission's content. Author or admin only.
+// @Tags         submissions
+// @Produce      json
+// @Param        id             path      int  true  "Submission ID"
+// @Param        attachment_id  path      int  true  "Attachment ID"
+// @Success      200            {object}  utils.Response
+// @Failure      404            {object}  utils.Response
+// @Failure      409            {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /submissions/{id}/attachments/{attachment_id} [delete]
+func DeleteSubmissionAttachment(c *gin.Context) {
+	submissionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid submission id")
+		return
+	}
+	attachmentID, err := strconv.ParseUint(c.Param("attachment_id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid attachment id")
+		return
+	}
+	if _, err := checkSubmissionVisible(c, uint(submissionID)); err != nil {
+		return
+	}
+
+	err = service.DeleteSubmissionAttachment(db.DB, uint(submissionID), uint(attachmentID))
+	if err != nil {
+		switch err {
+		case service.ErrSubmissionNotFound:
+			utils.Fail(c, 404, "submission not found")
+		case service.ErrAttachmentNotFound:
+			utils.Fail(c, 404, "attachment not found")
+		case service.ErrAttachmentReferenced:
+			utils.Fail(c, 409, err.Error())
+		default:
+			utils.Fail(c, 500, "failed to delete attachment")
+		}
+		return
+	}
+
+	utils.OK(c, nil)
+}
+
+// GetSubmissionAttachmentFile serves an attachment's stored file. Author
+// or admin only, same as the other attachment endpoints.
+//
+// @Summary      Download a submission attachment
+// @Description  Serves an attachment's stored file. Author or admin only.
+// @Tags         submissions
+// @Produce      application/octet-stream
+// @Param        id             path  int  true  "Submission ID"
+// @Param        attachment_id  path  int  true  "Attachment ID"
+// @Success      200            {file}    file
+// @Failure      404            {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /submissions/{id}/attachments/{attachment_id}/file [get]
+func GetSubmissionAttachmentFile(c *gin.Context) {
+	submissionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid submission id")
+		return
+	}
+	attachmentID, err := strconv.ParseUint(c.Param("attachment_id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid attachment id")
+		return
+	}
+	if _, err := checkSubmissionVisible(c, uint(submissionID)); err != nil {
+		return
+	}
+
+	attachment, err := service.GetSubmissionAttachment(db.DB, uint(submissionID), uint(attachmentID))
+	if err != nil {
+		utils.Fail(c, 404, "attachment not found")
+		return
+	}
+
+	subdir := filepath.Join("submissions", strconv.FormatUint(submissionID, 10))
+	serveUploadedFile(c, subdir, attachment.URL)
+}
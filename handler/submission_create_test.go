@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/1055373165/blog/config"
+	blogdb "github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/middleware"
+	"github.com/1055373165/blog/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestCreateSubmissionRejectsANonexistentSeriesIDWith400(t *testing.T) {
+	conn, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	if err := conn.AutoMigrate(&models.Submission{}, &models.User{}, &models.Series{}, &models.Article{}); err != nil {
+		t.Fatalf("failed to migrate test db: %v", err)
+	}
+	blogdb.DB = conn
+	config.App = &config.Config{JWTSecret: "test-secret"}
+
+	author := models.User{Username: "author", Email: "author@example.com"}
+	conn.Create(&author)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/submissions", middleware.RequireAuth(), CreateSubmission)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/submissions", strings.NewReader(`{"title":"draft","content":"body","series_id":999}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", bearerToken(t, author.ID, string(models.RoleUser)))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a nonexistent series_id, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var count int64
+	conn.Model(&models.Submission{}).Count(&count)
+	if count != 0 {
+		t.Errorf("expected no submission created, found %d", count)
+	}
+}
@@ -0,0 +1,253 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/middleware"
+	"github.com/1055373165/blog/models"
+	"github.com/1055373165/blog/service"
+	"github.com/1055373165/blog/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// defaultFavoritesPageSize and maxFavoritesPageSize bound the page_size
+// query parameter for GET /users/me/favorites.
+const (
+	defaultFavoritesPageSize = 20
+	maxFavoritesPageSize     = 100
+)
+
+// GetMyFavorites returns the caller's favorited articles, most recently
+// favorited first, paginated.
+//
+// @Summary      List my favorited articles
+// @Description  Returns the caller's favorited articles, most recently favorited first, paginated.
+// @Tags         users
+// @Produce      json
+// @Param        page       query     int  false  "Page number, starting at 1"
+// @Param        page_size  query     int  false  "Articles per page, max 100"
+// @Success      200        {object}  utils.Response{data=[]models.Article}
+// @Failure      400        {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /users/me/favorites [get]
+func GetMyFavorites(c *gin.Context) {
+	page := 1
+	if v := c.Query("page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			utils.Fail(c, 400, "invalid page")
+			return
+		}
+		page = n
+	}
+	pageSize := defaultFavoritesPageSize
+	if v := c.Query("page_size"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxFavoritesPageSize {
+			utils.Fail(c, 400, "invalid page_size")
+			return
+		}
+		pageSize = n
+	}
+
+	articles, err := service.GetUserFavorites(db.DB, middleware.CurrentUserID(c), page, pageSize)
+	if err != nil {
+		utils.Fail(c, 500, "failed to load favorites")
+		return
+	}
+	utils.OK(c, articles)
+}
+
+// defaultNotificationsPageSize and maxNotificationsPageSize bound the
+// page_size query parameter for GET /users/me/notifications.
+const (
+	defaultNotificationsPageSize = 20
+	maxNotificationsPageSize     = 100
+)
+
+// notificationsResponse is the payload returned by
+// GET /api/users/me/notifications.
+type notificationsResponse struct {
+	Notifications []models.Notification `json:"notifications"`
+	UnreadCount   int64                 `json:"unread_count"`
+}
+
+// GetMyNotifications returns the caller's notifications, most recent
+// first, paginated, with their current unread count.
+//
+// @Summary      List my notifications
+// @Description  Returns the caller's notifications, most recent first, paginated, with their unread count.
+// @Tags         users
+// @Produce      json
+// @Param        page       query     int  false  "Page number, starting at 1"
+// @Param        page_size  query     int  false  "Notifications per page, max 100"
+// @Success      200        {object}  utils.Response{data=notificationsResponse}
+// @Failure      400        {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /users/me/notifications [get]
+func GetMyNotifications(c *gin.Context) {
+	page := 1
+	if v := c.Query("page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			utils.Fail(c, 400, "invalid page")
+			return
+		}
+		page = n
+	}
+	pageSize := defaultNotificationsPageSize
+	if v := c.Query("page_size"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxNotificationsPageSize {
+			utils.Fail(c, 400, "invalid page_size")
+			return
+		}
+		pageSize = n
+	}
+
+	notifications, unreadCount, err := service.GetUserNotifications(db.DB, middleware.CurrentUserID(c), page, pageSize)
+	if err != nil {
+		utils.Fail(c, 500, "failed to load notifications")
+		return
+	}
+	utils.OK(c, notificationsResponse{Notifications: notifications, UnreadCount: unreadCount})
+}
+
+// defaultMyContentPageSize and maxMyContentPageSize bound the page_size
+// query parameter for GET /users/me/content.
+const (
+	defaultMyContentPageSize = 20
+	maxMyContentPageSize     = 100
+)
+
+// GetMyContent returns the caller's own articles and blogs - their
+// personal "drafts and published work" dashboard - most recently
+// updated first, paginated. status filters to "draft" or "published"
+// (Article only; every Blog is already visible once created, so
+// status=draft returns no blogs and any other value returns all of
+// them - see service.GetMyContent). q does a case-insensitive
+// title/content substring match, scoped to the caller's own author_id
+// so another user's drafts never leak into the response.
+//
+// @Summary      List my articles and blogs
+// @Description  Returns the caller's own articles and blogs, most recently updated first, filtered by status and a search query, paginated.
+// @Tags         users
+// @Produce      json
+// @Param        page       query     int     false  "Page number, starting at 1"
+// @Param        page_size  query     int     false  "Items per page, max 100"
+// @Param        status     query     string  false  "Filter by status: draft or published"
+// @Param        q          query     string  false  "Search term matched against title/content"
+// @Success      200        {object}  utils.Response{data=service.MyContentResult}
+// @Failure      400        {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /users/me/content [get]
+func GetMyContent(c *gin.Context) {
+	page := 1
+	if v := c.Query("page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			utils.Fail(c, 400, "invalid page")
+			return
+		}
+		page = n
+	}
+	pageSize := defaultMyContentPageSize
+	if v := c.Query("page_size"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxMyContentPageSize {
+			utils.Fail(c, 400, "invalid page_size")
+			return
+		}
+		pageSize = n
+	}
+	status := c.Query("status")
+	if status != "" && status != string(models.ArticleStatusDraft) && status != string(models.ArticleStatusPublished) {
+		utils.Fail(c, 400, "invalid status")
+		return
+	}
+
+	result, err := service.GetMyContent(db.DB, middleware.CurrentUserID(c), status, c.Query("q"), page, pageSize)
+	if err != nil {
+		utils.Fail(c, 500, "failed to load content")
+		return
+	}
+	utils.OK(c, result)
+}
+
+// SetUserSupporterRequest is the payload for POST /api/admin/users/:id/supporter.
+type SetUserSupporterRequest struct {
+	Supporter bool `json:"supporter"`
+}
+
+// SetUserSupporter grants or revokes a user's supporter flag, gating
+// their access to Articles with AccessLevel "supporters". There's no
+// self-serve payment flow in this tree, so this is admin-only.
+//
+// @Summary      Grant or revoke a user's supporter flag
+// @Description  Grants or revokes a user's supporter flag, gating access to supporters-only articles.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                        true  "User ID"
+// @Param        request  body      SetUserSupporterRequest    true  "Desired supporter state"
+// @Success      200      {object}  utils.Response{data=models.User}
+// @Failure      400      {object}  utils.Response
+// @Failure      404      {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /admin/users/{id}/supporter [post]
+func SetUserSupporter(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid user id")
+		return
+	}
+
+	var req SetUserSupporterRequest
+	if !utils.BindJSON(c, &req) {
+		return
+	}
+
+	user, err := service.SetUserSupporter(db.DB, uint(id), req.Supporter)
+	if err != nil {
+		if err == service.ErrUserNotFound {
+			utils.Fail(c, 404, "user not found")
+			return
+		}
+		utils.Fail(c, 500, "failed to update supporter state")
+		return
+	}
+
+	utils.OK(c, user)
+}
+
+// MarkNotificationRead marks one of the caller's notifications as read.
+//
+// @Summary      Mark a notification read
+// @Description  Marks the caller's notification as read.
+// @Tags         users
+// @Produce      json
+// @Param        id   path      int  true  "Notification ID"
+// @Success      200  {object}  utils.Response
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Security     BearerAuth
+// @Router       /notifications/{id}/read [post]
+func MarkNotificationRead(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "invalid notification id")
+		return
+	}
+
+	if err := service.MarkNotificationRead(db.DB, middleware.CurrentUserID(c), uint(id)); err != nil {
+		if err == service.ErrNotificationNotFound {
+			utils.Fail(c, 404, "notification not found")
+			return
+		}
+		utils.Fail(c, 500, "failed to mark notification read")
+		return
+	}
+
+	utils.OK(c, nil)
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/1055373165/blog/config"
+	blogdb "github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/middleware"
+	"github.com/1055373165/blog/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupMyContentTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	conn, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	if err := conn.AutoMigrate(&models.User{}, &models.Article{}, &models.Blog{}); err != nil {
+		t.Fatalf("failed to migrate test db: %v", err)
+	}
+	blogdb.DB = conn
+	return conn
+}
+
+func myContentRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/api/users/me/content", middleware.RequireAuth(), GetMyContent)
+	return r
+}
+
+func TestGetMyContentDoesNotLeakAnotherUsersDrafts(t *testing.T) {
+	db := setupMyContentTestDB(t)
+	config.App = &config.Config{JWTSecret: "test-secret"}
+
+	me := models.User{Username: "me", Email: "me@example.com"}
+	other := models.User{Username: "other", Email: "other@example.com"}
+	db.Create(&me)
+	db.Create(&other)
+
+	db.Create(&models.Article{AuthorID: me.ID, Title: "my draft", Slug: "my-draft", Status: models.ArticleStatusDraft})
+	db.Create(&models.Article{AuthorID: other.ID, Title: "their draft", Slug: "their-draft", Status: models.ArticleStatusDraft})
+
+	r := myContentRouter()
+	req := httptest.NewRequest(http.MethodGet, "/api/users/me/content", nil)
+	req.Header.Set("Authorization", bearerToken(t, me.ID, string(models.RoleUser)))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "my draft") {
+		t.Errorf("expected the caller's own draft in the response, got %s", w.Body.String())
+	}
+	if strings.Contains(w.Body.String(), "their draft") {
+		t.Errorf("another user's draft leaked into the response: %s", w.Body.String())
+	}
+}
+
+func TestGetMyContentRejectsInvalidStatus(t *testing.T) {
+	db := setupMyContentTestDB(t)
+	config.App = &config.Config{JWTSecret: "test-secret"}
+
+	me := models.User{Username: "me", Email: "me@example.com"}
+	db.Create(&me)
+
+	r := myContentRouter()
+	req := httptest.NewRequest(http.MethodGet, "/api/users/me/content?status=scheduled", nil)
+	req.Header.Set("Authorization", bearerToken(t, me.ID, string(models.RoleUser)))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an unsupported status, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestGetMyContentRequiresAuth(t *testing.T) {
+	setupMyContentTestDB(t)
+	config.App = &config.Config{JWTSecret: "test-secret"}
+
+	r := myContentRouter()
+	req := httptest.NewRequest(http.MethodGet, "/api/users/me/content", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without a token, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,281 @@
+// Package jobs is the designated home for scheduled background work,
+// replacing the ad-hoc time.Ticker goroutines that used to accumulate
+// one per feature (reminder scheduler, saved search digests, and any
+// future publishing/analytics/backup job). A Registry runs each
+// registered Job on its own interval, persists last-run/next-run/
+// last-error to the JobRun table so status survives a restart,
+// guarantees a slow run is never overlapped by its own next tick, and
+// recovers a panicking Run rather than taking the process down with it.
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/1055373165/blog/models"
+	"gorm.io/gorm"
+)
+
+// Func is a job's unit of work. It receives ctx, canceled when the
+// registry is stopped, so a long-running job can return early during
+// graceful shutdown.
+type Func func(ctx context.Context, db *gorm.DB) error
+
+// Job is a named unit of scheduled work and the interval it runs on.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	// Timeout bounds a single run's ctx, so a stuck aggregation can't
+	// hold a database connection forever even if nothing ever cancels
+	// the registry's own context. Zero means no additional bound beyond
+	// the registry's lifetime.
+	Timeout time.Duration
+	Run     Func
+}
+
+// ErrJobNotFound is returned by Trigger for a name no Job was
+// registered under.
+var ErrJobNotFound = errors.New("job not found")
+
+// ErrJobAlreadyRunning is returned by Trigger when the job's previous
+// run (scheduled or manual) hasn't finished yet.
+var ErrJobAlreadyRunning = errors.New("job is already running")
+
+type entry struct {
+	job     Job
+	running sync.Mutex
+	done    chan struct{}
+}
+
+// Registry owns the scheduling loop for every Job registered with it.
+type Registry struct {
+	db  *gorm.DB
+	mu  sync.Mutex
+	ctx context.Context
+
+	jobs map[string]*entry
+}
+
+// NewRegistry creates an empty Registry. Register every Job before
+// calling Start.
+func NewRegistry(db *gorm.DB) *Registry {
+	return &Registry{db: db, jobs: make(map[string]*entry)}
+}
+
+// Register adds job to the registry. Call before Start; registering
+// after Start has no effect on a loop already running.
+func (r *Registry) Register(job Job) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.jobs[job.Name] = &entry{job: job}
+}
+
+// Status is a Job's current scheduling state, for GET /api/admin/jobs.
+type Status struct {
+	Name            string     `json:"name"`
+	IntervalSeconds int        `json:"interval_seconds"`
+	LastRunAt       *time.Time `json:"last_run_at,omitempty"`
+	NextRunAt       *time.Time `json:"next_run_at,omitempty"`
+	LastSuccess     bool       `json:"last_success"`
+	LastError       string     `json:"last_error,omitempty"`
+}
+
+// Start launches every registered job's ticking loop in its own
+// goroutine and returns a stop function that cancels their shared
+// context and waits for any in-flight run to finish.
+func (r *Registry) Start() (stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	r.mu.Lock()
+	r.ctx = ctx
+	entries := make([]*entry, 0, len(r.jobs))
+	for _, e := range r.jobs {
+		e.done = make(chan struct{})
+		entries = append(entries, e)
+	}
+	r.mu.Unlock()
+
+	for _, e := range entries {
+		r.seedNextRun(e.job)
+		go r.loop(ctx, e)
+	}
+
+	return func() {
+		cancel()
+		for _, e := range entries {
+			<-e.done
+		}
+	}
+}
+
+func (r *Registry) loop(ctx context.Context, e *entry) {
+	defer close(e.done)
+	ticker := time.NewTicker(e.job.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.runLocked(ctx, e, false)
+		}
+	}
+}
+
+// runLocked runs e.job if it isn't already running, recording the
+// outcome. skipLog suppresses the "already running" log line for
+// manual triggers, which report that case to the caller instead.
+func (r *Registry) runLocked(ctx context.Context, e *entry, skipLog bool) error {
+	if !e.running.TryLock() {
+		if !skipLog {
+			log.Printf("jobs: %s still running, skipping this tick", e.job.Name)
+		}
+		return ErrJobAlreadyRunning
+	}
+	defer e.running.Unlock()
+
+	runCtx := ctx
+	if e.job.Timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, e.job.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	runErr := runWithRecover(runCtx, e.job, r.db)
+	next := start.Add(e.job.Interval)
+	if runErr != nil {
+		log.Printf("jobs: %s failed: %v", e.job.Name, runErr)
+	}
+	if err := r.recordRun(e.job.Name, start, runErr, next); err != nil {
+		log.Printf("jobs: %s: failed to record run: %v", e.job.Name, err)
+	}
+	return runErr
+}
+
+func runWithRecover(ctx context.Context, job Job, db *gorm.DB) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return job.Run(ctx, db)
+}
+
+// Trigger runs name's job immediately, outside its regular schedule,
+// and returns its resulting Status. It returns ErrJobAlreadyRunning
+// rather than blocking if the job's previous run hasn't finished.
+func (r *Registry) Trigger(name string) (Status, error) {
+	r.mu.Lock()
+	e, ok := r.jobs[name]
+	ctx := r.ctx
+	r.mu.Unlock()
+	if !ok {
+		return Status{}, ErrJobNotFound
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if err := r.runLocked(ctx, e, true); err != nil && errors.Is(err, ErrJobAlreadyRunning) {
+		return Status{}, ErrJobAlreadyRunning
+	}
+
+	return r.statusFor(e.job)
+}
+
+// List returns every registered job's current Status, sorted by name.
+func (r *Registry) List() ([]Status, error) {
+	r.mu.Lock()
+	jobsCopy := make([]Job, 0, len(r.jobs))
+	for _, e := range r.jobs {
+		jobsCopy = append(jobsCopy, e.job)
+	}
+	r.mu.Unlock()
+
+	statuses := make([]Status, 0, len(jobsCopy))
+	for _, job := range jobsCopy {
+		status, err := r.statusFor(job)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses, nil
+}
+
+func (r *Registry) statusFor(job Job) (Status, error) {
+	status := Status{Name: job.Name, IntervalSeconds: int(job.Interval.Seconds())}
+
+	var row models.JobRun
+	err := r.db.First(&row, "name = ?", job.Name).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return status, nil
+	case err != nil:
+		return Status{}, err
+	default:
+		status.LastRunAt = row.LastRunAt
+		status.NextRunAt = row.NextRunAt
+		status.LastSuccess = row.LastSuccess
+		status.LastError = row.LastError
+		return status, nil
+	}
+}
+
+// seedNextRun persists an initial NextRunAt for a job with no JobRun row
+// yet, so status is meaningful before its first tick fires.
+func (r *Registry) seedNextRun(job Job) {
+	var existing models.JobRun
+	err := r.db.First(&existing, "name = ?", job.Name).Error
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return
+	}
+	next := time.Now().Add(job.Interval)
+	if err := r.db.Create(&models.JobRun{Name: job.Name, NextRunAt: &next, UpdatedAt: time.Now()}).Error; err != nil {
+		log.Printf("jobs: %s: failed to seed initial status: %v", job.Name, err)
+	}
+}
+
+func (r *Registry) recordRun(name string, startedAt time.Time, runErr error, nextRunAt time.Time) error {
+	errText := ""
+	if runErr != nil {
+		errText = runErr.Error()
+	}
+	row := models.JobRun{
+		Name:        name,
+		LastRunAt:   &startedAt,
+		NextRunAt:   &nextRunAt,
+		LastSuccess: runErr == nil,
+		LastError:   errText,
+		UpdatedAt:   time.Now(),
+	}
+
+	var existing models.JobRun
+	err := r.db.First(&existing, "name = ?", name).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return r.db.Create(&row).Error
+	case err != nil:
+		return err
+	default:
+		return r.db.Model(&existing).Select("LastRunAt", "NextRunAt", "LastSuccess", "LastError", "UpdatedAt").Updates(row).Error
+	}
+}
+
+// Default is the process-wide registry, set up by Init. Handlers read
+// it to list and trigger jobs.
+var Default *Registry
+
+// Init creates Default. Call once at startup, mirroring events.Init and
+// presence.Init; register every Job and call Default.Start afterward.
+func Init(db *gorm.DB) {
+	Default = NewRegistry(db)
+}
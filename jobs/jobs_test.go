@@ -0,0 +1,199 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/1055373165/blog/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	conn, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	if err := conn.AutoMigrate(&models.JobRun{}); err != nil {
+		t.Fatalf("failed to migrate test db: %v", err)
+	}
+	return conn
+}
+
+func TestTriggerRunsJobImmediatelyAndRecordsStatus(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewRegistry(db)
+	var calls int32
+	r.Register(Job{
+		Name:     "noop",
+		Interval: time.Hour,
+		Run: func(ctx context.Context, db *gorm.DB) error {
+			atomic.AddInt32(&calls, 1)
+			return nil
+		},
+	})
+
+	before := time.Now()
+	status, err := r.Trigger("noop")
+	if err != nil {
+		t.Fatalf("Trigger returned error: %v", err)
+	}
+	if atomic.LoadInt32(&calls) != 1 {
+		t.Fatalf("expected job to run once, ran %d times", calls)
+	}
+	if !status.LastSuccess {
+		t.Error("expected LastSuccess true")
+	}
+	if status.LastRunAt == nil || status.LastRunAt.Before(before) {
+		t.Errorf("expected LastRunAt to be set to roughly now, got %v", status.LastRunAt)
+	}
+	if status.NextRunAt == nil || !status.NextRunAt.After(*status.LastRunAt) {
+		t.Errorf("expected NextRunAt after LastRunAt, got last=%v next=%v", status.LastRunAt, status.NextRunAt)
+	}
+	if got, want := status.NextRunAt.Sub(*status.LastRunAt), time.Hour; got < want-time.Second || got > want+time.Second {
+		t.Errorf("expected NextRunAt roughly an hour after LastRunAt, got %v", got)
+	}
+}
+
+func TestTriggerUnknownJobReturnsErrJobNotFound(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewRegistry(db)
+
+	if _, err := r.Trigger("does-not-exist"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected ErrJobNotFound, got %v", err)
+	}
+}
+
+func TestTriggerReturnsErrJobAlreadyRunningWhileOverlapping(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewRegistry(db)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	r.Register(Job{
+		Name:     "slow",
+		Interval: time.Hour,
+		Run: func(ctx context.Context, db *gorm.DB) error {
+			close(started)
+			<-release
+			return nil
+		},
+	})
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		r.Trigger("slow")
+	}()
+
+	<-started
+	if _, err := r.Trigger("slow"); !errors.Is(err, ErrJobAlreadyRunning) {
+		t.Errorf("expected ErrJobAlreadyRunning while the first run is in flight, got %v", err)
+	}
+	close(release)
+	wg.Wait()
+}
+
+func TestRunRecoversFromPanicAndRecordsLastError(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewRegistry(db)
+	r.Register(Job{
+		Name:     "panics",
+		Interval: time.Hour,
+		Run: func(ctx context.Context, db *gorm.DB) error {
+			panic("boom")
+		},
+	})
+
+	status, err := r.Trigger("panics")
+	if err != nil {
+		t.Fatalf("Trigger returned error: %v", err)
+	}
+	if status.LastSuccess {
+		t.Error("expected LastSuccess false after a panicking run")
+	}
+	if status.LastError == "" {
+		t.Error("expected LastError to be set")
+	}
+
+	// A panic in one run must not leave the job's mutex held, or every
+	// subsequent run (scheduled or manual) would report ErrJobAlreadyRunning
+	// forever.
+	if _, err := r.Trigger("panics"); err != nil {
+		t.Fatalf("expected a second Trigger to run after the panic was recovered, got %v", err)
+	}
+}
+
+func TestListReturnsAllRegisteredJobsSortedByName(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewRegistry(db)
+	r.Register(Job{Name: "zeta", Interval: time.Minute, Run: func(ctx context.Context, db *gorm.DB) error { return nil }})
+	r.Register(Job{Name: "alpha", Interval: time.Minute, Run: func(ctx context.Context, db *gorm.DB) error { return nil }})
+
+	statuses, err := r.List()
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if len(statuses) != 2 {
+		t.Fatalf("expected 2 statuses, got %d", len(statuses))
+	}
+	if statuses[0].Name != "alpha" || statuses[1].Name != "zeta" {
+		t.Errorf("expected sorted [alpha zeta], got [%s %s]", statuses[0].Name, statuses[1].Name)
+	}
+}
+
+func TestStartTicksOnIntervalAndStopWaitsForInFlightRun(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewRegistry(db)
+
+	var calls int32
+	inFlight := make(chan struct{})
+	proceed := make(chan struct{})
+	r.Register(Job{
+		Name:     "ticking",
+		Interval: 10 * time.Millisecond,
+		Run: func(ctx context.Context, db *gorm.DB) error {
+			atomic.AddInt32(&calls, 1)
+			close(inFlight)
+			<-proceed
+			return nil
+		},
+	})
+
+	stop := r.Start()
+
+	select {
+	case <-inFlight:
+	case <-time.After(time.Second):
+		t.Fatal("expected the job to have ticked at least once")
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		t.Fatal("Stop returned before the in-flight run finished")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(proceed)
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Stop did not return after the in-flight run finished")
+	}
+
+	if atomic.LoadInt32(&calls) == 0 {
+		t.Error("expected the job to have run at least once")
+	}
+}
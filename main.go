@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"github.com/1055373165/blog/backup"
+	"github.com/1055373165/blog/cache"
+	"github.com/1055373165/blog/config"
+	"github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/events"
+	"github.com/1055373165/blog/export"
+	"github.com/1055373165/blog/jobs"
+	"github.com/1055373165/blog/models"
+	"github.com/1055373165/blog/presence"
+	"github.com/1055373165/blog/router"
+	"github.com/1055373165/blog/search"
+	"github.com/1055373165/blog/searchstats"
+	"github.com/1055373165/blog/seed"
+	"github.com/1055373165/blog/service"
+	"github.com/1055373165/blog/siteconfig"
+	"gorm.io/gorm"
+)
+
+//go:generate go run ./cmd/gendocs
+
+// @title           Blog API
+// @version         1.0
+// @description     REST API for the Blog platform: articles, blogs, categories, tags, series, search, and submissions.
+// @BasePath        /api
+//
+// @securityDefinitions.apikey BearerAuth
+// @in                          header
+// @name                        Authorization
+// @description                Type "Bearer" followed by a space and the JWT issued to the caller.
+func main() {
+	seedFlag := flag.Bool("seed", false, "create the deterministic demo dataset, then start the server as usual")
+	wipeDemoFlag := flag.Bool("wipe-demo", false, "remove the demo dataset created by -seed and exit without starting the server")
+	flag.Parse()
+
+	cfg := config.Load()
+	logger := config.NewLogger(cfg)
+
+	conn, err := db.Init(cfg.DSN, logger, time.Duration(cfg.SlowQueryThresholdMS)*time.Millisecond)
+	if err != nil {
+		log.Fatalf("failed to connect to database: %v", err)
+	}
+
+	if err := conn.AutoMigrate(
+		&models.User{},
+		&models.Series{},
+		&models.Article{},
+		&models.Blog{},
+		&models.Category{},
+		&models.CategoryPin{},
+		&models.Tag{},
+		&models.Submission{},
+		&models.StudyPlan{},
+		&models.StudyItem{},
+		&models.StudyLog{},
+		&models.AuditLog{},
+		&models.Favorite{},
+		&models.ArticleContributor{},
+		&models.ArticleChangelog{},
+		&models.Follow{},
+		&models.SavedSearch{},
+		&models.Notification{},
+		&models.SubmissionComment{},
+		&models.SlugRedirect{},
+		&models.SubmissionAttachment{},
+		&models.CommentMention{},
+		&models.Annotation{},
+		&models.Flashcard{},
+		&models.Reaction{},
+		&models.SiteConfig{},
+		&models.JobRun{},
+		&models.SearchStatistics{},
+	); err != nil {
+		log.Fatalf("failed to migrate database: %v", err)
+	}
+
+	if *wipeDemoFlag {
+		if err := seed.Wipe(conn); err != nil {
+			log.Fatalf("failed to wipe demo data: %v", err)
+		}
+		log.Println("demo data wiped")
+		return
+	}
+	if *seedFlag {
+		if cfg.Env == "production" {
+			log.Fatalf("refusing to seed demo data: BLOG_ENV is production")
+		}
+		summary, err := seed.Run(conn)
+		if err != nil {
+			log.Fatalf("failed to seed demo data: %v", err)
+		}
+		log.Printf("demo data seeded: %+v", summary)
+	}
+
+	if err := siteconfig.Load(conn); err != nil {
+		log.Fatalf("failed to load site config: %v", err)
+	}
+
+	cache.Init(time.Duration(cfg.CacheTTLSeconds) * time.Second)
+
+	if err := search.Init(cfg.SearchIndexPath); err != nil {
+		log.Fatalf("failed to open search index: %v", err)
+	}
+	service.TriggerSearchIndexRebuildIfStale(conn, cfg.SearchIndexPath)
+
+	stopPresence := presence.Init(1000, 500, 90*time.Second, 30*time.Second)
+	defer stopPresence()
+
+	stopSearchStats := searchstats.Init(conn, 500, 5*time.Second, 30*time.Second)
+	defer stopSearchStats()
+
+	export.Init()
+
+	events.Init()
+
+	if cfg.BackupScheduleEnabled {
+		stopBackupScheduler := backup.StartScheduler(conn, cfg.BackupDir, cfg.BackupRetention, 24*time.Hour, log.Printf)
+		defer stopBackupScheduler()
+	}
+
+	jobs.Init(conn)
+	jobTimeout := time.Duration(cfg.JobTimeoutSeconds) * time.Second
+	jobs.Default.Register(jobs.Job{
+		Name:     "mastery_decay",
+		Interval: time.Duration(cfg.MasteryDecayJobIntervalSeconds) * time.Second,
+		Timeout:  jobTimeout,
+		Run: func(ctx context.Context, db *gorm.DB) error {
+			_, err := service.RunMasteryDecay(db.WithContext(ctx), time.Now())
+			return err
+		},
+	})
+	jobs.Default.Register(jobs.Job{
+		Name:     "saved_search_digest",
+		Interval: time.Duration(cfg.SavedSearchDigestJobIntervalSeconds) * time.Second,
+		Timeout:  jobTimeout,
+		Run: func(ctx context.Context, db *gorm.DB) error {
+			return service.NotifySavedSearches(db.WithContext(ctx), logger, time.Now())
+		},
+	})
+	stopJobs := jobs.Default.Start()
+	defer stopJobs()
+
+	r := router.New(logger)
+	if err := r.Run(":" + cfg.Port); err != nil {
+		log.Fatalf("server exited: %v", err)
+	}
+}
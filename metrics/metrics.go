@@ -0,0 +1,62 @@
+// Package metrics owns every Prometheus collector for the service, kept
+// separate from handler/service so callers can increment counters
+// without importing handler and creating a cycle.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// HTTPRequestsTotal counts requests per route and status.
+	HTTPRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Total HTTP requests processed, labeled by method, path, and status.",
+	}, []string{"method", "path", "status"})
+
+	// HTTPRequestDuration is the per-route request latency histogram.
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency in seconds, labeled by method and path.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "path"})
+
+	// DBOpenConnections mirrors sql.DBStats.OpenConnections.
+	DBOpenConnections = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "db_open_connections",
+		Help: "Number of open database connections.",
+	})
+	// DBIdleConnections mirrors sql.DBStats.Idle.
+	DBIdleConnections = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "db_idle_connections",
+		Help: "Number of idle database connections.",
+	})
+	// DBWaitCount mirrors sql.DBStats.WaitCount.
+	DBWaitCount = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "db_wait_count_total",
+		Help: "Total number of connections waited for.",
+	})
+
+	// SearchIndexDocs tracks the Bleve index's document count.
+	SearchIndexDocs = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "search_index_documents",
+		Help: "Number of documents in the Bleve search index.",
+	})
+
+	// ArticlesPublishedTotal counts every article transition to published.
+	ArticlesPublishedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "articles_published_total",
+		Help: "Total number of articles published.",
+	})
+	// CommentsCreatedTotal counts every comment created.
+	CommentsCreatedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "comments_created_total",
+		Help: "Total number of comments created.",
+	})
+	// UploadsBytesTotal sums bytes received by the upload handler.
+	UploadsBytesTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "uploads_bytes_total",
+		Help: "Total bytes received by the upload endpoint.",
+	})
+)
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaxBodyBytes caps the request body at limit bytes, enforced with
+// http.MaxBytesReader before any handler reads it - a request over the
+// cap is rejected once it exceeds limit rather than being fully read
+// off the wire first. A smaller limit applied this way can't later be
+// relaxed by wrapping the body again with a larger one further down
+// the chain, so a route that needs a bigger cap (e.g. file uploads)
+// must be registered ahead of the group-wide Use call that applies
+// this middleware, not layered under it.
+func MaxBodyBytes(limit int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		c.Next()
+	}
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/1055373165/blog/models"
+	"github.com/1055373165/blog/siteconfig"
+	"github.com/1055373165/blog/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// exemptFromMaintenance reports whether path should stay reachable while
+// maintenance mode is on: health checks (so orchestrators don't flag the
+// instance unhealthy) and the admin API (so an admin can turn maintenance
+// mode back off).
+func exemptFromMaintenance(path string) bool {
+	return strings.HasPrefix(path, "/health/") || strings.HasPrefix(path, "/api/admin/")
+}
+
+// MaintenanceMode returns 503 for every request once
+// siteconfig.KeyMaintenanceMode is true, except health checks, the admin
+// API, and callers authenticated as admin. It must run ahead of routing
+// (registered via Engine.Use), so it parses the Authorization header
+// itself rather than relying on RequireAuth having already run.
+func MaintenanceMode() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !siteconfig.MaintenanceMode() {
+			c.Next()
+			return
+		}
+		if exemptFromMaintenance(c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+
+		header := c.GetHeader("Authorization")
+		token := strings.TrimPrefix(header, "Bearer ")
+		if token != "" {
+			if claims, err := utils.ParseToken(token); err == nil && claims.Role == string(models.RoleAdmin) {
+				c.Next()
+				return
+			}
+		}
+
+		utils.Fail(c, 503, "the site is temporarily down for maintenance")
+		c.Abort()
+	}
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Annotation anchors a highlight and optional note to a span of an
+// Article's Content, scoped to the user who created it. StudyItemID links
+// it back to the study plan item it was made from, when any; it's nil for
+// a highlight made outside study mode.
+//
+// StartOffset/EndOffset/QuotedText anchor the highlight at creation time.
+// They're byte offsets into Article.Content, captured alongside the text
+// they spanned so a later edit to Content can be detected and, where
+// possible, the anchor re-located (see service.GetArticleAnnotations).
+type Annotation struct {
+	ID          uint   `gorm:"primaryKey" json:"id"`
+	UserID      uint   `gorm:"index:idx_annotation_user_article;not null" json:"user_id"`
+	ArticleID   uint   `gorm:"index:idx_annotation_user_article;not null" json:"article_id"`
+	StudyItemID *uint  `gorm:"index" json:"study_item_id,omitempty"`
+	StartOffset int    `gorm:"not null" json:"start_offset"`
+	EndOffset   int    `gorm:"not null" json:"end_offset"`
+	QuotedText  string `gorm:"type:text;not null" json:"quoted_text"`
+	Note        string `gorm:"type:text" json:"note"`
+	Color       string `gorm:"size:20" json:"color"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,107 @@
+package models
+
+import "time"
+
+// ArticleStatus is the publication state of an Article.
+type ArticleStatus string
+
+const (
+	ArticleStatusDraft     ArticleStatus = "draft"
+	ArticleStatusPublished ArticleStatus = "published"
+)
+
+// ArticleAccessLevel gates how much of an Article's content a caller
+// may see; see service.CanAccessArticleContent.
+type ArticleAccessLevel string
+
+const (
+	ArticleAccessPublic     ArticleAccessLevel = "public"
+	ArticleAccessMembers    ArticleAccessLevel = "members"
+	ArticleAccessSupporters ArticleAccessLevel = "supporters"
+)
+
+// Article is a blog post: long-form written content.
+type Article struct {
+	ID             uint          `gorm:"primaryKey" json:"id"`
+	AuthorID       uint          `gorm:"index;not null" json:"author_id"`
+	Title          string        `gorm:"size:255;not null" json:"title"`
+	Slug           string        `gorm:"size:255;uniqueIndex;not null" json:"slug"`
+	Content        string        `gorm:"type:longtext" json:"content"`
+	Status         ArticleStatus `gorm:"size:20;not null;default:draft" json:"status"`
+	Views          int64         `gorm:"not null;default:0" json:"views"`
+	Likes          int64         `gorm:"not null;default:0" json:"likes"`
+	FavoritesCount int64         `gorm:"not null;default:0" json:"favorites_count"`
+
+	// IsPinned and IsFeatured place this article on the home feed, ahead of
+	// the latest-articles list. At most maxPinnedArticles may be pinned at
+	// once; see service.SetArticlePinned.
+	IsPinned   bool `gorm:"not null;default:false" json:"is_pinned"`
+	IsFeatured bool `gorm:"not null;default:false" json:"is_featured"`
+
+	// AccessLevel gates how much of Content a caller sees: "public" (the
+	// default) shows everyone the full article, "members" requires any
+	// logged-in account, and "supporters" additionally requires
+	// User.Supporter. See service.CanAccessArticleContent.
+	AccessLevel ArticleAccessLevel `gorm:"size:20;not null;default:public" json:"access_level"`
+
+	// ExpiresAt, once set and in the past, makes a published Article
+	// behave like an unpublished draft (see canViewArticle): hidden from
+	// anyone but its author or an admin, without deleting the row. Nil
+	// means the article never expires.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+
+	// SeriesID and SeriesOrder place this article within a Series. Both are
+	// nil for standalone articles. The pair is unique per series.
+	SeriesID    *uint `gorm:"index:idx_series_order,unique" json:"series_id,omitempty"`
+	SeriesOrder *int  `gorm:"index:idx_series_order,unique" json:"series_order,omitempty"`
+
+	// Language is the BCP-47 code this version is written in. TranslationGroupID
+	// links it to other language versions of the same content; nil means this
+	// article has no known translations.
+	Language           string `gorm:"size:10;not null;default:zh-CN" json:"language"`
+	TranslationGroupID *uint  `gorm:"index" json:"translation_group_id,omitempty"`
+
+	Tags []Tag `gorm:"many2many:article_tags;" json:"tags,omitempty"`
+
+	// CoverImage is a canonical relative reference into this site's own
+	// upload storage ("/api/files/<name>" or "/api/media/<name>"),
+	// validated against config.App.UploadDir on CreateArticle - an
+	// external hotlink is rejected rather than downloaded and rehosted.
+	// Empty means the article has no cover. CoverWidth/CoverHeight are
+	// its pixel dimensions, decoded once at validation time so
+	// GetArticleCover doesn't need to re-decode the file to compute a
+	// crop. CoverFocalX/CoverFocalY are normalized (0-1) coordinates of
+	// the point GetArticleCover keeps inside the crop, defaulting to
+	// the image's center.
+	CoverImage  string  `gorm:"size:500" json:"cover_image,omitempty"`
+	CoverWidth  int     `json:"cover_width,omitempty"`
+	CoverHeight int     `json:"cover_height,omitempty"`
+	CoverFocalX float64 `gorm:"not null;default:0.5" json:"cover_focal_x"`
+	CoverFocalY float64 `gorm:"not null;default:0.5" json:"cover_focal_y"`
+
+	// Excerpt is a short plain-text preview of Content, used for gated
+	// previews and listings. CreateArticle fills it with utils.Excerpt
+	// (Markdown stripped and truncated) unless the request supplies one
+	// explicitly, in which case ExcerptAuto is false so
+	// service.BackfillExcerpts never overwrites it.
+	Excerpt     string `gorm:"type:text" json:"excerpt,omitempty"`
+	ExcerptAuto bool   `gorm:"not null" json:"excerpt_auto"`
+
+	// PublishedAt is set once, when PublishSubmission turns a submission
+	// into this Article - the only path that produces a published
+	// Article. Feeds that want publication order (e.g. GetFeedArticles)
+	// should sort by this rather than CreatedAt/UpdatedAt, neither of
+	// which distinguishes "went live" from "row was created/touched".
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+
+	// UpdatedSignificantlyAt is set whenever UpdateArticle is called with
+	// a non-empty changelog_summary, distinct from UpdatedAt, which moves
+	// on every minor edit. Listing endpoints that want "freshness" rather
+	// than "last touched" should sort by this instead of UpdatedAt; nil
+	// means no significant update has been recorded yet. See
+	// ArticleChangelog.
+	UpdatedSignificantlyAt *time.Time `json:"updated_significantly_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
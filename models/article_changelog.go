@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// ArticleChangelog is a public, author-written note about a
+// significant update to an Article - e.g. "fixed benchmark numbers".
+// Entries exist only when the author opts in (see
+// UpdateArticleRequest's changelog_summary), so routine edits don't
+// create noise. See GetArticleChangelog.
+type ArticleChangelog struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ArticleID uint      `gorm:"index;not null" json:"article_id"`
+	AuthorID  uint      `gorm:"not null" json:"author_id"`
+	Summary   string    `gorm:"type:text;not null" json:"summary"`
+	ChangedAt time.Time `gorm:"not null" json:"changed_at"`
+}
@@ -0,0 +1,26 @@
+package models
+
+// ContributorRole describes how a contributor is attributed on an
+// article, independent of Article.AuthorID, which remains the primary
+// owner for permission checks.
+type ContributorRole string
+
+const (
+	ContributorRoleAuthor     ContributorRole = "author"
+	ContributorRoleCoAuthor   ContributorRole = "co-author"
+	ContributorRoleTranslator ContributorRole = "translator"
+	ContributorRoleEditor     ContributorRole = "editor"
+)
+
+// ArticleContributor attributes a User to an Article beyond its primary
+// AuthorID, e.g. a co-author or translator on a guest post. Position
+// orders contributors for display.
+type ArticleContributor struct {
+	ID        uint            `gorm:"primaryKey" json:"id"`
+	ArticleID uint            `gorm:"uniqueIndex:idx_article_contributor;not null" json:"article_id"`
+	UserID    uint            `gorm:"uniqueIndex:idx_article_contributor;not null" json:"user_id"`
+	Role      ContributorRole `gorm:"size:20;not null" json:"role"`
+	Position  int             `gorm:"not null;default:0" json:"position"`
+
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// AuditLog records a single destructive or privilege-sensitive operation
+// for later review. Before/After hold a JSON snapshot of the affected
+// entity and may be empty when not applicable (e.g. a delete has no After).
+type AuditLog struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	ActorID    uint      `gorm:"index;not null" json:"actor_id"`
+	Action     string    `gorm:"size:64;index;not null" json:"action"`
+	EntityType string    `gorm:"size:64;index;not null" json:"entity_type"`
+	EntityID   uint      `gorm:"index;not null" json:"entity_id"`
+	Before     string    `gorm:"type:text" json:"before,omitempty"`
+	After      string    `gorm:"type:text" json:"after,omitempty"`
+	IP         string    `gorm:"size:64" json:"ip"`
+	CreatedAt  time.Time `gorm:"index" json:"created_at"`
+}
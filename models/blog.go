@@ -0,0 +1,134 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Chapter is a single navigation point within an audio/video Blog.
+type Chapter struct {
+	StartSeconds float64 `json:"start_seconds"`
+	Title        string  `json:"title"`
+}
+
+// Chapters is a JSON-encoded list of Chapter, stored in a single column.
+type Chapters []Chapter
+
+// Scan implements sql.Scanner so GORM can read the JSON column back.
+func (c *Chapters) Scan(value any) error {
+	if value == nil {
+		*c = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		s, ok := value.(string)
+		if !ok {
+			return errors.New("chapters: unsupported scan source")
+		}
+		bytes = []byte(s)
+	}
+	if len(bytes) == 0 {
+		*c = nil
+		return nil
+	}
+	return json.Unmarshal(bytes, c)
+}
+
+// Value implements driver.Valuer so GORM can persist Chapters as JSON.
+func (c Chapters) Value() (driver.Value, error) {
+	if c == nil {
+		return nil, nil
+	}
+	return json.Marshal(c)
+}
+
+// WaveformPeaks is a JSON-encoded array of normalized amplitude peaks,
+// stored in a single column, the same way as Chapters.
+type WaveformPeaks []float64
+
+// Scan implements sql.Scanner so GORM can read the JSON column back.
+func (p *WaveformPeaks) Scan(value any) error {
+	if value == nil {
+		*p = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		s, ok := value.(string)
+		if !ok {
+			return errors.New("waveform_peaks: unsupported scan source")
+		}
+		bytes = []byte(s)
+	}
+	if len(bytes) == 0 {
+		*p = nil
+		return nil
+	}
+	return json.Unmarshal(bytes, p)
+}
+
+// Value implements driver.Valuer so GORM can persist WaveformPeaks as JSON.
+func (p WaveformPeaks) Value() (driver.Value, error) {
+	if p == nil {
+		return nil, nil
+	}
+	return json.Marshal(p)
+}
+
+// WaveformStatus is the generation state of a Blog's WaveformPeaks.
+type WaveformStatus string
+
+const (
+	WaveformStatusPending WaveformStatus = "pending"
+	WaveformStatusReady   WaveformStatus = "ready"
+	WaveformStatusFailed  WaveformStatus = "failed"
+)
+
+// Blog is an audio or video post, distinct from a written Article.
+type Blog struct {
+	ID          uint   `gorm:"primaryKey" json:"id"`
+	AuthorID    uint   `gorm:"index;not null" json:"author_id"`
+	Title       string `gorm:"size:255;not null" json:"title"`
+	Slug        string `gorm:"size:255;uniqueIndex;not null" json:"slug"`
+	Description string `gorm:"type:text" json:"description"`
+	MediaURL    string `gorm:"size:500;not null" json:"media_url"`
+
+	// Duration is the media length in seconds.
+	Duration float64 `json:"duration"`
+
+	Transcript string   `gorm:"type:longtext" json:"transcript,omitempty"`
+	Chapters   Chapters `gorm:"type:text" json:"chapters,omitempty"`
+
+	// Views mirrors Article.Views: bumped by service.IncrementBlogViews,
+	// not by GetBlogBySlug itself, so reading a blog never has the write
+	// side effect of inflating its own view count.
+	Views int64 `gorm:"not null;default:0" json:"views"`
+
+	// Likes mirrors Article.Likes: the "like" ReactionType is kept in sync
+	// with it by service.ToggleReaction for backward compatibility.
+	Likes int64 `gorm:"not null;default:0" json:"likes"`
+
+	// SeriesID and SeriesOrder place this episode within a Series,
+	// mirroring Article. Both are nil for standalone blogs. The pair is
+	// unique per series.
+	SeriesID    *uint `gorm:"index:idx_blog_series_order,unique" json:"series_id,omitempty"`
+	SeriesOrder *int  `gorm:"index:idx_blog_series_order,unique" json:"series_order,omitempty"`
+
+	// WaveformPeaks and WaveformStatus hold the result of asynchronous
+	// waveform generation; see service.GenerateBlogWaveform. Peaks are
+	// nil while pending or on failure.
+	WaveformPeaks  WaveformPeaks  `gorm:"type:text" json:"waveform_peaks,omitempty"`
+	WaveformStatus WaveformStatus `gorm:"size:20;not null;default:pending" json:"waveform_status"`
+
+	// ExpiresAt, once set and in the past, hides this Blog from anyone but
+	// its author or an admin (see canViewBlog), without deleting the row.
+	// Nil means the blog never expires. Unlike Article, Blog has no
+	// draft/published Status, so this is its only visibility gate.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// Category is a top-level content grouping. Categories may nest via ParentID.
+type Category struct {
+	ID       uint   `gorm:"primaryKey" json:"id"`
+	Name     string `gorm:"size:100;not null" json:"name"`
+	Slug     string `gorm:"size:100;uniqueIndex;not null" json:"slug"`
+	ParentID *uint  `gorm:"index" json:"parent_id,omitempty"`
+
+	// Content is a Markdown body rendered on the category's landing page.
+	Content    string `gorm:"type:longtext" json:"content,omitempty"`
+	CoverImage string `gorm:"size:500" json:"cover_image,omitempty"`
+
+	// IsVisible hides an internal organizational category from
+	// GetCategories/GetCategoryTree for non-admin callers without
+	// deleting it. Admins always see it. No DB-level default: GORM skips
+	// zero-value fields (false included) on insert and would otherwise
+	// apply a `default:true` column default and silently flip an
+	// explicit false back to true, so CreateCategory defaults it to
+	// true in application code instead (see handler.CreateCategory).
+	IsVisible bool `gorm:"not null" json:"is_visible"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Tag is a free-form label articles can be marked with.
+type Tag struct {
+	ID   uint   `gorm:"primaryKey" json:"id"`
+	Name string `gorm:"size:100;not null" json:"name"`
+	Slug string `gorm:"size:100;uniqueIndex;not null" json:"slug"`
+
+	// Content is a Markdown body rendered on the tag's landing page.
+	Content    string `gorm:"type:longtext" json:"content,omitempty"`
+	CoverImage string `gorm:"size:500" json:"cover_image,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
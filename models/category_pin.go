@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// CategoryPin marks an Article as a "start here" pinned post for a
+// Category, independent of Article.IsPinned (which pins to the home
+// feed, not a category). PinnedAt orders pins for display.
+type CategoryPin struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	CategoryID uint      `gorm:"uniqueIndex:idx_category_pin;not null" json:"category_id"`
+	ArticleID  uint      `gorm:"uniqueIndex:idx_category_pin;not null" json:"article_id"`
+	PinnedAt   time.Time `json:"pinned_at"`
+
+	Article *Article `gorm:"foreignKey:ArticleID" json:"article,omitempty"`
+}
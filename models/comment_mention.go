@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// CommentMention records an @mention of UserID resolved inside a
+// SubmissionComment at creation time.
+type CommentMention struct {
+	ID                  uint      `gorm:"primaryKey" json:"id"`
+	SubmissionCommentID uint      `gorm:"index:idx_comment_mention,unique;not null" json:"submission_comment_id"`
+	UserID              uint      `gorm:"index:idx_comment_mention,unique;not null" json:"user_id"`
+	CreatedAt           time.Time `json:"created_at"`
+}
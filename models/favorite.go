@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// Favorite is a user's saved-for-later bookmark on an article, distinct
+// from a like. The (user_id, article_id) pair is unique.
+type Favorite struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"uniqueIndex:idx_user_article_favorite;not null" json:"user_id"`
+	ArticleID uint      `gorm:"uniqueIndex:idx_user_article_favorite;not null" json:"article_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
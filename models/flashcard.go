@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Flashcard is a single per-concept prompt tied to a StudyItem, scheduled
+// for review independently of the item's own status/next_review_at (see
+// service.ReviewFlashcard). SourceAnchor is an optional quote from the
+// item's article the card was generated from; unlike Annotation.QuotedText
+// it is never re-located if the article changes, since it's just a
+// reference for the card's author, not a rendered highlight.
+type Flashcard struct {
+	ID           uint   `gorm:"primaryKey" json:"id"`
+	StudyItemID  uint   `gorm:"index;not null" json:"study_item_id"`
+	Front        string `gorm:"type:text;not null" json:"front"`
+	Back         string `gorm:"type:text;not null" json:"back"`
+	SourceAnchor string `gorm:"type:text" json:"source_anchor,omitempty"`
+
+	// Interval, Ease, and NextReviewAt are this card's own spaced-repetition
+	// state, advanced independently of its StudyItem by
+	// service.ReviewFlashcard.
+	Interval     int        `gorm:"not null;default:1" json:"interval"`
+	Ease         float64    `gorm:"not null;default:2.5" json:"ease"`
+	NextReviewAt *time.Time `json:"next_review_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
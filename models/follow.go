@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// FollowEntityType is what a Follow targets.
+type FollowEntityType string
+
+const (
+	FollowEntityTag      FollowEntityType = "tag"
+	FollowEntityCategory FollowEntityType = "category"
+	FollowEntitySeries   FollowEntityType = "series"
+	FollowEntityAuthor   FollowEntityType = "author"
+)
+
+// Follow records that UserID wants published articles matching
+// EntityType/EntityID (a Tag, Category, Series, or author User) surfaced
+// in their personalized feed; see service.GetFeedArticles.
+type Follow struct {
+	ID         uint             `gorm:"primaryKey" json:"id"`
+	UserID     uint             `gorm:"uniqueIndex:idx_follow;not null" json:"user_id"`
+	EntityType FollowEntityType `gorm:"size:20;uniqueIndex:idx_follow;not null" json:"entity_type"`
+	EntityID   uint             `gorm:"uniqueIndex:idx_follow;not null" json:"entity_id"`
+	CreatedAt  time.Time        `json:"created_at"`
+}
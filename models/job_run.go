@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// JobRun persists one registered jobs.Job's most recent execution state,
+// keyed by job name, so status survives a restart and GET /api/admin/jobs
+// can report it without asking the in-process scheduler.
+type JobRun struct {
+	Name        string     `gorm:"primaryKey;size:64" json:"name"`
+	LastRunAt   *time.Time `json:"last_run_at,omitempty"`
+	NextRunAt   *time.Time `json:"next_run_at,omitempty"`
+	LastSuccess bool       `json:"last_success"`
+	LastError   string     `gorm:"type:text" json:"last_error,omitempty"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
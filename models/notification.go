@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// Notification is an in-app alert for a user, e.g. a reply to a thread
+// they're subscribed to or an @mention in a submission comment (see
+// service.CreateSubmissionComment). It carries no link to what
+// triggered it beyond Kind/Message.
+type Notification struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	RecipientID uint       `gorm:"index;not null" json:"recipient_id"`
+	Kind        string     `gorm:"size:50;not null" json:"kind"`
+	Message     string     `gorm:"size:500;not null" json:"message"`
+	ReadAt      *time.Time `json:"read_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// ReactionType is one of a small, fixed set of emoji-style reactions a
+// caller may toggle on an Article or Blog. "like" is kept around so it
+// can be mapped onto the legacy Article.Likes/Blog.Likes counters; see
+// service.ToggleReaction.
+type ReactionType string
+
+const (
+	ReactionLike      ReactionType = "like"
+	ReactionHeart     ReactionType = "heart"
+	ReactionCelebrate ReactionType = "celebrate"
+	ReactionThinking  ReactionType = "thinking"
+)
+
+// ValidReactionTypes is the configurable set of types ToggleReaction
+// accepts, matched against the "oneof" binding tag on the request structs
+// in package handler.
+var ValidReactionTypes = []ReactionType{ReactionLike, ReactionHeart, ReactionCelebrate, ReactionThinking}
+
+// Reaction is a single user's reaction on exactly one of an Article or a
+// Blog; ArticleID and BlogID are mutually exclusive. A user may leave at
+// most one Reaction per (target, type), enforced by a unique index rather
+// than left to the check-then-create in service.toggleReaction alone, so
+// two concurrent toggles for the same target/user/type can't both insert:
+// one wins, the other hits the unique constraint and is treated as
+// "already reacted" rather than failing (see service.toggleReaction).
+// Two separate indexes are needed since a plain (article_id, blog_id,
+// user_id, reaction_type) index wouldn't catch it: SQL treats every NULL
+// as distinct, so rows that share a NULL column (every Blog reaction has
+// a NULL ArticleID, and vice versa) never collide on that column alone.
+type Reaction struct {
+	ID           uint         `gorm:"primaryKey" json:"id"`
+	ArticleID    *uint        `gorm:"index;uniqueIndex:idx_reactions_article_user_type" json:"article_id,omitempty"`
+	BlogID       *uint        `gorm:"index;uniqueIndex:idx_reactions_blog_user_type" json:"blog_id,omitempty"`
+	UserID       uint         `gorm:"index;not null;uniqueIndex:idx_reactions_article_user_type;uniqueIndex:idx_reactions_blog_user_type" json:"user_id"`
+	ReactionType ReactionType `gorm:"size:20;not null;uniqueIndex:idx_reactions_article_user_type;uniqueIndex:idx_reactions_blog_user_type" json:"reaction_type"`
+	CreatedAt    time.Time    `gorm:"index" json:"created_at"`
+}
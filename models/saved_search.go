@@ -0,0 +1,56 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// SearchFilters is a JSON-encoded set of facet filters for a SavedSearch,
+// stored in a single column.
+type SearchFilters map[string]any
+
+// Scan implements sql.Scanner so GORM can read the JSON column back.
+func (f *SearchFilters) Scan(value any) error {
+	if value == nil {
+		*f = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		s, ok := value.(string)
+		if !ok {
+			return errors.New("search filters: unsupported scan source")
+		}
+		bytes = []byte(s)
+	}
+	if len(bytes) == 0 {
+		*f = nil
+		return nil
+	}
+	return json.Unmarshal(bytes, f)
+}
+
+// Value implements driver.Valuer so GORM can persist SearchFilters as JSON.
+func (f SearchFilters) Value() (driver.Value, error) {
+	if f == nil {
+		return nil, nil
+	}
+	return json.Marshal(f)
+}
+
+// SavedSearch is a user's persisted search query, optionally re-run
+// daily to notify them of new matches.
+type SavedSearch struct {
+	ID             uint          `gorm:"primaryKey" json:"id"`
+	UserID         uint          `gorm:"index;not null" json:"user_id"`
+	Name           string        `gorm:"size:100;not null" json:"name"`
+	Query          string        `gorm:"size:255;not null" json:"query"`
+	Filters        SearchFilters `gorm:"type:text" json:"filters,omitempty"`
+	Notify         bool          `gorm:"not null;default:false" json:"notify"`
+	LastNotifiedAt *time.Time    `json:"last_notified_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
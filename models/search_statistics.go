@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// SearchStatistics records one meaningful search query, for popular- and
+// recent-query reporting via service.GetSearchStats. Rows are written in
+// batches by searchstats.Recorder, not synchronously from the search
+// request - see that package's doc comment for why.
+type SearchStatistics struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Query       string    `gorm:"size:255;not null;index" json:"query"`
+	ResultCount int       `gorm:"not null" json:"result_count"`
+	IP          string    `gorm:"size:64" json:"ip,omitempty"`
+	CreatedAt   time.Time `gorm:"index" json:"created_at"`
+}
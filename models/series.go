@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// Series groups a sequence of related Articles, e.g. a multi-part tutorial.
+type Series struct {
+	ID    uint   `gorm:"primaryKey" json:"id"`
+	Title string `gorm:"size:255;not null" json:"title"`
+	Slug  string `gorm:"size:255;uniqueIndex;not null" json:"slug"`
+
+	// Content is a Markdown body rendered on the series' landing page.
+	Content    string `gorm:"type:longtext" json:"content,omitempty"`
+	CoverImage string `gorm:"size:500" json:"cover_image,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// SiteConfig persists a single runtime configuration value, keyed by
+// name, so operators can change site behavior (maintenance mode, rate
+// limits, comment moderation, ...) without a redeploy. See package
+// siteconfig for the typed, cached accessor layer built on top of this
+// table and the allowlist of known keys.
+type SiteConfig struct {
+	Key       string    `gorm:"primaryKey;size:64" json:"key"`
+	Value     string    `gorm:"type:text" json:"value"`
+	Type      string    `gorm:"size:16;not null" json:"type"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// SlugRedirect remembers that entityType's OldSlug now lives at
+// NewSlug, so a GetXBySlug lookup that misses can still point clients
+// at the right place after a rename.
+type SlugRedirect struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	EntityType string    `gorm:"size:20;not null;uniqueIndex:idx_slug_redirect" json:"entity_type"`
+	OldSlug    string    `gorm:"size:255;not null;uniqueIndex:idx_slug_redirect" json:"old_slug"`
+	NewSlug    string    `gorm:"size:255;not null" json:"new_slug"`
+	CreatedAt  time.Time `json:"created_at"`
+}
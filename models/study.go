@@ -0,0 +1,75 @@
+package models
+
+import "time"
+
+// StudyItemStatus tracks where an item sits in the spaced-repetition cycle.
+type StudyItemStatus string
+
+const (
+	StudyItemStatusNew      StudyItemStatus = "new"
+	StudyItemStatusReview   StudyItemStatus = "review"
+	StudyItemStatusMastered StudyItemStatus = "mastered"
+)
+
+// ReviewType distinguishes how a StudyLog entry was produced.
+const (
+	ReviewTypeManual     = "manual"
+	ReviewTypeDecayCheck = "decay_check"
+	ReviewTypeRead       = "read"
+)
+
+// StudyPlan groups a set of StudyItems under a spaced-repetition schedule
+// owned by a single user.
+type StudyPlan struct {
+	ID     uint   `gorm:"primaryKey" json:"id"`
+	UserID uint   `gorm:"index;not null" json:"user_id"`
+	Name   string `gorm:"size:255;not null" json:"name"`
+
+	// MasteryReviewInterval is the number of days a mastered item may go
+	// unreviewed before it decays back into the review queue. 0 disables decay.
+	MasteryReviewInterval int `gorm:"default:90" json:"mastery_review_interval"`
+
+	// IsPublic and ShareSlug back the public "shared plan" view and
+	// cloning flow (see service.ShareStudyPlan). ShareSlug is nil until
+	// the plan is first shared, and is replaced (never reused) on every
+	// subsequent share so an old link never resurfaces a plan that was
+	// unshared and shared again.
+	IsPublic  bool    `gorm:"not null;default:false" json:"is_public"`
+	ShareSlug *string `gorm:"size:32;uniqueIndex" json:"share_slug,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Items []StudyItem `gorm:"foreignKey:PlanID" json:"items,omitempty"`
+}
+
+// StudyItem is a single piece of content being tracked for spaced repetition.
+type StudyItem struct {
+	ID        uint            `gorm:"primaryKey" json:"id"`
+	PlanID    uint            `gorm:"index;not null" json:"plan_id"`
+	ArticleID *uint           `gorm:"index" json:"article_id,omitempty"`
+	Status    StudyItemStatus `gorm:"size:20;not null;default:new" json:"status"`
+
+	// PinMastered excludes the item from automatic mastery decay even once
+	// MasteryReviewInterval has elapsed.
+	PinMastered bool `gorm:"not null;default:false" json:"pin_mastered"`
+
+	LastReviewedAt *time.Time `json:"last_reviewed_at,omitempty"`
+	NextReviewAt   *time.Time `json:"next_review_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// StudyLog records a single interaction with a StudyItem, whether a manual
+// review, a passive read, or an automated decay transition.
+type StudyLog struct {
+	ID          uint   `gorm:"primaryKey" json:"id"`
+	StudyItemID uint   `gorm:"index;not null" json:"study_item_id"`
+	ReviewType  string `gorm:"size:20;not null" json:"review_type"`
+	Rating      *int   `json:"rating,omitempty"`
+	// DurationSeconds is the measured time-on-page for passive "read" logs.
+	DurationSeconds int       `json:"duration_seconds,omitempty"`
+	ReviewedAt      time.Time `gorm:"index" json:"reviewed_at"`
+	CreatedAt       time.Time `json:"created_at"`
+}
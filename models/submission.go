@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// SubmissionStatus tracks a contributor draft through editorial review.
+type SubmissionStatus string
+
+const (
+	SubmissionStatusDraft            SubmissionStatus = "draft"
+	SubmissionStatusPending          SubmissionStatus = "pending_review"
+	SubmissionStatusChangesRequested SubmissionStatus = "changes_requested"
+	SubmissionStatusApproved         SubmissionStatus = "approved"
+	SubmissionStatusRejected         SubmissionStatus = "rejected"
+)
+
+// Submission is a contributor-authored draft that must pass editorial
+// review before becoming a published Article.
+type Submission struct {
+	ID                 uint             `gorm:"primaryKey" json:"id"`
+	AuthorID           uint             `gorm:"index;not null" json:"author_id"`
+	Title              string           `gorm:"size:255;not null" json:"title"`
+	Content            string           `gorm:"type:longtext" json:"content"`
+	Status             SubmissionStatus `gorm:"size:20;not null;default:draft" json:"status"`
+	AssignedReviewerID *uint            `gorm:"index" json:"assigned_reviewer_id,omitempty"`
+	ReviewNotes        string           `gorm:"type:text" json:"review_notes,omitempty"`
+	ReviewedAt         *time.Time       `json:"reviewed_at,omitempty"`
+
+	// ArticleID links an approved submission to the published Article it
+	// became, if any. It's set automatically by PublishSubmission, or
+	// manually via POST /api/submissions/:id/link-article for a
+	// submission published by some other path.
+	ArticleID *uint `gorm:"index" json:"article_id,omitempty"`
+
+	// SeriesID and SeriesOrder are the contributor's or reviewer's
+	// intended placement of this submission within a Series once
+	// published. They're plain, unindexed hints rather than Article's
+	// enforced-unique pair: PublishSubmission resolves SeriesOrder
+	// against the series' current articles - shifting later ones down
+	// rather than failing - only once the submission actually becomes
+	// an Article. Nil means standalone.
+	SeriesID    *uint `json:"series_id,omitempty"`
+	SeriesOrder *int  `json:"series_order,omitempty"`
+
+	// SubmittedAt is set at creation time, since this tree has no
+	// separate draft -> pending_review submit action yet; it exists so
+	// reviewer workload stats have a stable start point to measure from.
+	SubmittedAt time.Time `json:"submitted_at"`
+
+	// Excerpt and ExcerptAuto mirror Article's fields of the same name:
+	// a short plain-text preview generated by utils.Excerpt unless the
+	// contributor supplies one explicitly.
+	Excerpt     string `gorm:"type:text" json:"excerpt,omitempty"`
+	ExcerptAuto bool   `gorm:"not null" json:"excerpt_auto"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
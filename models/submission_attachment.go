@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// SubmissionAttachment is an image or file attached to a Submission
+// draft, stored under a per-submission directory so it survives
+// independently of the general upload pool.
+type SubmissionAttachment struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	SubmissionID uint      `gorm:"index;not null" json:"submission_id"`
+	Filename     string    `gorm:"size:255;not null" json:"filename"`
+	URL          string    `gorm:"size:500;not null" json:"url"`
+	ContentType  string    `gorm:"size:100" json:"content_type"`
+	Size         int64     `gorm:"not null" json:"size"`
+	CreatedAt    time.Time `json:"created_at"`
+}
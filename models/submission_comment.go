@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// SubmissionComment is an editorial remark on a Submission, optionally
+// anchored to a range of the draft text and threaded via ParentID.
+type SubmissionComment struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	SubmissionID uint      `gorm:"index;not null" json:"submission_id"`
+	AuthorID     uint      `gorm:"index;not null" json:"author_id"`
+	Content      string    `gorm:"type:text;not null" json:"content"`
+	IsResolved   bool      `gorm:"not null;default:false" json:"is_resolved"`
+	ParentID     *uint     `gorm:"index" json:"parent_id,omitempty"`
+	Anchor       string    `gorm:"size:255" json:"anchor,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Role enumerates the privilege levels a User can hold.
+type Role string
+
+const (
+	RoleUser  Role = "user"
+	RoleAdmin Role = "admin"
+)
+
+// User is an account holder: author, commenter, or admin.
+type User struct {
+	ID           uint   `gorm:"primaryKey" json:"id"`
+	Username     string `gorm:"size:64;uniqueIndex;not null" json:"username"`
+	Email        string `gorm:"size:255;uniqueIndex;not null" json:"email"`
+	PasswordHash string `gorm:"size:255;not null" json:"-"`
+	Role         Role   `gorm:"size:20;not null;default:user" json:"role"`
+
+	// Supporter gates access to Articles with AccessLevel "supporters".
+	// Admin-togglable only; there's no self-serve payment flow in this
+	// tree to set it (see README's "Known gaps").
+	Supporter bool `gorm:"not null;default:false" json:"supporter"`
+
+	// Avatar and AvatarThumbnail are URLs to the user's uploaded 256px
+	// and 64px avatar images, set together by POST /api/users/me/avatar
+	// and cleared back to "" together by DELETE /api/users/me/avatar -
+	// the same state as a user who never uploaded one. In that state,
+	// handler.GetDefaultAvatar renders a generated identicon on demand,
+	// the same way Article's OGImage is rendered on demand rather than
+	// stored.
+	Avatar          string `gorm:"size:255" json:"avatar"`
+	AvatarThumbnail string `gorm:"size:255" json:"avatar_thumbnail"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,171 @@
+// Package presence tracks which anonymous clients are currently reading
+// an article, entirely in memory. It is explicitly best-effort, like
+// package cache: safe to lose on restart, not shared across instances,
+// and never written to the database, since "N people reading now" is a
+// transient signal, not a fact worth persisting.
+package presence
+
+import (
+	"sync"
+	"time"
+)
+
+// fuzzBelow is the minimum real count reported as-is; anything lower is
+// rounded down to 0 so a single reader can't be singled out.
+const fuzzBelow = 3
+
+type article struct {
+	tokens     map[string]time.Time
+	lastAccess time.Time
+}
+
+// Tracker maintains a bounded, concurrency-safe map of article ID to the
+// set of client tokens currently reading it. Tokens not re-pinged within
+// ttl are expired by a background goroutine; articles are capped at
+// maxArticles via LRU eviction, and each article's tokens are capped at
+// maxTokensPerArticle to bound total memory regardless of traffic.
+type Tracker struct {
+	mu                  sync.Mutex
+	articles            map[uint]*article
+	maxArticles         int
+	maxTokensPerArticle int
+	ttl                 time.Duration
+}
+
+// New creates a Tracker. Call Start to begin the expiry loop.
+func New(maxArticles, maxTokensPerArticle int, ttl time.Duration) *Tracker {
+	return &Tracker{
+		articles:            make(map[uint]*article),
+		maxArticles:         maxArticles,
+		maxTokensPerArticle: maxTokensPerArticle,
+		ttl:                 ttl,
+	}
+}
+
+// Start launches the background goroutine that expires stale tokens
+// every interval. It returns a stop function, the same shutdown
+// convention used by backup.StartScheduler. Unlike package jobs, this
+// loop is deliberately not a Job: presence counts are an ephemeral,
+// in-memory signal with nothing to persist or report status on.
+func (t *Tracker) Start(interval time.Duration) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				t.expire(time.Now())
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
+
+// Ping records that token is reading articleID right now, creating the
+// article's entry if needed. If the tracker is already tracking
+// maxArticles articles, the least-recently-pinged one is evicted first.
+func (t *Tracker) Ping(articleID uint, token string) {
+	now := time.Now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	a, ok := t.articles[articleID]
+	if !ok {
+		if len(t.articles) >= t.maxArticles {
+			t.evictLRU()
+		}
+		a = &article{tokens: make(map[string]time.Time)}
+		t.articles[articleID] = a
+	}
+
+	if _, seen := a.tokens[token]; !seen && len(a.tokens) >= t.maxTokensPerArticle {
+		t.evictOldestToken(a)
+	}
+	a.tokens[token] = now
+	a.lastAccess = now
+}
+
+// Count returns articleID's current reader count, fuzzed to 0 below
+// fuzzBelow so a handful of readers can't be fingerprinted.
+func (t *Tracker) Count(articleID uint) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	a, ok := t.articles[articleID]
+	if !ok {
+		return 0
+	}
+	n := len(a.tokens)
+	if n < fuzzBelow {
+		return 0
+	}
+	return n
+}
+
+// evictLRU removes the article with the oldest lastAccess. Callers must
+// hold t.mu. Only called when the tracker is at capacity, so its O(n)
+// scan runs at most once per new article.
+func (t *Tracker) evictLRU() {
+	var oldestID uint
+	var oldestAt time.Time
+	first := true
+	for id, a := range t.articles {
+		if first || a.lastAccess.Before(oldestAt) {
+			oldestID, oldestAt, first = id, a.lastAccess, false
+		}
+	}
+	if !first {
+		delete(t.articles, oldestID)
+	}
+}
+
+// evictOldestToken removes a's least-recently-pinged token. Callers must
+// hold t.mu.
+func (t *Tracker) evictOldestToken(a *article) {
+	var oldestToken string
+	var oldestAt time.Time
+	first := true
+	for token, seenAt := range a.tokens {
+		if first || seenAt.Before(oldestAt) {
+			oldestToken, oldestAt, first = token, seenAt, false
+		}
+	}
+	if !first {
+		delete(a.tokens, oldestToken)
+	}
+}
+
+// expire drops tokens last seen before now-ttl, and any article left
+// with no tokens.
+func (t *Tracker) expire(now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for id, a := range t.articles {
+		for token, seenAt := range a.tokens {
+			if now.Sub(seenAt) > t.ttl {
+				delete(a.tokens, token)
+			}
+		}
+		if len(a.tokens) == 0 {
+			delete(t.articles, id)
+		}
+	}
+}
+
+// Default is the process-wide tracker, initialized by main. Handlers may
+// read it directly; it is nil until Init runs.
+var Default *Tracker
+
+// Init sets up Default with the given bounds and starts its expiry loop
+// running every interval, returning a stop function.
+func Init(maxArticles, maxTokensPerArticle int, ttl, interval time.Duration) (stop func()) {
+	Default = New(maxArticles, maxTokensPerArticle, ttl)
+	return Default.Start(interval)
+}
@@ -0,0 +1,127 @@
+package presence
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCountFuzzesSmallCountsToZero(t *testing.T) {
+	tr := New(10, 10, time.Minute)
+	tr.Ping(1, "a")
+	tr.Ping(1, "b")
+	if got := tr.Count(1); got != 0 {
+		t.Fatalf("expected 2 readers to fuzz to 0, got %d", got)
+	}
+
+	tr.Ping(1, "c")
+	if got := tr.Count(1); got != 3 {
+		t.Fatalf("expected 3 readers to report exactly, got %d", got)
+	}
+}
+
+func TestCountIsZeroForUnknownArticle(t *testing.T) {
+	tr := New(10, 10, time.Minute)
+	if got := tr.Count(999); got != 0 {
+		t.Fatalf("expected 0 for an untracked article, got %d", got)
+	}
+}
+
+func TestPingEvictsOldestTokenWhenArticleAtCapacity(t *testing.T) {
+	tr := New(10, 2, time.Minute)
+	tr.Ping(1, "a")
+	time.Sleep(time.Millisecond)
+	tr.Ping(1, "b")
+	time.Sleep(time.Millisecond)
+	tr.Ping(1, "c") // evicts "a", the oldest
+
+	tr.mu.Lock()
+	_, hasA := tr.articles[1].tokens["a"]
+	_, hasC := tr.articles[1].tokens["c"]
+	n := len(tr.articles[1].tokens)
+	tr.mu.Unlock()
+
+	if hasA {
+		t.Error("expected the oldest token to be evicted")
+	}
+	if !hasC {
+		t.Error("expected the newest token to be retained")
+	}
+	if n != 2 {
+		t.Errorf("expected exactly maxTokensPerArticle tokens, got %d", n)
+	}
+}
+
+func TestPingEvictsLeastRecentlyAccessedArticleWhenAtCapacity(t *testing.T) {
+	tr := New(2, 10, time.Minute)
+	tr.Ping(1, "a")
+	time.Sleep(time.Millisecond)
+	tr.Ping(2, "b")
+	time.Sleep(time.Millisecond)
+	tr.Ping(3, "c") // article 1 is LRU, gets evicted
+
+	tr.mu.Lock()
+	_, has1 := tr.articles[1]
+	_, has2 := tr.articles[2]
+	_, has3 := tr.articles[3]
+	n := len(tr.articles)
+	tr.mu.Unlock()
+
+	if has1 {
+		t.Error("expected the least-recently-accessed article to be evicted")
+	}
+	if !has2 || !has3 {
+		t.Error("expected the two most recently accessed articles to remain")
+	}
+	if n != 2 {
+		t.Errorf("expected exactly maxArticles articles tracked, got %d", n)
+	}
+}
+
+func TestExpireDropsStaleTokensAndEmptyArticles(t *testing.T) {
+	tr := New(10, 10, 50*time.Millisecond)
+	tr.Ping(1, "a")
+
+	tr.expire(time.Now().Add(-time.Hour)) // "now" before the ping: nothing expires yet
+	if _, ok := tr.articles[1]; !ok {
+		t.Fatal("expected article to still be tracked before its ttl elapses")
+	}
+
+	tr.expire(time.Now().Add(time.Hour)) // "now" well past the ttl: everything expires
+	if _, ok := tr.articles[1]; ok {
+		t.Fatal("expected the article entry to be dropped once its only token expired")
+	}
+}
+
+func TestPingIsConcurrencySafeUnderLoad(t *testing.T) {
+	tr := New(50, 5000, time.Minute)
+
+	const articles = 20
+	const pingsPerGoroutine = 200
+	var wg sync.WaitGroup
+	for g := 0; g < 100; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < pingsPerGoroutine; i++ {
+				articleID := uint(g%articles + 1)
+				token := fmt.Sprintf("g%d-%d", g, i%10)
+				tr.Ping(articleID, token)
+				tr.Count(articleID)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	if len(tr.articles) == 0 {
+		t.Fatal("expected at least one article to be tracked after concurrent pings")
+	}
+	for id, a := range tr.articles {
+		if len(a.tokens) > tr.maxTokensPerArticle {
+			t.Errorf("article %d exceeded maxTokensPerArticle: %d", id, len(a.tokens))
+		}
+	}
+}
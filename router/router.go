@@ -0,0 +1,212 @@
+package router
+
+import (
+	"log/slog"
+
+	"github.com/1055373165/blog/config"
+	"github.com/1055373165/blog/handler"
+	"github.com/1055373165/blog/middleware"
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+)
+
+// New builds the application's route tree, logging requests via logger.
+func New(logger *slog.Logger) *gin.Engine {
+	r := gin.New()
+	r.Use(gin.Recovery(), middleware.RequestLogger(logger), middleware.Metrics(), middleware.MaintenanceMode())
+
+	r.GET("/metrics", handler.MetricsGuard, handler.Metrics)
+	r.GET("/health/live", handler.Liveness)
+	r.GET("/health/ready", handler.Readiness)
+	r.GET("/articles/:slug/print", middleware.OptionalAuth(), handler.GetArticlePrintView)
+
+	api := r.Group("/api")
+	{
+		// The multipart file-upload routes are registered before the
+		// MaxBodyBytes middleware below so each keeps its own, larger
+		// cap (config.App.UploadMaxBytes, enforced inside the handler)
+		// rather than inheriting the smaller JSON default - a later,
+		// larger http.MaxBytesReader wrap can't relax an earlier,
+		// smaller one, so the only way to give a route a bigger cap
+		// than the group default is to register it ahead of the
+		// middleware that applies that default.
+		api.POST("/upload", middleware.RequireAuth(), handler.UploadFile)
+		api.POST("/submissions/:id/attachments", middleware.RequireAuth(), handler.CreateSubmissionAttachment)
+		api.POST("/admin/import/markdown", middleware.RequireAuth(), middleware.RequireAdmin(), handler.ImportMarkdown)
+		api.POST("/users/me/avatar", middleware.RequireAuth(), handler.UploadAvatar)
+
+		api.Use(middleware.MaxBodyBytes(config.App.MaxJSONBodyBytes))
+
+		api.GET("/articles/:id", middleware.OptionalAuth(), handler.GetArticle)
+		api.GET("/articles/:id/stats", middleware.OptionalAuth(), handler.GetArticleStats)
+		api.POST("/articles/:id/view", middleware.OptionalAuth(), handler.RecordArticleView)
+		api.GET("/articles/slug/:slug", middleware.OptionalAuth(), handler.GetArticleBySlug)
+		api.PUT("/series/:id/reorder", middleware.RequireAuth(), handler.ReorderSeries)
+		api.GET("/series", handler.GetSeriesList)
+		api.GET("/series/:id/blogs", handler.GetBlogsBySeries)
+
+		api.GET("/categories", middleware.OptionalAuth(), handler.GetCategories)
+		api.GET("/categories/tree", middleware.OptionalAuth(), handler.GetCategoryTree)
+		api.POST("/categories", middleware.RequireAuth(), handler.CreateCategory)
+		api.PUT("/categories/:id", middleware.RequireAuth(), handler.UpdateCategory)
+		api.DELETE("/categories/:id", middleware.RequireAuth(), handler.DeleteCategory)
+		api.POST("/categories/:id/pin/:article_id", middleware.RequireAuth(), middleware.RequireAdmin(), handler.PinArticleToCategory)
+		api.DELETE("/categories/:id/pin/:article_id", middleware.RequireAuth(), middleware.RequireAdmin(), handler.UnpinArticleFromCategory)
+		api.GET("/tags", handler.GetTags)
+		api.GET("/tags/popular", handler.GetPopularTags)
+		api.GET("/tags/:id/stats", handler.GetTagStats)
+		api.POST("/tags/:id/follow", middleware.RequireAuth(), handler.ToggleTagFollow)
+		api.POST("/categories/:id/follow", middleware.RequireAuth(), handler.ToggleCategoryFollow)
+		api.POST("/series/:id/follow", middleware.RequireAuth(), handler.ToggleSeriesFollow)
+		api.POST("/users/:id/follow", middleware.RequireAuth(), handler.ToggleAuthorFollow)
+		api.GET("/users/me/follows", middleware.RequireAuth(), handler.GetMyFollows)
+		api.GET("/users/me/feed", middleware.RequireAuth(), handler.GetMyFeed)
+
+		api.POST("/articles", middleware.RequireAuth(), handler.CreateArticle)
+		api.PUT("/articles/:id", middleware.RequireAuth(), handler.UpdateArticle)
+		api.GET("/articles/:id/changelog", middleware.OptionalAuth(), handler.GetArticleChangelog)
+		api.POST("/articles/:id/like", middleware.RequireAuth(), handler.ToggleArticleLike)
+		api.POST("/articles/:id/favorite", middleware.RequireAuth(), handler.ToggleArticleFavorite)
+		api.POST("/articles/:id/reactions", middleware.RequireAuth(), handler.ToggleArticleReaction)
+		api.GET("/articles/:id/reactions", middleware.OptionalAuth(), handler.GetArticleReactions)
+		api.GET("/articles/popular", handler.GetPopularArticles)
+		api.GET("/users/me/favorites", middleware.RequireAuth(), handler.GetMyFavorites)
+		api.GET("/users/me/notifications", middleware.RequireAuth(), handler.GetMyNotifications)
+		api.GET("/users/me/content", middleware.RequireAuth(), handler.GetMyContent)
+		api.DELETE("/users/me/avatar", middleware.RequireAuth(), handler.DeleteAvatar)
+		api.POST("/notifications/:id/read", middleware.RequireAuth(), handler.MarkNotificationRead)
+		api.GET("/articles/:id/og-image.png", handler.GetArticleOGImage)
+		api.GET("/articles/:id/cover", handler.GetArticleCover)
+		api.POST("/articles/:id/transfer", middleware.RequireAuth(), middleware.RequireAdmin(), handler.TransferArticle)
+		api.POST("/articles/:id/presence", handler.PingArticlePresence)
+		api.GET("/articles/:id/presence", handler.GetArticlePresence)
+		api.POST("/articles/:id/translations", middleware.RequireAuth(), handler.LinkArticleTranslation)
+		api.POST("/articles/:id/pin", middleware.RequireAuth(), middleware.RequireAdmin(), handler.PinArticle)
+		api.POST("/articles/:id/feature", middleware.RequireAuth(), middleware.RequireAdmin(), handler.FeatureArticle)
+		api.POST("/articles/:id/annotations", middleware.RequireAuth(), handler.CreateAnnotation)
+		api.GET("/articles/:id/annotations", middleware.RequireAuth(), handler.GetArticleAnnotations)
+		api.PUT("/articles/:id/annotations/:annotation_id", middleware.RequireAuth(), handler.UpdateAnnotation)
+		api.DELETE("/articles/:id/annotations/:annotation_id", middleware.RequireAuth(), handler.DeleteAnnotation)
+		api.GET("/home-feed", handler.GetHomeFeed)
+
+		api.GET("/stats", handler.GetStats)
+		api.GET("/stats/taxonomy-trends", handler.GetTaxonomyTrends)
+		api.GET("/debug/cache", handler.GetCacheDebug)
+
+		api.POST("/submissions", middleware.RequireAuth(), handler.CreateSubmission)
+		api.POST("/submissions/:id/comments", middleware.RequireAuth(), handler.CreateSubmissionComment)
+		api.GET("/submissions/:id/comments", middleware.RequireAuth(), handler.GetSubmissionComments)
+		api.POST("/submissions/:id/comments/:comment_id/resolve", middleware.RequireAuth(), handler.ResolveSubmissionComment)
+		api.GET("/submissions/admin", middleware.RequireAuth(), middleware.RequireAdmin(), handler.GetAllSubmissions)
+		api.GET("/submissions/admin/queue-stats", middleware.RequireAuth(), middleware.RequireAdmin(), handler.GetSubmissionQueueStats)
+		api.POST("/submissions/:id/assign", middleware.RequireAuth(), middleware.RequireAdmin(), handler.AssignSubmissionReviewer)
+		api.POST("/submissions/:id/claim", middleware.RequireAuth(), middleware.RequireAdmin(), handler.ClaimSubmission)
+		api.POST("/submissions/:id/review", middleware.RequireAuth(), middleware.RequireAdmin(), handler.ReviewSubmission)
+		api.POST("/submissions/:id/publish", middleware.RequireAuth(), middleware.RequireAdmin(), handler.PublishSubmission)
+		api.POST("/submissions/:id/link-article", middleware.RequireAuth(), middleware.RequireAdmin(), handler.LinkSubmissionArticle)
+		api.GET("/submissions/:id/diff-published", middleware.RequireAuth(), handler.GetSubmissionDiffAgainstPublished)
+		api.GET("/submissions/:id/attachments", middleware.RequireAuth(), handler.GetSubmissionAttachments)
+		api.GET("/submissions/:id/attachments/:attachment_id/file", middleware.RequireAuth(), handler.GetSubmissionAttachmentFile)
+		api.DELETE("/submissions/:id/attachments/:attachment_id", middleware.RequireAuth(), handler.DeleteSubmissionAttachment)
+
+		api.GET("/search", handler.SearchBlogsAndArticles)
+
+		savedSearches := api.Group("/search/saved", middleware.RequireAuth())
+		{
+			savedSearches.POST("", handler.CreateSavedSearch)
+			savedSearches.GET("", handler.GetMySavedSearches)
+			savedSearches.PUT("/:id", handler.UpdateSavedSearch)
+			savedSearches.DELETE("/:id", handler.DeleteSavedSearch)
+			savedSearches.GET("/:id/run", handler.RunSavedSearch)
+		}
+
+		api.GET("/files/:filename", handler.GetFile)
+		api.GET("/media/:filename", handler.GetMedia)
+		api.GET("/avatars/default/:user_id", handler.GetDefaultAvatar)
+		api.GET("/avatars/u/:hash/:filename", handler.GetAvatarFile)
+
+		blogs := api.Group("/blogs")
+		{
+			blogs.POST("", middleware.RequireAuth(), handler.CreateBlog)
+			blogs.PUT("/:id", middleware.RequireAuth(), handler.UpdateBlog)
+			blogs.GET("/slug/:slug", middleware.OptionalAuth(), handler.GetBlogBySlug)
+			blogs.GET("/:id/stats", middleware.OptionalAuth(), handler.GetBlogStats)
+			blogs.POST("/:id/view", middleware.OptionalAuth(), handler.RecordBlogView)
+			blogs.GET("/:id/transcript.vtt", handler.GetBlogTranscriptVTT)
+			blogs.POST("/:id/generate-waveform", middleware.RequireAuth(), handler.GenerateBlogWaveform)
+			blogs.POST("/:id/reactions", middleware.RequireAuth(), handler.ToggleBlogReaction)
+			blogs.GET("/:id/reactions", middleware.OptionalAuth(), handler.GetBlogReactions)
+			blogs.POST("/:id/transfer", middleware.RequireAuth(), middleware.RequireAdmin(), handler.TransferBlog)
+		}
+
+		api.GET("/study/plans/shared/:slug", handler.GetSharedStudyPlan)
+
+		study := api.Group("/study", middleware.RequireAuth())
+		{
+			study.PUT("/plans/:id", handler.UpdateStudyPlan)
+			study.GET("/plans/:id/due", handler.GetDueStudyItems)
+			study.GET("/plans/:id/analytics/context", handler.GetStudyPlanAnalyticsContext)
+			study.POST("/plans/:id/share", handler.ShareStudyPlan)
+			study.POST("/plans/:id/unshare", handler.UnshareStudyPlan)
+			study.POST("/plans/shared/:slug/clone", handler.CloneSharedStudyPlan)
+			study.POST("/auto-log", handler.AutoLogRead)
+			study.POST("/items/:item_id/cards", handler.CreateFlashcard)
+			study.GET("/items/:item_id/cards", handler.GetItemFlashcards)
+			study.PUT("/items/:item_id/cards/:card_id", handler.UpdateFlashcard)
+			study.DELETE("/items/:item_id/cards/:card_id", handler.DeleteFlashcard)
+			study.GET("/due-cards", handler.GetDueFlashcards)
+			study.POST("/cards/:id/review", handler.ReviewFlashcard)
+		}
+
+		admin := api.Group("/admin", middleware.RequireAuth(), middleware.RequireAdmin())
+		{
+			admin.GET("/health", handler.AdminHealth)
+			admin.POST("/normalize-content-urls", handler.NormalizeContentURLs)
+			admin.GET("/users", handler.GetUsers)
+			admin.GET("/users/export.csv", handler.ExportUsersCSV)
+			admin.POST("/users/:id/supporter", handler.SetUserSupporter)
+			admin.POST("/users/:id/transfer-content", handler.TransferUserContent)
+			admin.GET("/audit-log", handler.GetAuditLog)
+			admin.GET("/duplicates", handler.GetDuplicateArticles)
+			admin.GET("/covers/broken", handler.GetBrokenCoverReport)
+			admin.GET("/editorial-calendar", handler.GetEditorialCalendar)
+			admin.POST("/backups", handler.CreateBackup)
+			admin.GET("/backups", handler.GetBackups)
+			admin.GET("/backups/:name/download", handler.DownloadBackup)
+			admin.POST("/export/static", handler.StartStaticExport)
+			admin.GET("/export/static/status", handler.GetStaticExportStatus)
+			admin.POST("/export/static/cancel", handler.CancelStaticExport)
+			admin.GET("/export/static/download", handler.DownloadStaticExport)
+			admin.GET("/config", handler.GetSiteConfig)
+			admin.PUT("/config/:key", handler.UpdateSiteConfig)
+			admin.GET("/jobs", handler.GetJobs)
+			admin.POST("/jobs", handler.TriggerJob)
+			admin.POST("/recount-likes", handler.RecountLikes)
+			admin.GET("/search-stats", handler.GetSearchStats)
+			admin.GET("/search/consistency", handler.GetSearchConsistency)
+			admin.POST("/search/repair", handler.PostSearchRepair)
+			admin.POST("/seed-demo", handler.SeedDemo)
+			admin.POST("/backfill-excerpts", handler.BackfillExcerpts)
+		}
+
+		// AdminEvents authenticates via ?token= (EventSource can't set
+		// custom headers), so it can't live inside the admin group above.
+		api.GET("/admin/events", middleware.RequireAdminViaQueryToken(), handler.AdminEvents)
+
+		docsGroup := api.Group("/docs")
+		{
+			swaggerUI := ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.URL("/api/docs/openapi.json"))
+			docsGroup.GET("", func(c *gin.Context) { c.Redirect(302, "/api/docs/index.html") })
+			docsGroup.GET("/*any", func(c *gin.Context) {
+				if c.Param("any") == "/openapi.json" {
+					handler.GetOpenAPISpec(c)
+					return
+				}
+				swaggerUI(c)
+			})
+		}
+	}
+
+	return r
+}
@@ -0,0 +1,113 @@
+package router
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"log/slog"
+	"math/rand"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/1055373165/blog/config"
+	blogdb "github.com/1055373165/blog/db"
+	"github.com/1055373165/blog/models"
+	"github.com/1055373165/blog/siteconfig"
+	"github.com/1055373165/blog/utils"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// setupRouterTestDB migrates the tables the avatar-upload route touches
+// and loads siteconfig, mirroring how handler tests set up their own
+// in-memory DB, so this test exercises the real router.New() middleware
+// chain rather than a bespoke single-route gin.Engine.
+func setupRouterTestDB(t *testing.T) (*gorm.DB, models.User) {
+	t.Helper()
+	conn, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	if err := conn.AutoMigrate(&models.User{}, &models.SiteConfig{}); err != nil {
+		t.Fatalf("failed to migrate test db: %v", err)
+	}
+	if err := siteconfig.Load(conn); err != nil {
+		t.Fatalf("failed to load siteconfig: %v", err)
+	}
+	blogdb.DB = conn
+
+	user := models.User{Username: "alice", Email: "alice@example.com"}
+	if err := conn.Create(&user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	return conn, user
+}
+
+// largePNG returns a PNG over minSize bytes: a gradient compresses too
+// well to reliably clear a ~1MiB bound, so the pixels are randomized.
+func largePNG(t *testing.T, minSize int) []byte {
+	t.Helper()
+	const side = 900
+	img := image.NewRGBA(image.Rect(0, 0, side, side))
+	rng := rand.New(rand.NewSource(1))
+	for y := 0; y < side; y++ {
+		for x := 0; x < side; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(rng.Intn(256)), G: uint8(rng.Intn(256)), B: uint8(rng.Intn(256)), A: 0xff})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() < minSize {
+		t.Fatalf("generated PNG is only %d bytes, need at least %d", buf.Len(), minSize)
+	}
+	return buf.Bytes()
+}
+
+func TestUploadAvatarAcceptsFileLargerThanMaxJSONBodyBytes(t *testing.T) {
+	_, user := setupRouterTestDB(t)
+	config.App = &config.Config{
+		UploadDir:        t.TempDir(),
+		UploadMaxBytes:   10 << 20,
+		MaxJSONBodyBytes: 1 << 20,
+		JWTSecret:        "test-secret",
+	}
+
+	r := New(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	token, err := utils.GenerateToken(user.ID, string(models.RoleUser))
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+
+	file := largePNG(t, int(config.App.MaxJSONBodyBytes)+1024)
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, err := w.CreateFormFile("file", "photo.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := part.Write(file); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/users/me/avatar", &body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 for a %d-byte avatar upload (between MaxJSONBodyBytes=%d and UploadMaxBytes=%d), got %d: %s",
+			len(file), config.App.MaxJSONBodyBytes, config.App.UploadMaxBytes, rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,304 @@
+// Package search wraps the Bleve full-text index used by /api/search.
+package search
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/1055373165/blog/models"
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/search/query"
+	"github.com/mozillazg/go-pinyin"
+)
+
+var (
+	idx     bleve.Index
+	idxOnce sync.Once
+	idxMu   sync.RWMutex
+)
+
+// blogDoc is the document shape indexed for each Blog. Transcript is
+// mapped with a higher analyzer boost so spoken content surfaces in
+// search alongside written articles. TitlePinyin is a space-joined,
+// tone-free romanization of Title's CJK characters, so latin-input
+// queries like "diaodu" can match a Chinese title like "调度". UpdatedAt
+// is the indexed Blog's UpdatedAt at index time (Unix seconds), so a
+// consistency check can tell a stale document from a current one
+// without re-reading the indexed content itself.
+type blogDoc struct {
+	Type        string `json:"type"`
+	Title       string `json:"title"`
+	TitlePinyin string `json:"title_pinyin"`
+	Transcript  string `json:"transcript"`
+	UpdatedAt   int64  `json:"updated_at"`
+}
+
+// maxFuzzyQueryTerms caps how many terms a query can have before fuzzy
+// expansion is skipped, to bound the number of FuzzyQuery clauses built
+// per search.
+const maxFuzzyQueryTerms = 5
+
+// fuzzyBoost and pinyinBoost weight fuzzy/pinyin-only matches below
+// exact matches, so typo and pinyin hits still rank lower.
+const (
+	fuzzyBoost  = 0.3
+	pinyinBoost = 0.5
+)
+
+// Init opens (or creates) the on-disk Bleve index at path. A freshly
+// created index is stamped with CurrentSchemaVersion; an index that
+// already existed is left as-is - see NeedsRebuild for when a caller
+// should follow up with Rebuild.
+func Init(path string) error {
+	var err error
+	idxOnce.Do(func() {
+		mapping := bleve.NewIndexMapping()
+		idx, err = bleve.Open(path)
+		if err != nil {
+			idx, err = bleve.New(path, mapping)
+			if err == nil {
+				err = writeSchemaVersion(path, CurrentSchemaVersion)
+			}
+		}
+	})
+	return err
+}
+
+// IndexBlog upserts blog's searchable document into the package-level
+// index, boosting the transcript field so spoken content is
+// discoverable via /api/search.
+func IndexBlog(blog *models.Blog) error {
+	idxMu.RLock()
+	defer idxMu.RUnlock()
+	if idx == nil {
+		return nil
+	}
+	return IndexBlogInto(idx, blog)
+}
+
+// IndexBlogInto upserts blog's searchable document into idx directly,
+// bypassing the package-level index and its lock - used by Rebuild's
+// populate callback to fill a new index that isn't live yet.
+func IndexBlogInto(idx bleve.Index, blog *models.Blog) error {
+	doc := blogDoc{
+		Type:        "blog",
+		Title:       blog.Title,
+		TitlePinyin: titlePinyin(blog.Title),
+		Transcript:  blog.Transcript,
+		UpdatedAt:   blog.UpdatedAt.Unix(),
+	}
+	return idx.Index(docID("blog", blog.ID), doc)
+}
+
+// DeleteBlog removes id's document from the index, if present. Used to
+// drop orphaned documents found by a search consistency repair - e.g. a
+// Blog that was hard-deleted outside the normal service.CreateBlog/
+// UpdateBlog path and so never got a chance to clean up its own entry.
+func DeleteBlog(id uint) error {
+	idxMu.RLock()
+	defer idxMu.RUnlock()
+	if idx == nil {
+		return nil
+	}
+	return idx.Delete(docID("blog", id))
+}
+
+// titlePinyin romanizes title's CJK characters to lowercase, tone-free
+// pinyin, dropping characters with no pinyin reading (e.g. latin text,
+// already searchable via the title field itself).
+func titlePinyin(title string) string {
+	args := pinyin.NewArgs()
+	return strings.ToLower(strings.Join(pinyin.LazyPinyin(title, args), ""))
+}
+
+// Hit is a single search result, flagged if it matched only via fuzzy
+// or pinyin expansion rather than an exact term match.
+type Hit struct {
+	ID    string  `json:"id"`
+	Score float64 `json:"score"`
+	Fuzzy bool    `json:"fuzzy"`
+}
+
+// Result is the response shape for a search: ranked hits, each flagged
+// if it was a fuzzy-only match, so the UI can show "did you mean"
+// messaging.
+type Result struct {
+	Total uint64 `json:"total"`
+	Hits  []Hit  `json:"hits"`
+}
+
+// Search runs a full-text query across indexed documents, tolerating
+// typos (edit distance 1-2, depending on term length) and pinyin input
+// against CJK titles. Fuzzy/pinyin-only matches rank below exact
+// matches and are flagged Fuzzy in the result. Fuzzy expansion is
+// skipped for queries longer than maxFuzzyQueryTerms terms, to bound
+// the number of query clauses built per search.
+func Search(q string, limit int) (*Result, error) {
+	idxMu.RLock()
+	defer idxMu.RUnlock()
+	if idx == nil {
+		return &Result{}, nil
+	}
+
+	exact := bleve.NewQueryStringQuery(q)
+	combined := buildSearchQuery(q, exact)
+
+	req := bleve.NewSearchRequest(combined)
+	req.Size = limit
+	res, err := idx.Search(req)
+	if err != nil {
+		return nil, err
+	}
+
+	exactIDs, err := matchedIDs(exact, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]Hit, len(res.Hits))
+	for i, h := range res.Hits {
+		hits[i] = Hit{ID: h.ID, Score: h.Score, Fuzzy: !exactIDs[h.ID]}
+	}
+	return &Result{Total: res.Total, Hits: hits}, nil
+}
+
+// buildSearchQuery combines exact with fuzzy and pinyin clauses at a
+// lower boost, unless q has too many terms to expand.
+func buildSearchQuery(q string, exact query.Query) query.Query {
+	terms := strings.Fields(q)
+	if len(terms) == 0 || len(terms) > maxFuzzyQueryTerms {
+		return exact
+	}
+
+	disjuncts := []query.Query{exact}
+	for _, term := range terms {
+		fuzziness := 1
+		if len(term) > 4 {
+			fuzziness = 2
+		}
+		fq := bleve.NewFuzzyQuery(term)
+		fq.Fuzziness = fuzziness
+		fq.SetBoost(fuzzyBoost)
+		disjuncts = append(disjuncts, fq)
+	}
+
+	pq := bleve.NewWildcardQuery("*" + strings.ToLower(strings.Join(terms, "")) + "*")
+	pq.SetField("title_pinyin")
+	pq.SetBoost(pinyinBoost)
+	disjuncts = append(disjuncts, pq)
+
+	return bleve.NewDisjunctionQuery(disjuncts...)
+}
+
+// matchedIDs runs q alone and returns the set of document IDs it
+// matches, used to tell exact hits apart from fuzzy/pinyin-only ones.
+func matchedIDs(q query.Query, limit int) (map[string]bool, error) {
+	req := bleve.NewSearchRequest(q)
+	req.Size = limit
+	res, err := idx.Search(req)
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]bool, len(res.Hits))
+	for _, h := range res.Hits {
+		ids[h.ID] = true
+	}
+	return ids, nil
+}
+
+// Ready reports whether the search index has been initialized.
+func Ready() bool {
+	idxMu.RLock()
+	defer idxMu.RUnlock()
+	return idx != nil
+}
+
+// DocCount returns the number of documents in the index, or 0 if the
+// index has not been initialized.
+func DocCount() uint64 {
+	idxMu.RLock()
+	defer idxMu.RUnlock()
+	if idx == nil {
+		return 0
+	}
+	count, err := idx.DocCount()
+	if err != nil {
+		return 0
+	}
+	return count
+}
+
+func docID(kind string, id uint) string {
+	return kind + ":" + strconv.FormatUint(uint64(id), 10)
+}
+
+// parseBlogDocID extracts the Blog ID from a "blog:<id>" document ID, as
+// produced by docID("blog", id).
+func parseBlogDocID(id string) (uint, bool) {
+	idStr, ok := strings.CutPrefix(id, "blog:")
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return uint(n), true
+}
+
+// indexedBlogPageSize bounds how many documents ForEachIndexedBlog
+// fetches per page, so a full index scan never loads every document
+// into memory at once.
+const indexedBlogPageSize = 200
+
+// IndexedBlogRef is a minimal reference to an indexed Blog document - its
+// ID and the Blog.UpdatedAt it was indexed with - as returned by
+// ForEachIndexedBlog.
+type IndexedBlogRef struct {
+	ID        uint
+	UpdatedAt time.Time
+}
+
+// ForEachIndexedBlog walks every indexed blog document via a match-all
+// query, paginated indexedBlogPageSize at a time rather than loading the
+// whole index into memory, calling fn with each document's ID and the
+// UpdatedAt it was indexed with. Used by the search consistency
+// check/repair to compare the index against the database.
+func ForEachIndexedBlog(fn func(IndexedBlogRef) error) error {
+	idxMu.RLock()
+	defer idxMu.RUnlock()
+	if idx == nil {
+		return nil
+	}
+
+	q := bleve.NewMatchAllQuery()
+	for from := 0; ; from += indexedBlogPageSize {
+		req := bleve.NewSearchRequest(q)
+		req.From = from
+		req.Size = indexedBlogPageSize
+		req.Fields = []string{"type", "updated_at"}
+		res, err := idx.Search(req)
+		if err != nil {
+			return err
+		}
+		if len(res.Hits) == 0 {
+			return nil
+		}
+
+		for _, h := range res.Hits {
+			if t, _ := h.Fields["type"].(string); t != "blog" {
+				continue
+			}
+			id, ok := parseBlogDocID(h.ID)
+			if !ok {
+				continue
+			}
+			unix, _ := h.Fields["updated_at"].(float64)
+			if err := fn(IndexedBlogRef{ID: id, UpdatedAt: time.Unix(int64(unix), 0)}); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package search
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/1055373165/blog/models"
+)
+
+// TestMain opens a single on-disk index for the whole package, since
+// Init's sync.Once only ever takes effect once per process - the same
+// constraint production code lives with (one index, one process).
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "search-index-test-*")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := Init(dir); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+func TestIndexBlogThenForEachIndexedBlogFindsIt(t *testing.T) {
+	blog := &models.Blog{ID: 9001, Title: "Indexed Once", UpdatedAt: time.Now().Truncate(time.Second)}
+	if err := IndexBlog(blog); err != nil {
+		t.Fatalf("IndexBlog returned error: %v", err)
+	}
+
+	found := false
+	if err := ForEachIndexedBlog(func(ref IndexedBlogRef) error {
+		if ref.ID == blog.ID {
+			found = true
+			if !ref.UpdatedAt.Equal(blog.UpdatedAt) {
+				t.Errorf("got UpdatedAt %v, want %v", ref.UpdatedAt, blog.UpdatedAt)
+			}
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("ForEachIndexedBlog returned error: %v", err)
+	}
+	if !found {
+		t.Error("expected ForEachIndexedBlog to find the indexed blog")
+	}
+}
+
+func TestDeleteBlogRemovesItFromForEachIndexedBlog(t *testing.T) {
+	blog := &models.Blog{ID: 9002, Title: "To Delete", UpdatedAt: time.Now()}
+	if err := IndexBlog(blog); err != nil {
+		t.Fatalf("IndexBlog returned error: %v", err)
+	}
+	if err := DeleteBlog(blog.ID); err != nil {
+		t.Fatalf("DeleteBlog returned error: %v", err)
+	}
+
+	if err := ForEachIndexedBlog(func(ref IndexedBlogRef) error {
+		if ref.ID == blog.ID {
+			t.Errorf("expected blog %d to be gone after DeleteBlog", blog.ID)
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("ForEachIndexedBlog returned error: %v", err)
+	}
+}
+
+func TestParseBlogDocID(t *testing.T) {
+	if id, ok := parseBlogDocID("blog:42"); !ok || id != 42 {
+		t.Errorf("got (%d, %v), want (42, true)", id, ok)
+	}
+	if _, ok := parseBlogDocID("not-a-blog-id"); ok {
+		t.Error("expected parseBlogDocID to reject a non-blog document ID")
+	}
+}
@@ -0,0 +1,116 @@
+package search
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/blevesearch/bleve/v2"
+)
+
+// CurrentSchemaVersion identifies the current document mapping.
+// Bump it whenever blogDoc gains/changes an indexed field that old
+// documents won't have been built with (e.g. a new boosted field), so
+// NeedsRebuild can tell a stale on-disk index apart from a current one.
+const CurrentSchemaVersion = 1
+
+// schemaVersionSuffix names the sidecar file, next to the index
+// directory itself, that records which CurrentSchemaVersion the index
+// on disk was built with. It lives alongside rather than inside the
+// index directory so reading it never requires opening the index.
+const schemaVersionSuffix = ".schema-version"
+
+func schemaVersionPath(indexPath string) string {
+	return strings.TrimSuffix(indexPath, "/") + schemaVersionSuffix
+}
+
+// writeSchemaVersion records version as the schema version indexPath's
+// on-disk index was built with.
+func writeSchemaVersion(indexPath string, version int) error {
+	return os.WriteFile(schemaVersionPath(indexPath), []byte(strconv.Itoa(version)), 0o644)
+}
+
+// onDiskSchemaVersion reads the schema version recorded for indexPath,
+// returning ok=false if no sidecar file exists yet - e.g. an index
+// built before this versioning was introduced, or a fresh one that
+// hasn't finished its first write.
+func onDiskSchemaVersion(indexPath string) (version int, ok bool) {
+	data, err := os.ReadFile(schemaVersionPath(indexPath))
+	if err != nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// NeedsRebuild reports whether indexPath's on-disk index was built with
+// a schema version older than CurrentSchemaVersion. An index with no
+// recorded version at all - one that predates this versioning, or a
+// brand new path that hasn't been initialized yet - is treated as
+// current rather than stale, so introducing this check doesn't force
+// every pre-existing deployment to rebuild on its next restart; only a
+// future CurrentSchemaVersion bump does.
+func NeedsRebuild(indexPath string) bool {
+	version, ok := onDiskSchemaVersion(indexPath)
+	if !ok {
+		return false
+	}
+	return version < CurrentSchemaVersion
+}
+
+// rebuildTmpSuffix names the sibling directory Rebuild populates the
+// replacement index in, so the old index at indexPath keeps serving
+// Search/IndexBlog/DeleteBlog calls until the new one is fully built.
+const rebuildTmpSuffix = ".rebuild-tmp"
+
+// Rebuild builds a fresh index at a sibling of indexPath, current as of
+// CurrentSchemaVersion, calls populate to fill it (typically by
+// re-indexing every row from the database), then swaps it in for the
+// package-level index under idxMu - so every Search/IndexBlog/DeleteBlog
+// call in flight up to that point still sees the old index, and every
+// one after sees the new one. The old index is closed and its directory
+// removed only after the swap succeeds.
+func Rebuild(indexPath string, populate func(bleve.Index) error) error {
+	tmpPath := strings.TrimSuffix(indexPath, "/") + rebuildTmpSuffix
+	if err := os.RemoveAll(tmpPath); err != nil {
+		return err
+	}
+
+	newIdx, err := bleve.New(tmpPath, bleve.NewIndexMapping())
+	if err != nil {
+		return err
+	}
+	if err := populate(newIdx); err != nil {
+		newIdx.Close()
+		os.RemoveAll(tmpPath)
+		return err
+	}
+	if err := newIdx.Close(); err != nil {
+		os.RemoveAll(tmpPath)
+		return err
+	}
+
+	idxMu.Lock()
+	defer idxMu.Unlock()
+
+	oldIdx := idx
+	if oldIdx != nil {
+		oldIdx.Close()
+	}
+	if err := os.RemoveAll(indexPath); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, indexPath); err != nil {
+		return err
+	}
+
+	reopened, err := bleve.Open(indexPath)
+	if err != nil {
+		return err
+	}
+	idx = reopened
+	return writeSchemaVersion(indexPath, CurrentSchemaVersion)
+}
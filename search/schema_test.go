@@ -0,0 +1,102 @@
+package search
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/1055373165/blog/models"
+	"github.com/blevesearch/bleve/v2"
+)
+
+func TestNeedsRebuildIsFalseWithNoSidecarFile(t *testing.T) {
+	dir := t.TempDir()
+	if NeedsRebuild(dir + "/missing") {
+		t.Error("expected NeedsRebuild to be false when no schema-version sidecar exists")
+	}
+}
+
+func TestNeedsRebuildIsFalseWhenVersionIsCurrent(t *testing.T) {
+	dir := t.TempDir()
+	path := dir + "/idx"
+	if err := writeSchemaVersion(path, CurrentSchemaVersion); err != nil {
+		t.Fatalf("writeSchemaVersion returned error: %v", err)
+	}
+	if NeedsRebuild(path) {
+		t.Error("expected NeedsRebuild to be false when the on-disk version matches CurrentSchemaVersion")
+	}
+}
+
+func TestNeedsRebuildIsTrueWhenVersionIsStale(t *testing.T) {
+	dir := t.TempDir()
+	path := dir + "/idx"
+	if err := writeSchemaVersion(path, CurrentSchemaVersion-1); err != nil {
+		t.Fatalf("writeSchemaVersion returned error: %v", err)
+	}
+	if !NeedsRebuild(path) {
+		t.Error("expected NeedsRebuild to be true when the on-disk version predates CurrentSchemaVersion")
+	}
+}
+
+func TestRebuildSwapsInAFreshlyPopulatedIndex(t *testing.T) {
+	dir := t.TempDir()
+	path := dir + "/idx"
+
+	oldIdx, err := bleve.New(path, bleve.NewIndexMapping())
+	if err != nil {
+		t.Fatalf("failed to seed an old index: %v", err)
+	}
+	if err := writeSchemaVersion(path, CurrentSchemaVersion-1); err != nil {
+		t.Fatalf("writeSchemaVersion returned error: %v", err)
+	}
+
+	// Rebuild swaps the package-level idx under idxMu, so this test -
+	// unlike the rest of the package, which only ever reads through the
+	// single index TestMain opens - must restore it afterward to avoid
+	// leaving every later test pointed at this test's throwaway index.
+	idxMu.Lock()
+	previous := idx
+	idx = oldIdx
+	idxMu.Unlock()
+	t.Cleanup(func() {
+		idxMu.Lock()
+		idx = previous
+		idxMu.Unlock()
+	})
+
+	blog := &models.Blog{ID: 9101, Title: "Rebuilt Document", UpdatedAt: time.Now().Truncate(time.Second)}
+	populated := false
+	if err := Rebuild(path, func(newIdx bleve.Index) error {
+		populated = true
+		return IndexBlogInto(newIdx, blog)
+	}); err != nil {
+		t.Fatalf("Rebuild returned error: %v", err)
+	}
+	if !populated {
+		t.Fatal("expected Rebuild to call populate")
+	}
+
+	found := false
+	if err := ForEachIndexedBlog(func(ref IndexedBlogRef) error {
+		if ref.ID == blog.ID {
+			found = true
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("ForEachIndexedBlog returned error: %v", err)
+	}
+	if !found {
+		t.Error("expected the rebuilt index to contain the document populate indexed")
+	}
+
+	if version, ok := onDiskSchemaVersion(path); !ok || version != CurrentSchemaVersion {
+		t.Errorf("expected on-disk version %d after Rebuild, got %d (ok=%v)", CurrentSchemaVersion, version, ok)
+	}
+	if NeedsRebuild(path) {
+		t.Error("expected NeedsRebuild to be false immediately after Rebuild")
+	}
+
+	if _, err := os.Stat(path + rebuildTmpSuffix); !os.IsNotExist(err) {
+		t.Errorf("expected the rebuild temp directory to be cleaned up, stat err = %v", err)
+	}
+}
@@ -0,0 +1,200 @@
+// Package searchstats records search queries for popular/recent-query
+// reporting without adding write latency to the search request path.
+//
+// SearchBlogsAndArticles fires on every frontend keystroke, so recording
+// a models.SearchStatistics row synchronously - one row per keystroke -
+// would both add write latency to the hottest read path and flood the
+// table with near-duplicate rows ("g", "go", "gol", "gola", ...).
+// Recorder instead buffers queries on a channel and drains it on a
+// background goroutine, collapsing a burst of same-IP queries where each
+// is a prefix of the next into a single pending entry; once dedupWindow
+// passes with no further extension of that entry, it's batched into the
+// next periodic insert as the final, presumably-meant query.
+package searchstats
+
+import (
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/1055373165/blog/models"
+	"gorm.io/gorm"
+)
+
+// Default is the process-wide Recorder, set by Init.
+var Default *Recorder
+
+// query is one recorded search, queued on Recorder.queue.
+type query struct {
+	IP          string
+	Query       string
+	ResultCount int
+	At          time.Time
+}
+
+// pending tracks the most recent not-yet-flushed query for one IP and
+// when it's old enough to flush as final.
+type pending struct {
+	query
+	deadline time.Time
+}
+
+// Recorder batches Record calls into periodic inserts, deduplicating a
+// burst of same-IP prefix queries (a caller typing "go" then "gola")
+// down to the final query in the burst.
+type Recorder struct {
+	db          *gorm.DB
+	queue       chan query
+	dedupWindow time.Duration
+	dropped     atomic.Uint64
+
+	mu      sync.Mutex
+	pending map[string]*pending
+}
+
+// New creates a Recorder. Call Start to begin the background writer.
+// bufferSize bounds how many not-yet-absorbed queries can queue before
+// Record starts dropping them; dedupWindow is how long a same-IP query
+// waits for a longer, prefix-extending follow-up before it's considered
+// final and eligible for the next flush.
+func New(db *gorm.DB, bufferSize int, dedupWindow time.Duration) *Recorder {
+	return &Recorder{
+		db:          db,
+		queue:       make(chan query, bufferSize),
+		dedupWindow: dedupWindow,
+		pending:     make(map[string]*pending),
+	}
+}
+
+// Init creates the process-wide Recorder and starts its background
+// writer, returning a stop function - the same lifecycle convention
+// package presence uses.
+func Init(db *gorm.DB, bufferSize int, dedupWindow, flushInterval time.Duration) (stop func()) {
+	Default = New(db, bufferSize, dedupWindow)
+	return Default.Start(flushInterval)
+}
+
+// Record queues a search query for batched, deduplicated recording. It
+// never blocks: if the buffer is full, the query is dropped and counted
+// in Dropped rather than slowing down the search request it came from.
+func (r *Recorder) Record(ip, q string, resultCount int) {
+	select {
+	case r.queue <- query{IP: ip, Query: q, ResultCount: resultCount, At: time.Now()}:
+	default:
+		r.dropped.Add(1)
+	}
+}
+
+// Dropped returns the number of Record calls dropped so far because the
+// buffer was full, for GetSearchStats to surface as a health signal.
+func (r *Recorder) Dropped() uint64 {
+	return r.dropped.Load()
+}
+
+// Start launches the background goroutine that drains queue into
+// pending (absorbing same-IP prefix bursts) and flushes entries whose
+// dedupWindow has elapsed to the database every flushInterval. The
+// returned stop function drains whatever's left in queue and flushes
+// every remaining pending entry, regardless of its deadline, before
+// returning - so a clean shutdown doesn't silently lose the last few
+// queries in flight.
+func (r *Recorder) Start(flushInterval time.Duration) (stop func()) {
+	ticker := time.NewTicker(flushInterval)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case q := <-r.queue:
+				r.absorb(q)
+			case <-ticker.C:
+				r.flush(false)
+			case <-done:
+				ticker.Stop()
+				r.drainQueue()
+				r.flush(true)
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// absorb folds q into pending: if q's IP has a pending entry whose query
+// is a prefix of q's (including being equal to it), q replaces it -
+// same burst, keep only the longer, final query. Otherwise the existing
+// pending entry (if any) is queued for insert as its own final query,
+// and q starts a new pending entry for that IP.
+func (r *Recorder) absorb(q query) {
+	r.mu.Lock()
+	evicted, evict := r.pending[q.IP], false
+	if p, ok := r.pending[q.IP]; ok && strings.HasPrefix(q.Query, p.Query) {
+		r.pending[q.IP] = &pending{query: q, deadline: q.At.Add(r.dedupWindow)}
+		r.mu.Unlock()
+		return
+	} else if ok {
+		evict = true
+	}
+	r.pending[q.IP] = &pending{query: q, deadline: q.At.Add(r.dedupWindow)}
+	r.mu.Unlock()
+
+	if evict {
+		r.insert([]models.SearchStatistics{toRow(evicted.query)})
+	}
+}
+
+// drainQueue absorbs whatever's already queued without blocking, for
+// use during shutdown after the background loop has stopped selecting
+// on queue.
+func (r *Recorder) drainQueue() {
+	for {
+		select {
+		case q := <-r.queue:
+			r.absorb(q)
+		default:
+			return
+		}
+	}
+}
+
+// flush batches every pending entry whose deadline has passed (or,
+// if force, every pending entry regardless) into one insert.
+func (r *Recorder) flush(force bool) {
+	now := time.Now()
+
+	r.mu.Lock()
+	var rows []models.SearchStatistics
+	for ip, p := range r.pending {
+		if force || now.After(p.deadline) {
+			rows = append(rows, toRow(p.query))
+			delete(r.pending, ip)
+		}
+	}
+	r.mu.Unlock()
+
+	r.insert(rows)
+}
+
+// insert batch-inserts rows, logging and otherwise swallowing a failure:
+// like package audit, stats recording must never surface as a search
+// failure. Must be called without r.mu held.
+func (r *Recorder) insert(rows []models.SearchStatistics) {
+	if len(rows) == 0 {
+		return
+	}
+	if err := r.db.Create(&rows).Error; err != nil {
+		slog.Error("failed to batch-insert search statistics", "count", len(rows), "error", err)
+	}
+}
+
+func toRow(q query) models.SearchStatistics {
+	return models.SearchStatistics{Query: q.Query, ResultCount: q.ResultCount, IP: q.IP, CreatedAt: q.At}
+}
@@ -0,0 +1,144 @@
+package searchstats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/1055373165/blog/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	conn, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	if err := conn.AutoMigrate(&models.SearchStatistics{}); err != nil {
+		t.Fatalf("failed to migrate test db: %v", err)
+	}
+	return conn
+}
+
+func TestRecordDropsAndCountsWhenBufferFull(t *testing.T) {
+	r := New(setupTestDB(t), 1, time.Minute)
+	r.queue <- query{IP: "1.1.1.1", Query: "fills the buffer"}
+
+	r.Record("2.2.2.2", "overflow", 0)
+	r.Record("2.2.2.2", "overflow again", 0)
+
+	if got := r.Dropped(); got != 2 {
+		t.Fatalf("expected 2 dropped records, got %d", got)
+	}
+}
+
+func TestAbsorbCollapsesSameIPPrefixBurstIntoOnePending(t *testing.T) {
+	r := New(setupTestDB(t), 10, time.Minute)
+
+	r.absorb(query{IP: "1.1.1.1", Query: "g", At: time.Now()})
+	r.absorb(query{IP: "1.1.1.1", Query: "go", At: time.Now()})
+	r.absorb(query{IP: "1.1.1.1", Query: "gola", At: time.Now()})
+
+	r.mu.Lock()
+	n := len(r.pending)
+	got := r.pending["1.1.1.1"].Query
+	r.mu.Unlock()
+
+	if n != 1 {
+		t.Fatalf("expected exactly one pending entry for the IP, got %d", n)
+	}
+	if got != "gola" {
+		t.Errorf("expected pending entry to be the final query in the burst, got %q", got)
+	}
+}
+
+func TestAbsorbEvictsPendingWhenNextQueryIsNotAPrefixExtension(t *testing.T) {
+	r := New(setupTestDB(t), 10, time.Minute)
+
+	r.absorb(query{IP: "1.1.1.1", Query: "golang", ResultCount: 5, At: time.Now()})
+	r.absorb(query{IP: "1.1.1.1", Query: "rust", ResultCount: 2, At: time.Now()})
+
+	var rows []models.SearchStatistics
+	if err := r.db.Find(&rows).Error; err != nil {
+		t.Fatalf("failed to query rows: %v", err)
+	}
+	if len(rows) != 1 || rows[0].Query != "golang" {
+		t.Fatalf("expected the evicted entry ('golang') to be inserted immediately, got %+v", rows)
+	}
+
+	r.mu.Lock()
+	got := r.pending["1.1.1.1"].Query
+	r.mu.Unlock()
+	if got != "rust" {
+		t.Errorf("expected the new query to start a fresh pending entry, got %q", got)
+	}
+}
+
+func TestAbsorbTracksDifferentIPsIndependently(t *testing.T) {
+	r := New(setupTestDB(t), 10, time.Minute)
+
+	r.absorb(query{IP: "1.1.1.1", Query: "go", At: time.Now()})
+	r.absorb(query{IP: "2.2.2.2", Query: "rust", At: time.Now()})
+
+	r.mu.Lock()
+	n := len(r.pending)
+	r.mu.Unlock()
+	if n != 2 {
+		t.Fatalf("expected independent pending entries per IP, got %d", n)
+	}
+}
+
+func TestFlushInsertsOnlyExpiredEntriesUnlessForced(t *testing.T) {
+	r := New(setupTestDB(t), 10, time.Hour)
+
+	r.absorb(query{IP: "1.1.1.1", Query: "expired", At: time.Now().Add(-2 * time.Hour)})
+	r.mu.Lock()
+	r.pending["1.1.1.1"].deadline = time.Now().Add(-time.Minute)
+	r.mu.Unlock()
+	r.absorb(query{IP: "2.2.2.2", Query: "still fresh", At: time.Now()})
+
+	r.flush(false)
+
+	var rows []models.SearchStatistics
+	if err := r.db.Find(&rows).Error; err != nil {
+		t.Fatalf("failed to query rows: %v", err)
+	}
+	if len(rows) != 1 || rows[0].Query != "expired" {
+		t.Fatalf("expected only the expired entry to be flushed, got %+v", rows)
+	}
+
+	r.mu.Lock()
+	_, stillPending := r.pending["2.2.2.2"]
+	r.mu.Unlock()
+	if !stillPending {
+		t.Error("expected the unexpired entry to remain pending")
+	}
+
+	r.flush(true)
+	var all []models.SearchStatistics
+	if err := r.db.Find(&all).Error; err != nil {
+		t.Fatalf("failed to query rows: %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("expected a forced flush to insert every remaining pending entry, got %d rows", len(all))
+	}
+}
+
+func TestStopFlushesPendingEntriesBeforeReturning(t *testing.T) {
+	r := New(setupTestDB(t), 10, time.Hour)
+	stop := r.Start(time.Hour)
+
+	r.Record("1.1.1.1", "never extended", 3)
+	time.Sleep(20 * time.Millisecond) // let the background goroutine absorb it
+
+	stop()
+
+	var rows []models.SearchStatistics
+	if err := r.db.Find(&rows).Error; err != nil {
+		t.Fatalf("failed to query rows: %v", err)
+	}
+	if len(rows) != 1 || rows[0].Query != "never extended" {
+		t.Fatalf("expected stop to flush the pending query despite the long dedupWindow, got %+v", rows)
+	}
+}
@@ -0,0 +1,353 @@
+// Package seed creates (and removes) a deterministic demo dataset for
+// local development, so a fresh environment has something to click
+// through without hand-creating users, categories, tags, and articles
+// first. Every row it creates lives in a fixed, recognizable
+// namespace - "demo_"-prefixed usernames, "demo-"-prefixed slugs, and
+// "Demo "-prefixed titles - so Wipe can remove exactly what Run
+// created by matching that namespace, without a separate tracking
+// table.
+package seed
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/1055373165/blog/models"
+	"gorm.io/gorm"
+)
+
+const (
+	demoAdminUsername = "demo_admin"
+	demoUserUsername  = "demo_user"
+
+	articleCount = 50
+	tagCount     = 20
+	// batchSize bounds how many rows go into a single CreateInBatches
+	// call, the same batching NormalizeArticleContentURLs and
+	// RecountLikes use to process large row sets without one giant
+	// statement.
+	batchSize = 25
+)
+
+// Summary reports how many rows of each type Run created. Created is
+// false on a no-op rerun, when the demo dataset already exists; every
+// count is then 0 since nothing was touched.
+type Summary struct {
+	Created     bool `json:"created"`
+	Users       int  `json:"users"`
+	Categories  int  `json:"categories"`
+	Tags        int  `json:"tags"`
+	Series      int  `json:"series"`
+	Articles    int  `json:"articles"`
+	Blogs       int  `json:"blogs"`
+	Submissions int  `json:"submissions"`
+	Comments    int  `json:"comments"`
+	StudyPlans  int  `json:"study_plans"`
+}
+
+// Run creates the demo dataset - an admin and a regular user, a
+// 3-level category tree, tags, series, published articles with likes
+// and views, blogs, a submission in every review status with a
+// threaded comment, and a study plan - in a single transaction,
+// batching the larger inserts. It is idempotent: if demoAdminUsername
+// already exists, Run assumes the dataset is already seeded and
+// returns immediately without creating or duplicating anything.
+func Run(db *gorm.DB) (Summary, error) {
+	var existing models.User
+	err := db.Where("username = ?", demoAdminUsername).First(&existing).Error
+	if err == nil {
+		return Summary{Created: false}, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return Summary{}, err
+	}
+
+	summary := Summary{Created: true}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		admin := models.User{Username: demoAdminUsername, Email: "demo_admin@example.com", PasswordHash: "seed-only, no login flow exists", Role: models.RoleAdmin}
+		user := models.User{Username: demoUserUsername, Email: "demo_user@example.com", PasswordHash: "seed-only, no login flow exists", Role: models.RoleUser}
+		if err := tx.Create(&admin).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		summary.Users = 2
+
+		root := models.Category{Name: "Demo Root", Slug: "demo-root", IsVisible: true}
+		if err := tx.Create(&root).Error; err != nil {
+			return err
+		}
+		mid := models.Category{Name: "Demo Mid", Slug: "demo-mid", ParentID: &root.ID, IsVisible: true}
+		if err := tx.Create(&mid).Error; err != nil {
+			return err
+		}
+		leaf := models.Category{Name: "Demo Leaf", Slug: "demo-leaf", ParentID: &mid.ID, IsVisible: true}
+		if err := tx.Create(&leaf).Error; err != nil {
+			return err
+		}
+		summary.Categories = 3
+
+		tags := make([]models.Tag, tagCount)
+		for i := range tags {
+			tags[i] = models.Tag{Name: fmt.Sprintf("Demo Tag %d", i+1), Slug: fmt.Sprintf("demo-tag-%d", i+1)}
+		}
+		if err := tx.CreateInBatches(&tags, batchSize).Error; err != nil {
+			return err
+		}
+		summary.Tags = len(tags)
+
+		series := []models.Series{
+			{Title: "Demo Series One", Slug: "demo-series-1"},
+			{Title: "Demo Series Two", Slug: "demo-series-2"},
+		}
+		if err := tx.Create(&series).Error; err != nil {
+			return err
+		}
+		summary.Series = len(series)
+
+		now := time.Now()
+		articles := make([]models.Article, articleCount)
+		for i := range articles {
+			authorID := user.ID
+			if i%5 == 0 {
+				authorID = admin.ID
+			}
+			articles[i] = models.Article{
+				AuthorID:  authorID,
+				Title:     demoArticleTitle(i),
+				Slug:      fmt.Sprintf("demo-article-%d", i+1),
+				Content:   demoArticleContent(i),
+				Status:    models.ArticleStatusPublished,
+				Views:     int64((i*37)%900 + 10),
+				CreatedAt: now.Add(-time.Duration(89-i%90) * 24 * time.Hour),
+			}
+			if i < 10 {
+				order := i / 2
+				articles[i].SeriesID = &series[i%2].ID
+				articles[i].SeriesOrder = &order
+			}
+		}
+		if err := tx.CreateInBatches(&articles, batchSize).Error; err != nil {
+			return err
+		}
+		summary.Articles = len(articles)
+
+		for i := range articles {
+			tag := tags[i%len(tags)]
+			if err := tx.Model(&articles[i]).Association("Tags").Append(&tag); err != nil {
+				return err
+			}
+		}
+
+		// Likes are real Reaction rows, backdated across the last 90
+		// days, so Article.Likes reflects actual liking activity rather
+		// than a number written directly into the counter - the same
+		// Reaction-rows-are-the-source-of-truth model RecountLikes
+		// resynchronizes from.
+		for i := range articles {
+			if i%3 != 0 {
+				continue
+			}
+			reaction := models.Reaction{
+				ArticleID:    &articles[i].ID,
+				UserID:       user.ID,
+				ReactionType: models.ReactionLike,
+				CreatedAt:    now.Add(-time.Duration((i*7)%90) * 24 * time.Hour),
+			}
+			if err := tx.Create(&reaction).Error; err != nil {
+				return err
+			}
+			articles[i].Likes = 1
+			if err := tx.Model(&articles[i]).Update("likes", 1).Error; err != nil {
+				return err
+			}
+		}
+
+		blogs := []models.Blog{
+			{AuthorID: admin.ID, Title: "Demo Blog One", Slug: "demo-blog-1", MediaURL: "https://example.com/demo-1.mp3"},
+			{AuthorID: user.ID, Title: "Demo Blog Two", Slug: "demo-blog-2", MediaURL: "https://example.com/demo-2.mp3"},
+		}
+		if err := tx.Create(&blogs).Error; err != nil {
+			return err
+		}
+		summary.Blogs = len(blogs)
+
+		submissions := []models.Submission{
+			{AuthorID: user.ID, Title: "Demo Submission Draft", Content: "This draft hasn't been submitted for review yet.", Status: models.SubmissionStatusDraft},
+			{AuthorID: user.ID, Title: "Demo Submission Pending Review", Content: "Awaiting an editor.", Status: models.SubmissionStatusPending},
+			{AuthorID: user.ID, Title: "Demo Submission Changes Requested", Content: "An editor asked for changes.", Status: models.SubmissionStatusChangesRequested},
+			{AuthorID: user.ID, Title: "Demo Submission Approved", Content: "Approved and ready to publish.", Status: models.SubmissionStatusApproved},
+			{AuthorID: user.ID, Title: "Demo Submission Rejected", Content: "Not a fit for the blog.", Status: models.SubmissionStatusRejected},
+		}
+		if err := tx.Create(&submissions).Error; err != nil {
+			return err
+		}
+		summary.Submissions = len(submissions)
+
+		rootComment := models.SubmissionComment{SubmissionID: submissions[2].ID, AuthorID: admin.ID, Content: "Can you expand the intro paragraph?"}
+		if err := tx.Create(&rootComment).Error; err != nil {
+			return err
+		}
+		reply := models.SubmissionComment{SubmissionID: submissions[2].ID, AuthorID: user.ID, Content: "Done, please take another look.", ParentID: &rootComment.ID}
+		if err := tx.Create(&reply).Error; err != nil {
+			return err
+		}
+		summary.Comments = 2
+
+		plan := models.StudyPlan{UserID: user.ID, Name: "Demo Study Plan"}
+		if err := tx.Create(&plan).Error; err != nil {
+			return err
+		}
+		items := make([]models.StudyItem, 3)
+		for i := range items {
+			articleID := articles[i].ID
+			items[i] = models.StudyItem{PlanID: plan.ID, ArticleID: &articleID, Status: models.StudyItemStatusNew}
+		}
+		if err := tx.Create(&items).Error; err != nil {
+			return err
+		}
+		logs := make([]models.StudyLog, len(items))
+		for i, item := range items {
+			logs[i] = models.StudyLog{StudyItemID: item.ID, ReviewType: models.ReviewTypeManual, ReviewedAt: now.Add(-time.Duration(i) * 24 * time.Hour)}
+		}
+		if err := tx.Create(&logs).Error; err != nil {
+			return err
+		}
+		summary.StudyPlans = 1
+
+		return nil
+	})
+	if err != nil {
+		return Summary{}, err
+	}
+	return summary, nil
+}
+
+// Wipe removes every row Run created, identified the same way Run
+// named them - demo_admin/demo_user, "demo-"-slugged categories, tags,
+// series, articles, and blogs, and the submissions/comments/study plan
+// attached to demo_user. Nothing outside that namespace is touched.
+func Wipe(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var users []models.User
+		if err := tx.Where("username IN ?", []string{demoAdminUsername, demoUserUsername}).Find(&users).Error; err != nil {
+			return err
+		}
+		if len(users) == 0 {
+			return nil
+		}
+		userIDs := make([]uint, len(users))
+		for i, u := range users {
+			userIDs[i] = u.ID
+		}
+
+		var articles []models.Article
+		if err := tx.Where("slug LIKE ?", "demo-article-%").Find(&articles).Error; err != nil {
+			return err
+		}
+		articleIDs := make([]uint, len(articles))
+		for i, a := range articles {
+			articleIDs[i] = a.ID
+		}
+
+		var plans []models.StudyPlan
+		if err := tx.Where("user_id IN ?", userIDs).Find(&plans).Error; err != nil {
+			return err
+		}
+		planIDs := make([]uint, len(plans))
+		for i, p := range plans {
+			planIDs[i] = p.ID
+		}
+		if len(planIDs) > 0 {
+			var items []models.StudyItem
+			if err := tx.Where("plan_id IN ?", planIDs).Find(&items).Error; err != nil {
+				return err
+			}
+			itemIDs := make([]uint, len(items))
+			for i, it := range items {
+				itemIDs[i] = it.ID
+			}
+			if len(itemIDs) > 0 {
+				if err := tx.Where("study_item_id IN ?", itemIDs).Delete(&models.StudyLog{}).Error; err != nil {
+					return err
+				}
+				if err := tx.Where("id IN ?", itemIDs).Delete(&models.StudyItem{}).Error; err != nil {
+					return err
+				}
+			}
+			if err := tx.Where("id IN ?", planIDs).Delete(&models.StudyPlan{}).Error; err != nil {
+				return err
+			}
+		}
+
+		var submissions []models.Submission
+		if err := tx.Where("title LIKE ?", "Demo Submission%").Find(&submissions).Error; err != nil {
+			return err
+		}
+		submissionIDs := make([]uint, len(submissions))
+		for i, s := range submissions {
+			submissionIDs[i] = s.ID
+		}
+		if len(submissionIDs) > 0 {
+			if err := tx.Where("submission_id IN ?", submissionIDs).Delete(&models.SubmissionComment{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("id IN ?", submissionIDs).Delete(&models.Submission{}).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(articleIDs) > 0 {
+			if err := tx.Where("article_id IN ?", articleIDs).Delete(&models.Reaction{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("DELETE FROM article_tags WHERE article_id IN ?", articleIDs).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("id IN ?", articleIDs).Delete(&models.Article{}).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("slug LIKE ?", "demo-blog-%").Delete(&models.Blog{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("slug LIKE ?", "demo-series-%").Delete(&models.Series{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("slug LIKE ?", "demo-tag-%").Delete(&models.Tag{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("slug IN ?", []string{"demo-leaf", "demo-mid", "demo-root"}).Delete(&models.Category{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("id IN ?", userIDs).Delete(&models.User{}).Error; err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+// demoTitles/demoSnippets alternate CJK and English demo content so
+// the seeded dataset exercises both without needing a real corpus.
+var demoSnippets = []struct {
+	title   string
+	content string
+}{
+	{"示例文章：Go 语言并发入门", "本文介绍 Go 语言中的 goroutine 与 channel，帮助初学者理解并发编程的基本模型。\n\nConcurrency in Go is built around goroutines and channels rather than threads and locks."},
+	{"Demo Article: Building REST APIs with Gin", "This post walks through building a small REST API with the Gin framework, covering routing, middleware, and JSON binding.\n\n本文用 Gin 框架演示如何构建一个简单的 REST API。"},
+	{"示例文章：数据库索引优化", "讨论常见的数据库索引策略，以及如何通过 EXPLAIN 分析查询计划来定位慢查询。\n\nIndexing strategy matters as much as query shape when chasing down slow queries."},
+	{"Demo Article: A Tour of GORM", "GORM is the ORM most of this codebase's data access goes through. This post tours its query builder and association helpers.\n\nGORM 的查询构造器和关联处理是本项目数据访问层的核心。"},
+	{"示例文章：前端状态管理漫谈", "从 Redux 到 Zustand，聊聊不同状态管理方案的取舍。\n\nState management tradeoffs look different depending on how much of your state is server-derived."},
+}
+
+func demoArticleTitle(i int) string {
+	base := demoSnippets[i%len(demoSnippets)].title
+	return fmt.Sprintf("%s (%d)", base, i+1)
+}
+
+func demoArticleContent(i int) string {
+	return demoSnippets[i%len(demoSnippets)].content
+}
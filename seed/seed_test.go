@@ -0,0 +1,128 @@
+package seed
+
+import (
+	"testing"
+
+	"github.com/1055373165/blog/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupSeedTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	conn, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	if err := conn.AutoMigrate(
+		&models.User{}, &models.Category{}, &models.Tag{}, &models.Series{},
+		&models.Article{}, &models.Blog{}, &models.Reaction{}, &models.Submission{},
+		&models.SubmissionComment{}, &models.StudyPlan{}, &models.StudyItem{}, &models.StudyLog{},
+	); err != nil {
+		t.Fatalf("failed to migrate test db: %v", err)
+	}
+	return conn
+}
+
+func TestRunCreatesDeterministicDataset(t *testing.T) {
+	db := setupSeedTestDB(t)
+
+	summary, err := Run(db)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !summary.Created {
+		t.Fatalf("expected Created=true on first run, got %+v", summary)
+	}
+	if summary.Users != 2 || summary.Categories != 3 || summary.Tags != tagCount ||
+		summary.Series != 2 || summary.Articles != articleCount || summary.Blogs != 2 ||
+		summary.Submissions != 5 || summary.Comments != 2 || summary.StudyPlans != 1 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+
+	var articleCountInDB int64
+	db.Model(&models.Article{}).Where("slug LIKE ?", "demo-article-%").Count(&articleCountInDB)
+	if articleCountInDB != int64(articleCount) {
+		t.Errorf("expected %d demo articles in db, got %d", articleCount, articleCountInDB)
+	}
+
+	var likedCount int64
+	db.Model(&models.Reaction{}).Count(&likedCount)
+	if likedCount == 0 {
+		t.Errorf("expected at least one backdated like reaction")
+	}
+}
+
+func TestRunIsIdempotent(t *testing.T) {
+	db := setupSeedTestDB(t)
+
+	if _, err := Run(db); err != nil {
+		t.Fatalf("first Run: %v", err)
+	}
+	summary, err := Run(db)
+	if err != nil {
+		t.Fatalf("second Run: %v", err)
+	}
+	if summary.Created {
+		t.Fatalf("expected second Run to be a no-op, got %+v", summary)
+	}
+
+	var userCount int64
+	db.Model(&models.User{}).Where("username IN ?", []string{demoAdminUsername, demoUserUsername}).Count(&userCount)
+	if userCount != 2 {
+		t.Errorf("expected exactly 2 demo users after a re-run, got %d", userCount)
+	}
+
+	var articleCountInDB int64
+	db.Model(&models.Article{}).Where("slug LIKE ?", "demo-article-%").Count(&articleCountInDB)
+	if articleCountInDB != int64(articleCount) {
+		t.Errorf("expected no duplicate demo articles after a re-run, got %d", articleCountInDB)
+	}
+}
+
+func TestWipeRemovesOnlyDemoData(t *testing.T) {
+	db := setupSeedTestDB(t)
+
+	other := models.User{Username: "real_user", Email: "real@example.com"}
+	if err := db.Create(&other).Error; err != nil {
+		t.Fatal(err)
+	}
+	otherArticle := models.Article{AuthorID: other.ID, Title: "Real Article", Slug: "real-article"}
+	if err := db.Create(&otherArticle).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Run(db); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if err := Wipe(db); err != nil {
+		t.Fatalf("Wipe: %v", err)
+	}
+
+	var demoUserCount int64
+	db.Model(&models.User{}).Where("username IN ?", []string{demoAdminUsername, demoUserUsername}).Count(&demoUserCount)
+	if demoUserCount != 0 {
+		t.Errorf("expected demo users removed, found %d", demoUserCount)
+	}
+	var demoArticleCount int64
+	db.Model(&models.Article{}).Where("slug LIKE ?", "demo-article-%").Count(&demoArticleCount)
+	if demoArticleCount != 0 {
+		t.Errorf("expected demo articles removed, found %d", demoArticleCount)
+	}
+
+	var realUser models.User
+	if err := db.First(&realUser, other.ID).Error; err != nil {
+		t.Errorf("expected non-demo user to survive Wipe: %v", err)
+	}
+	var realArticle models.Article
+	if err := db.First(&realArticle, otherArticle.ID).Error; err != nil {
+		t.Errorf("expected non-demo article to survive Wipe: %v", err)
+	}
+}
+
+func TestWipeOnEmptyDatabaseIsNoop(t *testing.T) {
+	db := setupSeedTestDB(t)
+	if err := Wipe(db); err != nil {
+		t.Fatalf("Wipe on empty db: %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/1055373165/blog/models"
+	"gorm.io/gorm"
+)
+
+// ErrUserNotFound is returned when an operation targets a User row that
+// doesn't exist.
+var ErrUserNotFound = errors.New("user not found")
+
+// CanAccessArticleContent reports whether userID (0 for anonymous) with
+// role may see article's full Content. Public articles are always
+// visible; members-level requires any authenticated account; supporters-
+// level additionally requires User.Supporter. Admins always pass.
+func CanAccessArticleContent(db *gorm.DB, userID uint, role string, article models.Article) (bool, error) {
+	if article.AccessLevel == "" || article.AccessLevel == models.ArticleAccessPublic {
+		return true, nil
+	}
+	if role == string(models.RoleAdmin) {
+		return true, nil
+	}
+	if userID == 0 {
+		return false, nil
+	}
+	if article.AccessLevel == models.ArticleAccessMembers {
+		return true, nil
+	}
+
+	var user models.User
+	if err := db.First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return user.Supporter, nil
+}
+
+// SetUserSupporter toggles userID's supporter flag, used to grant or
+// revoke access to Articles with AccessLevel "supporters".
+func SetUserSupporter(db *gorm.DB, userID uint, supporter bool) (*models.User, error) {
+	var user models.User
+	if err := db.First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	if err := db.Model(&user).Update("supporter", supporter).Error; err != nil {
+		return nil, err
+	}
+	user.Supporter = supporter
+	return &user, nil
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/1055373165/blog/models"
+)
+
+func TestCanAccessArticleContent(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.User{})
+
+	member := models.User{Username: "member", Email: "member@example.com"}
+	db.Create(&member)
+	supporter := models.User{Username: "supporter", Email: "supporter@example.com", Supporter: true}
+	db.Create(&supporter)
+
+	cases := []struct {
+		name   string
+		level  models.ArticleAccessLevel
+		userID uint
+		role   string
+		want   bool
+	}{
+		{"public article, anonymous", models.ArticleAccessPublic, 0, "", true},
+		{"members article, anonymous", models.ArticleAccessMembers, 0, "", false},
+		{"members article, member", models.ArticleAccessMembers, member.ID, string(models.RoleUser), true},
+		{"supporters article, member", models.ArticleAccessSupporters, member.ID, string(models.RoleUser), false},
+		{"supporters article, supporter", models.ArticleAccessSupporters, supporter.ID, string(models.RoleUser), true},
+		{"supporters article, admin", models.ArticleAccessSupporters, 0, string(models.RoleAdmin), true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			article := models.Article{AccessLevel: tc.level}
+			got, err := CanAccessArticleContent(db, tc.userID, tc.role, article)
+			if err != nil {
+				t.Fatalf("CanAccessArticleContent returned error: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSetUserSupporter(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.User{})
+
+	user := models.User{Username: "bob", Email: "bob@example.com"}
+	db.Create(&user)
+
+	updated, err := SetUserSupporter(db, user.ID, true)
+	if err != nil {
+		t.Fatalf("SetUserSupporter returned error: %v", err)
+	}
+	if !updated.Supporter {
+		t.Error("expected Supporter to be true")
+	}
+
+	var reloaded models.User
+	db.First(&reloaded, user.ID)
+	if !reloaded.Supporter {
+		t.Error("expected Supporter to persist as true")
+	}
+
+	if _, err := SetUserSupporter(db, 9999, true); err != ErrUserNotFound {
+		t.Errorf("expected ErrUserNotFound for a missing user, got %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"github.com/1055373165/blog/models"
+	"gorm.io/gorm"
+)
+
+// AdminUserRow is one row of the admin user list: a User plus aggregate
+// activity columns computed alongside it in the same query, so listing
+// N users never costs N additional queries.
+type AdminUserRow struct {
+	models.User
+	ArticleCount    int64 `json:"article_count"`
+	SubmissionCount int64 `json:"submission_count"`
+}
+
+// AdminUserFilter narrows AdminUserQuery's result set.
+type AdminUserFilter struct {
+	// Q matches a case-insensitive substring of Username or Email.
+	Q string
+	// Role, if non-empty, restricts to that exact models.Role.
+	Role models.Role
+	// Sort is "created_at" or "username"; anything else falls back to
+	// "created_at". There's no last-login or last-activity column to
+	// sort by - recording one would require a login endpoint, and this
+	// tree has none (see README "Known gaps").
+	Sort string
+	// Desc reverses Sort's normal ascending order.
+	Desc bool
+}
+
+// adminUserQuery returns filter applied to the Users table, joined with
+// per-user article and submission counts computed in pre-grouped
+// subqueries rather than a direct join, so a user with several articles
+// and several submissions doesn't get fanned out into several rows.
+func adminUserQuery(db *gorm.DB, filter AdminUserFilter) *gorm.DB {
+	q := db.Table("users").
+		Select(`users.*,
+			COALESCE(article_counts.count, 0) AS article_count,
+			COALESCE(submission_counts.count, 0) AS submission_count`).
+		Joins(`LEFT JOIN (SELECT author_id, COUNT(*) AS count FROM articles GROUP BY author_id) article_counts ON article_counts.author_id = users.id`).
+		Joins(`LEFT JOIN (SELECT author_id, COUNT(*) AS count FROM submissions GROUP BY author_id) submission_counts ON submission_counts.author_id = users.id`)
+
+	if filter.Q != "" {
+		like := "%" + filter.Q + "%"
+		q = q.Where("users.username LIKE ? OR users.email LIKE ?", like, like)
+	}
+	if filter.Role != "" {
+		q = q.Where("users.role = ?", filter.Role)
+	}
+
+	sort := "users.created_at"
+	if filter.Sort == "username" {
+		sort = "users.username"
+	}
+	if filter.Desc {
+		sort += " desc"
+	}
+	return q.Order(sort)
+}
+
+// ListAdminUsers returns a page of users matching filter, most recent
+// first by default, alongside the total matching count for pagination.
+func ListAdminUsers(db *gorm.DB, filter AdminUserFilter, page, pageSize int) (rows []AdminUserRow, total int64, err error) {
+	base := adminUserQuery(db, filter).Session(&gorm.Session{})
+	if err := base.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := base.Offset((page - 1) * pageSize).Limit(pageSize).Scan(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+	return rows, total, nil
+}
+
+// StreamAdminUsers runs filter's query unpaginated and calls visit once
+// per matching row, in query order. The caller is expected to stream
+// each row out (e.g. as a CSV line) rather than accumulate them, so the
+// full result set is never held in memory at once.
+func StreamAdminUsers(db *gorm.DB, filter AdminUserFilter, visit func(AdminUserRow) error) error {
+	rows, err := adminUserQuery(db, filter).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row AdminUserRow
+		if err := db.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		if err := visit(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
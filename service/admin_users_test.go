@@ -0,0 +1,95 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/1055373165/blog/models"
+)
+
+func TestListAdminUsersComputesArticleAndSubmissionCountsWithoutFanout(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.User{}, &models.Article{}, &models.Submission{})
+
+	busy := models.User{Username: "busy", Email: "busy@example.com"}
+	idle := models.User{Username: "idle", Email: "idle@example.com"}
+	db.Create(&busy)
+	db.Create(&idle)
+
+	db.Create(&models.Article{AuthorID: busy.ID, Title: "a1", Slug: "a1"})
+	db.Create(&models.Article{AuthorID: busy.ID, Title: "a2", Slug: "a2"})
+	db.Create(&models.Submission{AuthorID: busy.ID, Title: "s1"})
+	db.Create(&models.Submission{AuthorID: busy.ID, Title: "s2"})
+	db.Create(&models.Submission{AuthorID: busy.ID, Title: "s3"})
+
+	rows, total, err := ListAdminUsers(db, AdminUserFilter{}, 1, 50)
+	if err != nil {
+		t.Fatalf("ListAdminUsers: %v", err)
+	}
+	if total != 2 {
+		t.Fatalf("expected total 2, got %d", total)
+	}
+
+	var gotBusy bool
+	for _, r := range rows {
+		if r.ID == busy.ID {
+			gotBusy = true
+			if r.ArticleCount != 2 {
+				t.Errorf("expected article_count 2, got %d", r.ArticleCount)
+			}
+			if r.SubmissionCount != 3 {
+				t.Errorf("expected submission_count 3, got %d", r.SubmissionCount)
+			}
+		} else if r.ID == idle.ID {
+			if r.ArticleCount != 0 || r.SubmissionCount != 0 {
+				t.Errorf("expected idle user to have zero counts, got %d/%d", r.ArticleCount, r.SubmissionCount)
+			}
+		}
+	}
+	if !gotBusy {
+		t.Fatalf("expected busy user in results, got %+v", rows)
+	}
+}
+
+func TestListAdminUsersFiltersByQAndRole(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.User{}, &models.Article{}, &models.Submission{})
+
+	db.Create(&models.User{Username: "alice", Email: "alice@example.com", Role: models.RoleAdmin})
+	db.Create(&models.User{Username: "bobby", Email: "bobby@example.com", Role: models.RoleUser})
+
+	rows, total, err := ListAdminUsers(db, AdminUserFilter{Q: "ali"}, 1, 50)
+	if err != nil {
+		t.Fatalf("ListAdminUsers: %v", err)
+	}
+	if total != 1 || len(rows) != 1 || rows[0].Username != "alice" {
+		t.Fatalf("expected only alice to match q=ali, got %+v", rows)
+	}
+
+	rows, total, err = ListAdminUsers(db, AdminUserFilter{Role: models.RoleAdmin}, 1, 50)
+	if err != nil {
+		t.Fatalf("ListAdminUsers: %v", err)
+	}
+	if total != 1 || len(rows) != 1 || rows[0].Username != "alice" {
+		t.Fatalf("expected only the admin to match role=admin, got %+v", rows)
+	}
+}
+
+func TestStreamAdminUsersVisitsEveryMatchingRowInOrder(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.User{}, &models.Article{}, &models.Submission{})
+
+	db.Create(&models.User{Username: "alice", Email: "alice@example.com"})
+	db.Create(&models.User{Username: "bob", Email: "bob@example.com"})
+
+	var seen []string
+	err := StreamAdminUsers(db, AdminUserFilter{Sort: "username", Desc: false}, func(row AdminUserRow) error {
+		seen = append(seen, row.Username)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamAdminUsers: %v", err)
+	}
+	if len(seen) != 2 || seen[0] != "alice" || seen[1] != "bob" {
+		t.Fatalf("expected [alice bob] in username order, got %v", seen)
+	}
+}
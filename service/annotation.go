@@ -0,0 +1,122 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/1055373165/blog/models"
+	"gorm.io/gorm"
+)
+
+var ErrAnnotationNotFound = errors.New("annotation not found")
+
+// AnnotationWithStatus decorates an Annotation with its anchor's validity
+// against the article's current content. If the original offsets no
+// longer match QuotedText, GetArticleAnnotations tries to re-locate it by
+// searching for QuotedText and rewrites StartOffset/EndOffset to the match;
+// if QuotedText can't be found at all, Orphaned is set instead of dropping
+// the annotation.
+type AnnotationWithStatus struct {
+	models.Annotation
+	Orphaned bool `json:"orphaned"`
+}
+
+// CreateAnnotation saves a new annotation owned by annotation.UserID.
+func CreateAnnotation(db *gorm.DB, annotation *models.Annotation) error {
+	return db.Create(annotation).Error
+}
+
+// GetArticleAnnotations returns every annotation userID has made on
+// articleID, decorated with up-to-date anchor positions against content
+// (the article's current Content). An anchor still matching its stored
+// offsets is returned unchanged; one that doesn't is re-located via
+// QuotedText, or flagged Orphaned if QuotedText is no longer present.
+func GetArticleAnnotations(db *gorm.DB, userID, articleID uint, content string) ([]AnnotationWithStatus, error) {
+	var annotations []models.Annotation
+	if err := db.Where("user_id = ? AND article_id = ?", userID, articleID).
+		Order("created_at asc").Find(&annotations).Error; err != nil {
+		return nil, err
+	}
+
+	out := make([]AnnotationWithStatus, 0, len(annotations))
+	for _, a := range annotations {
+		decorated := AnnotationWithStatus{Annotation: a}
+		if !anchorMatches(content, a) {
+			if idx := strings.Index(content, a.QuotedText); idx >= 0 {
+				decorated.StartOffset = idx
+				decorated.EndOffset = idx + len(a.QuotedText)
+			} else {
+				decorated.Orphaned = true
+			}
+		}
+		out = append(out, decorated)
+	}
+	return out, nil
+}
+
+func anchorMatches(content string, a models.Annotation) bool {
+	if a.StartOffset < 0 || a.EndOffset > len(content) || a.StartOffset >= a.EndOffset {
+		return false
+	}
+	return content[a.StartOffset:a.EndOffset] == a.QuotedText
+}
+
+// UpdateAnnotation applies updates to the annotation owned by (id, userID).
+func UpdateAnnotation(db *gorm.DB, id, userID uint, updates map[string]any) (*models.Annotation, error) {
+	annotation, err := findOwnedAnnotation(db, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(updates) > 0 {
+		if err := db.Model(annotation).Updates(updates).Error; err != nil {
+			return nil, err
+		}
+	}
+	return annotation, nil
+}
+
+// DeleteAnnotation deletes the annotation owned by (id, userID).
+func DeleteAnnotation(db *gorm.DB, id, userID uint) error {
+	annotation, err := findOwnedAnnotation(db, id, userID)
+	if err != nil {
+		return err
+	}
+	return db.Delete(annotation).Error
+}
+
+func findOwnedAnnotation(db *gorm.DB, id, userID uint) (*models.Annotation, error) {
+	var annotation models.Annotation
+	if err := db.Where("id = ? AND user_id = ?", id, userID).First(&annotation).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAnnotationNotFound
+		}
+		return nil, err
+	}
+	return &annotation, nil
+}
+
+// CountAnnotationsByArticle returns, for userID, how many annotations they
+// have on each of articleIDs, keyed by article ID. Articles with no
+// annotations are simply absent from the map.
+func CountAnnotationsByArticle(db *gorm.DB, userID uint, articleIDs []uint) (map[uint]int64, error) {
+	counts := make(map[uint]int64, len(articleIDs))
+	if len(articleIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		ArticleID uint
+		Count     int64
+	}
+	if err := db.Model(&models.Annotation{}).
+		Select("article_id, count(*) as count").
+		Where("user_id = ? AND article_id IN ?", userID, articleIDs).
+		Group("article_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		counts[r.ArticleID] = r.Count
+	}
+	return counts, nil
+}
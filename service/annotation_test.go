@@ -0,0 +1,108 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/1055373165/blog/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupAnnotationTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	conn, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	if err := conn.AutoMigrate(&models.Annotation{}, &models.Article{}); err != nil {
+		t.Fatalf("failed to migrate test db: %v", err)
+	}
+	return conn
+}
+
+func TestGetArticleAnnotationsRelocatesMovedAnchor(t *testing.T) {
+	db := setupAnnotationTestDB(t)
+	annotation := models.Annotation{
+		UserID: 1, ArticleID: 1,
+		StartOffset: 0, EndOffset: 5, QuotedText: "hello",
+	}
+	if err := CreateAnnotation(db, &annotation); err != nil {
+		t.Fatalf("CreateAnnotation failed: %v", err)
+	}
+
+	got, err := GetArticleAnnotations(db, 1, 1, "prefix hello world")
+	if err != nil {
+		t.Fatalf("GetArticleAnnotations failed: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("expected 1 annotation, got %d", len(got))
+	}
+	if got[0].Orphaned {
+		t.Error("expected annotation to be re-located, not orphaned")
+	}
+	if got[0].StartOffset != 7 || got[0].EndOffset != 12 {
+		t.Errorf("expected re-located offsets 7-12, got %d-%d", got[0].StartOffset, got[0].EndOffset)
+	}
+}
+
+func TestGetArticleAnnotationsFlagsOrphanedWhenTextGone(t *testing.T) {
+	db := setupAnnotationTestDB(t)
+	annotation := models.Annotation{
+		UserID: 1, ArticleID: 1,
+		StartOffset: 0, EndOffset: 5, QuotedText: "hello",
+	}
+	if err := CreateAnnotation(db, &annotation); err != nil {
+		t.Fatalf("CreateAnnotation failed: %v", err)
+	}
+
+	got, err := GetArticleAnnotations(db, 1, 1, "completely different content")
+	if err != nil {
+		t.Fatalf("GetArticleAnnotations failed: %v", err)
+	}
+	if len(got) != 1 || !got[0].Orphaned {
+		t.Fatalf("expected annotation to be flagged orphaned, got %+v", got)
+	}
+}
+
+func TestUpdateAndDeleteAnnotationScopedToOwner(t *testing.T) {
+	db := setupAnnotationTestDB(t)
+	annotation := models.Annotation{UserID: 1, ArticleID: 1, StartOffset: 0, EndOffset: 5, QuotedText: "hello"}
+	if err := CreateAnnotation(db, &annotation); err != nil {
+		t.Fatalf("CreateAnnotation failed: %v", err)
+	}
+
+	if _, err := UpdateAnnotation(db, annotation.ID, 2, map[string]any{"note": "nope"}); err != ErrAnnotationNotFound {
+		t.Errorf("expected ErrAnnotationNotFound for a non-owner update, got %v", err)
+	}
+
+	updated, err := UpdateAnnotation(db, annotation.ID, 1, map[string]any{"note": "great point"})
+	if err != nil {
+		t.Fatalf("UpdateAnnotation failed: %v", err)
+	}
+	if updated.Note != "great point" {
+		t.Errorf("expected note to be updated, got %q", updated.Note)
+	}
+
+	if err := DeleteAnnotation(db, annotation.ID, 2); err != ErrAnnotationNotFound {
+		t.Errorf("expected ErrAnnotationNotFound for a non-owner delete, got %v", err)
+	}
+	if err := DeleteAnnotation(db, annotation.ID, 1); err != nil {
+		t.Fatalf("DeleteAnnotation failed: %v", err)
+	}
+}
+
+func TestCountAnnotationsByArticle(t *testing.T) {
+	db := setupAnnotationTestDB(t)
+	db.Create(&models.Annotation{UserID: 1, ArticleID: 1, StartOffset: 0, EndOffset: 1, QuotedText: "a"})
+	db.Create(&models.Annotation{UserID: 1, ArticleID: 1, StartOffset: 1, EndOffset: 2, QuotedText: "b"})
+	db.Create(&models.Annotation{UserID: 1, ArticleID: 2, StartOffset: 0, EndOffset: 1, QuotedText: "a"})
+	db.Create(&models.Annotation{UserID: 2, ArticleID: 1, StartOffset: 0, EndOffset: 1, QuotedText: "a"})
+
+	counts, err := CountAnnotationsByArticle(db, 1, []uint{1, 2, 3})
+	if err != nil {
+		t.Fatalf("CountAnnotationsByArticle failed: %v", err)
+	}
+	if counts[1] != 2 || counts[2] != 1 || counts[3] != 0 {
+		t.Errorf("unexpected counts: %+v", counts)
+	}
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/1055373165/blog/models"
+	"gorm.io/gorm"
+)
+
+// IncrementArticleViews bumps an article's view counter by one.
+func IncrementArticleViews(db *gorm.DB, articleID uint) error {
+	return db.Model(&models.Article{}).Where("id = ?", articleID).
+		UpdateColumn("views", gorm.Expr("views + 1")).Error
+}
+
+// ArticleStats is the cheap counter snapshot returned by
+// GET /api/articles/:id/stats, for callers (admin dashboards,
+// prerendering) that only need current views/likes without paying for
+// the full Article row - Content included - that GetArticle returns.
+type ArticleStats struct {
+	Views int64 `json:"views"`
+	Likes int64 `json:"likes"`
+}
+
+var ErrArticleNotFound = errors.New("article not found")
+
+// ToggleFavorite flips userID's favorite on a published articleID,
+// keeping Article.FavoritesCount accurate in the same transaction.
+// Unfavoriting an article that wasn't favorited is a no-op that reports
+// favorited=false rather than an error, so UI toggles can be optimistic.
+func ToggleFavorite(db *gorm.DB, userID, articleID uint) (favorited bool, err error) {
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var article models.Article
+		if err := tx.Where("id = ? AND status = ?", articleID, models.ArticleStatusPublished).
+			First(&article).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrArticleNotFound
+			}
+			return err
+		}
+
+		var existing models.Favorite
+		err := tx.Where("user_id = ? AND article_id = ?", userID, articleID).First(&existing).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			if err := tx.Create(&models.Favorite{UserID: userID, ArticleID: articleID}).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&article).UpdateColumn("favorites_count", gorm.Expr("favorites_count + 1")).Error; err != nil {
+				return err
+			}
+			favorited = true
+			return nil
+		case err != nil:
+			return err
+		default:
+			if err := tx.Delete(&existing).Error; err != nil {
+				return err
+			}
+			return tx.Model(&article).UpdateColumn("favorites_count", gorm.Expr("favorites_count - 1")).Error
+		}
+	})
+	return favorited, err
+}
+
+// GetUserFavorites returns the articles userID has favorited, most
+// recently favorited first, paginated.
+func GetUserFavorites(db *gorm.DB, userID uint, page, pageSize int) ([]models.Article, error) {
+	var articles []models.Article
+	err := db.Model(&models.Article{}).
+		Joins("JOIN favorites ON favorites.article_id = articles.id").
+		Where("favorites.user_id = ?", userID).
+		Order("favorites.created_at desc").
+		Offset((page - 1) * pageSize).Limit(pageSize).
+		Find(&articles).Error
+	return articles, err
+}
+
+// GetArticlesBySeries returns every article in seriesID ordered by
+// series_order, with unordered (NULL) articles sorted last.
+func GetArticlesBySeries(db *gorm.DB, seriesID uint) ([]models.Article, error) {
+	var articles []models.Article
+	err := db.Where("series_id = ?", seriesID).
+		Order("series_order IS NULL, series_order asc").
+		Find(&articles).Error
+	return articles, err
+}
+
+var ErrSeriesOrderMismatch = errors.New("article ids do not match the series membership exactly")
+
+// ReorderSeries assigns sequential series_order values (starting at 1) to
+// the articles in seriesID according to orderedArticleIDs. It fails if
+// orderedArticleIDs does not contain exactly the articles currently in the
+// series.
+func ReorderSeries(db *gorm.DB, seriesID uint, orderedArticleIDs []uint) ([]models.Article, error) {
+	var current []models.Article
+	if err := db.Where("series_id = ?", seriesID).Find(&current).Error; err != nil {
+		return nil, err
+	}
+
+	currentSet := make(map[uint]bool, len(current))
+	for _, a := range current {
+		currentSet[a.ID] = true
+	}
+	if len(orderedArticleIDs) != len(current) {
+		return nil, ErrSeriesOrderMismatch
+	}
+	seen := make(map[uint]bool, len(orderedArticleIDs))
+	for _, id := range orderedArticleIDs {
+		if !currentSet[id] || seen[id] {
+			return nil, ErrSeriesOrderMismatch
+		}
+		seen[id] = true
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// Clear existing orders first to avoid transient unique-index
+		// collisions while reassigning sequential values.
+		if err := tx.Model(&models.Article{}).Where("series_id = ?", seriesID).
+			Update("series_order", nil).Error; err != nil {
+			return err
+		}
+		for i, id := range orderedArticleIDs {
+			order := i + 1
+			if err := tx.Model(&models.Article{}).Where("id = ?", id).
+				Update("series_order", order).Error; err != nil {
+				return fmt.Errorf("setting order for article %d: %w", id, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return GetArticlesBySeries(db, seriesID)
+}
+
+// maxPinnedArticles caps how many articles can be pinned to the home feed
+// at once, keeping the pinned row from crowding out everything else.
+const maxPinnedArticles = 3
+
+var ErrPinLimitReached = errors.New("at most 3 articles may be pinned at once")
+
+// SetArticlePinned pins or unpins articleID on the home feed, rejecting a
+// pin once maxPinnedArticles are already pinned.
+func SetArticlePinned(db *gorm.DB, articleID uint, pinned bool) (*models.Article, error) {
+	var article models.Article
+	if err := db.First(&article, articleID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrArticleNotFound
+		}
+		return nil, err
+	}
+
+	if pinned && !article.IsPinned {
+		var count int64
+		if err := db.Model(&models.Article{}).Where("is_pinned = ?", true).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		if count >= maxPinnedArticles {
+			return nil, ErrPinLimitReached
+		}
+	}
+
+	if err := db.Model(&article).Update("is_pinned", pinned).Error; err != nil {
+		return nil, err
+	}
+	article.IsPinned = pinned
+	return &article, nil
+}
+
+// SetArticleFeatured adds or removes articleID from the home feed's
+// featured carousel.
+func SetArticleFeatured(db *gorm.DB, articleID uint, featured bool) (*models.Article, error) {
+	var article models.Article
+	if err := db.First(&article, articleID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrArticleNotFound
+		}
+		return nil, err
+	}
+
+	if err := db.Model(&article).Update("is_featured", featured).Error; err != nil {
+		return nil, err
+	}
+	article.IsFeatured = featured
+	return &article, nil
+}
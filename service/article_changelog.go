@@ -0,0 +1,63 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/1055373165/blog/models"
+	"gorm.io/gorm"
+)
+
+// UpdateArticle applies updates to an existing Article. When
+// changelogSummary is non-empty, it also atomically records a public
+// ArticleChangelog entry (authored by article.AuthorID) and bumps
+// UpdatedSignificantlyAt, mirroring how service.UpdateBlog applies a
+// plain column update - the changelog insert just rides along in the
+// same transaction. An empty changelogSummary updates the row as usual
+// without touching the changelog.
+func UpdateArticle(db *gorm.DB, article *models.Article, updates map[string]any, changelogSummary string) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if len(updates) > 0 {
+			if err := tx.Model(article).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		if changelogSummary != "" {
+			now := time.Now()
+			entry := models.ArticleChangelog{
+				ArticleID: article.ID,
+				AuthorID:  article.AuthorID,
+				Summary:   changelogSummary,
+				ChangedAt: now,
+			}
+			if err := tx.Create(&entry).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(article).Update("updated_significantly_at", now).Error; err != nil {
+				return err
+			}
+		}
+		return tx.First(article, article.ID).Error
+	})
+}
+
+// GetArticleChangelog returns articleID's changelog entries, newest first.
+func GetArticleChangelog(db *gorm.DB, articleID uint) ([]models.ArticleChangelog, error) {
+	var entries []models.ArticleChangelog
+	err := db.Where("article_id = ?", articleID).Order("changed_at desc").Find(&entries).Error
+	return entries, err
+}
+
+// GetLatestArticleChangelog returns articleID's most recent changelog
+// entry, or nil if it has none, for inlining into article responses.
+func GetLatestArticleChangelog(db *gorm.DB, articleID uint) (*models.ArticleChangelog, error) {
+	var entry models.ArticleChangelog
+	err := db.Where("article_id = ?", articleID).Order("changed_at desc").First(&entry).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/1055373165/blog/models"
+)
+
+func TestReorderSeries(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Series{}, &models.Article{})
+
+	series := models.Series{Title: "Go Basics", Slug: "go-basics"}
+	db.Create(&series)
+
+	a1 := models.Article{AuthorID: 1, Title: "Part 1", Slug: "part-1", SeriesID: &series.ID}
+	a2 := models.Article{AuthorID: 1, Title: "Part 2", Slug: "part-2", SeriesID: &series.ID}
+	a3 := models.Article{AuthorID: 1, Title: "Part 3", Slug: "part-3", SeriesID: &series.ID}
+	for _, a := range []*models.Article{&a1, &a2, &a3} {
+		db.Create(a)
+	}
+
+	ordered, err := ReorderSeries(db, series.ID, []uint{a3.ID, a1.ID, a2.ID})
+	if err != nil {
+		t.Fatalf("ReorderSeries returned error: %v", err)
+	}
+	if len(ordered) != 3 || ordered[0].ID != a3.ID || ordered[1].ID != a1.ID || ordered[2].ID != a2.ID {
+		t.Fatalf("unexpected order: %+v", ordered)
+	}
+
+	if _, err := ReorderSeries(db, series.ID, []uint{a1.ID, a2.ID}); err != ErrSeriesOrderMismatch {
+		t.Errorf("expected ErrSeriesOrderMismatch for incomplete set, got %v", err)
+	}
+}
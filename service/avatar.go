@@ -0,0 +1,30 @@
+package service
+
+import (
+	"github.com/1055373165/blog/models"
+	"gorm.io/gorm"
+)
+
+// SetUserAvatar sets userID's Avatar and AvatarThumbnail to avatarURL
+// and thumbnailURL, returning the updated user.
+func SetUserAvatar(db *gorm.DB, userID uint, avatarURL, thumbnailURL string) (*models.User, error) {
+	var user models.User
+	if err := db.First(&user, userID).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Model(&user).Updates(map[string]any{
+		"avatar":           avatarURL,
+		"avatar_thumbnail": thumbnailURL,
+	}).Error; err != nil {
+		return nil, err
+	}
+	user.Avatar = avatarURL
+	user.AvatarThumbnail = thumbnailURL
+	return &user, nil
+}
+
+// ClearUserAvatar resets userID's Avatar and AvatarThumbnail to "", the
+// same state as a user who never uploaded one.
+func ClearUserAvatar(db *gorm.DB, userID uint) (*models.User, error) {
+	return SetUserAvatar(db, userID, "", "")
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"path/filepath"
+	"sort"
+
+	"github.com/1055373165/blog/config"
+	"github.com/1055373165/blog/models"
+	"github.com/1055373165/blog/search"
+	"github.com/1055373165/blog/waveform"
+	"gorm.io/gorm"
+)
+
+// defaultWaveformPeakCount is the number of downsampled amplitude peaks
+// generated per blog, enough for a smooth player waveform without a
+// heavy payload.
+const defaultWaveformPeakCount = 800
+
+var ErrInvalidChapters = errors.New("chapters must have ascending start times within the media duration")
+
+// ValidateChapters checks that chapters are sorted by start_seconds and
+// every timestamp falls within [0, duration].
+func ValidateChapters(chapters models.Chapters, duration float64) error {
+	if !sort.SliceIsSorted(chapters, func(i, j int) bool {
+		return chapters[i].StartSeconds < chapters[j].StartSeconds
+	}) {
+		return ErrInvalidChapters
+	}
+	for _, ch := range chapters {
+		if ch.StartSeconds < 0 || ch.StartSeconds > duration {
+			return ErrInvalidChapters
+		}
+	}
+	return nil
+}
+
+// CreateBlog persists a new Blog and indexes its transcript for search.
+func CreateBlog(db *gorm.DB, blog *models.Blog) error {
+	if err := ValidateChapters(blog.Chapters, blog.Duration); err != nil {
+		return err
+	}
+	if err := db.Create(blog).Error; err != nil {
+		return err
+	}
+	return search.IndexBlog(blog)
+}
+
+// UpdateBlog applies updates to an existing Blog and re-indexes it.
+func UpdateBlog(db *gorm.DB, blog *models.Blog, updates map[string]any) error {
+	if chapters, ok := updates["chapters"].(models.Chapters); ok {
+		duration := blog.Duration
+		if d, ok := updates["duration"].(float64); ok {
+			duration = d
+		}
+		if err := ValidateChapters(chapters, duration); err != nil {
+			return err
+		}
+	}
+	if err := db.Model(blog).Updates(updates).Error; err != nil {
+		return err
+	}
+	if err := db.First(blog, blog.ID).Error; err != nil {
+		return err
+	}
+	return search.IndexBlog(blog)
+}
+
+// GetBlogBySlug loads a Blog (including chapters) by its slug.
+func GetBlogBySlug(db *gorm.DB, slug string) (*models.Blog, error) {
+	var blog models.Blog
+	if err := db.Where("slug = ?", slug).First(&blog).Error; err != nil {
+		return nil, err
+	}
+	return &blog, nil
+}
+
+// IncrementBlogViews bumps a blog's view counter by one, mirroring
+// IncrementArticleViews.
+func IncrementBlogViews(db *gorm.DB, blogID uint) error {
+	return db.Model(&models.Blog{}).Where("id = ?", blogID).
+		UpdateColumn("views", gorm.Expr("views + 1")).Error
+}
+
+// BlogStats is the cheap counter snapshot returned by
+// GET /api/blogs/:id/stats, mirroring ArticleStats.
+type BlogStats struct {
+	Views int64 `json:"views"`
+	Likes int64 `json:"likes"`
+}
+
+// TriggerBlogWaveformGeneration runs GenerateBlogWaveform in the
+// background, since decoding an hour-long file takes seconds and
+// callers (upload, or the generate-waveform endpoint) shouldn't block
+// on it. Errors are logged, not returned, since there's no caller left
+// to receive them by the time decoding finishes.
+func TriggerBlogWaveformGeneration(db *gorm.DB, blogID uint) {
+	go func() {
+		if err := GenerateBlogWaveform(db, blogID); err != nil {
+			log.Printf("waveform generation for blog %d failed: %v", blogID, err)
+		}
+	}()
+}
+
+// GenerateBlogWaveform decodes blogID's media file and stores
+// defaultWaveformPeakCount downsampled amplitude peaks on it. Decoding
+// failure (most commonly an unsupported codec; see package waveform)
+// is not an error here: it's recorded as WaveformStatusFailed with nil
+// peaks, and the caller is not expected to treat that as a failure
+// either.
+func GenerateBlogWaveform(db *gorm.DB, blogID uint) error {
+	var blog models.Blog
+	if err := db.First(&blog, blogID).Error; err != nil {
+		return err
+	}
+
+	path := filepath.Join(config.App.UploadDir, filepath.Base(blog.MediaURL))
+	peaks, err := waveform.ComputePeaks(path, defaultWaveformPeakCount)
+	if err != nil {
+		return db.Model(&blog).Updates(map[string]any{
+			"waveform_peaks":  nil,
+			"waveform_status": models.WaveformStatusFailed,
+		}).Error
+	}
+
+	return db.Model(&blog).Updates(map[string]any{
+		"waveform_peaks":  models.WaveformPeaks(peaks),
+		"waveform_status": models.WaveformStatusReady,
+	}).Error
+}
+
+// GetBlogsBySeries returns every blog in seriesID ordered by
+// series_order, with unordered (NULL) episodes sorted last.
+func GetBlogsBySeries(db *gorm.DB, seriesID uint) ([]models.Blog, error) {
+	var blogs []models.Blog
+	err := db.Where("series_id = ?", seriesID).
+		Order("series_order IS NULL, series_order asc").
+		Find(&blogs).Error
+	return blogs, err
+}
+
+// BlogEpisodeRef is a lightweight pointer to a neighboring episode in the
+// same series, for previous/next navigation.
+type BlogEpisodeRef struct {
+	ID    uint   `json:"id"`
+	Title string `json:"title"`
+	Slug  string `json:"slug"`
+}
+
+// GetBlogEpisodeNav returns the previous and next episodes adjacent to
+// blog within its series by series_order, or nil for either when blog
+// has no series, no order, or is at an end of the series.
+func GetBlogEpisodeNav(db *gorm.DB, blog *models.Blog) (previous, next *BlogEpisodeRef, err error) {
+	if blog.SeriesID == nil || blog.SeriesOrder == nil {
+		return nil, nil, nil
+	}
+
+	var prevBlog models.Blog
+	err = db.Where("series_id = ? AND series_order < ?", *blog.SeriesID, *blog.SeriesOrder).
+		Order("series_order desc").First(&prevBlog).Error
+	if err == nil {
+		previous = &BlogEpisodeRef{ID: prevBlog.ID, Title: prevBlog.Title, Slug: prevBlog.Slug}
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, err
+	}
+
+	var nextBlog models.Blog
+	err = db.Where("series_id = ? AND series_order > ?", *blog.SeriesID, *blog.SeriesOrder).
+		Order("series_order asc").First(&nextBlog).Error
+	if err == nil {
+		next = &BlogEpisodeRef{ID: nextBlog.ID, Title: nextBlog.Title, Slug: nextBlog.Slug}
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, err
+	}
+
+	return previous, next, nil
+}
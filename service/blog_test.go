@@ -0,0 +1,162 @@
+package service
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/1055373165/blog/config"
+	"github.com/1055373165/blog/models"
+)
+
+func TestValidateChapters(t *testing.T) {
+	duration := 600.0
+
+	valid := models.Chapters{{StartSeconds: 0, Title: "Intro"}, {StartSeconds: 120, Title: "Main"}}
+	if err := ValidateChapters(valid, duration); err != nil {
+		t.Errorf("expected valid chapters to pass, got %v", err)
+	}
+
+	outOfOrder := models.Chapters{{StartSeconds: 200, Title: "B"}, {StartSeconds: 10, Title: "A"}}
+	if err := ValidateChapters(outOfOrder, duration); err != ErrInvalidChapters {
+		t.Errorf("expected ErrInvalidChapters for out-of-order chapters, got %v", err)
+	}
+
+	beyondDuration := models.Chapters{{StartSeconds: 700, Title: "Too far"}}
+	if err := ValidateChapters(beyondDuration, duration); err != ErrInvalidChapters {
+		t.Errorf("expected ErrInvalidChapters for out-of-bounds chapter, got %v", err)
+	}
+}
+
+func TestGetBlogsBySeriesAndEpisodeNav(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Blog{}, &models.Series{})
+
+	series := models.Series{Title: "Podcast", Slug: "podcast"}
+	db.Create(&series)
+
+	order1, order2, order3 := 1, 2, 3
+	ep1 := models.Blog{Title: "Episode 1", Slug: "ep-1", MediaURL: "a.mp3", SeriesID: &series.ID, SeriesOrder: &order1}
+	ep2 := models.Blog{Title: "Episode 2", Slug: "ep-2", MediaURL: "b.mp3", SeriesID: &series.ID, SeriesOrder: &order2}
+	ep3 := models.Blog{Title: "Episode 3", Slug: "ep-3", MediaURL: "c.mp3", SeriesID: &series.ID, SeriesOrder: &order3}
+	db.Create(&ep1)
+	db.Create(&ep2)
+	db.Create(&ep3)
+
+	blogs, err := GetBlogsBySeries(db, series.ID)
+	if err != nil {
+		t.Fatalf("GetBlogsBySeries returned error: %v", err)
+	}
+	if len(blogs) != 3 || blogs[0].Slug != "ep-1" || blogs[2].Slug != "ep-3" {
+		t.Fatalf("expected episodes ordered ep-1..ep-3, got %+v", blogs)
+	}
+
+	previous, next, err := GetBlogEpisodeNav(db, &ep2)
+	if err != nil {
+		t.Fatalf("GetBlogEpisodeNav returned error: %v", err)
+	}
+	if previous == nil || previous.Slug != "ep-1" {
+		t.Errorf("expected previous episode ep-1, got %+v", previous)
+	}
+	if next == nil || next.Slug != "ep-3" {
+		t.Errorf("expected next episode ep-3, got %+v", next)
+	}
+
+	previous, next, err = GetBlogEpisodeNav(db, &ep1)
+	if err != nil {
+		t.Fatalf("GetBlogEpisodeNav returned error: %v", err)
+	}
+	if previous != nil {
+		t.Errorf("expected no previous episode before ep-1, got %+v", previous)
+	}
+	if next == nil || next.Slug != "ep-2" {
+		t.Errorf("expected next episode ep-2, got %+v", next)
+	}
+
+	standalone := models.Blog{Title: "Standalone", Slug: "standalone", MediaURL: "d.mp3"}
+	db.Create(&standalone)
+	previous, next, err = GetBlogEpisodeNav(db, &standalone)
+	if err != nil {
+		t.Fatalf("GetBlogEpisodeNav returned error: %v", err)
+	}
+	if previous != nil || next != nil {
+		t.Errorf("expected no nav for a standalone blog, got previous=%+v next=%+v", previous, next)
+	}
+}
+
+func TestGenerateBlogWaveform(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Blog{})
+
+	uploadDir := t.TempDir()
+	config.App = &config.Config{UploadDir: uploadDir}
+
+	wavBytes := buildTestWAV(t, make([]int16, 2000))
+	if err := os.WriteFile(filepath.Join(uploadDir, "episode.wav"), wavBytes, 0o644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+	ready := models.Blog{Title: "Ready", Slug: "ready", MediaURL: "http://example.com/api/files/episode.wav"}
+	db.Create(&ready)
+
+	if err := GenerateBlogWaveform(db, ready.ID); err != nil {
+		t.Fatalf("GenerateBlogWaveform returned error: %v", err)
+	}
+	var reloaded models.Blog
+	db.First(&reloaded, ready.ID)
+	if reloaded.WaveformStatus != models.WaveformStatusReady {
+		t.Errorf("expected status ready, got %v", reloaded.WaveformStatus)
+	}
+	if len(reloaded.WaveformPeaks) != defaultWaveformPeakCount {
+		t.Errorf("expected %d peaks, got %d", defaultWaveformPeakCount, len(reloaded.WaveformPeaks))
+	}
+
+	if err := os.WriteFile(filepath.Join(uploadDir, "episode.mp3"), []byte("not decodable"), 0o644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+	unsupported := models.Blog{Title: "Unsupported", Slug: "unsupported", MediaURL: "http://example.com/api/files/episode.mp3"}
+	db.Create(&unsupported)
+
+	if err := GenerateBlogWaveform(db, unsupported.ID); err != nil {
+		t.Fatalf("GenerateBlogWaveform should not error on unsupported format, got %v", err)
+	}
+	var reloadedUnsupported models.Blog
+	db.First(&reloadedUnsupported, unsupported.ID)
+	if reloadedUnsupported.WaveformStatus != models.WaveformStatusFailed {
+		t.Errorf("expected status failed, got %v", reloadedUnsupported.WaveformStatus)
+	}
+	if reloadedUnsupported.WaveformPeaks != nil {
+		t.Errorf("expected nil peaks on failure, got %v", reloadedUnsupported.WaveformPeaks)
+	}
+}
+
+// buildTestWAV builds a minimal mono 16-bit PCM WAV file for tests.
+func buildTestWAV(t *testing.T, samples []int16) []byte {
+	t.Helper()
+
+	var data bytes.Buffer
+	for _, s := range samples {
+		binary.Write(&data, binary.LittleEndian, s)
+	}
+
+	var fmtChunk bytes.Buffer
+	binary.Write(&fmtChunk, binary.LittleEndian, uint16(1))
+	binary.Write(&fmtChunk, binary.LittleEndian, uint16(1))
+	binary.Write(&fmtChunk, binary.LittleEndian, uint32(44100))
+	binary.Write(&fmtChunk, binary.LittleEndian, uint32(44100*2))
+	binary.Write(&fmtChunk, binary.LittleEndian, uint16(2))
+	binary.Write(&fmtChunk, binary.LittleEndian, uint16(16))
+
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(4+8+fmtChunk.Len()+8+data.Len()))
+	buf.WriteString("WAVE")
+	buf.WriteString("fmt ")
+	binary.Write(&buf, binary.LittleEndian, uint32(fmtChunk.Len()))
+	buf.Write(fmtChunk.Bytes())
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, uint32(data.Len()))
+	buf.Write(data.Bytes())
+	return buf.Bytes()
+}
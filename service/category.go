@@ -0,0 +1,87 @@
+package service
+
+import (
+	"github.com/1055373165/blog/models"
+	"gorm.io/gorm"
+)
+
+// GetCategories returns every category, flat and unordered by hierarchy.
+func GetCategories(db *gorm.DB) ([]models.Category, error) {
+	var categories []models.Category
+	err := db.Order("id asc").Find(&categories).Error
+	return categories, err
+}
+
+// FilterVisibleCategories drops categories with IsVisible false, used to
+// hide internal organizational categories from non-admin callers without
+// deleting them.
+func FilterVisibleCategories(categories []models.Category) []models.Category {
+	visible := make([]models.Category, 0, len(categories))
+	for _, cat := range categories {
+		if cat.IsVisible {
+			visible = append(visible, cat)
+		}
+	}
+	return visible
+}
+
+// FilterVisibleCategoryTree drops invisible categories, and everything
+// nested beneath them, from a tree produced by GetCategoryTree. It
+// copies rather than mutates its input, since GetCategoryTree's result
+// is shared across callers via cache.Default.
+func FilterVisibleCategoryTree(nodes []*CategoryNode) []*CategoryNode {
+	visible := make([]*CategoryNode, 0, len(nodes))
+	for _, node := range nodes {
+		if !node.IsVisible {
+			continue
+		}
+		visible = append(visible, &CategoryNode{
+			Category: node.Category,
+			Children: FilterVisibleCategoryTree(node.Children),
+		})
+	}
+	return visible
+}
+
+// CategoryNode is a Category decorated with its direct children, used to
+// render GetCategoryTree's nested response.
+type CategoryNode struct {
+	models.Category
+	Children []*CategoryNode `json:"children,omitempty"`
+}
+
+// GetCategoryTree loads every category in a single query and assembles
+// the parent/child hierarchy in memory.
+func GetCategoryTree(db *gorm.DB) ([]*CategoryNode, error) {
+	categories, err := GetCategories(db)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[uint]*CategoryNode, len(categories))
+	for _, cat := range categories {
+		nodes[cat.ID] = &CategoryNode{Category: cat}
+	}
+
+	var roots []*CategoryNode
+	for _, cat := range categories {
+		node := nodes[cat.ID]
+		if cat.ParentID == nil {
+			roots = append(roots, node)
+			continue
+		}
+		if parent, ok := nodes[*cat.ParentID]; ok {
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+	return roots, nil
+}
+
+// GetTags returns every tag.
+func GetTags(db *gorm.DB) ([]models.Tag, error) {
+	var tags []models.Tag
+	err := db.Order("id asc").Find(&tags).Error
+	return tags, err
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/1055373165/blog/models"
+	"gorm.io/gorm"
+)
+
+// maxCategoryPins caps how many articles can be pinned to a single
+// category at once, keeping the pinned set short enough to stay "start
+// here" material.
+const maxCategoryPins = 2
+
+var (
+	ErrCategoryNotFound        = errors.New("category not found")
+	ErrCategoryPinLimitReached = errors.New("at most 2 articles may be pinned per category")
+)
+
+// PinArticleToCategory pins articleID to categoryID, rejecting the pin
+// once maxCategoryPins are already pinned for that category.
+func PinArticleToCategory(db *gorm.DB, categoryID, articleID uint) (*models.CategoryPin, error) {
+	var category models.Category
+	if err := db.First(&category, categoryID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, err
+	}
+
+	var article models.Article
+	if err := db.First(&article, articleID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrArticleNotFound
+		}
+		return nil, err
+	}
+
+	var existing models.CategoryPin
+	err := db.Where("category_id = ? AND article_id = ?", categoryID, articleID).First(&existing).Error
+	if err == nil {
+		return &existing, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	var count int64
+	if err := db.Model(&models.CategoryPin{}).Where("category_id = ?", categoryID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count >= maxCategoryPins {
+		return nil, ErrCategoryPinLimitReached
+	}
+
+	pin := models.CategoryPin{CategoryID: categoryID, ArticleID: articleID, PinnedAt: time.Now()}
+	if err := db.Create(&pin).Error; err != nil {
+		return nil, err
+	}
+	return &pin, nil
+}
+
+// UnpinArticleFromCategory removes articleID's pin from categoryID, if any.
+func UnpinArticleFromCategory(db *gorm.DB, categoryID, articleID uint) error {
+	return db.Where("category_id = ? AND article_id = ?", categoryID, articleID).Delete(&models.CategoryPin{}).Error
+}
+
+// GetCategoryPins returns categoryID's pinned articles, oldest pin first.
+func GetCategoryPins(db *gorm.DB, categoryID uint) ([]models.CategoryPin, error) {
+	var pins []models.CategoryPin
+	err := db.Where("category_id = ?", categoryID).Order("pinned_at asc").Preload("Article").Find(&pins).Error
+	return pins, err
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/1055373165/blog/models"
+	"gorm.io/gorm"
+)
+
+func TestFilterVisibleCategories(t *testing.T) {
+	categories := []models.Category{
+		{ID: 1, Name: "Public", IsVisible: true},
+		{ID: 2, Name: "Internal", IsVisible: false},
+	}
+
+	visible := FilterVisibleCategories(categories)
+	if len(visible) != 1 || visible[0].ID != 1 {
+		t.Errorf("got %+v, want only category 1", visible)
+	}
+}
+
+func TestPinArticleToCategoryEnforcesCapAndIdempotence(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Category{}, &models.Article{}, &models.CategoryPin{})
+
+	category := models.Category{Name: "Guides", Slug: "guides"}
+	db.Create(&category)
+	var articles []models.Article
+	for i := 0; i < 3; i++ {
+		a := models.Article{AuthorID: 1, Title: "a", Slug: "a" + strconv.Itoa(i)}
+		db.Create(&a)
+		articles = append(articles, a)
+	}
+
+	if _, err := PinArticleToCategory(db, category.ID, articles[0].ID); err != nil {
+		t.Fatalf("first pin: %v", err)
+	}
+	if _, err := PinArticleToCategory(db, category.ID, articles[0].ID); err != nil {
+		t.Fatalf("re-pinning the same article should be a no-op, got: %v", err)
+	}
+	if _, err := PinArticleToCategory(db, category.ID, articles[1].ID); err != nil {
+		t.Fatalf("second pin: %v", err)
+	}
+	if _, err := PinArticleToCategory(db, category.ID, articles[2].ID); err != ErrCategoryPinLimitReached {
+		t.Fatalf("expected ErrCategoryPinLimitReached, got %v", err)
+	}
+
+	pins, err := GetCategoryPins(db, category.ID)
+	if err != nil {
+		t.Fatalf("GetCategoryPins: %v", err)
+	}
+	if len(pins) != 2 {
+		t.Fatalf("expected 2 pins, got %d", len(pins))
+	}
+
+	if err := UnpinArticleFromCategory(db, category.ID, articles[0].ID); err != nil {
+		t.Fatalf("unpin: %v", err)
+	}
+	if _, err := PinArticleToCategory(db, category.ID, articles[2].ID); err != nil {
+		t.Fatalf("expected room after unpinning, got: %v", err)
+	}
+}
+
+func TestFilterVisibleCategoryTree(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Category{})
+
+	root := models.Category{Name: "Root", Slug: "root", IsVisible: false}
+	db.Create(&root)
+	child := models.Category{Name: "Child", Slug: "child", ParentID: &root.ID, IsVisible: true}
+	db.Create(&child)
+	other := models.Category{Name: "Other", Slug: "other", IsVisible: true}
+	db.Create(&other)
+
+	tree, err := GetCategoryTree(db)
+	if err != nil {
+		t.Fatalf("GetCategoryTree returned error: %v", err)
+	}
+
+	filtered := FilterVisibleCategoryTree(tree)
+	if len(filtered) != 1 || filtered[0].ID != other.ID {
+		t.Errorf("got %+v, want only the Other root (Root and its child are hidden)", filtered)
+	}
+
+	// The cached/shared tree must not be mutated by filtering.
+	for _, node := range tree {
+		if node.ID == root.ID && len(node.Children) != 1 {
+			t.Errorf("FilterVisibleCategoryTree mutated the shared tree: %+v", node)
+		}
+	}
+}
+
+// TestGetCategoryTreeIssuesConstantQueryCountForDeepHierarchy guards
+// against a regression back to one query per node: GetCategoryTree loads
+// every category in a single query and assembles the hierarchy in Go, so
+// the number of queries issued must not grow with the tree's depth or
+// width.
+func TestGetCategoryTreeIssuesConstantQueryCountForDeepHierarchy(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Category{})
+
+	var parentID *uint
+	var chain []models.Category
+	for level := 0; level < 4; level++ {
+		var chainNode models.Category
+		for i := 0; i < 3; i++ {
+			cat := models.Category{Name: "L" + strconv.Itoa(level) + "N" + strconv.Itoa(i), Slug: "l" + strconv.Itoa(level) + "n" + strconv.Itoa(i), ParentID: parentID, IsVisible: true}
+			db.Create(&cat)
+			if i == 0 {
+				chainNode = cat
+			}
+		}
+		chain = append(chain, chainNode)
+		parentID = &chainNode.ID
+	}
+
+	var queries int32
+	db.Callback().Query().Before("gorm:query").Register("count_queries", func(tx *gorm.DB) {
+		atomic.AddInt32(&queries, 1)
+	})
+
+	tree, err := GetCategoryTree(db)
+	if err != nil {
+		t.Fatalf("GetCategoryTree returned error: %v", err)
+	}
+
+	if got := atomic.LoadInt32(&queries); got != 1 {
+		t.Errorf("expected GetCategoryTree to issue exactly 1 query, issued %d", got)
+	}
+
+	if len(tree) != 3 {
+		t.Fatalf("expected 3 root categories, got %d", len(tree))
+	}
+	node := findCategoryNode(tree, chain[0].ID)
+	for level := 1; level < len(chain); level++ {
+		if node == nil {
+			t.Fatalf("expected to find category at level %d, tree was truncated", level-1)
+		}
+		if len(node.Children) != 3 {
+			t.Fatalf("expected 3 children at level %d, got %d", level-1, len(node.Children))
+		}
+		node = findCategoryNode(node.Children, chain[level].ID)
+	}
+	if node != nil && len(node.Children) != 0 {
+		t.Errorf("expected the deepest level to have no children, got %d", len(node.Children))
+	}
+}
+
+func findCategoryNode(nodes []*CategoryNode, id uint) *CategoryNode {
+	for _, node := range nodes {
+		if node.ID == id {
+			return node
+		}
+	}
+	return nil
+}
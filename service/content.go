@@ -0,0 +1,102 @@
+package service
+
+import (
+	"github.com/1055373165/blog/models"
+	"github.com/1055373165/blog/utils"
+	"gorm.io/gorm"
+)
+
+// NormalizeArticleContentURLs rewrites every Article's stored Content
+// field into the canonical relative upload-URL form, batchSize rows at
+// a time, and returns the number of URLs rewritten keyed by article ID
+// for every article that changed.
+func NormalizeArticleContentURLs(db *gorm.DB, batchSize int) (map[uint]int, error) {
+	touched := map[uint]int{}
+	var lastID uint
+	for {
+		var articles []models.Article
+		if err := db.Where("id > ?", lastID).Order("id asc").Limit(batchSize).Find(&articles).Error; err != nil {
+			return nil, err
+		}
+		if len(articles) == 0 {
+			break
+		}
+
+		for _, a := range articles {
+			lastID = a.ID
+
+			rewritten, count := utils.NormalizeContentURLs(a.Content)
+			if count == 0 {
+				continue
+			}
+			if err := db.Model(&models.Article{}).Where("id = ?", a.ID).
+				Update("content", rewritten).Error; err != nil {
+				return nil, err
+			}
+			touched[a.ID] = count
+		}
+	}
+	return touched, nil
+}
+
+// excerptBackfillBatchSize is the number of rows BackfillExcerpts
+// processes per batch, for articles and submissions alike.
+const excerptBackfillBatchSize = 100
+
+// ExcerptBackfillResult reports how many Article and Submission rows
+// BackfillExcerpts regenerated.
+type ExcerptBackfillResult struct {
+	ArticlesUpdated    int `json:"articles_updated"`
+	SubmissionsUpdated int `json:"submissions_updated"`
+}
+
+// BackfillExcerpts regenerates Excerpt for every Article and Submission
+// row where ExcerptAuto is true or Excerpt is empty, batchSize rows at
+// a time, leaving hand-written excerpts (ExcerptAuto false with a
+// non-empty Excerpt) untouched. For use after a bulk content import, or
+// once for rows created before Excerpt existed.
+func BackfillExcerpts(db *gorm.DB) (ExcerptBackfillResult, error) {
+	var result ExcerptBackfillResult
+
+	var lastID uint
+	for {
+		var articles []models.Article
+		if err := db.Where("id > ? AND (excerpt_auto = ? OR excerpt = ?)", lastID, true, "").
+			Order("id asc").Limit(excerptBackfillBatchSize).Find(&articles).Error; err != nil {
+			return result, err
+		}
+		if len(articles) == 0 {
+			break
+		}
+		for _, a := range articles {
+			lastID = a.ID
+			if err := db.Model(&models.Article{}).Where("id = ?", a.ID).
+				Updates(map[string]any{"excerpt": utils.Excerpt(a.Content), "excerpt_auto": true}).Error; err != nil {
+				return result, err
+			}
+			result.ArticlesUpdated++
+		}
+	}
+
+	lastID = 0
+	for {
+		var submissions []models.Submission
+		if err := db.Where("id > ? AND (excerpt_auto = ? OR excerpt = ?)", lastID, true, "").
+			Order("id asc").Limit(excerptBackfillBatchSize).Find(&submissions).Error; err != nil {
+			return result, err
+		}
+		if len(submissions) == 0 {
+			break
+		}
+		for _, s := range submissions {
+			lastID = s.ID
+			if err := db.Model(&models.Submission{}).Where("id = ?", s.ID).
+				Updates(map[string]any{"excerpt": utils.Excerpt(s.Content), "excerpt_auto": true}).Error; err != nil {
+				return result, err
+			}
+			result.SubmissionsUpdated++
+		}
+	}
+
+	return result, nil
+}
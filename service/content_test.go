@@ -0,0 +1,84 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/1055373165/blog/models"
+	"github.com/1055373165/blog/utils"
+)
+
+func TestNormalizeArticleContentURLs(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Article{})
+
+	clean := models.Article{AuthorID: 1, Title: "Clean", Slug: "clean", Content: `<img src="/api/files/a.png">`}
+	dirty := models.Article{AuthorID: 1, Title: "Dirty", Slug: "dirty",
+		Content: `<img src="http://localhost:3001/api/files/b.png"> <img src="https://www.godepth.top/api/media/c.mp4">`}
+	for _, a := range []*models.Article{&clean, &dirty} {
+		db.Create(a)
+	}
+
+	touched, err := NormalizeArticleContentURLs(db, 1)
+	if err != nil {
+		t.Fatalf("NormalizeArticleContentURLs returned error: %v", err)
+	}
+
+	if _, ok := touched[clean.ID]; ok {
+		t.Errorf("expected clean article to be untouched, got %+v", touched)
+	}
+	if touched[dirty.ID] != 2 {
+		t.Errorf("expected 2 urls touched for dirty article, got %d", touched[dirty.ID])
+	}
+
+	var reloaded models.Article
+	db.First(&reloaded, dirty.ID)
+	want := `<img src="/api/files/b.png"> <img src="/api/media/c.mp4">`
+	if reloaded.Content != want {
+		t.Errorf("got content %q, want %q", reloaded.Content, want)
+	}
+}
+
+func TestBackfillExcerptsRegeneratesAutoAndEmptyOnly(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Article{}, &models.Submission{})
+
+	autoArticle := models.Article{AuthorID: 1, Title: "Auto", Slug: "auto", Content: "# Heading\n\nSome body text.", ExcerptAuto: true}
+	emptyArticle := models.Article{AuthorID: 1, Title: "Empty", Slug: "empty", Content: "More **body** text.", ExcerptAuto: false}
+	handWritten := models.Article{AuthorID: 1, Title: "Hand", Slug: "hand", Content: "# Heading\n\nIgnored body.", Excerpt: "A hand-written teaser.", ExcerptAuto: false}
+	for _, a := range []*models.Article{&autoArticle, &emptyArticle, &handWritten} {
+		if err := db.Create(a).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	autoSubmission := models.Submission{AuthorID: 1, Title: "Auto", Content: "# Draft\n\nDraft body.", ExcerptAuto: true}
+	if err := db.Create(&autoSubmission).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := BackfillExcerpts(db)
+	if err != nil {
+		t.Fatalf("BackfillExcerpts returned error: %v", err)
+	}
+	if result.ArticlesUpdated != 2 {
+		t.Errorf("expected 2 articles updated, got %d", result.ArticlesUpdated)
+	}
+	if result.SubmissionsUpdated != 1 {
+		t.Errorf("expected 1 submission updated, got %d", result.SubmissionsUpdated)
+	}
+
+	var reloadedAuto, reloadedEmpty, reloadedHand models.Article
+	db.First(&reloadedAuto, autoArticle.ID)
+	db.First(&reloadedEmpty, emptyArticle.ID)
+	db.First(&reloadedHand, handWritten.ID)
+
+	if reloadedAuto.Excerpt != utils.Excerpt(autoArticle.Content) {
+		t.Errorf("got excerpt %q, want %q", reloadedAuto.Excerpt, utils.Excerpt(autoArticle.Content))
+	}
+	if reloadedEmpty.Excerpt != utils.Excerpt(emptyArticle.Content) || !reloadedEmpty.ExcerptAuto {
+		t.Errorf("expected empty-excerpt article to be backfilled and marked auto, got %+v", reloadedEmpty)
+	}
+	if reloadedHand.Excerpt != "A hand-written teaser." || reloadedHand.ExcerptAuto {
+		t.Errorf("expected hand-written excerpt left untouched, got %+v", reloadedHand)
+	}
+}
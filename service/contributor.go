@@ -0,0 +1,36 @@
+package service
+
+import (
+	"github.com/1055373165/blog/models"
+	"gorm.io/gorm"
+)
+
+// SetArticleContributors replaces articleID's contributor list with
+// contributors, in the given order, within a single transaction.
+// Article.AuthorID is unaffected; it remains the primary owner used for
+// permission checks.
+func SetArticleContributors(db *gorm.DB, articleID uint, contributors []models.ArticleContributor) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("article_id = ?", articleID).Delete(&models.ArticleContributor{}).Error; err != nil {
+			return err
+		}
+		for i := range contributors {
+			contributors[i].ID = 0
+			contributors[i].ArticleID = articleID
+			contributors[i].Position = i
+		}
+		if len(contributors) == 0 {
+			return nil
+		}
+		return tx.Create(&contributors).Error
+	})
+}
+
+// GetArticleContributors returns articleID's contributors ordered by
+// position, each with its User preloaded for name/avatar attribution.
+func GetArticleContributors(db *gorm.DB, articleID uint) ([]models.ArticleContributor, error) {
+	var contributors []models.ArticleContributor
+	err := db.Preload("User").Where("article_id = ?", articleID).
+		Order("position asc").Find(&contributors).Error
+	return contributors, err
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/1055373165/blog/models"
+)
+
+func TestSetArticleContributorsReplacesAndOrders(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Article{}, &models.User{}, &models.ArticleContributor{})
+
+	article := models.Article{AuthorID: 1, Title: "A", Slug: "a", Status: models.ArticleStatusPublished}
+	db.Create(&article)
+	coAuthor := models.User{Username: "jun", Email: "jun@example.com"}
+	translator := models.User{Username: "taro", Email: "taro@example.com"}
+	db.Create(&coAuthor)
+	db.Create(&translator)
+
+	err := SetArticleContributors(db, article.ID, []models.ArticleContributor{
+		{UserID: coAuthor.ID, Role: models.ContributorRoleCoAuthor},
+		{UserID: translator.ID, Role: models.ContributorRoleTranslator},
+	})
+	if err != nil {
+		t.Fatalf("SetArticleContributors returned error: %v", err)
+	}
+
+	contributors, err := GetArticleContributors(db, article.ID)
+	if err != nil {
+		t.Fatalf("GetArticleContributors returned error: %v", err)
+	}
+	if len(contributors) != 2 {
+		t.Fatalf("expected 2 contributors, got %d", len(contributors))
+	}
+	if contributors[1].UserID != translator.ID || contributors[1].User == nil || contributors[1].User.Username != "taro" {
+		t.Errorf("expected second contributor's User preloaded, got %+v", contributors[1])
+	}
+
+	// Replacing drops the prior set entirely.
+	if err := SetArticleContributors(db, article.ID, []models.ArticleContributor{
+		{UserID: translator.ID, Role: models.ContributorRoleEditor},
+	}); err != nil {
+		t.Fatalf("SetArticleContributors returned error: %v", err)
+	}
+	contributors, err = GetArticleContributors(db, article.ID)
+	if err != nil {
+		t.Fatalf("GetArticleContributors returned error: %v", err)
+	}
+	if len(contributors) != 1 || contributors[0].Role != models.ContributorRoleEditor {
+		t.Errorf("expected contributor set replaced, got %+v", contributors)
+	}
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"errors"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+
+	"github.com/1055373165/blog/config"
+	"github.com/1055373165/blog/models"
+	"github.com/1055373165/blog/utils"
+	"gorm.io/gorm"
+)
+
+// ErrCoverImageNotOwnStorage is returned when a cover_image doesn't
+// point at this site's own /api/files or /api/media endpoints -
+// external hotlinks are rejected outright rather than downloaded and
+// rehosted (see README "Known gaps").
+var ErrCoverImageNotOwnStorage = errors.New("cover_image must reference an already-uploaded file under /api/files or /api/media")
+
+// ErrCoverImageFileMissing is returned when cover_image names a file
+// that isn't actually present in upload storage.
+var ErrCoverImageFileMissing = errors.New("cover_image file does not exist in upload storage")
+
+// ResolveCoverImagePath validates coverImage against this site's own
+// upload storage and returns the referenced file's path on disk.
+func ResolveCoverImagePath(coverImage string) (string, error) {
+	filename, ok := utils.ParseUploadReference(coverImage)
+	if !ok {
+		return "", ErrCoverImageNotOwnStorage
+	}
+	// utils.ResolveUploadPath also guards against a stored cover_image
+	// whose filename resolves, via a symlink planted in the upload
+	// directory, to a file outside it - the same containment check
+	// handler.serveUploadedFile applies to request-path-driven downloads.
+	path, err := utils.ResolveUploadPath(config.App.UploadDir, "", filename)
+	if err != nil {
+		return "", ErrCoverImageFileMissing
+	}
+	return path, nil
+}
+
+// DecodeImageDimensions reads just enough of the file at path to
+// report its pixel dimensions, without decoding the full image.
+func DecodeImageDimensions(path string) (width, height int, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+	cfg, _, err := image.DecodeConfig(f)
+	if err != nil {
+		return 0, 0, err
+	}
+	return cfg.Width, cfg.Height, nil
+}
+
+// BrokenCover pairs an Article with the reason its stored cover_image
+// could no longer be resolved, for GetArticlesWithBrokenCovers.
+type BrokenCover struct {
+	models.Article
+	Reason string `json:"reason"`
+}
+
+// GetArticlesWithBrokenCovers scans every article with a non-empty
+// CoverImage and reports those whose file has since gone missing from
+// upload storage (e.g. deleted out from under the site, or moved when
+// BLOG_UPLOAD_DIR changed).
+func GetArticlesWithBrokenCovers(db *gorm.DB) ([]BrokenCover, error) {
+	var articles []models.Article
+	if err := db.Where("cover_image != ?", "").Find(&articles).Error; err != nil {
+		return nil, err
+	}
+
+	var broken []BrokenCover
+	for _, a := range articles {
+		if _, err := ResolveCoverImagePath(a.CoverImage); err != nil {
+			broken = append(broken, BrokenCover{Article: a, Reason: err.Error()})
+		}
+	}
+	return broken, nil
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/1055373165/blog/config"
+	"github.com/1055373165/blog/models"
+)
+
+func TestResolveCoverImagePathRejectsExternalHotlink(t *testing.T) {
+	config.App = &config.Config{UploadDir: t.TempDir()}
+
+	_, err := ResolveCoverImagePath("https://example.com/cat.png")
+	if !errors.Is(err, ErrCoverImageNotOwnStorage) {
+		t.Fatalf("expected ErrCoverImageNotOwnStorage for an external URL, got %v", err)
+	}
+}
+
+func TestResolveCoverImagePathRejectsMissingFile(t *testing.T) {
+	config.App = &config.Config{UploadDir: t.TempDir()}
+
+	_, err := ResolveCoverImagePath("/api/files/does-not-exist.png")
+	if !errors.Is(err, ErrCoverImageFileMissing) {
+		t.Fatalf("expected ErrCoverImageFileMissing for a file not on disk, got %v", err)
+	}
+}
+
+func TestResolveCoverImagePathAcceptsExistingFile(t *testing.T) {
+	dir := t.TempDir()
+	config.App = &config.Config{UploadDir: dir}
+	if err := os.WriteFile(filepath.Join(dir, "cover.png"), []byte("fake"), 0o644); err != nil {
+		t.Fatalf("failed to seed upload dir: %v", err)
+	}
+
+	path, err := ResolveCoverImagePath("/api/files/cover.png")
+	if err != nil {
+		t.Fatalf("ResolveCoverImagePath: %v", err)
+	}
+	if path != filepath.Join(dir, "cover.png") {
+		t.Errorf("expected resolved path %q, got %q", filepath.Join(dir, "cover.png"), path)
+	}
+}
+
+func TestGetArticlesWithBrokenCoversFindsMissingFile(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Article{})
+	config.App = &config.Config{UploadDir: t.TempDir()}
+
+	db.Create(&models.Article{AuthorID: 1, Title: "ok", Slug: "ok"})
+	broken := models.Article{AuthorID: 1, Title: "broken", Slug: "broken", CoverImage: "/api/files/missing.png"}
+	db.Create(&broken)
+
+	report, err := GetArticlesWithBrokenCovers(db)
+	if err != nil {
+		t.Fatalf("GetArticlesWithBrokenCovers: %v", err)
+	}
+	if len(report) != 1 || report[0].ID != broken.ID {
+		t.Fatalf("expected exactly the article with the missing cover file, got %+v", report)
+	}
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"errors"
+	"time"
+)
+
+// maxDateRangeDays bounds the from/to window accepted by GetPopularArticles,
+// GetStats, and GetSearchStats so a caller can't force a table-wide scan via
+// a huge range.
+const maxDateRangeDays = 366
+
+var (
+	ErrDateRangeInvalid  = errors.New("to must not be before from")
+	ErrDateRangeTooLarge = errors.New("date range must not exceed 366 days")
+)
+
+// DateRange is an inclusive [From, To] window shared by the admin
+// comparison-period endpoints.
+type DateRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// NewDateRange validates from/to the same way GetEditorialCalendar does
+// (to must not precede from, and the span is capped) and returns the
+// resulting DateRange.
+func NewDateRange(from, to time.Time) (DateRange, error) {
+	if to.Before(from) {
+		return DateRange{}, ErrDateRangeInvalid
+	}
+	if to.Sub(from) > maxDateRangeDays*24*time.Hour {
+		return DateRange{}, ErrDateRangeTooLarge
+	}
+	return DateRange{From: from, To: to}, nil
+}
+
+// Previous returns the window of equal length immediately preceding r,
+// with no gap and no overlap, for compare=previous callers.
+func (r DateRange) Previous() DateRange {
+	span := r.To.Sub(r.From)
+	return DateRange{From: r.From.Add(-span - time.Nanosecond), To: r.From.Add(-time.Nanosecond)}
+}
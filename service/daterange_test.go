@@ -0,0 +1,44 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewDateRangeRejectsInvertedRange(t *testing.T) {
+	from := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, -1)
+
+	if _, err := NewDateRange(from, to); err != ErrDateRangeInvalid {
+		t.Fatalf("expected ErrDateRangeInvalid, got %v", err)
+	}
+}
+
+func TestNewDateRangeRejectsOversizedRange(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 367)
+
+	if _, err := NewDateRange(from, to); err != ErrDateRangeTooLarge {
+		t.Fatalf("expected ErrDateRangeTooLarge, got %v", err)
+	}
+}
+
+func TestDateRangePreviousIsEqualLengthWithNoGapOrOverlap(t *testing.T) {
+	from := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 6, 30, 23, 59, 59, 999999999, time.UTC)
+	r, err := NewDateRange(from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	previous := r.Previous()
+	if got, want := r.To.Sub(r.From), previous.To.Sub(previous.From); got != want {
+		t.Errorf("expected equal-length windows, got %v and %v", want, got)
+	}
+	if !previous.To.Before(r.From) {
+		t.Errorf("expected previous window to end before the current one starts, got previous.To=%v, From=%v", previous.To, r.From)
+	}
+	if gap := r.From.Sub(previous.To); gap != time.Nanosecond {
+		t.Errorf("expected no gap between the windows, got a %v gap", gap)
+	}
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/1055373165/blog/models"
+)
+
+func TestFindPossibleDuplicatesMatchesSimilarTitle(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Article{})
+
+	existing := models.Article{
+		Title:   "Understanding Go Channels",
+		Slug:    "understanding-go-channels",
+		Content: "Channels are the primary means of communication between goroutines.",
+		Status:  models.ArticleStatusPublished,
+	}
+	if err := db.Create(&existing).Error; err != nil {
+		t.Fatal(err)
+	}
+	draft := models.Article{
+		Title:   "Understanding Go Channel",
+		Content: "unrelated content",
+		Status:  models.ArticleStatusDraft,
+	}
+	if err := db.Create(&draft).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	candidates, err := FindPossibleDuplicates(db, "Understanding Go Channel", "unrelated content", DefaultDuplicateThreshold)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candidates) != 1 || candidates[0].ID != existing.ID {
+		t.Fatalf("expected one duplicate matching article %d, got %+v", existing.ID, candidates)
+	}
+	if candidates[0].Score < DefaultDuplicateThreshold {
+		t.Fatalf("expected score >= %v, got %v", DefaultDuplicateThreshold, candidates[0].Score)
+	}
+}
+
+func TestFindPossibleDuplicatesIgnoresUnrelatedTitles(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Article{})
+
+	existing := models.Article{Title: "Understanding Go Channels", Slug: "a", Content: "channels", Status: models.ArticleStatusPublished}
+	if err := db.Create(&existing).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	candidates, err := FindPossibleDuplicates(db, "A Guide to French Cooking", "baguettes and butter", DefaultDuplicateThreshold)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candidates) != 0 {
+		t.Fatalf("expected no duplicates, got %+v", candidates)
+	}
+}
+
+func TestScanDuplicatePairsFindsNearIdenticalArticles(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Article{})
+
+	a := models.Article{Title: "Getting Started with Kubernetes", Slug: "a", Content: "pods and services", Status: models.ArticleStatusPublished}
+	b := models.Article{Title: "Getting Started with Kubernetes!", Slug: "b", Content: "pods and services", Status: models.ArticleStatusPublished}
+	c := models.Article{Title: "French Cooking Basics", Slug: "c", Content: "baguettes", Status: models.ArticleStatusPublished}
+	for _, art := range []*models.Article{&a, &b, &c} {
+		if err := db.Create(art).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pairs, err := ScanDuplicatePairs(db, DefaultDuplicateThreshold)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pairs) != 1 {
+		t.Fatalf("expected exactly one suspect pair, got %+v", pairs)
+	}
+	if pairs[0].ArticleA.ID != a.ID || pairs[0].ArticleB.ID != b.ID {
+		t.Fatalf("expected pair (%d, %d), got (%d, %d)", a.ID, b.ID, pairs[0].ArticleA.ID, pairs[0].ArticleB.ID)
+	}
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/1055373165/blog/models"
+)
+
+func TestGetEditorialCalendarBucketsByDate(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Submission{}, &models.Article{})
+
+	from := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 6, 30, 23, 59, 59, 0, time.UTC)
+
+	submitted := from.AddDate(0, 0, 5)
+	submission := models.Submission{AuthorID: 1, Title: "draft one", Status: models.SubmissionStatusPending, SubmittedAt: submitted}
+	if err := db.Create(&submission).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	published := from.AddDate(0, 0, 10)
+	article := models.Article{AuthorID: 1, Title: "post one", Slug: "post-one", Status: models.ArticleStatusPublished, CreatedAt: published}
+	if err := db.Create(&article).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	outOfRange := models.Article{AuthorID: 1, Title: "post two", Slug: "post-two", Status: models.ArticleStatusPublished, CreatedAt: from.AddDate(0, -1, 0)}
+	if err := db.Create(&outOfRange).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	calendar, err := GetEditorialCalendar(db, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	submittedKey := submitted.Format("2006-01-02")
+	if entries := calendar[submittedKey]; len(entries) != 1 || entries[0].Type != "submission_submitted" {
+		t.Fatalf("expected one submission_submitted entry on %s, got %+v", submittedKey, entries)
+	}
+	publishedKey := published.Format("2006-01-02")
+	if entries := calendar[publishedKey]; len(entries) != 1 || entries[0].Type != "article_published" {
+		t.Fatalf("expected one article_published entry on %s, got %+v", publishedKey, entries)
+	}
+
+	total := 0
+	for _, entries := range calendar {
+		total += len(entries)
+	}
+	if total != 2 {
+		t.Fatalf("expected 2 total entries (out-of-range article excluded), got %d", total)
+	}
+}
+
+func TestGetEditorialCalendarRejectsOversizedRange(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Submission{}, &models.Article{})
+
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 93)
+
+	if _, err := GetEditorialCalendar(db, from, to); err != ErrEditorialCalendarRangeTooLarge {
+		t.Fatalf("expected ErrEditorialCalendarRangeTooLarge, got %v", err)
+	}
+}
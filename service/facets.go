@@ -0,0 +1,67 @@
+package service
+
+import (
+	"github.com/1055373165/blog/models"
+	"gorm.io/gorm"
+)
+
+// TagFacet pairs a Tag with how many published articles use it.
+type TagFacet struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+// GetTagFacets returns the top limit tags ranked by published-article usage.
+func GetTagFacets(db *gorm.DB, limit int) ([]TagFacet, error) {
+	var facets []TagFacet
+	err := db.Table("tags").
+		Select("tags.id, tags.name, COUNT(articles.id) as count").
+		Joins("LEFT JOIN article_tags ON article_tags.tag_id = tags.id").
+		Joins("LEFT JOIN articles ON articles.id = article_tags.article_id AND articles.status = ?", models.ArticleStatusPublished).
+		Group("tags.id").
+		Order("count desc").
+		Limit(limit).
+		Scan(&facets).Error
+	return facets, err
+}
+
+// SeriesFacet pairs a Series with how many published articles it contains.
+type SeriesFacet struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+// GetSeriesFacets returns the top limit series ranked by published-article count.
+func GetSeriesFacets(db *gorm.DB, limit int) ([]SeriesFacet, error) {
+	var facets []SeriesFacet
+	err := db.Table("series").
+		Select("series.id, series.title as name, COUNT(articles.id) as count").
+		Joins("LEFT JOIN articles ON articles.series_id = series.id AND articles.status = ?", models.ArticleStatusPublished).
+		Group("series.id").
+		Order("count desc").
+		Limit(limit).
+		Scan(&facets).Error
+	return facets, err
+}
+
+// YearFacet is a published-article count for a single calendar year.
+type YearFacet struct {
+	Year  int   `json:"year"`
+	Count int64 `json:"count"`
+}
+
+// GetYearFacets returns the top limit years ranked by published-article
+// count, bucketed by Article.CreatedAt.
+func GetYearFacets(db *gorm.DB, limit int) ([]YearFacet, error) {
+	var facets []YearFacet
+	err := db.Model(&models.Article{}).
+		Select("CAST(strftime('%Y', created_at) AS INTEGER) as year, COUNT(*) as count").
+		Where("status = ?", models.ArticleStatusPublished).
+		Group("year").
+		Order("count desc").
+		Limit(limit).
+		Scan(&facets).Error
+	return facets, err
+}
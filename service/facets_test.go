@@ -0,0 +1,48 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/1055373165/blog/models"
+)
+
+func TestGetTagFacetsCountsPublishedArticlesOnly(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Article{}, &models.Tag{}, &models.Series{})
+
+	tag := models.Tag{Name: "Go", Slug: "go"}
+	db.Create(&tag)
+
+	published := models.Article{AuthorID: 1, Title: "A", Slug: "a", Status: models.ArticleStatusPublished, Tags: []models.Tag{tag}}
+	draft := models.Article{AuthorID: 1, Title: "B", Slug: "b", Status: models.ArticleStatusDraft, Tags: []models.Tag{tag}}
+	db.Create(&published)
+	db.Create(&draft)
+
+	facets, err := GetTagFacets(db, 15)
+	if err != nil {
+		t.Fatalf("GetTagFacets returned error: %v", err)
+	}
+	if len(facets) != 1 || facets[0].Count != 1 {
+		t.Errorf("expected tag %q with count 1, got %+v", tag.Name, facets)
+	}
+}
+
+func TestGetYearFacetsGroupsByCreatedAtYear(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Article{})
+
+	db.Create(&models.Article{AuthorID: 1, Title: "A", Slug: "a", Status: models.ArticleStatusPublished})
+	db.Create(&models.Article{AuthorID: 1, Title: "B", Slug: "b", Status: models.ArticleStatusPublished})
+
+	facets, err := GetYearFacets(db, 15)
+	if err != nil {
+		t.Fatalf("GetYearFacets returned error: %v", err)
+	}
+	var total int64
+	for _, f := range facets {
+		total += f.Count
+	}
+	if total != 2 {
+		t.Errorf("expected 2 published articles across year facets, got %d", total)
+	}
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/1055373165/blog/models"
+)
+
+func TestToggleFavorite(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Article{}, &models.Favorite{})
+
+	article := models.Article{AuthorID: 1, Title: "A", Slug: "a", Status: models.ArticleStatusPublished}
+	db.Create(&article)
+
+	favorited, err := ToggleFavorite(db, 42, article.ID)
+	if err != nil {
+		t.Fatalf("ToggleFavorite returned error: %v", err)
+	}
+	if !favorited {
+		t.Fatal("expected favorited=true after first toggle")
+	}
+
+	var reloaded models.Article
+	db.First(&reloaded, article.ID)
+	if reloaded.FavoritesCount != 1 {
+		t.Errorf("expected favorites_count 1, got %d", reloaded.FavoritesCount)
+	}
+
+	favorited, err = ToggleFavorite(db, 42, article.ID)
+	if err != nil {
+		t.Fatalf("ToggleFavorite returned error: %v", err)
+	}
+	if favorited {
+		t.Fatal("expected favorited=false after second toggle")
+	}
+
+	db.First(&reloaded, article.ID)
+	if reloaded.FavoritesCount != 0 {
+		t.Errorf("expected favorites_count 0 after unfavoriting, got %d", reloaded.FavoritesCount)
+	}
+}
+
+func TestToggleFavoriteOnUnpublishedArticleNotFound(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Article{}, &models.Favorite{})
+
+	draft := models.Article{AuthorID: 1, Title: "Draft", Slug: "draft", Status: models.ArticleStatusDraft}
+	db.Create(&draft)
+
+	if _, err := ToggleFavorite(db, 1, draft.ID); err != ErrArticleNotFound {
+		t.Errorf("expected ErrArticleNotFound, got %v", err)
+	}
+}
+
+func TestGetUserFavoritesOrdersMostRecentFirst(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Article{}, &models.Favorite{})
+
+	a1 := models.Article{AuthorID: 1, Title: "A1", Slug: "a1", Status: models.ArticleStatusPublished}
+	a2 := models.Article{AuthorID: 1, Title: "A2", Slug: "a2", Status: models.ArticleStatusPublished}
+	db.Create(&a1)
+	db.Create(&a2)
+
+	if _, err := ToggleFavorite(db, 1, a1.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ToggleFavorite(db, 1, a2.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	favorites, err := GetUserFavorites(db, 1, 1, 10)
+	if err != nil {
+		t.Fatalf("GetUserFavorites returned error: %v", err)
+	}
+	if len(favorites) != 2 {
+		t.Fatalf("expected 2 favorites, got %d", len(favorites))
+	}
+	if favorites[0].ID != a2.ID {
+		t.Errorf("expected most recently favorited article first, got %+v", favorites[0])
+	}
+}
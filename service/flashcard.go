@@ -0,0 +1,151 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/1055373165/blog/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrStudyItemNotFound = errors.New("study item not found")
+	ErrFlashcardNotFound = errors.New("flashcard not found")
+)
+
+// minEase is the floor service.ReviewFlashcard clamps Ease to, matching the
+// standard SM-2 minimum so a string of poor ratings can't push a card's
+// interval growth to zero or negative.
+const minEase = 1.3
+
+func findOwnedStudyItem(db *gorm.DB, itemID, userID uint) (*models.StudyItem, error) {
+	var item models.StudyItem
+	err := db.Joins("JOIN study_plans ON study_plans.id = study_items.plan_id").
+		Where("study_items.id = ? AND study_plans.user_id = ?", itemID, userID).
+		First(&item).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrStudyItemNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func findOwnedFlashcard(db *gorm.DB, cardID, userID uint) (*models.Flashcard, error) {
+	var card models.Flashcard
+	err := db.Joins("JOIN study_items ON study_items.id = flashcards.study_item_id").
+		Joins("JOIN study_plans ON study_plans.id = study_items.plan_id").
+		Where("flashcards.id = ? AND study_plans.user_id = ?", cardID, userID).
+		First(&card).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrFlashcardNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &card, nil
+}
+
+// CreateFlashcard saves a new card under itemID, which must belong to a
+// plan owned by userID.
+func CreateFlashcard(db *gorm.DB, itemID, userID uint, card *models.Flashcard) error {
+	if _, err := findOwnedStudyItem(db, itemID, userID); err != nil {
+		return err
+	}
+	card.StudyItemID = itemID
+	return db.Create(card).Error
+}
+
+// GetItemFlashcards returns every card under itemID, which must belong to a
+// plan owned by userID.
+func GetItemFlashcards(db *gorm.DB, itemID, userID uint) ([]models.Flashcard, error) {
+	if _, err := findOwnedStudyItem(db, itemID, userID); err != nil {
+		return nil, err
+	}
+	var cards []models.Flashcard
+	err := db.Where("study_item_id = ?", itemID).Order("id asc").Find(&cards).Error
+	return cards, err
+}
+
+// UpdateFlashcard applies updates to the card owned by (cardID, userID).
+func UpdateFlashcard(db *gorm.DB, cardID, userID uint, updates map[string]any) (*models.Flashcard, error) {
+	card, err := findOwnedFlashcard(db, cardID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(updates) > 0 {
+		if err := db.Model(card).Updates(updates).Error; err != nil {
+			return nil, err
+		}
+	}
+	return card, nil
+}
+
+// DeleteFlashcard deletes the card owned by (cardID, userID).
+func DeleteFlashcard(db *gorm.DB, cardID, userID uint) error {
+	card, err := findOwnedFlashcard(db, cardID, userID)
+	if err != nil {
+		return err
+	}
+	return db.Delete(card).Error
+}
+
+// GetDueFlashcards returns every flashcard belonging to userID, across all
+// of their study plans, whose next_review_at is unset or has passed.
+func GetDueFlashcards(db *gorm.DB, userID uint, now time.Time) ([]models.Flashcard, error) {
+	var cards []models.Flashcard
+	err := db.Joins("JOIN study_items ON study_items.id = flashcards.study_item_id").
+		Joins("JOIN study_plans ON study_plans.id = study_items.plan_id").
+		Where("study_plans.user_id = ? AND (flashcards.next_review_at IS NULL OR flashcards.next_review_at <= ?)", userID, now).
+		Order("flashcards.next_review_at asc").
+		Find(&cards).Error
+	return cards, err
+}
+
+// ReviewFlashcard records a review of the card owned by (cardID, userID)
+// and advances its interval/ease/next_review_at using the SM-2 algorithm,
+// keyed on rating (0-5, where 3+ counts as a pass). This is the only
+// spaced-repetition algorithm in the codebase; StudyItem-level scheduling
+// (see RunMasteryDecay) uses a simpler fixed-interval decay instead, so
+// there's nothing existing for this to share an implementation with.
+func ReviewFlashcard(db *gorm.DB, cardID, userID uint, rating int, now time.Time) (*models.Flashcard, error) {
+	card, err := findOwnedFlashcard(db, cardID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var nextInterval int
+	ease := card.Ease
+	if rating < 3 {
+		nextInterval = 1
+		ease -= 0.2
+	} else {
+		switch card.Interval {
+		case 1:
+			nextInterval = 6
+		default:
+			nextInterval = int(float64(card.Interval) * ease)
+		}
+		ease += 0.1 - float64(5-rating)*(0.08+float64(5-rating)*0.02)
+	}
+	if ease < minEase {
+		ease = minEase
+	}
+	if nextInterval < 1 {
+		nextInterval = 1
+	}
+	nextReview := now.AddDate(0, 0, nextInterval)
+
+	if err := db.Model(card).Updates(map[string]any{
+		"interval":       nextInterval,
+		"ease":           ease,
+		"next_review_at": nextReview,
+	}).Error; err != nil {
+		return nil, err
+	}
+	card.Interval = nextInterval
+	card.Ease = ease
+	card.NextReviewAt = &nextReview
+	return card, nil
+}
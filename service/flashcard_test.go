@@ -0,0 +1,151 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/1055373165/blog/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupFlashcardTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	conn, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	if err := conn.AutoMigrate(&models.StudyPlan{}, &models.StudyItem{}, &models.Flashcard{}); err != nil {
+		t.Fatalf("failed to migrate test db: %v", err)
+	}
+	return conn
+}
+
+func TestCreateAndListFlashcardsScopedToOwner(t *testing.T) {
+	db := setupFlashcardTestDB(t)
+	plan := models.StudyPlan{UserID: 1, Name: "Go"}
+	db.Create(&plan)
+	item := models.StudyItem{PlanID: plan.ID, Status: models.StudyItemStatusNew}
+	db.Create(&item)
+
+	card := models.Flashcard{Front: "What is a goroutine?", Back: "A lightweight thread managed by the Go runtime."}
+	if err := CreateFlashcard(db, item.ID, 1, &card); err != nil {
+		t.Fatalf("CreateFlashcard failed: %v", err)
+	}
+	if card.Interval != 1 || card.Ease != 2.5 {
+		t.Errorf("expected default interval/ease, got %d/%v", card.Interval, card.Ease)
+	}
+
+	if err := CreateFlashcard(db, item.ID, 2, &models.Flashcard{Front: "x", Back: "y"}); err != ErrStudyItemNotFound {
+		t.Errorf("expected ErrStudyItemNotFound for a non-owner, got %v", err)
+	}
+
+	cards, err := GetItemFlashcards(db, item.ID, 1)
+	if err != nil {
+		t.Fatalf("GetItemFlashcards failed: %v", err)
+	}
+	if len(cards) != 1 || cards[0].Front != "What is a goroutine?" {
+		t.Fatalf("expected 1 card, got %+v", cards)
+	}
+
+	if _, err := GetItemFlashcards(db, item.ID, 2); err != ErrStudyItemNotFound {
+		t.Errorf("expected ErrStudyItemNotFound for a non-owner, got %v", err)
+	}
+}
+
+func TestUpdateAndDeleteFlashcardScopedToOwner(t *testing.T) {
+	db := setupFlashcardTestDB(t)
+	plan := models.StudyPlan{UserID: 1, Name: "Go"}
+	db.Create(&plan)
+	item := models.StudyItem{PlanID: plan.ID, Status: models.StudyItemStatusNew}
+	db.Create(&item)
+	card := models.Flashcard{Front: "a", Back: "b"}
+	if err := CreateFlashcard(db, item.ID, 1, &card); err != nil {
+		t.Fatalf("CreateFlashcard failed: %v", err)
+	}
+
+	if _, err := UpdateFlashcard(db, card.ID, 2, map[string]any{"front": "hijacked"}); err != ErrFlashcardNotFound {
+		t.Errorf("expected ErrFlashcardNotFound for a non-owner update, got %v", err)
+	}
+
+	updated, err := UpdateFlashcard(db, card.ID, 1, map[string]any{"front": "revised"})
+	if err != nil {
+		t.Fatalf("UpdateFlashcard failed: %v", err)
+	}
+	if updated.Front != "revised" {
+		t.Errorf("expected front to be updated, got %q", updated.Front)
+	}
+
+	if err := DeleteFlashcard(db, card.ID, 2); err != ErrFlashcardNotFound {
+		t.Errorf("expected ErrFlashcardNotFound for a non-owner delete, got %v", err)
+	}
+	if err := DeleteFlashcard(db, card.ID, 1); err != nil {
+		t.Fatalf("DeleteFlashcard failed: %v", err)
+	}
+}
+
+func TestReviewFlashcardAdvancesOnPassResetsOnFail(t *testing.T) {
+	db := setupFlashcardTestDB(t)
+	plan := models.StudyPlan{UserID: 1, Name: "Go"}
+	db.Create(&plan)
+	item := models.StudyItem{PlanID: plan.ID, Status: models.StudyItemStatusNew}
+	db.Create(&item)
+	card := models.Flashcard{Front: "a", Back: "b"}
+	if err := CreateFlashcard(db, item.ID, 1, &card); err != nil {
+		t.Fatalf("CreateFlashcard failed: %v", err)
+	}
+	now := time.Now()
+
+	reviewed, err := ReviewFlashcard(db, card.ID, 1, 4, now)
+	if err != nil {
+		t.Fatalf("ReviewFlashcard failed: %v", err)
+	}
+	if reviewed.Interval != 6 {
+		t.Errorf("expected first pass to jump interval to 6, got %d", reviewed.Interval)
+	}
+	if reviewed.NextReviewAt == nil || !reviewed.NextReviewAt.After(now) {
+		t.Errorf("expected next_review_at to be set in the future, got %+v", reviewed.NextReviewAt)
+	}
+
+	failed, err := ReviewFlashcard(db, card.ID, 1, 1, now)
+	if err != nil {
+		t.Fatalf("ReviewFlashcard failed: %v", err)
+	}
+	if failed.Interval != 1 {
+		t.Errorf("expected a failing rating to reset interval to 1, got %d", failed.Interval)
+	}
+	if failed.Ease >= 2.5 {
+		t.Errorf("expected a failing rating to lower ease below the default, got %v", failed.Ease)
+	}
+}
+
+func TestGetDueFlashcardsScopedToOwnerAcrossPlans(t *testing.T) {
+	db := setupFlashcardTestDB(t)
+	plan := models.StudyPlan{UserID: 1, Name: "Go"}
+	db.Create(&plan)
+	item := models.StudyItem{PlanID: plan.ID, Status: models.StudyItemStatusNew}
+	db.Create(&item)
+
+	due := models.Flashcard{StudyItemID: item.ID, Front: "due", Back: "b"}
+	db.Create(&due)
+
+	future := time.Now().Add(48 * time.Hour)
+	notDue := models.Flashcard{StudyItemID: item.ID, Front: "not due", Back: "b", NextReviewAt: &future}
+	db.Create(&notDue)
+
+	cards, err := GetDueFlashcards(db, 1, time.Now())
+	if err != nil {
+		t.Fatalf("GetDueFlashcards failed: %v", err)
+	}
+	if len(cards) != 1 || cards[0].Front != "due" {
+		t.Fatalf("expected only the due card, got %+v", cards)
+	}
+
+	cards, err = GetDueFlashcards(db, 2, time.Now())
+	if err != nil {
+		t.Fatalf("GetDueFlashcards failed: %v", err)
+	}
+	if len(cards) != 0 {
+		t.Errorf("expected no due cards for a different user, got %+v", cards)
+	}
+}
@@ -0,0 +1,276 @@
+package service
+
+import (
+	"errors"
+	"log"
+
+	"github.com/1055373165/blog/models"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidFollowEntity is returned by ToggleFollow when entityType
+// isn't one of the known models.FollowEntity* values, or entityID
+// doesn't reference an existing row of that type.
+var ErrInvalidFollowEntity = errors.New("entity type or id does not reference anything that can be followed")
+
+// validateFollowEntity confirms entityID references an existing row of
+// entityType, so a Follow can't be created pointing at nothing.
+func validateFollowEntity(db *gorm.DB, entityType models.FollowEntityType, entityID uint) error {
+	var model any
+	switch entityType {
+	case models.FollowEntityTag:
+		model = &models.Tag{}
+	case models.FollowEntityCategory:
+		model = &models.Category{}
+	case models.FollowEntitySeries:
+		model = &models.Series{}
+	case models.FollowEntityAuthor:
+		model = &models.User{}
+	default:
+		return ErrInvalidFollowEntity
+	}
+	if err := db.First(model, entityID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidFollowEntity
+		}
+		return err
+	}
+	return nil
+}
+
+// ToggleFollow flips userID's follow of (entityType, entityID): creates
+// it if absent, removes it if present. The check-then-write isn't
+// atomic, so a concurrent duplicate insert can still race past it; the
+// unique index on models.Follow catches that case, and the losing call
+// is retried as a delete, mirroring ToggleArticleReaction.
+func ToggleFollow(db *gorm.DB, userID uint, entityType models.FollowEntityType, entityID uint) (followed bool, err error) {
+	if err := validateFollowEntity(db, entityType, entityID); err != nil {
+		return false, err
+	}
+
+	var existing models.Follow
+	err = db.Where("user_id = ? AND entity_type = ? AND entity_id = ?", userID, entityType, entityID).
+		First(&existing).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if err := db.Create(&models.Follow{UserID: userID, EntityType: entityType, EntityID: entityID}).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return db.Where("user_id = ? AND entity_type = ? AND entity_id = ?", userID, entityType, entityID).
+					Delete(&models.Follow{}).Error == nil, nil
+			}
+			return false, err
+		}
+		return true, nil
+	case err != nil:
+		return false, err
+	default:
+		if err := db.Delete(&existing).Error; err != nil {
+			return false, err
+		}
+		return false, nil
+	}
+}
+
+// GetUserFollows returns every entity userID follows, newest first.
+func GetUserFollows(db *gorm.DB, userID uint) ([]models.Follow, error) {
+	var follows []models.Follow
+	err := db.Where("user_id = ?", userID).Order("created_at desc").Find(&follows).Error
+	return follows, err
+}
+
+// GetFollowerCount returns how many users follow (entityType, entityID).
+func GetFollowerCount(db *gorm.DB, entityType models.FollowEntityType, entityID uint) (int64, error) {
+	var count int64
+	err := db.Model(&models.Follow{}).Where("entity_type = ? AND entity_id = ?", entityType, entityID).Count(&count).Error
+	return count, err
+}
+
+// GetFollowerCounts returns GetFollowerCount for every id in entityIDs
+// of entityType, keyed by entity ID, in one query - for list endpoints
+// (GetTags, GetCategories, GetSeriesList) that need every row's count
+// rather than one at a time.
+func GetFollowerCounts(db *gorm.DB, entityType models.FollowEntityType, entityIDs []uint) (map[uint]int64, error) {
+	counts := make(map[uint]int64, len(entityIDs))
+	if len(entityIDs) == 0 {
+		return counts, nil
+	}
+	var rows []struct {
+		EntityID uint
+		Count    int64
+	}
+	err := db.Model(&models.Follow{}).
+		Select("entity_id, count(*) as count").
+		Where("entity_type = ? AND entity_id IN ?", entityType, entityIDs).
+		Group("entity_id").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		counts[r.EntityID] = r.Count
+	}
+	return counts, nil
+}
+
+// GetFeedArticles returns published articles matching any entity userID
+// follows (tag, category - approximated by CategoryPin, since Article
+// has no direct category relation, see the CategoryPin doc comment -
+// series, or author), deduplicated, newest-published first, paginated.
+// It's computed as a bounded set of queries: one id-set query per
+// followed entity type actually in use, unioned in Go, then a single
+// hydrate query - never one query per followed entity.
+func GetFeedArticles(db *gorm.DB, userID uint, page, pageSize int) ([]models.Article, error) {
+	follows, err := GetUserFollows(db, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(follows) == 0 {
+		return nil, nil
+	}
+
+	var tagIDs, categoryIDs, seriesIDs, authorIDs []uint
+	for _, f := range follows {
+		switch f.EntityType {
+		case models.FollowEntityTag:
+			tagIDs = append(tagIDs, f.EntityID)
+		case models.FollowEntityCategory:
+			categoryIDs = append(categoryIDs, f.EntityID)
+		case models.FollowEntitySeries:
+			seriesIDs = append(seriesIDs, f.EntityID)
+		case models.FollowEntityAuthor:
+			authorIDs = append(authorIDs, f.EntityID)
+		}
+	}
+
+	articleIDs := map[uint]bool{}
+	if err := collectIDs(db.Table("article_tags").
+		Select("article_id").Where("tag_id IN ?", tagIDs), tagIDs, articleIDs); err != nil {
+		return nil, err
+	}
+	if err := collectIDs(db.Table("category_pins").
+		Select("article_id").Where("category_id IN ?", categoryIDs), categoryIDs, articleIDs); err != nil {
+		return nil, err
+	}
+	if err := collectIDs(db.Model(&models.Article{}).
+		Select("id").Where("series_id IN ?", seriesIDs), seriesIDs, articleIDs); err != nil {
+		return nil, err
+	}
+	if err := collectIDs(db.Model(&models.Article{}).
+		Select("id").Where("author_id IN ?", authorIDs), authorIDs, articleIDs); err != nil {
+		return nil, err
+	}
+	if len(articleIDs) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uint, 0, len(articleIDs))
+	for id := range articleIDs {
+		ids = append(ids, id)
+	}
+
+	var articles []models.Article
+	err = db.Where("id IN ? AND status = ?", ids, models.ArticleStatusPublished).
+		Order("published_at desc").
+		Offset((page - 1) * pageSize).Limit(pageSize).
+		Find(&articles).Error
+	return articles, err
+}
+
+// collectIDs runs query (only if filterIDs is non-empty) and adds every
+// resulting id into into.
+func collectIDs(query *gorm.DB, filterIDs []uint, into map[uint]bool) error {
+	if len(filterIDs) == 0 {
+		return nil
+	}
+	var ids []uint
+	if err := query.Find(&ids).Error; err != nil {
+		return err
+	}
+	for _, id := range ids {
+		into[id] = true
+	}
+	return nil
+}
+
+// TriggerArticleFollowerNotifications runs NotifyArticleFollowers in the
+// background, mirroring TriggerBlogWaveformGeneration: PublishSubmission
+// shouldn't block its caller while a popular tag's follower list is
+// notified. Errors are logged, not returned, since there's no caller
+// left to receive them by the time it finishes.
+func TriggerArticleFollowerNotifications(db *gorm.DB, articleID uint) {
+	go func() {
+		if err := NotifyArticleFollowers(db, articleID); err != nil {
+			log.Printf("follower notification for article %d failed: %v", articleID, err)
+		}
+	}()
+}
+
+// NotifyArticleFollowers notifies, in one batched insert, every user who
+// follows articleID's author, series (if any), category (via
+// CategoryPin, if pinned), or tags - so publishing to a followed tag
+// with thousands of followers creates one INSERT, not thousands of
+// round trips. A follower who matches the article more than one way
+// (e.g. follows both its author and a tag on it) is notified once.
+func NotifyArticleFollowers(db *gorm.DB, articleID uint) error {
+	var article models.Article
+	if err := db.Preload("Tags").First(&article, articleID).Error; err != nil {
+		return err
+	}
+
+	recipients := map[uint]bool{}
+	if err := collectFollowerIDs(db, models.FollowEntityAuthor, []uint{article.AuthorID}, recipients); err != nil {
+		return err
+	}
+	if article.SeriesID != nil {
+		if err := collectFollowerIDs(db, models.FollowEntitySeries, []uint{*article.SeriesID}, recipients); err != nil {
+			return err
+		}
+	}
+	tagIDs := make([]uint, len(article.Tags))
+	for i, t := range article.Tags {
+		tagIDs[i] = t.ID
+	}
+	if err := collectFollowerIDs(db, models.FollowEntityTag, tagIDs, recipients); err != nil {
+		return err
+	}
+	var categoryIDs []uint
+	if err := db.Table("category_pins").Select("category_id").Where("article_id = ?", articleID).Find(&categoryIDs).Error; err != nil {
+		return err
+	}
+	if err := collectFollowerIDs(db, models.FollowEntityCategory, categoryIDs, recipients); err != nil {
+		return err
+	}
+	delete(recipients, article.AuthorID)
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	notifications := make([]models.Notification, 0, len(recipients))
+	for userID := range recipients {
+		notifications = append(notifications, models.Notification{
+			RecipientID: userID,
+			Kind:        "followed_article_published",
+			Message:     "A new article was published: " + article.Title,
+		})
+	}
+	return db.Create(&notifications).Error
+}
+
+// collectFollowerIDs adds every follower of entityType/entityIDs into
+// into.
+func collectFollowerIDs(db *gorm.DB, entityType models.FollowEntityType, entityIDs []uint, into map[uint]bool) error {
+	if len(entityIDs) == 0 {
+		return nil
+	}
+	var userIDs []uint
+	if err := db.Model(&models.Follow{}).
+		Select("user_id").
+		Where("entity_type = ? AND entity_id IN ?", entityType, entityIDs).
+		Find(&userIDs).Error; err != nil {
+		return err
+	}
+	for _, id := range userIDs {
+		into[id] = true
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/1055373165/blog/models"
+)
+
+func TestToggleFollowCreatesThenRemoves(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Follow{}, &models.Tag{})
+
+	tag := models.Tag{Name: "Go", Slug: "go"}
+	db.Create(&tag)
+
+	followed, err := ToggleFollow(db, 1, models.FollowEntityTag, tag.ID)
+	if err != nil {
+		t.Fatalf("ToggleFollow returned error: %v", err)
+	}
+	if !followed {
+		t.Errorf("expected followed=true on first toggle")
+	}
+
+	followed, err = ToggleFollow(db, 1, models.FollowEntityTag, tag.ID)
+	if err != nil {
+		t.Fatalf("ToggleFollow returned error: %v", err)
+	}
+	if followed {
+		t.Errorf("expected followed=false on second toggle")
+	}
+
+	var count int64
+	db.Model(&models.Follow{}).Count(&count)
+	if count != 0 {
+		t.Errorf("expected no rows left after unfollowing, got %d", count)
+	}
+}
+
+func TestToggleFollowRejectsANonexistentEntity(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Follow{}, &models.Tag{})
+
+	if _, err := ToggleFollow(db, 1, models.FollowEntityTag, 999); err != ErrInvalidFollowEntity {
+		t.Errorf("expected ErrInvalidFollowEntity, got %v", err)
+	}
+}
+
+func TestGetFeedArticlesMatchesFollowedTagSeriesAndAuthor(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Follow{}, &models.Tag{}, &models.Article{}, &models.Series{}, &models.CategoryPin{}, &models.Category{}, &models.User{})
+
+	reader := models.User{Username: "reader", Email: "reader@example.com"}
+	db.Create(&reader)
+	watchedAuthor := models.User{Username: "watched", Email: "watched@example.com"}
+	db.Create(&watchedAuthor)
+
+	tag := models.Tag{Name: "Go", Slug: "go"}
+	db.Create(&tag)
+	series := models.Series{Title: "chapters", Slug: "chapters"}
+	db.Create(&series)
+
+	byTag := models.Article{AuthorID: 5, Title: "by tag", Slug: "by-tag", Status: models.ArticleStatusPublished, Tags: []models.Tag{tag}}
+	db.Create(&byTag)
+	bySeries := models.Article{AuthorID: 5, Title: "by series", Slug: "by-series", Status: models.ArticleStatusPublished, SeriesID: &series.ID}
+	db.Create(&bySeries)
+	byAuthor := models.Article{AuthorID: watchedAuthor.ID, Title: "by author", Slug: "by-author", Status: models.ArticleStatusPublished}
+	db.Create(&byAuthor)
+	unrelated := models.Article{AuthorID: 1, Title: "unrelated", Slug: "unrelated", Status: models.ArticleStatusPublished}
+	db.Create(&unrelated)
+	unpublished := models.Article{AuthorID: watchedAuthor.ID, Title: "draft", Slug: "draft", Status: models.ArticleStatusDraft}
+	db.Create(&unpublished)
+
+	ToggleFollow(db, reader.ID, models.FollowEntityTag, tag.ID)
+	ToggleFollow(db, reader.ID, models.FollowEntitySeries, series.ID)
+	ToggleFollow(db, reader.ID, models.FollowEntityAuthor, watchedAuthor.ID)
+
+	articles, err := GetFeedArticles(db, 1, 1, 20)
+	if err != nil {
+		t.Fatalf("GetFeedArticles returned error: %v", err)
+	}
+	if len(articles) != 3 {
+		t.Fatalf("expected 3 matching articles, got %d: %+v", len(articles), articles)
+	}
+	seen := map[string]bool{}
+	for _, a := range articles {
+		seen[a.Slug] = true
+	}
+	for _, slug := range []string{"by-tag", "by-series", "by-author"} {
+		if !seen[slug] {
+			t.Errorf("expected feed to include %q, got %+v", slug, articles)
+		}
+	}
+	if seen["unrelated"] || seen["draft"] {
+		t.Errorf("expected feed to exclude unrelated/unpublished articles, got %+v", articles)
+	}
+}
+
+func TestNotifyArticleFollowersBatchesOneInsertAndSkipsTheAuthor(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Follow{}, &models.Tag{}, &models.Article{}, &models.Notification{}, &models.CategoryPin{}, &models.Category{})
+
+	tag := models.Tag{Name: "Go", Slug: "go"}
+	db.Create(&tag)
+	article := models.Article{AuthorID: 9, Title: "new post", Slug: "new-post", Status: models.ArticleStatusPublished, Tags: []models.Tag{tag}}
+	db.Create(&article)
+
+	ToggleFollow(db, 1, models.FollowEntityTag, tag.ID)
+	ToggleFollow(db, 2, models.FollowEntityTag, tag.ID)
+	ToggleFollow(db, 9, models.FollowEntityTag, tag.ID) // the author follows their own tag
+
+	if err := NotifyArticleFollowers(db, article.ID); err != nil {
+		t.Fatalf("NotifyArticleFollowers returned error: %v", err)
+	}
+
+	var notifications []models.Notification
+	db.Find(&notifications)
+	if len(notifications) != 2 {
+		t.Fatalf("expected 2 notifications (excluding the author), got %d: %+v", len(notifications), notifications)
+	}
+	for _, n := range notifications {
+		if n.RecipientID == 9 {
+			t.Errorf("expected the author not to be notified of their own article, got %+v", n)
+		}
+	}
+}
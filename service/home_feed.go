@@ -0,0 +1,63 @@
+package service
+
+import (
+	"github.com/1055373165/blog/models"
+	"gorm.io/gorm"
+)
+
+// homeFeedFeaturedLimit caps the featured carousel shown on the home feed.
+const homeFeedFeaturedLimit = 6
+
+// homeFeedLatestPageSize is the number of additional articles shown after
+// the pinned and featured ones.
+const homeFeedLatestPageSize = 10
+
+// HomeFeed is the composed payload returned by GetHomeFeed: pinned
+// articles first, then a featured carousel, then the latest published
+// articles not already shown in either list.
+type HomeFeed struct {
+	Pinned   []models.Article `json:"pinned"`
+	Featured []models.Article `json:"featured"`
+	Latest   []models.Article `json:"latest"`
+}
+
+// GetHomeFeed assembles the home feed in a handful of queries: pinned
+// articles (capped at maxPinnedArticles), the featured carousel, then the
+// latest published articles excluding anything already surfaced above.
+func GetHomeFeed(db *gorm.DB) (HomeFeed, error) {
+	var feed HomeFeed
+
+	if err := db.Where("status = ? AND is_pinned = ?", models.ArticleStatusPublished, true).
+		Order("updated_at desc").
+		Limit(maxPinnedArticles).
+		Find(&feed.Pinned).Error; err != nil {
+		return feed, err
+	}
+
+	if err := db.Where("status = ? AND is_featured = ?", models.ArticleStatusPublished, true).
+		Order("updated_at desc").
+		Limit(homeFeedFeaturedLimit).
+		Find(&feed.Featured).Error; err != nil {
+		return feed, err
+	}
+
+	shown := make([]uint, 0, len(feed.Pinned)+len(feed.Featured))
+	for _, a := range feed.Pinned {
+		shown = append(shown, a.ID)
+	}
+	for _, a := range feed.Featured {
+		shown = append(shown, a.ID)
+	}
+
+	latest := db.Where("status = ?", models.ArticleStatusPublished)
+	if len(shown) > 0 {
+		latest = latest.Where("id NOT IN ?", shown)
+	}
+	if err := latest.Order("created_at desc").
+		Limit(homeFeedLatestPageSize).
+		Find(&feed.Latest).Error; err != nil {
+		return feed, err
+	}
+
+	return feed, nil
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/1055373165/blog/models"
+)
+
+func TestSetArticlePinnedEnforcesLimit(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Article{})
+
+	var ids []uint
+	for i := 0; i < 3; i++ {
+		article := models.Article{Title: "a", Slug: "a" + string(rune('1'+i)), Status: models.ArticleStatusPublished}
+		if err := db.Create(&article).Error; err != nil {
+			t.Fatal(err)
+		}
+		if _, err := SetArticlePinned(db, article.ID, true); err != nil {
+			t.Fatalf("pin %d: %v", i, err)
+		}
+		ids = append(ids, article.ID)
+	}
+
+	extra := models.Article{Title: "extra", Slug: "extra", Status: models.ArticleStatusPublished}
+	if err := db.Create(&extra).Error; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SetArticlePinned(db, extra.ID, true); err != ErrPinLimitReached {
+		t.Fatalf("expected ErrPinLimitReached, got %v", err)
+	}
+
+	if _, err := SetArticlePinned(db, ids[0], false); err != nil {
+		t.Fatalf("unpin: %v", err)
+	}
+	if _, err := SetArticlePinned(db, extra.ID, true); err != nil {
+		t.Fatalf("pin after freeing a slot: %v", err)
+	}
+}
+
+func TestGetHomeFeedOrdersPinnedFeaturedThenLatestWithoutOverlap(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Article{})
+
+	pinned := models.Article{Title: "pinned", Slug: "pinned", Status: models.ArticleStatusPublished, IsPinned: true}
+	featured := models.Article{Title: "featured", Slug: "featured", Status: models.ArticleStatusPublished, IsFeatured: true}
+	latest := models.Article{Title: "latest", Slug: "latest", Status: models.ArticleStatusPublished}
+	draft := models.Article{Title: "draft", Slug: "draft", Status: models.ArticleStatusDraft}
+	for _, a := range []*models.Article{&pinned, &featured, &latest, &draft} {
+		if err := db.Create(a).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	feed, err := GetHomeFeed(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(feed.Pinned) != 1 || feed.Pinned[0].ID != pinned.ID {
+		t.Fatalf("expected pinned=[%d], got %+v", pinned.ID, feed.Pinned)
+	}
+	if len(feed.Featured) != 1 || feed.Featured[0].ID != featured.ID {
+		t.Fatalf("expected featured=[%d], got %+v", featured.ID, feed.Featured)
+	}
+	if len(feed.Latest) != 1 || feed.Latest[0].ID != latest.ID {
+		t.Fatalf("expected latest=[%d] (excluding pinned/featured/draft), got %+v", latest.ID, feed.Latest)
+	}
+}
@@ -0,0 +1,373 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/1055373165/blog/models"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+)
+
+// ImportFileStatus is the outcome of importing a single Markdown file.
+type ImportFileStatus string
+
+const (
+	ImportFileCreated         ImportFileStatus = "created"
+	ImportFileSkippedDupeSlug ImportFileStatus = "skipped_duplicate_slug"
+	ImportFileError           ImportFileStatus = "error"
+)
+
+// ImportFileResult reports what happened to one .md file in the zip.
+type ImportFileResult struct {
+	Filename  string           `json:"filename"`
+	Status    ImportFileStatus `json:"status"`
+	Slug      string           `json:"slug,omitempty"`
+	ArticleID uint             `json:"article_id,omitempty"`
+	Error     string           `json:"error,omitempty"`
+}
+
+// ImportMarkdownResult is the per-file report returned by
+// ImportMarkdownZip, alongside whether it was a dry run.
+type ImportMarkdownResult struct {
+	DryRun bool               `json:"dry_run"`
+	Files  []ImportFileResult `json:"files"`
+}
+
+// markdownFrontMatter is the set of Hugo/Hexo front-matter fields this
+// importer understands. Both YAML (---) and TOML (+++) delimiters are
+// supported; any other front-matter fields are ignored.
+type markdownFrontMatter struct {
+	Title      string   `yaml:"title" toml:"title"`
+	Date       string   `yaml:"date" toml:"date"`
+	Slug       string   `yaml:"slug" toml:"slug"`
+	Draft      bool     `yaml:"draft" toml:"draft"`
+	Tags       []string `yaml:"tags" toml:"tags"`
+	Categories []string `yaml:"categories" toml:"categories"`
+}
+
+// markdownDateLayouts are the date formats this importer recognizes in
+// front matter, tried in order.
+var markdownDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// markdownImageRef matches a Markdown image reference so its path can be
+// resolved against the zip and rewritten to the upload pipeline.
+var markdownImageRef = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)\)`)
+
+// ImportMarkdownZip imports every .md file in zipData as a draft or
+// published Article owned by authorID, mapping front-matter tags and
+// categories to existing records by name or creating them, and
+// rewriting any zip-relative images it references into uploadDir. With
+// dryRun, every file is parsed and validated (including slug-collision
+// and tag/category resolution) but nothing is written to the database
+// or uploadDir.
+func ImportMarkdownZip(db *gorm.DB, zipData []byte, authorID uint, uploadDir string, dryRun bool) (*ImportMarkdownResult, error) {
+	zr, err := zip.NewReader(bytes.NewReader(zipData), int64(len(zipData)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid zip archive: %w", err)
+	}
+
+	filesByPath := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		filesByPath[f.Name] = f
+	}
+
+	result := &ImportMarkdownResult{DryRun: dryRun}
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || !strings.EqualFold(filepath.Ext(f.Name), ".md") {
+			continue
+		}
+		result.Files = append(result.Files, importMarkdownFile(db, f, filesByPath, authorID, uploadDir, dryRun))
+	}
+	return result, nil
+}
+
+func importMarkdownFile(db *gorm.DB, f *zip.File, filesByPath map[string]*zip.File, authorID uint, uploadDir string, dryRun bool) ImportFileResult {
+	res := ImportFileResult{Filename: f.Name}
+
+	raw, err := readZipFile(f)
+	if err != nil {
+		res.Status = ImportFileError
+		res.Error = err.Error()
+		return res
+	}
+
+	fm, body := splitFrontMatter(raw)
+
+	title := fm.Title
+	if title == "" {
+		title = strings.TrimSuffix(filepath.Base(f.Name), filepath.Ext(f.Name))
+	}
+
+	slug := fm.Slug
+	if slug == "" {
+		slug = slugify(strings.TrimSuffix(filepath.Base(f.Name), filepath.Ext(f.Name)))
+	}
+	res.Slug = slug
+
+	var existing models.Article
+	if err := db.Where("slug = ?", slug).First(&existing).Error; err == nil {
+		res.Status = ImportFileSkippedDupeSlug
+		return res
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		res.Status = ImportFileError
+		res.Error = err.Error()
+		return res
+	}
+
+	status := models.ArticleStatusPublished
+	if fm.Draft {
+		status = models.ArticleStatusDraft
+	}
+
+	createdAt := time.Now()
+	if fm.Date != "" {
+		if parsed, ok := parseMarkdownDate(fm.Date); ok {
+			createdAt = parsed
+		}
+	}
+
+	content := string(body)
+	if !dryRun {
+		content, err = rewriteMarkdownImages(content, f.Name, filesByPath, uploadDir)
+		if err != nil {
+			res.Status = ImportFileError
+			res.Error = err.Error()
+			return res
+		}
+	}
+
+	tags, err := resolveTags(db, fm.Tags, dryRun)
+	if err != nil {
+		res.Status = ImportFileError
+		res.Error = err.Error()
+		return res
+	}
+	// Categories are mapped to existing or newly created Category rows by
+	// name, as requested, but Article has no CategoryID (see README
+	// "Known gaps"), so there's nothing on the article itself to attach
+	// them to; resolving them here just ensures the records exist for
+	// whenever that link is added.
+	if _, err := resolveCategories(db, fm.Categories, dryRun); err != nil {
+		res.Status = ImportFileError
+		res.Error = err.Error()
+		return res
+	}
+
+	if dryRun {
+		res.Status = ImportFileCreated
+		return res
+	}
+
+	article := models.Article{
+		AuthorID:  authorID,
+		Title:     title,
+		Slug:      slug,
+		Content:   content,
+		Status:    status,
+		CreatedAt: createdAt,
+		Tags:      tags,
+	}
+	if err := db.Create(&article).Error; err != nil {
+		res.Status = ImportFileError
+		res.Error = err.Error()
+		return res
+	}
+
+	res.Status = ImportFileCreated
+	res.ArticleID = article.ID
+	return res
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	r, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// splitFrontMatter separates content into its YAML (---) or TOML (+++)
+// front matter and body. Files with no recognized front-matter block are
+// returned with a zero-value markdownFrontMatter and the whole content
+// as the body, so the caller can fall back to the filename as title.
+func splitFrontMatter(content []byte) (markdownFrontMatter, []byte) {
+	var fm markdownFrontMatter
+
+	s := strings.TrimPrefix(string(content), "\uFEFF")
+	for _, d := range []struct {
+		delim     string
+		unmarshal func([]byte, any) error
+	}{
+		{"---", yaml.Unmarshal},
+		{"+++", toml.Unmarshal},
+	} {
+		prefix := d.delim + "\n"
+		if !strings.HasPrefix(s, prefix) {
+			continue
+		}
+		rest := s[len(prefix):]
+		closing := "\n" + d.delim
+		idx := strings.Index(rest, closing)
+		if idx == -1 {
+			continue
+		}
+		block := rest[:idx]
+		body := strings.TrimPrefix(rest[idx+len(closing):], "\n")
+		_ = d.unmarshal([]byte(block), &fm) // malformed front matter falls back to filename/empty fields
+		return fm, []byte(body)
+	}
+	return fm, content
+}
+
+// rewriteMarkdownImages resolves each Markdown image reference in
+// content against the zip (relative to mdPath's directory), copies any
+// match into uploadDir under a random name, and rewrites the reference
+// to the canonical /api/files/ URL. References that don't resolve to a
+// file in the zip (e.g. already-absolute URLs) are left untouched.
+func rewriteMarkdownImages(content, mdPath string, filesByPath map[string]*zip.File, uploadDir string) (string, error) {
+	dir := path.Dir(mdPath)
+	var rewriteErr error
+
+	rewritten := markdownImageRef.ReplaceAllStringFunc(content, func(match string) string {
+		groups := markdownImageRef.FindStringSubmatch(match)
+		alt, ref := groups[1], groups[2]
+		if strings.Contains(ref, "://") {
+			return match
+		}
+
+		zipPath := path.Clean(path.Join(dir, ref))
+		f, ok := filesByPath[zipPath]
+		if !ok {
+			return match
+		}
+
+		data, err := readZipFile(f)
+		if err != nil {
+			rewriteErr = err
+			return match
+		}
+
+		name := randomImportFilename() + filepath.Ext(zipPath)
+		if err := os.MkdirAll(uploadDir, 0o755); err != nil {
+			rewriteErr = err
+			return match
+		}
+		if err := os.WriteFile(filepath.Join(uploadDir, name), data, 0o644); err != nil {
+			rewriteErr = err
+			return match
+		}
+
+		return fmt.Sprintf("![%s](/api/files/%s)", alt, name)
+	})
+	if rewriteErr != nil {
+		return "", rewriteErr
+	}
+	return rewritten, nil
+}
+
+func randomImportFilename() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// resolveTags finds or creates a Tag for each name, skipping the
+// database write (but still resolving by name to detect errors) when
+// dryRun is set.
+func resolveTags(db *gorm.DB, names []string, dryRun bool) ([]models.Tag, error) {
+	tags := make([]models.Tag, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		var tag models.Tag
+		err := db.Where("name = ?", name).First(&tag).Error
+		switch {
+		case err == nil:
+			tags = append(tags, tag)
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			if dryRun {
+				tags = append(tags, models.Tag{Name: name, Slug: slugify(name)})
+				continue
+			}
+			tag = models.Tag{Name: name, Slug: slugify(name)}
+			if err := db.Create(&tag).Error; err != nil {
+				return nil, err
+			}
+			tags = append(tags, tag)
+		default:
+			return nil, err
+		}
+	}
+	return tags, nil
+}
+
+// resolveCategories mirrors resolveTags for Category records.
+func resolveCategories(db *gorm.DB, names []string, dryRun bool) ([]models.Category, error) {
+	categories := make([]models.Category, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		var category models.Category
+		err := db.Where("name = ?", name).First(&category).Error
+		switch {
+		case err == nil:
+			categories = append(categories, category)
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			if dryRun {
+				categories = append(categories, models.Category{Name: name, Slug: slugify(name)})
+				continue
+			}
+			category = models.Category{Name: name, Slug: slugify(name)}
+			if err := db.Create(&category).Error; err != nil {
+				return nil, err
+			}
+			categories = append(categories, category)
+		default:
+			return nil, err
+		}
+	}
+	return categories, nil
+}
+
+var slugifyNonAlnum = regexp.MustCompile(`[^a-z0-9]+`)
+
+// slugify derives a URL-safe slug from name. It's deliberately simple
+// (lowercase, non-alphanumeric runs collapsed to a single hyphen) since
+// nothing else in this tree auto-generates slugs; every other create/
+// update endpoint requires the caller to supply one.
+func slugify(name string) string {
+	s := slugifyNonAlnum.ReplaceAllString(strings.ToLower(name), "-")
+	return strings.Trim(s, "-")
+}
+
+func parseMarkdownDate(value string) (time.Time, bool) {
+	for _, layout := range markdownDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/1055373165/blog/models"
+	"gorm.io/gorm"
+)
+
+func setupMarkdownImportTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&models.Article{}, &models.Tag{}, &models.Category{}); err != nil {
+		t.Fatalf("failed to migrate test db: %v", err)
+	}
+	return db
+}
+
+// buildZip packs files (path -> contents) into an in-memory zip archive.
+func buildZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, contents := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("failed to add %q to test zip: %v", name, err)
+		}
+		if _, err := w.Write([]byte(contents)); err != nil {
+			t.Fatalf("failed to write %q to test zip: %v", name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to close test zip: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestImportMarkdownZipParsesYAMLFrontMatter(t *testing.T) {
+	db := setupMarkdownImportTestDB(t)
+	zipData := buildZip(t, map[string]string{
+		"post.md": "---\ntitle: Hello World\nslug: hello-world\ntags:\n  - go\n  - web\ndraft: false\n---\nbody text\n",
+	})
+
+	result, err := ImportMarkdownZip(db, zipData, 1, t.TempDir(), false)
+	if err != nil {
+		t.Fatalf("ImportMarkdownZip returned error: %v", err)
+	}
+	if len(result.Files) != 1 || result.Files[0].Status != ImportFileCreated {
+		t.Fatalf("expected 1 created file, got %+v", result.Files)
+	}
+
+	var article models.Article
+	if err := db.Preload("Tags").First(&article, result.Files[0].ArticleID).Error; err != nil {
+		t.Fatalf("failed to load imported article: %v", err)
+	}
+	if article.Title != "Hello World" || article.Slug != "hello-world" {
+		t.Errorf("unexpected article: %+v", article)
+	}
+	if article.Status != models.ArticleStatusPublished {
+		t.Errorf("expected published status, got %q", article.Status)
+	}
+	if len(article.Tags) != 2 {
+		t.Errorf("expected 2 tags attached, got %d", len(article.Tags))
+	}
+}
+
+func TestImportMarkdownZipParsesTOMLFrontMatter(t *testing.T) {
+	db := setupMarkdownImportTestDB(t)
+	zipData := buildZip(t, map[string]string{
+		"post.md": "+++\ntitle = \"From Hexo\"\ndraft = true\ncategories = [\"Notes\"]\n+++\nbody text\n",
+	})
+
+	result, err := ImportMarkdownZip(db, zipData, 1, t.TempDir(), false)
+	if err != nil {
+		t.Fatalf("ImportMarkdownZip returned error: %v", err)
+	}
+	if len(result.Files) != 1 || result.Files[0].Status != ImportFileCreated {
+		t.Fatalf("expected 1 created file, got %+v", result.Files)
+	}
+
+	var article models.Article
+	db.First(&article, result.Files[0].ArticleID)
+	if article.Title != "From Hexo" {
+		t.Errorf("expected title from TOML front matter, got %q", article.Title)
+	}
+	if article.Status != models.ArticleStatusDraft {
+		t.Errorf("expected draft status, got %q", article.Status)
+	}
+
+	var category models.Category
+	if err := db.Where("name = ?", "Notes").First(&category).Error; err != nil {
+		t.Errorf("expected a Notes category to be created: %v", err)
+	}
+}
+
+func TestImportMarkdownZipFallsBackToFilenameWithoutFrontMatter(t *testing.T) {
+	db := setupMarkdownImportTestDB(t)
+	zipData := buildZip(t, map[string]string{
+		"my-first-post.md": "just a plain body, no front matter\n",
+	})
+
+	result, err := ImportMarkdownZip(db, zipData, 1, t.TempDir(), false)
+	if err != nil {
+		t.Fatalf("ImportMarkdownZip returned error: %v", err)
+	}
+	if len(result.Files) != 1 || result.Files[0].Status != ImportFileCreated {
+		t.Fatalf("expected 1 created file, got %+v", result.Files)
+	}
+
+	var article models.Article
+	db.First(&article, result.Files[0].ArticleID)
+	if article.Title != "my-first-post" || article.Slug != "my-first-post" {
+		t.Errorf("expected filename fallback for title/slug, got %+v", article)
+	}
+}
+
+func TestImportMarkdownZipSkipsDuplicateSlug(t *testing.T) {
+	db := setupMarkdownImportTestDB(t)
+	if err := db.Create(&models.Article{AuthorID: 1, Title: "Existing", Slug: "hello-world", Status: models.ArticleStatusPublished}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	zipData := buildZip(t, map[string]string{
+		"post.md": "---\ntitle: Hello World\nslug: hello-world\n---\nbody\n",
+	})
+
+	result, err := ImportMarkdownZip(db, zipData, 1, t.TempDir(), false)
+	if err != nil {
+		t.Fatalf("ImportMarkdownZip returned error: %v", err)
+	}
+	if len(result.Files) != 1 || result.Files[0].Status != ImportFileSkippedDupeSlug {
+		t.Fatalf("expected the duplicate slug to be skipped, got %+v", result.Files)
+	}
+}
+
+func TestImportMarkdownZipDryRunWritesNothing(t *testing.T) {
+	db := setupMarkdownImportTestDB(t)
+	zipData := buildZip(t, map[string]string{
+		"post.md": "---\ntitle: Hello World\nslug: hello-world\ntags:\n  - go\n---\nbody\n",
+	})
+
+	result, err := ImportMarkdownZip(db, zipData, 1, t.TempDir(), true)
+	if err != nil {
+		t.Fatalf("ImportMarkdownZip returned error: %v", err)
+	}
+	if !result.DryRun || len(result.Files) != 1 || result.Files[0].Status != ImportFileCreated {
+		t.Fatalf("expected a dry-run created report, got %+v", result)
+	}
+
+	var articleCount, tagCount int64
+	db.Model(&models.Article{}).Count(&articleCount)
+	db.Model(&models.Tag{}).Count(&tagCount)
+	if articleCount != 0 || tagCount != 0 {
+		t.Errorf("expected dry run to write nothing, got %d articles and %d tags", articleCount, tagCount)
+	}
+}
+
+func TestImportMarkdownZipRewritesRelativeImages(t *testing.T) {
+	db := setupMarkdownImportTestDB(t)
+	uploadDir := t.TempDir()
+	zipData := buildZip(t, map[string]string{
+		"posts/post.md":          "---\ntitle: With Image\nslug: with-image\n---\n![alt text](images/cover.png)\n",
+		"posts/images/cover.png": "fake-png-bytes",
+	})
+
+	result, err := ImportMarkdownZip(db, zipData, 1, uploadDir, false)
+	if err != nil {
+		t.Fatalf("ImportMarkdownZip returned error: %v", err)
+	}
+	if len(result.Files) != 1 || result.Files[0].Status != ImportFileCreated {
+		t.Fatalf("expected 1 created file, got %+v", result.Files)
+	}
+
+	var article models.Article
+	db.First(&article, result.Files[0].ArticleID)
+	if !strings.Contains(article.Content, "/api/files/") {
+		t.Errorf("expected image reference rewritten to /api/files/, got %q", article.Content)
+	}
+	if strings.Contains(article.Content, "images/cover.png") {
+		t.Errorf("expected original relative path to be replaced, got %q", article.Content)
+	}
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"errors"
+	"regexp"
+
+	"github.com/1055373165/blog/models"
+	"gorm.io/gorm"
+)
+
+// codeSpanPattern strips inline code spans before mention scanning, so
+// an @-looking token inside backticks never triggers a mention.
+var codeSpanPattern = regexp.MustCompile("`[^`]*`")
+
+// mentionPattern matches @[Bracketed Name] for usernames containing
+// spaces, or a plain @token otherwise.
+var mentionPattern = regexp.MustCompile("@\\[([^\\]]+)\\]|@([^\\s@,.!?;:()\\[\\]`]+)")
+
+// MentionedUser is a resolved @mention, used both to create
+// CommentMention rows and to surface linkifiable references in comment
+// responses.
+type MentionedUser struct {
+	UserID   uint   `json:"user_id"`
+	Username string `json:"username"`
+}
+
+// ParseMentions extracts @mentions from content, ignoring inline code
+// spans, and resolves each to a registered user by exact username match
+// or, failing that, an unambiguous username prefix match. Self-mentions
+// by authorID are ignored, and each user is returned at most once.
+func ParseMentions(db *gorm.DB, content string, authorID uint) ([]MentionedUser, error) {
+	stripped := codeSpanPattern.ReplaceAllString(content, "")
+	matches := mentionPattern.FindAllStringSubmatch(stripped, -1)
+	if len(matches) == 0 {
+		return nil, nil
+	}
+
+	seen := make(map[uint]bool)
+	var mentions []MentionedUser
+	for _, m := range matches {
+		name := m[1]
+		if name == "" {
+			name = m[2]
+		}
+		user, err := resolveMentionedUser(db, name)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil || user.ID == authorID || seen[user.ID] {
+			continue
+		}
+		seen[user.ID] = true
+		mentions = append(mentions, MentionedUser{UserID: user.ID, Username: user.Username})
+	}
+	return mentions, nil
+}
+
+// resolveMentionedUser matches name against Username exactly, falling
+// back to an unambiguous prefix match. It returns a nil user (not an
+// error) when nothing or more than one candidate matches.
+func resolveMentionedUser(db *gorm.DB, name string) (*models.User, error) {
+	var exact models.User
+	err := db.Where("username = ?", name).First(&exact).Error
+	if err == nil {
+		return &exact, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	var candidates []models.User
+	if err := db.Where("username LIKE ?", name+"%").Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+	if len(candidates) == 1 {
+		return &candidates[0], nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/1055373165/blog/models"
+)
+
+func TestParseMentionsResolvesUsernamesAndIgnoresCodeSpansAndSelf(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.User{})
+
+	alice := models.User{Username: "alice"}
+	alicia := models.User{Username: "alicia"}
+	bob := models.User{Username: "bob"}
+	db.Create(&alice)
+	db.Create(&alicia)
+	db.Create(&bob)
+
+	content := "hey @bob, see `@bob` in the code and @alice [not a mention] again @bob, also @self"
+	mentions, err := ParseMentions(db, content, bob.ID)
+	if err != nil {
+		t.Fatalf("ParseMentions returned error: %v", err)
+	}
+	if len(mentions) != 1 || mentions[0].Username != "alice" {
+		t.Fatalf("expected only alice mentioned once, got %+v", mentions)
+	}
+
+	// "alic" is an ambiguous prefix of both alice and alicia, so it must
+	// not resolve to either.
+	mentions, err = ParseMentions(db, "hi @alic", alice.ID)
+	if err != nil {
+		t.Fatalf("ParseMentions returned error: %v", err)
+	}
+	if len(mentions) != 0 {
+		t.Fatalf("expected ambiguous prefix to resolve to nothing, got %+v", mentions)
+	}
+
+	mentions, err = ParseMentions(db, "hi @[alice]", bob.ID)
+	if err != nil {
+		t.Fatalf("ParseMentions returned error: %v", err)
+	}
+	if len(mentions) != 1 || mentions[0].Username != "alice" {
+		t.Fatalf("expected bracketed mention to resolve to alice, got %+v", mentions)
+	}
+}
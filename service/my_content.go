@@ -0,0 +1,67 @@
+package service
+
+import (
+	"github.com/1055373165/blog/models"
+	"gorm.io/gorm"
+)
+
+// MyContentResult is the payload for GetMyContent: the caller's own
+// articles and blogs, each paginated and counted independently since
+// they come from separate tables.
+type MyContentResult struct {
+	Articles      []models.Article `json:"articles"`
+	ArticlesTotal int64            `json:"articles_total"`
+	Blogs         []models.Blog    `json:"blogs"`
+	BlogsTotal    int64            `json:"blogs_total"`
+}
+
+// GetMyContent returns userID's own articles and blogs, most recently
+// updated first, filtered by an optional status ("draft" or
+// "published") and an optional case-insensitive title/content substring
+// query. Blog has no draft/published Status of its own (every Blog is
+// visible once created - see models.Blog), so a status filter of
+// "draft" returns no blogs and "published" returns all of them; status
+// applies to Article as normal. Views and likes are already columns on
+// each row (Article.Views/Likes, Blog.Likes), so unlike a comment count
+// would need, no extra per-row or grouped query is required to report
+// them here.
+func GetMyContent(db *gorm.DB, userID uint, status, query string, page, pageSize int) (MyContentResult, error) {
+	var result MyContentResult
+
+	articleQuery := db.Model(&models.Article{}).Where("author_id = ?", userID)
+	if status != "" {
+		articleQuery = articleQuery.Where("status = ?", status)
+	}
+	if query != "" {
+		like := "%" + query + "%"
+		articleQuery = articleQuery.Where("title LIKE ? OR content LIKE ?", like, like)
+	}
+	if err := articleQuery.Session(&gorm.Session{}).Count(&result.ArticlesTotal).Error; err != nil {
+		return MyContentResult{}, err
+	}
+	if err := articleQuery.Order("updated_at desc").
+		Offset((page - 1) * pageSize).Limit(pageSize).
+		Find(&result.Articles).Error; err != nil {
+		return MyContentResult{}, err
+	}
+
+	if status == string(models.ArticleStatusDraft) {
+		return result, nil
+	}
+
+	blogQuery := db.Model(&models.Blog{}).Where("author_id = ?", userID)
+	if query != "" {
+		like := "%" + query + "%"
+		blogQuery = blogQuery.Where("title LIKE ? OR description LIKE ?", like, like)
+	}
+	if err := blogQuery.Session(&gorm.Session{}).Count(&result.BlogsTotal).Error; err != nil {
+		return MyContentResult{}, err
+	}
+	if err := blogQuery.Order("updated_at desc").
+		Offset((page - 1) * pageSize).Limit(pageSize).
+		Find(&result.Blogs).Error; err != nil {
+		return MyContentResult{}, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/1055373165/blog/models"
+)
+
+func TestGetMyContentScopesToCallerAndFiltersByStatus(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Article{}, &models.Blog{})
+
+	db.Create(&models.Article{AuthorID: 1, Title: "my draft", Slug: "my-draft", Status: models.ArticleStatusDraft})
+	db.Create(&models.Article{AuthorID: 1, Title: "my published", Slug: "my-published", Status: models.ArticleStatusPublished})
+	db.Create(&models.Article{AuthorID: 2, Title: "their draft", Slug: "their-draft", Status: models.ArticleStatusDraft})
+	db.Create(&models.Blog{AuthorID: 1, Title: "my blog", Slug: "my-blog", MediaURL: "http://x/1.mp3"})
+	db.Create(&models.Blog{AuthorID: 2, Title: "their blog", Slug: "their-blog", MediaURL: "http://x/2.mp3"})
+
+	result, err := GetMyContent(db, 1, "", "", 1, 20)
+	if err != nil {
+		t.Fatalf("GetMyContent: %v", err)
+	}
+	if result.ArticlesTotal != 2 {
+		t.Errorf("expected 2 of the caller's own articles, got %d", result.ArticlesTotal)
+	}
+	if result.BlogsTotal != 1 {
+		t.Errorf("expected 1 of the caller's own blogs, got %d", result.BlogsTotal)
+	}
+	for _, a := range result.Articles {
+		if a.AuthorID != 1 {
+			t.Errorf("expected only author 1's articles, found one from author %d", a.AuthorID)
+		}
+	}
+	for _, b := range result.Blogs {
+		if b.AuthorID != 1 {
+			t.Errorf("expected only author 1's blogs, found one from author %d", b.AuthorID)
+		}
+	}
+
+	drafts, err := GetMyContent(db, 1, "draft", "", 1, 20)
+	if err != nil {
+		t.Fatalf("GetMyContent(draft): %v", err)
+	}
+	if drafts.ArticlesTotal != 1 || len(drafts.Articles) != 1 || drafts.Articles[0].Title != "my draft" {
+		t.Errorf("expected exactly the caller's draft article, got %+v", drafts.Articles)
+	}
+	if drafts.BlogsTotal != 0 || len(drafts.Blogs) != 0 {
+		t.Errorf("expected no blogs under status=draft since Blog has no draft state, got %+v", drafts.Blogs)
+	}
+
+	published, err := GetMyContent(db, 1, "published", "", 1, 20)
+	if err != nil {
+		t.Fatalf("GetMyContent(published): %v", err)
+	}
+	if published.ArticlesTotal != 1 || len(published.Articles) != 1 || published.Articles[0].Title != "my published" {
+		t.Errorf("expected exactly the caller's published article, got %+v", published.Articles)
+	}
+	if published.BlogsTotal != 1 {
+		t.Errorf("expected status=published to include all of the caller's blogs, got %d", published.BlogsTotal)
+	}
+}
+
+func TestGetMyContentFiltersBySearchQuery(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Article{}, &models.Blog{})
+
+	db.Create(&models.Article{AuthorID: 1, Title: "Learning Go", Slug: "learning-go", Content: "generics", Status: models.ArticleStatusPublished})
+	db.Create(&models.Article{AuthorID: 1, Title: "Rust basics", Slug: "rust-basics", Content: "ownership", Status: models.ArticleStatusPublished})
+	db.Create(&models.Blog{AuthorID: 1, Title: "Go podcast", Slug: "go-podcast", MediaURL: "http://x/1.mp3"})
+	db.Create(&models.Blog{AuthorID: 1, Title: "Rust podcast", Slug: "rust-podcast", MediaURL: "http://x/2.mp3"})
+
+	result, err := GetMyContent(db, 1, "", "Go", 1, 20)
+	if err != nil {
+		t.Fatalf("GetMyContent: %v", err)
+	}
+	if result.ArticlesTotal != 1 || len(result.Articles) != 1 || result.Articles[0].Title != "Learning Go" {
+		t.Errorf("expected the search to match only the Go article, got %+v", result.Articles)
+	}
+	if result.BlogsTotal != 1 || len(result.Blogs) != 1 || result.Blogs[0].Title != "Go podcast" {
+		t.Errorf("expected the search to match only the Go podcast, got %+v", result.Blogs)
+	}
+}
+
+func TestGetMyContentPaginates(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Article{}, &models.Blog{})
+
+	for i := 0; i < 5; i++ {
+		db.Create(&models.Article{AuthorID: 1, Title: "post", Slug: "post-" + string(rune('a'+i)), Status: models.ArticleStatusPublished})
+	}
+
+	page1, err := GetMyContent(db, 1, "", "", 1, 2)
+	if err != nil {
+		t.Fatalf("GetMyContent page 1: %v", err)
+	}
+	if page1.ArticlesTotal != 5 || len(page1.Articles) != 2 {
+		t.Fatalf("expected total 5, page size 2, got total %d len %d", page1.ArticlesTotal, len(page1.Articles))
+	}
+
+	page3, err := GetMyContent(db, 1, "", "", 3, 2)
+	if err != nil {
+		t.Fatalf("GetMyContent page 3: %v", err)
+	}
+	if len(page3.Articles) != 1 {
+		t.Errorf("expected the last page to have the remaining 1 article, got %d", len(page3.Articles))
+	}
+}
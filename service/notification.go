@@ -0,0 +1,50 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/1055373165/blog/models"
+	"gorm.io/gorm"
+)
+
+// CreateNotification records a new in-app notification for recipientID.
+func CreateNotification(db *gorm.DB, recipientID uint, kind, message string) error {
+	return db.Create(&models.Notification{RecipientID: recipientID, Kind: kind, Message: message}).Error
+}
+
+// GetUserNotifications returns userID's notifications, most recent
+// first, paginated, along with their current unread count.
+func GetUserNotifications(db *gorm.DB, userID uint, page, pageSize int) (notifications []models.Notification, unreadCount int64, err error) {
+	if err = db.Where("recipient_id = ?", userID).
+		Order("created_at desc").
+		Offset((page - 1) * pageSize).Limit(pageSize).
+		Find(&notifications).Error; err != nil {
+		return nil, 0, err
+	}
+	if err = db.Model(&models.Notification{}).
+		Where("recipient_id = ? AND read_at IS NULL", userID).
+		Count(&unreadCount).Error; err != nil {
+		return nil, 0, err
+	}
+	return notifications, unreadCount, nil
+}
+
+var ErrNotificationNotFound = errors.New("notification not found")
+
+// MarkNotificationRead sets notificationID's read_at to now, if it
+// belongs to userID and isn't already read.
+func MarkNotificationRead(db *gorm.DB, userID, notificationID uint) error {
+	var n models.Notification
+	if err := db.Where("id = ? AND recipient_id = ?", notificationID, userID).First(&n).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotificationNotFound
+		}
+		return err
+	}
+	if n.ReadAt != nil {
+		return nil
+	}
+	now := time.Now()
+	return db.Model(&n).Update("read_at", now).Error
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/1055373165/blog/models"
+)
+
+func TestGetUserNotificationsReturnsUnreadCountAndOrdering(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Notification{})
+
+	if err := CreateNotification(db, 1, "reply", "first"); err != nil {
+		t.Fatalf("CreateNotification returned error: %v", err)
+	}
+	if err := CreateNotification(db, 1, "reply", "second"); err != nil {
+		t.Fatalf("CreateNotification returned error: %v", err)
+	}
+	if err := CreateNotification(db, 2, "reply", "other user"); err != nil {
+		t.Fatalf("CreateNotification returned error: %v", err)
+	}
+
+	notifications, unreadCount, err := GetUserNotifications(db, 1, 1, 10)
+	if err != nil {
+		t.Fatalf("GetUserNotifications returned error: %v", err)
+	}
+	if len(notifications) != 2 {
+		t.Fatalf("expected 2 notifications for user 1, got %d", len(notifications))
+	}
+	if notifications[0].Message != "second" {
+		t.Errorf("expected most recent notification first, got %q", notifications[0].Message)
+	}
+	if unreadCount != 2 {
+		t.Errorf("expected unread count 2, got %d", unreadCount)
+	}
+}
+
+func TestMarkNotificationReadIsOwnershipScopedAndIdempotent(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Notification{})
+
+	n := models.Notification{RecipientID: 1, Kind: "reply", Message: "hi"}
+	db.Create(&n)
+
+	if err := MarkNotificationRead(db, 2, n.ID); err != ErrNotificationNotFound {
+		t.Errorf("expected ErrNotificationNotFound for non-owner, got %v", err)
+	}
+
+	if err := MarkNotificationRead(db, 1, n.ID); err != nil {
+		t.Fatalf("MarkNotificationRead returned error: %v", err)
+	}
+	var reloaded models.Notification
+	db.First(&reloaded, n.ID)
+	if reloaded.ReadAt == nil {
+		t.Fatal("expected ReadAt to be set")
+	}
+
+	firstReadAt := *reloaded.ReadAt
+	if err := MarkNotificationRead(db, 1, n.ID); err != nil {
+		t.Fatalf("expected marking an already-read notification to be a no-op, got error: %v", err)
+	}
+	db.First(&reloaded, n.ID)
+	if !reloaded.ReadAt.Equal(firstReadAt) {
+		t.Errorf("expected ReadAt unchanged on second call, got %v (was %v)", reloaded.ReadAt, firstReadAt)
+	}
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/1055373165/blog/models"
+	"gorm.io/gorm"
+)
+
+// ErrSameOwner is returned when a transfer's target is the current owner.
+var ErrSameOwner = errors.New("target user already owns this content")
+
+// TransferArticleOwner reassigns article's AuthorID to newOwnerID within
+// a transaction, after confirming newOwnerID exists. It returns the
+// article's AuthorID before the change, for the caller to audit/notify.
+func TransferArticleOwner(db *gorm.DB, articleID, newOwnerID uint) (oldOwnerID uint, err error) {
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var article models.Article
+		if err := tx.First(&article, articleID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrArticleNotFound
+			}
+			return err
+		}
+		if article.AuthorID == newOwnerID {
+			return ErrSameOwner
+		}
+		var newOwner models.User
+		if err := tx.First(&newOwner, newOwnerID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+		oldOwnerID = article.AuthorID
+		return tx.Model(&article).Update("author_id", newOwnerID).Error
+	})
+	return oldOwnerID, err
+}
+
+// TransferBlogOwner reassigns blog's AuthorID to newOwnerID within a
+// transaction, after confirming newOwnerID exists. It returns the
+// blog's AuthorID before the change, for the caller to audit/notify.
+func TransferBlogOwner(db *gorm.DB, blogID, newOwnerID uint) (oldOwnerID uint, err error) {
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var blog models.Blog
+		if err := tx.First(&blog, blogID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrBlogNotFound
+			}
+			return err
+		}
+		if blog.AuthorID == newOwnerID {
+			return ErrSameOwner
+		}
+		var newOwner models.User
+		if err := tx.First(&newOwner, newOwnerID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+		oldOwnerID = blog.AuthorID
+		return tx.Model(&blog).Update("author_id", newOwnerID).Error
+	})
+	return oldOwnerID, err
+}
+
+// ContentTransferCounts reports how many rows of each type
+// TransferAllContent moved, for the admin's response.
+type ContentTransferCounts struct {
+	ArticlesMoved    int64 `json:"articles_moved"`
+	BlogsMoved       int64 `json:"blogs_moved"`
+	SubmissionsMoved int64 `json:"submissions_moved"`
+}
+
+// TransferAllContent moves every Article, Blog, and draft-status
+// Submission owned by fromUserID to toUserID, within a single
+// transaction, for use right before fromUserID's account is
+// deactivated. Submissions past the draft stage are left alone, since
+// they are already in someone else's review queue. It returns how many
+// rows of each type moved.
+func TransferAllContent(db *gorm.DB, fromUserID, toUserID uint) (ContentTransferCounts, error) {
+	var counts ContentTransferCounts
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if fromUserID == toUserID {
+			return ErrSameOwner
+		}
+		var toUser models.User
+		if err := tx.First(&toUser, toUserID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+
+		articles := tx.Model(&models.Article{}).Where("author_id = ?", fromUserID).Update("author_id", toUserID)
+		if articles.Error != nil {
+			return articles.Error
+		}
+		counts.ArticlesMoved = articles.RowsAffected
+
+		blogs := tx.Model(&models.Blog{}).Where("author_id = ?", fromUserID).Update("author_id", toUserID)
+		if blogs.Error != nil {
+			return blogs.Error
+		}
+		counts.BlogsMoved = blogs.RowsAffected
+
+		submissions := tx.Model(&models.Submission{}).
+			Where("author_id = ? AND status = ?", fromUserID, models.SubmissionStatusDraft).
+			Update("author_id", toUserID)
+		if submissions.Error != nil {
+			return submissions.Error
+		}
+		counts.SubmissionsMoved = submissions.RowsAffected
+
+		return nil
+	})
+	return counts, err
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/1055373165/blog/models"
+)
+
+func TestTransferArticleOwnerMovesAuthorID(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.User{}, &models.Article{})
+
+	oldOwner := models.User{Username: "old", Email: "old@example.com"}
+	newOwner := models.User{Username: "new", Email: "new@example.com"}
+	db.Create(&oldOwner)
+	db.Create(&newOwner)
+	article := models.Article{AuthorID: oldOwner.ID, Title: "A", Slug: "a"}
+	db.Create(&article)
+
+	gotOldOwner, err := TransferArticleOwner(db, article.ID, newOwner.ID)
+	if err != nil {
+		t.Fatalf("TransferArticleOwner: %v", err)
+	}
+	if gotOldOwner != oldOwner.ID {
+		t.Errorf("expected reported old owner %d, got %d", oldOwner.ID, gotOldOwner)
+	}
+
+	var reloaded models.Article
+	db.First(&reloaded, article.ID)
+	if reloaded.AuthorID != newOwner.ID {
+		t.Errorf("expected author_id %d, got %d", newOwner.ID, reloaded.AuthorID)
+	}
+}
+
+func TestTransferArticleOwnerRejectsUnknownTarget(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.User{}, &models.Article{})
+
+	owner := models.User{Username: "owner", Email: "owner@example.com"}
+	db.Create(&owner)
+	article := models.Article{AuthorID: owner.ID, Title: "A", Slug: "a"}
+	db.Create(&article)
+
+	_, err := TransferArticleOwner(db, article.ID, 999)
+	if !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+
+	var reloaded models.Article
+	db.First(&reloaded, article.ID)
+	if reloaded.AuthorID != owner.ID {
+		t.Errorf("expected author_id unchanged at %d, got %d", owner.ID, reloaded.AuthorID)
+	}
+}
+
+func TestTransferArticleOwnerRejectsSameOwner(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.User{}, &models.Article{})
+
+	owner := models.User{Username: "owner", Email: "owner@example.com"}
+	db.Create(&owner)
+	article := models.Article{AuthorID: owner.ID, Title: "A", Slug: "a"}
+	db.Create(&article)
+
+	_, err := TransferArticleOwner(db, article.ID, owner.ID)
+	if !errors.Is(err, ErrSameOwner) {
+		t.Fatalf("expected ErrSameOwner, got %v", err)
+	}
+}
+
+func TestTransferBlogOwnerMovesAuthorID(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.User{}, &models.Blog{})
+
+	oldOwner := models.User{Username: "old", Email: "old@example.com"}
+	newOwner := models.User{Username: "new", Email: "new@example.com"}
+	db.Create(&oldOwner)
+	db.Create(&newOwner)
+	blog := models.Blog{AuthorID: oldOwner.ID, Title: "B"}
+	db.Create(&blog)
+
+	gotOldOwner, err := TransferBlogOwner(db, blog.ID, newOwner.ID)
+	if err != nil {
+		t.Fatalf("TransferBlogOwner: %v", err)
+	}
+	if gotOldOwner != oldOwner.ID {
+		t.Errorf("expected reported old owner %d, got %d", oldOwner.ID, gotOldOwner)
+	}
+
+	var reloaded models.Blog
+	db.First(&reloaded, blog.ID)
+	if reloaded.AuthorID != newOwner.ID {
+		t.Errorf("expected author_id %d, got %d", newOwner.ID, reloaded.AuthorID)
+	}
+}
+
+func TestTransferAllContentMovesArticlesBlogsAndDraftSubmissionsOnly(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.User{}, &models.Article{}, &models.Blog{}, &models.Submission{})
+
+	from := models.User{Username: "leaving", Email: "leaving@example.com"}
+	to := models.User{Username: "staying", Email: "staying@example.com"}
+	db.Create(&from)
+	db.Create(&to)
+
+	db.Create(&models.Article{AuthorID: from.ID, Title: "a1", Slug: "a1"})
+	db.Create(&models.Article{AuthorID: from.ID, Title: "a2", Slug: "a2"})
+	db.Create(&models.Blog{AuthorID: from.ID, Title: "b1"})
+	draft := models.Submission{AuthorID: from.ID, Title: "s1", Status: models.SubmissionStatusDraft}
+	pending := models.Submission{AuthorID: from.ID, Title: "s2", Status: models.SubmissionStatusPending}
+	db.Create(&draft)
+	db.Create(&pending)
+
+	counts, err := TransferAllContent(db, from.ID, to.ID)
+	if err != nil {
+		t.Fatalf("TransferAllContent: %v", err)
+	}
+	if counts.ArticlesMoved != 2 {
+		t.Errorf("expected 2 articles moved, got %d", counts.ArticlesMoved)
+	}
+	if counts.BlogsMoved != 1 {
+		t.Errorf("expected 1 blog moved, got %d", counts.BlogsMoved)
+	}
+	if counts.SubmissionsMoved != 1 {
+		t.Errorf("expected 1 draft submission moved, got %d", counts.SubmissionsMoved)
+	}
+
+	var reloadedPending models.Submission
+	db.First(&reloadedPending, pending.ID)
+	if reloadedPending.AuthorID != from.ID {
+		t.Errorf("expected non-draft submission to stay with %d, got %d", from.ID, reloadedPending.AuthorID)
+	}
+}
+
+func TestTransferAllContentRejectsUnknownTarget(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.User{}, &models.Article{}, &models.Blog{}, &models.Submission{})
+
+	from := models.User{Username: "leaving", Email: "leaving@example.com"}
+	db.Create(&from)
+
+	_, err := TransferAllContent(db, from.ID, 999)
+	if !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,301 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/1055373165/blog/models"
+	"gorm.io/gorm"
+)
+
+var ErrBlogNotFound = errors.New("blog not found")
+
+// ErrInvalidReactionType is returned for a reactionType outside
+// models.ValidReactionTypes.
+var ErrInvalidReactionType = errors.New("invalid reaction type")
+
+// ReactionCounts maps each reaction type present on a target to its
+// total count.
+type ReactionCounts map[models.ReactionType]int64
+
+func isValidReactionType(t models.ReactionType) bool {
+	for _, v := range models.ValidReactionTypes {
+		if v == t {
+			return true
+		}
+	}
+	return false
+}
+
+// toggleReaction creates r if no reaction matching its target/user/type
+// exists yet, or deletes the existing one otherwise. It returns whether a
+// reaction exists after the call (reacted) and whether this call is the
+// one that actually changed that (changed), so a caller keeping a
+// denormalized counter in sync only applies its delta once - see below.
+// It's shared by ToggleArticleReaction and ToggleBlogReaction, which
+// differ only in which of r.ArticleID/r.BlogID is set.
+//
+// The check-then-create isn't atomic, so a concurrent duplicate insert
+// can still race past it; the unique index on models.Reaction catches
+// that case. The losing call is then treated as "already reacted"
+// rather than erroring, but reports changed=false so its caller skips
+// re-applying a delta that the winning call already applied - otherwise
+// both calls would bump Article.Likes/Blog.Likes for what is, from the
+// data's point of view, a single like.
+func toggleReaction(tx *gorm.DB, r *models.Reaction) (reacted, changed bool, err error) {
+	query := tx.Where("user_id = ? AND reaction_type = ?", r.UserID, r.ReactionType)
+	if r.ArticleID != nil {
+		query = query.Where("article_id = ?", *r.ArticleID)
+	} else {
+		query = query.Where("blog_id = ?", *r.BlogID)
+	}
+
+	var existing models.Reaction
+	err = query.First(&existing).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return toggleReactionCreate(tx, r)
+	case err != nil:
+		return false, false, err
+	default:
+		return false, true, tx.Delete(&existing).Error
+	}
+}
+
+// toggleReactionCreate is the "create" half of toggleReaction, split out so
+// the duplicate-key race it guards against (a concurrent call winning the
+// insert between toggleReaction's own check and this Create) can be
+// exercised directly in tests without actually racing two goroutines.
+func toggleReactionCreate(tx *gorm.DB, r *models.Reaction) (reacted, changed bool, err error) {
+	if err := tx.Create(r).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return true, false, nil
+		}
+		return false, false, err
+	}
+	return true, true, nil
+}
+
+// reactionCounts groups a target's reactions by type.
+func reactionCounts(tx *gorm.DB, column string, targetID uint) (ReactionCounts, error) {
+	var rows []struct {
+		ReactionType models.ReactionType
+		Count        int64
+	}
+	if err := tx.Model(&models.Reaction{}).
+		Select("reaction_type, count(*) as count").
+		Where(column+" = ?", targetID).
+		Group("reaction_type").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	counts := make(ReactionCounts, len(rows))
+	for _, row := range rows {
+		counts[row.ReactionType] = row.Count
+	}
+	return counts, nil
+}
+
+// userReactionTypes returns the reaction types userID has left on a target.
+func userReactionTypes(tx *gorm.DB, column string, targetID, userID uint) ([]models.ReactionType, error) {
+	var types []models.ReactionType
+	err := tx.Model(&models.Reaction{}).
+		Where(column+" = ? AND user_id = ?", targetID, userID).
+		Pluck("reaction_type", &types).Error
+	return types, err
+}
+
+// ToggleArticleReaction flips userID's reactionType on a published
+// articleID, keeping Article.Likes in sync when reactionType is
+// models.ReactionLike, for backward compatibility with the plain like
+// counter ToggleArticleLike bumps. Removing a reaction that wasn't set
+// is a no-op reported as reacted=false, not an error.
+func ToggleArticleReaction(db *gorm.DB, userID, articleID uint, reactionType models.ReactionType) (reacted bool, err error) {
+	if !isValidReactionType(reactionType) {
+		return false, ErrInvalidReactionType
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var article models.Article
+		if err := tx.Where("id = ? AND status = ?", articleID, models.ArticleStatusPublished).
+			First(&article).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrArticleNotFound
+			}
+			return err
+		}
+
+		var changed bool
+		reacted, changed, err = toggleReaction(tx, &models.Reaction{ArticleID: &articleID, UserID: userID, ReactionType: reactionType})
+		if err != nil {
+			return err
+		}
+		if reactionType != models.ReactionLike || !changed {
+			return nil
+		}
+		delta := 1
+		if !reacted {
+			delta = -1
+		}
+		return tx.Model(&article).UpdateColumn("likes", gorm.Expr("MAX(likes + ?, 0)", delta)).Error
+	})
+	return reacted, err
+}
+
+// GetArticleReactionCounts returns articleID's per-type reaction counts,
+// viewer-independent and so safe to embed in a cached Article response.
+func GetArticleReactionCounts(db *gorm.DB, articleID uint) (ReactionCounts, error) {
+	return reactionCounts(db, "article_id", articleID)
+}
+
+// GetArticleReactions returns articleID's per-type reaction counts plus
+// the types userID has reacted with (userID 0 for an unauthenticated
+// caller, who simply gets an empty "mine" list).
+func GetArticleReactions(db *gorm.DB, articleID, userID uint) (counts ReactionCounts, mine []models.ReactionType, err error) {
+	counts, err = reactionCounts(db, "article_id", articleID)
+	if err != nil || userID == 0 {
+		return counts, mine, err
+	}
+	mine, err = userReactionTypes(db, "article_id", articleID, userID)
+	return counts, mine, err
+}
+
+// ToggleBlogReaction is ToggleArticleReaction for a Blog, keeping
+// Blog.Likes in sync the same way.
+func ToggleBlogReaction(db *gorm.DB, userID, blogID uint, reactionType models.ReactionType) (reacted bool, err error) {
+	if !isValidReactionType(reactionType) {
+		return false, ErrInvalidReactionType
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var blog models.Blog
+		if err := tx.First(&blog, blogID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrBlogNotFound
+			}
+			return err
+		}
+
+		var changed bool
+		reacted, changed, err = toggleReaction(tx, &models.Reaction{BlogID: &blogID, UserID: userID, ReactionType: reactionType})
+		if err != nil {
+			return err
+		}
+		if reactionType != models.ReactionLike || !changed {
+			return nil
+		}
+		delta := 1
+		if !reacted {
+			delta = -1
+		}
+		return tx.Model(&blog).UpdateColumn("likes", gorm.Expr("MAX(likes + ?, 0)", delta)).Error
+	})
+	return reacted, err
+}
+
+// GetBlogReactions is GetArticleReactions for a Blog.
+func GetBlogReactions(db *gorm.DB, blogID, userID uint) (counts ReactionCounts, mine []models.ReactionType, err error) {
+	counts, err = reactionCounts(db, "blog_id", blogID)
+	if err != nil || userID == 0 {
+		return counts, mine, err
+	}
+	mine, err = userReactionTypes(db, "blog_id", blogID, userID)
+	return counts, mine, err
+}
+
+// likesRecountBatchSize is the number of rows RecountLikes processes per
+// batch, per table.
+const likesRecountBatchSize = 200
+
+// LikesRecountResult reports how many Article/Blog rows RecountLikes
+// actually corrected, for the admin maintenance endpoint's response.
+type LikesRecountResult struct {
+	ArticlesFixed int `json:"articles_fixed"`
+	BlogsFixed    int `json:"blogs_fixed"`
+}
+
+// RecountLikes resynchronizes Article.Likes and Blog.Likes with the
+// actual number of "like" Reaction rows for each target. The atomic
+// `likes + delta` updates ToggleArticleReaction/ToggleBlogReaction apply
+// can't drift on their own, but this is still the one place to repair
+// drift left over from before those functions existed, or from a
+// Reaction row deleted out-of-band (a cascading delete, a manual fixup
+// query). It walks both tables in batches of likesRecountBatchSize,
+// keyset-paginated like NormalizeArticleContentURLs, rather than
+// recomputing every row in one long-running UPDATE.
+func RecountLikes(db *gorm.DB) (LikesRecountResult, error) {
+	articlesFixed, err := recountArticleLikes(db)
+	if err != nil {
+		return LikesRecountResult{}, err
+	}
+	blogsFixed, err := recountBlogLikes(db)
+	if err != nil {
+		return LikesRecountResult{}, err
+	}
+	return LikesRecountResult{ArticlesFixed: articlesFixed, BlogsFixed: blogsFixed}, nil
+}
+
+func recountArticleLikes(db *gorm.DB) (int, error) {
+	fixed := 0
+	var lastID uint
+	for {
+		var articles []models.Article
+		if err := db.Where("id > ?", lastID).Order("id asc").Limit(likesRecountBatchSize).Find(&articles).Error; err != nil {
+			return fixed, err
+		}
+		if len(articles) == 0 {
+			break
+		}
+
+		for _, a := range articles {
+			lastID = a.ID
+
+			var actual int64
+			if err := db.Model(&models.Reaction{}).
+				Where("article_id = ? AND reaction_type = ?", a.ID, models.ReactionLike).
+				Count(&actual).Error; err != nil {
+				return fixed, err
+			}
+			if int64(a.Likes) == actual {
+				continue
+			}
+			if err := db.Model(&models.Article{}).Where("id = ?", a.ID).
+				Update("likes", actual).Error; err != nil {
+				return fixed, err
+			}
+			fixed++
+		}
+	}
+	return fixed, nil
+}
+
+func recountBlogLikes(db *gorm.DB) (int, error) {
+	fixed := 0
+	var lastID uint
+	for {
+		var blogs []models.Blog
+		if err := db.Where("id > ?", lastID).Order("id asc").Limit(likesRecountBatchSize).Find(&blogs).Error; err != nil {
+			return fixed, err
+		}
+		if len(blogs) == 0 {
+			break
+		}
+
+		for _, b := range blogs {
+			lastID = b.ID
+
+			var actual int64
+			if err := db.Model(&models.Reaction{}).
+				Where("blog_id = ? AND reaction_type = ?", b.ID, models.ReactionLike).
+				Count(&actual).Error; err != nil {
+				return fixed, err
+			}
+			if int64(b.Likes) == actual {
+				continue
+			}
+			if err := db.Model(&models.Blog{}).Where("id = ?", b.ID).
+				Update("likes", actual).Error; err != nil {
+				return fixed, err
+			}
+			fixed++
+		}
+	}
+	return fixed, nil
+}
@@ -0,0 +1,264 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/1055373165/blog/models"
+	"gorm.io/gorm"
+)
+
+func TestToggleArticleReactionLikeSyncsLegacyCounter(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Article{}, &models.Reaction{})
+
+	article := models.Article{AuthorID: 1, Title: "A", Slug: "a", Status: models.ArticleStatusPublished}
+	db.Create(&article)
+
+	reacted, err := ToggleArticleReaction(db, 42, article.ID, models.ReactionLike)
+	if err != nil {
+		t.Fatalf("ToggleArticleReaction returned error: %v", err)
+	}
+	if !reacted {
+		t.Fatal("expected reacted=true after first toggle")
+	}
+
+	var reloaded models.Article
+	db.First(&reloaded, article.ID)
+	if reloaded.Likes != 1 {
+		t.Errorf("expected likes 1, got %d", reloaded.Likes)
+	}
+
+	reacted, err = ToggleArticleReaction(db, 42, article.ID, models.ReactionLike)
+	if err != nil {
+		t.Fatalf("ToggleArticleReaction returned error: %v", err)
+	}
+	if reacted {
+		t.Fatal("expected reacted=false after second toggle")
+	}
+
+	db.First(&reloaded, article.ID)
+	if reloaded.Likes != 0 {
+		t.Errorf("expected likes 0 after untoggling, got %d", reloaded.Likes)
+	}
+}
+
+func TestToggleArticleReactionNonLikeTypeLeavesLegacyCounterAlone(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Article{}, &models.Reaction{})
+
+	article := models.Article{AuthorID: 1, Title: "A", Slug: "a", Status: models.ArticleStatusPublished}
+	db.Create(&article)
+
+	if _, err := ToggleArticleReaction(db, 42, article.ID, models.ReactionHeart); err != nil {
+		t.Fatalf("ToggleArticleReaction returned error: %v", err)
+	}
+
+	var reloaded models.Article
+	db.First(&reloaded, article.ID)
+	if reloaded.Likes != 0 {
+		t.Errorf("expected likes to stay 0 for a non-like reaction, got %d", reloaded.Likes)
+	}
+}
+
+func TestToggleArticleReactionRejectsInvalidType(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Article{}, &models.Reaction{})
+
+	article := models.Article{AuthorID: 1, Title: "A", Slug: "a", Status: models.ArticleStatusPublished}
+	db.Create(&article)
+
+	if _, err := ToggleArticleReaction(db, 42, article.ID, "wat"); err != ErrInvalidReactionType {
+		t.Errorf("expected ErrInvalidReactionType, got %v", err)
+	}
+}
+
+func TestToggleArticleReactionOnUnpublishedArticleNotFound(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Article{}, &models.Reaction{})
+
+	draft := models.Article{AuthorID: 1, Title: "Draft", Slug: "draft", Status: models.ArticleStatusDraft}
+	db.Create(&draft)
+
+	if _, err := ToggleArticleReaction(db, 1, draft.ID, models.ReactionLike); err != ErrArticleNotFound {
+		t.Errorf("expected ErrArticleNotFound, got %v", err)
+	}
+}
+
+func TestGetArticleReactionsCountsAndMine(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Article{}, &models.Reaction{})
+
+	article := models.Article{AuthorID: 1, Title: "A", Slug: "a", Status: models.ArticleStatusPublished}
+	db.Create(&article)
+
+	if _, err := ToggleArticleReaction(db, 1, article.ID, models.ReactionHeart); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ToggleArticleReaction(db, 2, article.ID, models.ReactionHeart); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ToggleArticleReaction(db, 1, article.ID, models.ReactionCelebrate); err != nil {
+		t.Fatal(err)
+	}
+
+	counts, mine, err := GetArticleReactions(db, article.ID, 1)
+	if err != nil {
+		t.Fatalf("GetArticleReactions returned error: %v", err)
+	}
+	if counts[models.ReactionHeart] != 2 {
+		t.Errorf("expected 2 heart reactions, got %d", counts[models.ReactionHeart])
+	}
+	if counts[models.ReactionCelebrate] != 1 {
+		t.Errorf("expected 1 celebrate reaction, got %d", counts[models.ReactionCelebrate])
+	}
+	if len(mine) != 2 {
+		t.Errorf("expected caller to have 2 reactions, got %v", mine)
+	}
+
+	_, anonymousMine, err := GetArticleReactions(db, article.ID, 0)
+	if err != nil {
+		t.Fatalf("GetArticleReactions returned error: %v", err)
+	}
+	if len(anonymousMine) != 0 {
+		t.Errorf("expected no 'mine' reactions for an unauthenticated caller, got %v", anonymousMine)
+	}
+}
+
+func TestToggleBlogReactionLikeSyncsLegacyCounter(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Blog{}, &models.Reaction{})
+
+	blog := models.Blog{AuthorID: 1, Title: "B", Slug: "b", MediaURL: "http://example.com/b.mp3"}
+	db.Create(&blog)
+
+	reacted, err := ToggleBlogReaction(db, 42, blog.ID, models.ReactionLike)
+	if err != nil {
+		t.Fatalf("ToggleBlogReaction returned error: %v", err)
+	}
+	if !reacted {
+		t.Fatal("expected reacted=true after first toggle")
+	}
+
+	var reloaded models.Blog
+	db.First(&reloaded, blog.ID)
+	if reloaded.Likes != 1 {
+		t.Errorf("expected likes 1, got %d", reloaded.Likes)
+	}
+}
+
+func TestToggleBlogReactionOnMissingBlogNotFound(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Blog{}, &models.Reaction{})
+
+	if _, err := ToggleBlogReaction(db, 1, 999, models.ReactionLike); err != ErrBlogNotFound {
+		t.Errorf("expected ErrBlogNotFound, got %v", err)
+	}
+}
+
+// TestReactionUniqueIndexRejectsDuplicateInsert confirms the unique index
+// added to models.Reaction actually stops a second identical
+// (article, user, type) row from being inserted, and that it surfaces as
+// gorm.ErrDuplicatedKey (via gorm.Config.TranslateError) rather than a raw
+// driver error - the condition toggleReaction's duplicate-key race handling
+// depends on.
+func TestReactionUniqueIndexRejectsDuplicateInsert(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Article{}, &models.Reaction{})
+
+	article := models.Article{AuthorID: 1, Title: "A", Slug: "a", Status: models.ArticleStatusPublished}
+	db.Create(&article)
+
+	if err := db.Create(&models.Reaction{ArticleID: &article.ID, UserID: 42, ReactionType: models.ReactionLike}).Error; err != nil {
+		t.Fatalf("failed to create first reaction: %v", err)
+	}
+
+	err := db.Create(&models.Reaction{ArticleID: &article.ID, UserID: 42, ReactionType: models.ReactionLike}).Error
+	if !errors.Is(err, gorm.ErrDuplicatedKey) {
+		t.Errorf("expected gorm.ErrDuplicatedKey for a duplicate reaction, got %v", err)
+	}
+}
+
+// TestToggleReactionDuplicateKeyRaceReportsUnchanged simulates the losing
+// side of toggleReaction's check-then-create race directly: it builds the
+// Reaction row toggleReaction would have tried to create, inserts it through
+// a second handle first (as a concurrent winner would), then asks
+// toggleReaction to create that same row. toggleReaction should treat the
+// resulting gorm.ErrDuplicatedKey as "already reacted" and report
+// changed=false, so its caller doesn't re-apply a counter delta the winner
+// already applied.
+func TestToggleReactionDuplicateKeyRaceReportsUnchanged(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Article{}, &models.Reaction{})
+
+	article := models.Article{AuthorID: 1, Title: "A", Slug: "a", Status: models.ArticleStatusPublished}
+	db.Create(&article)
+
+	r := &models.Reaction{ArticleID: &article.ID, UserID: 42, ReactionType: models.ReactionLike}
+
+	// This mimics toggleReaction's own query finding no row yet, then a
+	// concurrent call winning the insert before this one's Create runs.
+	if err := db.Create(&models.Reaction{ArticleID: &article.ID, UserID: 42, ReactionType: models.ReactionLike}).Error; err != nil {
+		t.Fatalf("failed to seed the concurrent winner's row: %v", err)
+	}
+
+	reacted, changed, err := toggleReactionCreate(db, r)
+	if err != nil {
+		t.Fatalf("toggleReactionCreate returned error: %v", err)
+	}
+	if !reacted {
+		t.Error("expected reacted=true since a reaction now exists")
+	}
+	if changed {
+		t.Error("expected changed=false for the race loser, since the row already existed")
+	}
+
+	var count int64
+	db.Model(&models.Reaction{}).Where("article_id = ? AND user_id = ?", article.ID, 42).Count(&count)
+	if count != 1 {
+		t.Errorf("expected exactly 1 reaction row, got %d", count)
+	}
+}
+
+func TestRecountLikesFixesDriftedArticleAndBlogCounters(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Article{}, &models.Blog{}, &models.Reaction{})
+
+	driftedArticle := models.Article{AuthorID: 1, Title: "A", Slug: "a", Status: models.ArticleStatusPublished, Likes: 5}
+	db.Create(&driftedArticle)
+	correctArticle := models.Article{AuthorID: 1, Title: "B", Slug: "b", Status: models.ArticleStatusPublished, Likes: 1}
+	db.Create(&correctArticle)
+	db.Create(&models.Reaction{ArticleID: &correctArticle.ID, UserID: 1, ReactionType: models.ReactionLike})
+
+	driftedBlog := models.Blog{AuthorID: 1, Title: "C", Slug: "c", MediaURL: "http://example.com/c.mp3", Likes: 3}
+	db.Create(&driftedBlog)
+	db.Create(&models.Reaction{BlogID: &driftedBlog.ID, UserID: 1, ReactionType: models.ReactionLike})
+	db.Create(&models.Reaction{BlogID: &driftedBlog.ID, UserID: 2, ReactionType: models.ReactionLike})
+
+	result, err := RecountLikes(db)
+	if err != nil {
+		t.Fatalf("RecountLikes returned error: %v", err)
+	}
+	if result.ArticlesFixed != 1 {
+		t.Errorf("expected 1 article fixed, got %d", result.ArticlesFixed)
+	}
+	if result.BlogsFixed != 1 {
+		t.Errorf("expected 1 blog fixed, got %d", result.BlogsFixed)
+	}
+
+	var reloadedArticle models.Article
+	db.First(&reloadedArticle, driftedArticle.ID)
+	if reloadedArticle.Likes != 0 {
+		t.Errorf("expected drifted article likes corrected to 0, got %d", reloadedArticle.Likes)
+	}
+	var reloadedCorrectArticle models.Article
+	db.First(&reloadedCorrectArticle, correctArticle.ID)
+	if reloadedCorrectArticle.Likes != 1 {
+		t.Errorf("expected already-correct article likes to stay 1, got %d", reloadedCorrectArticle.Likes)
+	}
+	var reloadedBlog models.Blog
+	db.First(&reloadedBlog, driftedBlog.ID)
+	if reloadedBlog.Likes != 2 {
+		t.Errorf("expected drifted blog likes corrected to 2, got %d", reloadedBlog.Likes)
+	}
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/1055373165/blog/models"
+	"gorm.io/gorm"
+)
+
+// MaxSavedSearchesPerUser bounds how many SavedSearch rows a user may have.
+const MaxSavedSearchesPerUser = 20
+
+var ErrSavedSearchLimitReached = errors.New("saved search limit reached")
+
+// CreateSavedSearch saves a new search for search.UserID, rejecting the
+// MaxSavedSearchesPerUser+1'th.
+func CreateSavedSearch(db *gorm.DB, search *models.SavedSearch) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.SavedSearch{}).Where("user_id = ?", search.UserID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= MaxSavedSearchesPerUser {
+			return ErrSavedSearchLimitReached
+		}
+		return tx.Create(search).Error
+	})
+}
+
+// ArticleMatch is a single new article matched by a saved search.
+type ArticleMatch struct {
+	ArticleID uint   `json:"article_id"`
+	Title     string `json:"title"`
+	Slug      string `json:"slug"`
+}
+
+// RunSavedSearchAgainstArticles finds published articles created after
+// since whose title or content contains query (case-insensitive). This
+// is a plain substring match rather than the Bleve-backed pipeline
+// behind /api/search, since Articles aren't indexed there.
+func RunSavedSearchAgainstArticles(db *gorm.DB, query string, since time.Time) ([]ArticleMatch, error) {
+	var articles []models.Article
+	like := "%" + query + "%"
+	err := db.Where("status = ? AND created_at > ? AND (title LIKE ? OR content LIKE ?)",
+		models.ArticleStatusPublished, since, like, like).
+		Order("created_at asc").
+		Find(&articles).Error
+	if err != nil {
+		return nil, err
+	}
+	matches := make([]ArticleMatch, len(articles))
+	for i, a := range articles {
+		matches[i] = ArticleMatch{ArticleID: a.ID, Title: a.Title, Slug: a.Slug}
+	}
+	return matches, nil
+}
+
+// NotifySavedSearches re-runs every notify-enabled SavedSearch against
+// articles published since its LastNotifiedAt, and logs a summary of
+// new matches in lieu of sending an email: this tree has no SMTP/email
+// integration (see README "Known gaps"). LastNotifiedAt always advances
+// to now, even with zero matches, so a repeatedly-edited article isn't
+// re-alerted on a later run.
+func NotifySavedSearches(db *gorm.DB, logger *slog.Logger, now time.Time) error {
+	var searches []models.SavedSearch
+	if err := db.Where("notify = ?", true).Find(&searches).Error; err != nil {
+		return err
+	}
+	for _, s := range searches {
+		since := s.CreatedAt
+		if s.LastNotifiedAt != nil {
+			since = *s.LastNotifiedAt
+		}
+		matches, err := RunSavedSearchAgainstArticles(db, s.Query, since)
+		if err != nil {
+			logger.Error("saved search notify failed", "saved_search_id", s.ID, "error", err)
+			continue
+		}
+		if len(matches) > 0 {
+			logger.Info("saved search new matches", "saved_search_id", s.ID, "user_id", s.UserID, "query", s.Query, "match_count", len(matches))
+		}
+		if err := db.Model(&models.SavedSearch{}).Where("id = ?", s.ID).
+			Update("last_notified_at", now).Error; err != nil {
+			logger.Error("saved search failed to advance last_notified_at", "saved_search_id", s.ID, "error", err)
+		}
+	}
+	return nil
+}
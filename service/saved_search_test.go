@@ -0,0 +1,71 @@
+package service
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/1055373165/blog/models"
+)
+
+func TestCreateSavedSearchEnforcesPerUserLimit(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.SavedSearch{})
+
+	for i := 0; i < MaxSavedSearchesPerUser; i++ {
+		s := models.SavedSearch{UserID: 1, Name: "s", Query: "go"}
+		if err := CreateSavedSearch(db, &s); err != nil {
+			t.Fatalf("CreateSavedSearch returned error on iteration %d: %v", i, err)
+		}
+	}
+
+	over := models.SavedSearch{UserID: 1, Name: "one too many", Query: "go"}
+	if err := CreateSavedSearch(db, &over); err != ErrSavedSearchLimitReached {
+		t.Errorf("expected ErrSavedSearchLimitReached, got %v", err)
+	}
+
+	// A different user is unaffected by the first user's cap.
+	other := models.SavedSearch{UserID: 2, Name: "s", Query: "go"}
+	if err := CreateSavedSearch(db, &other); err != nil {
+		t.Errorf("expected other user's save to succeed, got %v", err)
+	}
+}
+
+func TestRunSavedSearchAgainstArticlesFiltersBySinceAndQuery(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Article{})
+
+	cutoff := time.Now().Add(-time.Hour)
+	db.Create(&models.Article{AuthorID: 1, Title: "old kubernetes post", Slug: "old", Status: models.ArticleStatusPublished, CreatedAt: cutoff.Add(-time.Minute)})
+	db.Create(&models.Article{AuthorID: 1, Title: "new kubernetes operator", Slug: "new", Status: models.ArticleStatusPublished, CreatedAt: cutoff.Add(time.Minute)})
+	db.Create(&models.Article{AuthorID: 1, Title: "new unrelated post", Slug: "unrelated", Status: models.ArticleStatusPublished, CreatedAt: cutoff.Add(time.Minute)})
+
+	matches, err := RunSavedSearchAgainstArticles(db, "kubernetes", cutoff)
+	if err != nil {
+		t.Fatalf("RunSavedSearchAgainstArticles returned error: %v", err)
+	}
+	if len(matches) != 1 || matches[0].Slug != "new" {
+		t.Errorf("expected only the new kubernetes article, got %+v", matches)
+	}
+}
+
+func TestNotifySavedSearchesAdvancesLastNotifiedAtEvenWithoutMatches(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.SavedSearch{}, &models.Article{})
+
+	saved := models.SavedSearch{UserID: 1, Name: "s", Query: "nonexistent", Notify: true}
+	db.Create(&saved)
+
+	logger := slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+	now := time.Now()
+	if err := NotifySavedSearches(db, logger, now); err != nil {
+		t.Fatalf("NotifySavedSearches returned error: %v", err)
+	}
+
+	var reloaded models.SavedSearch
+	db.First(&reloaded, saved.ID)
+	if reloaded.LastNotifiedAt == nil || !reloaded.LastNotifiedAt.Equal(now) {
+		t.Errorf("expected LastNotifiedAt advanced to %v, got %v", now, reloaded.LastNotifiedAt)
+	}
+}
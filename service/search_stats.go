@@ -0,0 +1,86 @@
+package service
+
+import (
+	"github.com/1055373165/blog/models"
+	"gorm.io/gorm"
+)
+
+// PopularQuery pairs a search query with how many times it was recorded.
+type PopularQuery struct {
+	Query string `json:"query"`
+	Count int64  `json:"count"`
+}
+
+// SearchStatsResult is the payload for GetSearchStats: the top queries by
+// frequency, the most recent queries, and how many Record calls were
+// dropped by searchstats.Recorder because its buffer was full.
+type SearchStatsResult struct {
+	Popular []PopularQuery            `json:"popular"`
+	Recent  []models.SearchStatistics `json:"recent"`
+	Dropped uint64                    `json:"dropped"`
+}
+
+// GetSearchStats returns the top limit queries by frequency and the most
+// recent limit queries recorded by searchstats.Recorder. dropped is the
+// caller's searchstats.Recorder.Dropped() value, merged into the result
+// here so handlers don't need a separate field for it.
+func GetSearchStats(db *gorm.DB, limit int, dropped uint64) (SearchStatsResult, error) {
+	var popular []PopularQuery
+	if err := db.Model(&models.SearchStatistics{}).
+		Select("query, COUNT(*) as count").
+		Group("query").
+		Order("count desc").
+		Limit(limit).
+		Scan(&popular).Error; err != nil {
+		return SearchStatsResult{}, err
+	}
+
+	var recent []models.SearchStatistics
+	if err := db.Order("created_at desc").Limit(limit).Find(&recent).Error; err != nil {
+		return SearchStatsResult{}, err
+	}
+
+	return SearchStatsResult{Popular: popular, Recent: recent, Dropped: dropped}, nil
+}
+
+// SearchStatsWindow is GetSearchStatsWindow's payload: the top queries and
+// most recent queries recorded within r, plus how many queries were
+// recorded in total during r. Dropped has no created_at of its own -
+// searchstats.Recorder only tracks a running total, not per-drop
+// timestamps - so it isn't windowed here; see GetSearchStats for that.
+type SearchStatsWindow struct {
+	Popular    []PopularQuery            `json:"popular"`
+	Recent     []models.SearchStatistics `json:"recent"`
+	QueryCount int64                     `json:"query_count"`
+}
+
+// GetSearchStatsWindow returns the top limit queries by frequency and the
+// most recent limit queries, both restricted to r, plus the total number
+// of queries recorded within r.
+func GetSearchStatsWindow(db *gorm.DB, limit int, r DateRange) (SearchStatsWindow, error) {
+	var popular []PopularQuery
+	if err := db.Model(&models.SearchStatistics{}).
+		Select("query, COUNT(*) as count").
+		Where("created_at >= ? AND created_at <= ?", r.From, r.To).
+		Group("query").
+		Order("count desc").
+		Limit(limit).
+		Scan(&popular).Error; err != nil {
+		return SearchStatsWindow{}, err
+	}
+
+	var recent []models.SearchStatistics
+	if err := db.Where("created_at >= ? AND created_at <= ?", r.From, r.To).
+		Order("created_at desc").Limit(limit).Find(&recent).Error; err != nil {
+		return SearchStatsWindow{}, err
+	}
+
+	var count int64
+	if err := db.Model(&models.SearchStatistics{}).
+		Where("created_at >= ? AND created_at <= ?", r.From, r.To).
+		Count(&count).Error; err != nil {
+		return SearchStatsWindow{}, err
+	}
+
+	return SearchStatsWindow{Popular: popular, Recent: recent, QueryCount: count}, nil
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/1055373165/blog/models"
+)
+
+func TestGetSearchStatsWindowComparesTwoMonths(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.SearchStatistics{})
+
+	june, err := NewDateRange(
+		time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, 6, 30, 23, 59, 59, 999999999, time.UTC),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	may := june.Previous()
+
+	seed := func(query string, createdAt time.Time) {
+		s := models.SearchStatistics{Query: query, ResultCount: 1, CreatedAt: createdAt}
+		if err := db.Create(&s).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Three June queries ("golang" twice), one May query.
+	seed("golang", june.From.AddDate(0, 0, 1))
+	seed("golang", june.From.AddDate(0, 0, 2))
+	seed("rust", june.From.AddDate(0, 0, 3))
+	seed("rust", may.From.AddDate(0, 0, 1))
+
+	juneWindow, err := GetSearchStatsWindow(db, 10, june)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mayWindow, err := GetSearchStatsWindow(db, 10, may)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if juneWindow.QueryCount != 3 {
+		t.Errorf("expected 3 queries in June, got %d", juneWindow.QueryCount)
+	}
+	if mayWindow.QueryCount != 1 {
+		t.Errorf("expected 1 query in May, got %d", mayWindow.QueryCount)
+	}
+	if delta := juneWindow.QueryCount - mayWindow.QueryCount; delta != 2 {
+		t.Errorf("expected query_count delta of 2, got %d", delta)
+	}
+
+	if len(juneWindow.Popular) == 0 || juneWindow.Popular[0].Query != "golang" || juneWindow.Popular[0].Count != 2 {
+		t.Fatalf("expected golang to be June's top query with count 2, got %+v", juneWindow.Popular)
+	}
+	if len(juneWindow.Recent) != 3 {
+		t.Errorf("expected 3 recent queries in June, got %d", len(juneWindow.Recent))
+	}
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"log"
+	"time"
+
+	"github.com/1055373165/blog/models"
+	"github.com/1055373165/blog/search"
+	"github.com/blevesearch/bleve/v2"
+	"gorm.io/gorm"
+)
+
+// searchConsistencyBatchSize bounds how many Blog rows diffSearchIndex
+// loads per page when walking the table, the same keyset-pagination
+// pattern as NormalizeArticleContentURLs.
+const searchConsistencyBatchSize = 100
+
+// searchConsistencySampleLimit bounds how many IDs a
+// SearchConsistencyBucket carries as a sample, so a large discrepancy
+// doesn't inflate the report into a full ID dump.
+const searchConsistencySampleLimit = 20
+
+// SearchConsistencyBucket is one category of discrepancy between the
+// Blog table and the search index: how many rows fall into it, and a
+// bounded sample of their IDs.
+type SearchConsistencyBucket struct {
+	Count     int    `json:"count"`
+	SampleIDs []uint `json:"sample_ids"`
+}
+
+func (b *SearchConsistencyBucket) add(id uint) {
+	b.Count++
+	if len(b.SampleIDs) < searchConsistencySampleLimit {
+		b.SampleIDs = append(b.SampleIDs, id)
+	}
+}
+
+// SearchConsistencyReport compares the Blog table against the search
+// index (the only content type search/index.go indexes; see README
+// "Known gaps"): Missing is a visible Blog with no indexed document,
+// Orphaned is an indexed document whose Blog has expired or no longer
+// exists, and Stale is an indexed document whose UpdatedAt predates its
+// Blog's current UpdatedAt.
+type SearchConsistencyReport struct {
+	Missing  SearchConsistencyBucket `json:"missing"`
+	Orphaned SearchConsistencyBucket `json:"orphaned"`
+	Stale    SearchConsistencyBucket `json:"stale"`
+}
+
+// SearchConsistencyRepairResult reports how many documents
+// RepairSearchConsistency indexed, deleted, or reindexed.
+type SearchConsistencyRepairResult struct {
+	Indexed   int `json:"indexed"`
+	Deleted   int `json:"deleted"`
+	Reindexed int `json:"reindexed"`
+}
+
+// searchDiffHandler is notified by diffSearchIndex for each discrepancy
+// it finds, so CheckSearchConsistency and RepairSearchConsistency can
+// share the same walk and differ only in what they do with each case.
+type searchDiffHandler struct {
+	onMissing  func(models.Blog) error
+	onOrphaned func(id uint) error
+	onStale    func(models.Blog) error
+}
+
+// CheckSearchConsistency reports, without changing anything, how the
+// Blog table and the search index currently disagree.
+func CheckSearchConsistency(db *gorm.DB) (SearchConsistencyReport, error) {
+	var report SearchConsistencyReport
+	err := diffSearchIndex(db, searchDiffHandler{
+		onMissing:  func(b models.Blog) error { report.Missing.add(b.ID); return nil },
+		onOrphaned: func(id uint) error { report.Orphaned.add(id); return nil },
+		onStale:    func(b models.Blog) error { report.Stale.add(b.ID); return nil },
+	})
+	return report, err
+}
+
+// RepairSearchConsistency fixes exactly the discrepancies
+// CheckSearchConsistency would report: it indexes every missing Blog,
+// deletes every orphaned document, and reindexes every stale one,
+// without rebuilding the index from scratch.
+func RepairSearchConsistency(db *gorm.DB) (SearchConsistencyRepairResult, error) {
+	var result SearchConsistencyRepairResult
+	err := diffSearchIndex(db, searchDiffHandler{
+		onMissing: func(b models.Blog) error {
+			if err := search.IndexBlog(&b); err != nil {
+				return err
+			}
+			result.Indexed++
+			return nil
+		},
+		onOrphaned: func(id uint) error {
+			if err := search.DeleteBlog(id); err != nil {
+				return err
+			}
+			result.Deleted++
+			return nil
+		},
+		onStale: func(b models.Blog) error {
+			if err := search.IndexBlog(&b); err != nil {
+				return err
+			}
+			result.Reindexed++
+			return nil
+		},
+	})
+	return result, err
+}
+
+// TriggerSearchIndexRebuildIfStale rebuilds the search index from
+// scratch against db, in the background, if indexPath's on-disk index
+// predates search.CurrentSchemaVersion (see search.NeedsRebuild) - e.g.
+// after a deploy that added a new indexed field. The old index keeps
+// serving every Search/IndexBlog/DeleteBlog call until the new one is
+// fully populated and swapped in by search.Rebuild; errors are logged,
+// not returned, since there's no caller left to receive them by the
+// time the rebuild finishes.
+func TriggerSearchIndexRebuildIfStale(db *gorm.DB, indexPath string) {
+	if !search.NeedsRebuild(indexPath) {
+		return
+	}
+	go func() {
+		if err := search.Rebuild(indexPath, func(newIdx bleve.Index) error {
+			return reindexAllBlogsInto(db, newIdx)
+		}); err != nil {
+			log.Printf("search index rebuild for %s failed: %v", indexPath, err)
+		}
+	}()
+}
+
+// reindexAllBlogsInto walks every Blog row, keyset-paginated
+// searchConsistencyBatchSize at a time, and indexes each one into idx -
+// used to populate a freshly rebuilt index from scratch.
+func reindexAllBlogsInto(db *gorm.DB, idx bleve.Index) error {
+	var lastID uint
+	for {
+		var blogs []models.Blog
+		if err := db.Where("id > ?", lastID).Order("id asc").Limit(searchConsistencyBatchSize).Find(&blogs).Error; err != nil {
+			return err
+		}
+		if len(blogs) == 0 {
+			return nil
+		}
+		for _, b := range blogs {
+			lastID = b.ID
+			if err := search.IndexBlogInto(idx, &b); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// diffSearchIndex walks every indexed blog document via
+// search.ForEachIndexedBlog (paginated, never loading the whole index
+// into memory) and every Blog row (keyset-paginated, searchConsistencyBatchSize
+// at a time), reconciling the two: h.onMissing fires for a visible Blog
+// with no indexed document, h.onStale for one whose indexed document
+// predates its current UpdatedAt, and h.onOrphaned for an indexed
+// document whose Blog has expired or whose row no longer exists at all.
+func diffSearchIndex(db *gorm.DB, h searchDiffHandler) error {
+	indexed := make(map[uint]time.Time)
+	if err := search.ForEachIndexedBlog(func(ref search.IndexedBlogRef) error {
+		indexed[ref.ID] = ref.UpdatedAt
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var lastID uint
+	for {
+		var blogs []models.Blog
+		if err := db.Where("id > ?", lastID).Order("id asc").Limit(searchConsistencyBatchSize).Find(&blogs).Error; err != nil {
+			return err
+		}
+		if len(blogs) == 0 {
+			break
+		}
+
+		for _, b := range blogs {
+			lastID = b.ID
+			indexedAt, ok := indexed[b.ID]
+			delete(indexed, b.ID)
+
+			visible := b.ExpiresAt == nil || b.ExpiresAt.After(now)
+			switch {
+			case !visible && ok:
+				if err := h.onOrphaned(b.ID); err != nil {
+					return err
+				}
+			case visible && !ok:
+				if err := h.onMissing(b); err != nil {
+					return err
+				}
+			case visible && ok && indexedAt.Before(b.UpdatedAt.Truncate(time.Second)):
+				if err := h.onStale(b); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	// Whatever's left in indexed has no matching Blog row at all - it
+	// was hard-deleted (or never existed) outside of this scan.
+	for id := range indexed {
+		if err := h.onOrphaned(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/1055373165/blog/models"
+	"github.com/1055373165/blog/search"
+)
+
+// TestMain opens a single on-disk index for the whole package test
+// binary, since search.Init's sync.Once only ever takes effect once per
+// process - the same constraint production code lives with (one index,
+// one process). Removing its directory before every test has finished
+// would pull the rug out from under an index that's still open.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "search-consistency-test-*")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := search.Init(dir); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+func TestCheckSearchConsistencyFindsMissingOrphanedAndStale(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Blog{})
+
+	missing := models.Blog{Title: "Missing", Slug: "missing"}
+	stale := models.Blog{Title: "Stale", Slug: "stale"}
+	current := models.Blog{Title: "Current", Slug: "current"}
+	expired := models.Blog{Title: "Expired", Slug: "expired", ExpiresAt: timePtr(time.Now().Add(-time.Hour))}
+	for _, b := range []*models.Blog{&missing, &stale, &current, &expired} {
+		if err := db.Create(b).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The index is shared across this package's whole test binary (see
+	// TestMain), so every document this test indexes must be cleaned up
+	// afterwards - otherwise it leaks into another test's report as an
+	// orphan with no matching row in that test's own throwaway database.
+	t.Cleanup(func() {
+		search.DeleteBlog(current.ID)
+		search.DeleteBlog(stale.ID)
+		search.DeleteBlog(expired.ID)
+		search.DeleteBlog(999999)
+	})
+
+	// missing: never indexed.
+	// current: indexed with its real, current UpdatedAt.
+	if err := search.IndexBlog(&current); err != nil {
+		t.Fatal(err)
+	}
+	// stale: indexed, then the row's UpdatedAt moves forward without a
+	// reindex - simulating a crash between the DB write and the index
+	// write. UpdatedAt is set explicitly, well clear of the indexed
+	// value, rather than relying on GORM's auto-timestamp landing in a
+	// different wall-clock second than the one just indexed.
+	if err := search.IndexBlog(&stale); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(&stale).Updates(map[string]any{"title": "Stale Updated", "updated_at": time.Now().Add(time.Hour)}).Error; err != nil {
+		t.Fatal(err)
+	}
+	// expired: indexed while visible, then expired without ever being
+	// removed from the index.
+	if err := search.IndexBlog(&expired); err != nil {
+		t.Fatal(err)
+	}
+	// orphaned-by-deletion: indexed, then its row is gone entirely.
+	deleted := models.Blog{ID: 999999, Title: "Deleted", Slug: "deleted"}
+	if err := search.IndexBlog(&deleted); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := CheckSearchConsistency(db)
+	if err != nil {
+		t.Fatalf("CheckSearchConsistency returned error: %v", err)
+	}
+
+	if report.Missing.Count != 1 || report.Missing.SampleIDs[0] != missing.ID {
+		t.Errorf("got Missing %+v, want 1 row: %d", report.Missing, missing.ID)
+	}
+	if report.Stale.Count != 1 || report.Stale.SampleIDs[0] != stale.ID {
+		t.Errorf("got Stale %+v, want 1 row: %d", report.Stale, stale.ID)
+	}
+	if report.Orphaned.Count != 2 {
+		t.Errorf("got Orphaned %+v, want 2 (expired + hard-deleted)", report.Orphaned)
+	}
+}
+
+func TestRepairSearchConsistencyFixesEveryDiscrepancy(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Blog{})
+
+	missing := models.Blog{Title: "Missing", Slug: "repair-missing"}
+	stale := models.Blog{Title: "Stale", Slug: "repair-stale"}
+	expired := models.Blog{Title: "Expired", Slug: "repair-expired", ExpiresAt: timePtr(time.Now().Add(-time.Hour))}
+	for _, b := range []*models.Blog{&missing, &stale, &expired} {
+		if err := db.Create(b).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Cleanup(func() {
+		search.DeleteBlog(missing.ID)
+		search.DeleteBlog(stale.ID)
+		search.DeleteBlog(expired.ID)
+	})
+
+	if err := search.IndexBlog(&stale); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(&stale).Updates(map[string]any{"title": "Stale Updated", "updated_at": time.Now().Add(time.Hour)}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := search.IndexBlog(&expired); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := RepairSearchConsistency(db)
+	if err != nil {
+		t.Fatalf("RepairSearchConsistency returned error: %v", err)
+	}
+	if result.Indexed != 1 {
+		t.Errorf("got Indexed %d, want 1", result.Indexed)
+	}
+	if result.Reindexed != 1 {
+		t.Errorf("got Reindexed %d, want 1", result.Reindexed)
+	}
+	if result.Deleted != 1 {
+		t.Errorf("got Deleted %d, want 1", result.Deleted)
+	}
+
+	report, err := CheckSearchConsistency(db)
+	if err != nil {
+		t.Fatalf("CheckSearchConsistency returned error: %v", err)
+	}
+	if report.Missing.Count != 0 || report.Orphaned.Count != 0 || report.Stale.Count != 0 {
+		t.Errorf("expected a clean report after repair, got %+v", report)
+	}
+}
+
+func timePtr(t time.Time) *time.Time { return &t }
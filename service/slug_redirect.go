@@ -0,0 +1,68 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/1055373165/blog/models"
+	"gorm.io/gorm"
+)
+
+// ErrSlugRedirectCycle is returned by RecordSlugRedirect when oldSlug
+// and newSlug would create a redirect cycle.
+var ErrSlugRedirectCycle = errors.New("slug redirect would create a cycle")
+
+// RecordSlugRedirect notes that entityType's oldSlug now lives at
+// newSlug. Any existing redirect chain that pointed at oldSlug is
+// collapsed to point directly at newSlug, and changes that would
+// create a cycle are rejected. A no-op if oldSlug == newSlug.
+func RecordSlugRedirect(db *gorm.DB, entityType, oldSlug, newSlug string) error {
+	if oldSlug == newSlug {
+		return nil
+	}
+	if resolveSlugChain(db, entityType, newSlug) == oldSlug {
+		return ErrSlugRedirectCycle
+	}
+
+	if err := db.Model(&models.SlugRedirect{}).
+		Where("entity_type = ? AND new_slug = ?", entityType, oldSlug).
+		Update("new_slug", newSlug).Error; err != nil {
+		return err
+	}
+
+	var existing models.SlugRedirect
+	err := db.Where("entity_type = ? AND old_slug = ?", entityType, oldSlug).First(&existing).Error
+	if err == nil {
+		return db.Model(&existing).Update("new_slug", newSlug).Error
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return db.Create(&models.SlugRedirect{EntityType: entityType, OldSlug: oldSlug, NewSlug: newSlug}).Error
+}
+
+// ResolveSlugRedirect returns entityType's current slug for a
+// possibly-stale oldSlug, and whether a redirect was found.
+func ResolveSlugRedirect(db *gorm.DB, entityType, oldSlug string) (string, bool) {
+	var redirect models.SlugRedirect
+	if err := db.Where("entity_type = ? AND old_slug = ?", entityType, oldSlug).First(&redirect).Error; err != nil {
+		return "", false
+	}
+	return redirect.NewSlug, true
+}
+
+// resolveSlugChain follows the old_slug -> new_slug chain starting at
+// slug and returns the final slug reached, or slug itself if it isn't
+// a redirect source.
+func resolveSlugChain(db *gorm.DB, entityType, slug string) string {
+	visited := map[string]bool{}
+	cur := slug
+	for !visited[cur] {
+		visited[cur] = true
+		var redirect models.SlugRedirect
+		if err := db.Where("entity_type = ? AND old_slug = ?", entityType, cur).First(&redirect).Error; err != nil {
+			break
+		}
+		cur = redirect.NewSlug
+	}
+	return cur
+}
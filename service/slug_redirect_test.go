@@ -0,0 +1,53 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/1055373165/blog/models"
+)
+
+func TestRecordSlugRedirectCollapsesChains(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.SlugRedirect{})
+
+	if err := RecordSlugRedirect(db, "article", "a", "b"); err != nil {
+		t.Fatalf("RecordSlugRedirect returned error: %v", err)
+	}
+	if err := RecordSlugRedirect(db, "article", "b", "c"); err != nil {
+		t.Fatalf("RecordSlugRedirect returned error: %v", err)
+	}
+
+	target, ok := ResolveSlugRedirect(db, "article", "a")
+	if !ok || target != "c" {
+		t.Errorf("expected chain a->b->c to collapse to c, got %q (ok=%v)", target, ok)
+	}
+	target, ok = ResolveSlugRedirect(db, "article", "b")
+	if !ok || target != "c" {
+		t.Errorf("expected b to redirect to c, got %q (ok=%v)", target, ok)
+	}
+}
+
+func TestRecordSlugRedirectRejectsCycles(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.SlugRedirect{})
+
+	if err := RecordSlugRedirect(db, "article", "a", "b"); err != nil {
+		t.Fatalf("RecordSlugRedirect returned error: %v", err)
+	}
+	if err := RecordSlugRedirect(db, "article", "b", "a"); err != ErrSlugRedirectCycle {
+		t.Errorf("expected ErrSlugRedirectCycle, got %v", err)
+	}
+}
+
+func TestRecordSlugRedirectIsScopedByEntityType(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.SlugRedirect{})
+
+	if err := RecordSlugRedirect(db, "article", "shared", "shared-new"); err != nil {
+		t.Fatalf("RecordSlugRedirect returned error: %v", err)
+	}
+
+	if _, ok := ResolveSlugRedirect(db, "blog", "shared"); ok {
+		t.Error("expected no redirect for a different entity type sharing the same old slug")
+	}
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"github.com/1055373165/blog/models"
+	"gorm.io/gorm"
+)
+
+// TagCount pairs a Tag with its usage count, used by GetPopularTags.
+type TagCount struct {
+	models.Tag
+	ArticleCount int64 `json:"article_count"`
+}
+
+// GetPopularTags returns tags ordered by how many published articles use
+// them.
+func GetPopularTags(db *gorm.DB) ([]TagCount, error) {
+	var results []TagCount
+	// articles.id, not article_tags.article_id, must be the counted
+	// column: the published-status condition lives on the articles join,
+	// so a row from a tag pinned only to drafts has a non-null
+	// article_tags.article_id but a null articles.id, and only the
+	// latter actually reflects the join filter.
+	err := db.Table("tags").
+		Select("tags.*, COUNT(articles.id) as article_count").
+		Joins("LEFT JOIN article_tags ON article_tags.tag_id = tags.id").
+		Joins("LEFT JOIN articles ON articles.id = article_tags.article_id AND articles.status = ?", models.ArticleStatusPublished).
+		Group("tags.id").
+		Order("article_count desc").
+		Scan(&results).Error
+	return results, err
+}
+
+// GetPopularArticles returns the most-viewed published articles.
+func GetPopularArticles(db *gorm.DB, limit int) ([]models.Article, error) {
+	var articles []models.Article
+	err := db.Where("status = ?", models.ArticleStatusPublished).
+		Order("views desc").
+		Limit(limit).
+		Find(&articles).Error
+	return articles, err
+}
+
+// PopularArticleWindow pairs an Article with how many "like" Reactions it
+// received within the requested window. Article.Views has no per-event
+// timestamp log anywhere in this tree (no ArticleView model, no viewed_at
+// column - see README "Known gaps"), so it can't be windowed; Reaction is
+// the only genuinely timestamped per-article engagement signal, which is
+// why GetPopularArticlesWindow ranks by windowed likes rather than views.
+// Views is still returned on the embedded Article for reference.
+type PopularArticleWindow struct {
+	models.Article
+	WindowLikes int64 `json:"window_likes"`
+}
+
+// GetPopularArticlesWindow returns the limit published articles with the
+// most "like" Reactions created within r, most-liked first.
+func GetPopularArticlesWindow(db *gorm.DB, limit int, r DateRange) ([]PopularArticleWindow, error) {
+	var rows []PopularArticleWindow
+	err := db.Table("articles").
+		Select("articles.*, COUNT(reactions.id) as window_likes").
+		Joins(`LEFT JOIN reactions ON reactions.article_id = articles.id
+			AND reactions.reaction_type = ?
+			AND reactions.created_at >= ? AND reactions.created_at <= ?`,
+			models.ReactionLike, r.From, r.To).
+		Where("articles.status = ?", models.ArticleStatusPublished).
+		Group("articles.id").
+		Order("window_likes desc").
+		Limit(limit).
+		Scan(&rows).Error
+	return rows, err
+}
+
+// GetArticleLikeCounts returns how many "like" Reactions each of
+// articleIDs received within r, keyed by article ID. An ID with no likes
+// in r is simply absent from the result rather than mapped to zero.
+func GetArticleLikeCounts(db *gorm.DB, articleIDs []uint, r DateRange) (map[uint]int64, error) {
+	counts := make(map[uint]int64, len(articleIDs))
+	if len(articleIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		ArticleID uint
+		Count     int64
+	}
+	err := db.Model(&models.Reaction{}).
+		Select("article_id, COUNT(*) as count").
+		Where("article_id IN ? AND reaction_type = ? AND created_at >= ? AND created_at <= ?",
+			articleIDs, models.ReactionLike, r.From, r.To).
+		Group("article_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.ArticleID] = row.Count
+	}
+	return counts, nil
+}
+
+// Stats is the site-wide summary returned by GetStats.
+type Stats struct {
+	ArticleCount int64 `json:"article_count"`
+	BlogCount    int64 `json:"blog_count"`
+	UserCount    int64 `json:"user_count"`
+}
+
+// GetStats aggregates top-level counts across the site.
+func GetStats(db *gorm.DB) (Stats, error) {
+	var s Stats
+	if err := db.Model(&models.Article{}).Count(&s.ArticleCount).Error; err != nil {
+		return s, err
+	}
+	if err := db.Model(&models.Blog{}).Count(&s.BlogCount).Error; err != nil {
+		return s, err
+	}
+	if err := db.Model(&models.User{}).Count(&s.UserCount).Error; err != nil {
+		return s, err
+	}
+	return s, nil
+}
+
+// StatsWindow is the site-wide summary for a single window, as returned by
+// GetStatsWindow: new articles/blogs/users created within it, and how many
+// "like" Reactions were created within it. Unlike Stats, every field is a
+// count of events that happened during the window rather than a
+// point-in-time total, so it means "activity during [From, To]".
+type StatsWindow struct {
+	NewArticles int64 `json:"new_articles"`
+	NewBlogs    int64 `json:"new_blogs"`
+	NewUsers    int64 `json:"new_users"`
+	Likes       int64 `json:"likes"`
+}
+
+// GetStatsWindow aggregates how much happened on the site within r.
+func GetStatsWindow(db *gorm.DB, r DateRange) (StatsWindow, error) {
+	var w StatsWindow
+	if err := db.Model(&models.Article{}).Where("created_at >= ? AND created_at <= ?", r.From, r.To).Count(&w.NewArticles).Error; err != nil {
+		return w, err
+	}
+	if err := db.Model(&models.Blog{}).Where("created_at >= ? AND created_at <= ?", r.From, r.To).Count(&w.NewBlogs).Error; err != nil {
+		return w, err
+	}
+	if err := db.Model(&models.User{}).Where("created_at >= ? AND created_at <= ?", r.From, r.To).Count(&w.NewUsers).Error; err != nil {
+		return w, err
+	}
+	if err := db.Model(&models.Reaction{}).
+		Where("reaction_type = ? AND created_at >= ? AND created_at <= ?", models.ReactionLike, r.From, r.To).
+		Count(&w.Likes).Error; err != nil {
+		return w, err
+	}
+	return w, nil
+}
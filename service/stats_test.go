@@ -0,0 +1,167 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/1055373165/blog/models"
+)
+
+func TestGetPopularTagsExcludesTagsUsedOnlyByDrafts(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Tag{}, &models.Article{})
+
+	draftOnly := models.Tag{Name: "unused", Slug: "unused"}
+	used := models.Tag{Name: "go", Slug: "go"}
+	db.Create(&draftOnly)
+	db.Create(&used)
+
+	draft := models.Article{AuthorID: 1, Title: "d", Slug: "d", Status: models.ArticleStatusDraft, Tags: []models.Tag{draftOnly}}
+	db.Create(&draft)
+
+	published := models.Article{AuthorID: 1, Title: "p", Slug: "p", Status: models.ArticleStatusPublished, Tags: []models.Tag{used}}
+	db.Create(&published)
+
+	results, err := GetPopularTags(db)
+	if err != nil {
+		t.Fatalf("GetPopularTags: %v", err)
+	}
+
+	var draftOnlyCount, usedCount int64 = -1, -1
+	for _, r := range results {
+		switch r.Tag.ID {
+		case draftOnly.ID:
+			draftOnlyCount = r.ArticleCount
+		case used.ID:
+			usedCount = r.ArticleCount
+		}
+	}
+
+	if draftOnlyCount != 0 {
+		t.Errorf("expected a tag used only by drafts to have article_count 0, got %d", draftOnlyCount)
+	}
+	if usedCount != 1 {
+		t.Errorf("expected the tag used by a published article to have article_count 1, got %d", usedCount)
+	}
+	if len(results) > 0 && results[0].Tag.ID != used.ID {
+		t.Errorf("expected the genuinely used tag to sort first, got tag %d first", results[0].Tag.ID)
+	}
+}
+
+func TestGetStatsWindowComparesTwoMonths(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Article{}, &models.Blog{}, &models.User{}, &models.Reaction{})
+
+	june, err := NewDateRange(
+		time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, 6, 30, 23, 59, 59, 999999999, time.UTC),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	may := june.Previous()
+
+	seedArticle := func(createdAt time.Time) models.Article {
+		a := models.Article{AuthorID: 1, Title: "t", Slug: createdAt.Format("2006-01-02-150405.000000000"), Status: models.ArticleStatusPublished, CreatedAt: createdAt}
+		if err := db.Create(&a).Error; err != nil {
+			t.Fatal(err)
+		}
+		return a
+	}
+	seedLike := func(articleID uint, userID uint, createdAt time.Time) {
+		r := models.Reaction{ArticleID: &articleID, UserID: userID, ReactionType: models.ReactionLike, CreatedAt: createdAt}
+		if err := db.Create(&r).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Two articles and three likes in June, one article and one like in May.
+	a1 := seedArticle(june.From.AddDate(0, 0, 1))
+	seedArticle(june.From.AddDate(0, 0, 2))
+	seedLike(a1.ID, 1, june.From.AddDate(0, 0, 1))
+	seedLike(a1.ID, 2, june.From.AddDate(0, 0, 1))
+	seedLike(a1.ID, 3, june.From.AddDate(0, 0, 2))
+
+	mayArticle := seedArticle(may.From.AddDate(0, 0, 1))
+	seedLike(mayArticle.ID, 1, may.From.AddDate(0, 0, 1))
+
+	juneWindow, err := GetStatsWindow(db, june)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mayWindow, err := GetStatsWindow(db, may)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if juneWindow.NewArticles != 2 {
+		t.Errorf("expected 2 new articles in June, got %d", juneWindow.NewArticles)
+	}
+	if juneWindow.Likes != 3 {
+		t.Errorf("expected 3 likes in June, got %d", juneWindow.Likes)
+	}
+	if mayWindow.NewArticles != 1 {
+		t.Errorf("expected 1 new article in May, got %d", mayWindow.NewArticles)
+	}
+	if mayWindow.Likes != 1 {
+		t.Errorf("expected 1 like in May, got %d", mayWindow.Likes)
+	}
+
+	if deltaArticles := juneWindow.NewArticles - mayWindow.NewArticles; deltaArticles != 1 {
+		t.Errorf("expected new_articles delta of 1, got %d", deltaArticles)
+	}
+	if deltaLikes := juneWindow.Likes - mayWindow.Likes; deltaLikes != 2 {
+		t.Errorf("expected likes delta of 2, got %d", deltaLikes)
+	}
+}
+
+func TestGetPopularArticlesWindowRanksByWindowedLikesNotViews(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Article{}, &models.Reaction{})
+
+	june, err := NewDateRange(
+		time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, 6, 30, 23, 59, 59, 999999999, time.UTC),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	may := june.Previous()
+
+	// mostViewed has the highest all-time Views but no June likes.
+	mostViewed := models.Article{AuthorID: 1, Title: "most viewed", Slug: "most-viewed", Status: models.ArticleStatusPublished, Views: 1000}
+	db.Create(&mostViewed)
+	// mostLikedInJune has fewer views but two likes in June.
+	mostLikedInJune := models.Article{AuthorID: 1, Title: "liked in june", Slug: "liked-in-june", Status: models.ArticleStatusPublished, Views: 5}
+	db.Create(&mostLikedInJune)
+
+	db.Create(&models.Reaction{ArticleID: &mostLikedInJune.ID, UserID: 1, ReactionType: models.ReactionLike, CreatedAt: june.From.AddDate(0, 0, 1)})
+	db.Create(&models.Reaction{ArticleID: &mostLikedInJune.ID, UserID: 2, ReactionType: models.ReactionLike, CreatedAt: june.From.AddDate(0, 0, 2)})
+	// One like outside June (in May) must not count toward the June window.
+	db.Create(&models.Reaction{ArticleID: &mostViewed.ID, UserID: 1, ReactionType: models.ReactionLike, CreatedAt: may.From.AddDate(0, 0, 1)})
+
+	results, err := GetPopularArticlesWindow(db, 10, june)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 articles, got %d", len(results))
+	}
+	if results[0].ID != mostLikedInJune.ID || results[0].WindowLikes != 2 {
+		t.Fatalf("expected %q first with window_likes 2, got %q with %d", mostLikedInJune.Title, results[0].Title, results[0].WindowLikes)
+	}
+	if results[1].WindowLikes != 0 {
+		t.Errorf("expected the May-only like to not count toward June, got window_likes %d", results[1].WindowLikes)
+	}
+
+	counts, err := GetArticleLikeCounts(db, []uint{mostLikedInJune.ID, mostViewed.ID}, may)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counts[mostViewed.ID] != 1 {
+		t.Errorf("expected 1 like in May for %q, got %d", mostViewed.Title, counts[mostViewed.ID])
+	}
+	if _, ok := counts[mostLikedInJune.ID]; ok {
+		t.Errorf("expected no May likes entry for %q, got %d", mostLikedInJune.Title, counts[mostLikedInJune.ID])
+	}
+}
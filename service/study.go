@@ -0,0 +1,195 @@
+package service
+
+import (
+	"time"
+
+	"github.com/1055373165/blog/models"
+	"gorm.io/gorm"
+)
+
+// StudyItemWithReadInfo decorates a StudyItem with the derived last_read_at
+// timestamp of its most recent passive "read" StudyLog, and with how many
+// annotations the plan's owner has made on the item's article, so heavily
+// annotated items are visible in the plan view.
+type StudyItemWithReadInfo struct {
+	models.StudyItem
+	LastReadAt      *time.Time `json:"last_read_at,omitempty"`
+	AnnotationCount int64      `json:"annotation_count"`
+}
+
+// GetStudyItems returns every item in plan along with last_read_at, derived
+// from passive "read" StudyLog entries rather than formal reviews, and each
+// item's annotation_count.
+func GetStudyItems(db *gorm.DB, planID uint) ([]StudyItemWithReadInfo, error) {
+	var plan models.StudyPlan
+	if err := db.First(&plan, planID).Error; err != nil {
+		return nil, err
+	}
+
+	var items []models.StudyItem
+	if err := db.Where("plan_id = ?", planID).Order("id asc").Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	var reads []models.StudyLog
+	if err := db.Where("review_type = ? AND study_item_id IN (?)", models.ReviewTypeRead, itemIDs(items)).
+		Order("reviewed_at asc").
+		Find(&reads).Error; err != nil {
+		return nil, err
+	}
+	lastRead := make(map[uint]time.Time, len(reads))
+	for _, r := range reads {
+		lastRead[r.StudyItemID] = r.ReviewedAt
+	}
+
+	annotationCounts, err := CountAnnotationsByArticle(db, plan.UserID, itemArticleIDs(items))
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]StudyItemWithReadInfo, 0, len(items))
+	for _, item := range items {
+		decorated := StudyItemWithReadInfo{StudyItem: item}
+		if t, ok := lastRead[item.ID]; ok {
+			decorated.LastReadAt = &t
+		}
+		if item.ArticleID != nil {
+			decorated.AnnotationCount = annotationCounts[*item.ArticleID]
+		}
+		out = append(out, decorated)
+	}
+	return out, nil
+}
+
+func itemIDs(items []models.StudyItem) []uint {
+	ids := make([]uint, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	return ids
+}
+
+func itemArticleIDs(items []models.StudyItem) []uint {
+	ids := make([]uint, 0, len(items))
+	for _, item := range items {
+		if item.ArticleID != nil {
+			ids = append(ids, *item.ArticleID)
+		}
+	}
+	return ids
+}
+
+// LogArticleRead records a passive "read" StudyLog for the StudyItem that
+// links planID's items to articleID, without advancing the item's
+// spaced-repetition schedule. It is idempotent per item per calendar day:
+// a second call on the same day is a no-op.
+func LogArticleRead(db *gorm.DB, userID, articleID uint, now time.Time, duration time.Duration) error {
+	var item models.StudyItem
+	err := db.Joins("JOIN study_plans ON study_plans.id = study_items.plan_id").
+		Where("study_plans.user_id = ? AND study_items.article_id = ?", userID, articleID).
+		First(&item).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	dayEnd := dayStart.Add(24 * time.Hour)
+
+	var existing int64
+	if err := db.Model(&models.StudyLog{}).
+		Where("study_item_id = ? AND review_type = ? AND reviewed_at >= ? AND reviewed_at < ?",
+			item.ID, models.ReviewTypeRead, dayStart, dayEnd).
+		Count(&existing).Error; err != nil {
+		return err
+	}
+	if existing > 0 {
+		return nil
+	}
+
+	return db.Create(&models.StudyLog{
+		StudyItemID:     item.ID,
+		ReviewType:      models.ReviewTypeRead,
+		ReviewedAt:      now,
+		DurationSeconds: int(duration.Seconds()),
+	}).Error
+}
+
+// GetDueStudyItems returns a page (1-indexed, pageSize per page) of the
+// items in plan that are due for review, i.e. status "new" or "review"
+// (or status, if non-nil, scoped to just one of those) with a
+// next_review_at at or before now. Mastered items are included only
+// after RunMasteryDecay has flipped them back to "review". Results are
+// ordered overdue-first entirely in SQL. total is the full due count
+// across all pages, for "N items due" messaging.
+func GetDueStudyItems(db *gorm.DB, planID uint, status *models.StudyItemStatus, now time.Time, page, pageSize int) (items []models.StudyItem, total int64, err error) {
+	query := db.Model(&models.StudyItem{}).Where("plan_id = ?", planID)
+	if status != nil {
+		query = query.Where("status = ?", *status)
+	} else {
+		query = query.Where("status IN ?", []models.StudyItemStatus{models.StudyItemStatusNew, models.StudyItemStatusReview})
+	}
+	query = query.Where(db.Where("next_review_at IS NULL").Or("next_review_at <= ?", now))
+
+	if err = query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err = query.Order("next_review_at asc").
+		Offset((page - 1) * pageSize).Limit(pageSize).
+		Find(&items).Error
+	return items, total, err
+}
+
+// RunMasteryDecay scans every StudyPlan for mastered items whose
+// last_reviewed_at has exceeded the plan's mastery_review_interval and
+// flips them back to "review" with a fresh next_review_at, unless the item
+// has pin_mastered set. Each transition is recorded as a StudyLog with
+// review_type "decay_check" so analytics can distinguish it from a manual
+// review.
+func RunMasteryDecay(db *gorm.DB, now time.Time) (int, error) {
+	var plans []models.StudyPlan
+	if err := db.Find(&plans).Error; err != nil {
+		return 0, err
+	}
+
+	decayed := 0
+	for _, plan := range plans {
+		if plan.MasteryReviewInterval <= 0 {
+			continue
+		}
+		cutoff := now.AddDate(0, 0, -plan.MasteryReviewInterval)
+
+		var items []models.StudyItem
+		err := db.Where("plan_id = ? AND status = ? AND pin_mastered = ? AND last_reviewed_at <= ?",
+			plan.ID, models.StudyItemStatusMastered, false, cutoff).
+			Find(&items).Error
+		if err != nil {
+			return decayed, err
+		}
+
+		for _, item := range items {
+			item := item
+			err := db.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Model(&item).Updates(map[string]any{
+					"status":         models.StudyItemStatusReview,
+					"next_review_at": now,
+				}).Error; err != nil {
+					return err
+				}
+				return tx.Create(&models.StudyLog{
+					StudyItemID: item.ID,
+					ReviewType:  models.ReviewTypeDecayCheck,
+					ReviewedAt:  now,
+				}).Error
+			})
+			if err != nil {
+				return decayed, err
+			}
+			decayed++
+		}
+	}
+	return decayed, nil
+}
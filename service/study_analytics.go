@@ -0,0 +1,153 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	"github.com/1055373165/blog/models"
+	"gorm.io/gorm"
+)
+
+// StudyAnalyticsBucket is one chart-friendly row of a StudySessionAnalyticsContext
+// breakdown: how many sessions fell into this bucket, their average rating
+// (nil if none of them carry one), and the total time spent.
+type StudyAnalyticsBucket struct {
+	Bucket       string   `json:"bucket"`
+	Sessions     int64    `json:"sessions"`
+	AvgRating    *float64 `json:"avg_rating,omitempty"`
+	TotalMinutes float64  `json:"total_minutes"`
+}
+
+// StudySessionAnalyticsContext is the result of
+// GetStudySessionAnalyticsContext: a plan's study sessions broken down by
+// time of day and by review method, plus a one-line summary naming
+// whichever time-of-day bucket rates best.
+//
+// StudyLog has no captured device or location to break sessions down by,
+// so time of day - derived from ReviewedAt - is the only session-context
+// dimension this can report on; see README "Known gaps".
+type StudySessionAnalyticsContext struct {
+	ByTimeOfDay []StudyAnalyticsBucket `json:"by_time_of_day"`
+	ByMethod    []StudyAnalyticsBucket `json:"by_method"`
+	Summary     string                 `json:"summary,omitempty"`
+}
+
+// timeOfDayBucket maps t's local hour to one of four fixed buckets.
+func timeOfDayBucket(t time.Time) string {
+	switch h := t.Hour(); {
+	case h >= 5 && h < 12:
+		return "morning"
+	case h >= 12 && h < 17:
+		return "afternoon"
+	case h >= 17 && h < 21:
+		return "evening"
+	default:
+		return "night"
+	}
+}
+
+// GetStudySessionAnalyticsContext aggregates planID's StudyLog sessions,
+// optionally restricted to [from, to) (either left zero for no bound), by
+// time of day and by review method. Each breakdown is a chart-friendly
+// list of StudyAnalyticsBucket, sorted by bucket name.
+func GetStudySessionAnalyticsContext(db *gorm.DB, planID uint, from, to time.Time) (StudySessionAnalyticsContext, error) {
+	var itemIDs []uint
+	if err := db.Model(&models.StudyItem{}).Where("plan_id = ?", planID).Pluck("id", &itemIDs).Error; err != nil {
+		return StudySessionAnalyticsContext{}, err
+	}
+	if len(itemIDs) == 0 {
+		return StudySessionAnalyticsContext{}, nil
+	}
+
+	query := db.Model(&models.StudyLog{}).Where("study_item_id IN ?", itemIDs)
+	if !from.IsZero() {
+		query = query.Where("reviewed_at >= ?", from)
+	}
+	if !to.IsZero() {
+		query = query.Where("reviewed_at <= ?", to)
+	}
+	var logs []models.StudyLog
+	if err := query.Find(&logs).Error; err != nil {
+		return StudySessionAnalyticsContext{}, err
+	}
+
+	byTimeOfDay := aggregateStudyLogs(logs, func(l models.StudyLog) string {
+		return timeOfDayBucket(l.ReviewedAt)
+	})
+	byMethod := aggregateStudyLogs(logs, func(l models.StudyLog) string {
+		if l.ReviewType == "" {
+			return "unknown"
+		}
+		return l.ReviewType
+	})
+
+	return StudySessionAnalyticsContext{
+		ByTimeOfDay: byTimeOfDay,
+		ByMethod:    byMethod,
+		Summary:     bestRatedBucketSummary(byTimeOfDay),
+	}, nil
+}
+
+// aggregateStudyLogs groups logs by bucketFn and returns one
+// StudyAnalyticsBucket per distinct bucket, ordered by bucket name so the
+// response shape is stable across calls.
+func aggregateStudyLogs(logs []models.StudyLog, bucketFn func(models.StudyLog) string) []StudyAnalyticsBucket {
+	type accum struct {
+		sessions     int64
+		ratingSum    int
+		ratingCount  int64
+		totalMinutes float64
+	}
+	buckets := map[string]*accum{}
+	for _, l := range logs {
+		key := bucketFn(l)
+		a, ok := buckets[key]
+		if !ok {
+			a = &accum{}
+			buckets[key] = a
+		}
+		a.sessions++
+		a.totalMinutes += float64(l.DurationSeconds) / 60
+		if l.Rating != nil {
+			a.ratingSum += *l.Rating
+			a.ratingCount++
+		}
+	}
+
+	keys := make([]string, 0, len(buckets))
+	for k := range buckets {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	out := make([]StudyAnalyticsBucket, 0, len(keys))
+	for _, k := range keys {
+		a := buckets[k]
+		bucket := StudyAnalyticsBucket{Bucket: k, Sessions: a.sessions, TotalMinutes: a.totalMinutes}
+		if a.ratingCount > 0 {
+			avg := float64(a.ratingSum) / float64(a.ratingCount)
+			bucket.AvgRating = &avg
+		}
+		out = append(out, bucket)
+	}
+	return out
+}
+
+// bestRatedBucketSummary names whichever time-of-day bucket has the
+// highest average rating, or "" if no session in range carries a rating.
+func bestRatedBucketSummary(byTimeOfDay []StudyAnalyticsBucket) string {
+	var best *StudyAnalyticsBucket
+	for i := range byTimeOfDay {
+		b := &byTimeOfDay[i]
+		if b.AvgRating == nil {
+			continue
+		}
+		if best == nil || *b.AvgRating > *best.AvgRating {
+			best = b
+		}
+	}
+	if best == nil {
+		return ""
+	}
+	return "Your best-rated sessions happen in the " + best.Bucket + "."
+}
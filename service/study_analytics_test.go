@@ -0,0 +1,127 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/1055373165/blog/models"
+)
+
+func TestGetStudySessionAnalyticsContextBucketsByTimeOfDayAndMethod(t *testing.T) {
+	db := setupTestDB(t)
+
+	plan := models.StudyPlan{UserID: 1, Name: "Go"}
+	if err := db.Create(&plan).Error; err != nil {
+		t.Fatal(err)
+	}
+	item := models.StudyItem{PlanID: plan.ID}
+	if err := db.Create(&item).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	morningGood := 5
+	morningOK := 3
+	morning := time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC)
+	night := time.Date(2026, 1, 1, 23, 0, 0, 0, time.UTC)
+	logs := []models.StudyLog{
+		{StudyItemID: item.ID, ReviewType: models.ReviewTypeManual, ReviewedAt: morning, Rating: &morningGood, DurationSeconds: 600},
+		{StudyItemID: item.ID, ReviewType: models.ReviewTypeManual, ReviewedAt: morning, Rating: &morningOK, DurationSeconds: 300},
+		{StudyItemID: item.ID, ReviewType: models.ReviewTypeRead, ReviewedAt: night, DurationSeconds: 120},
+	}
+	for _, l := range logs {
+		l := l
+		if err := db.Create(&l).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, err := GetStudySessionAnalyticsContext(db, plan.ID, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("GetStudySessionAnalyticsContext returned error: %v", err)
+	}
+
+	if len(ctx.ByTimeOfDay) != 2 {
+		t.Fatalf("expected 2 time-of-day buckets, got %+v", ctx.ByTimeOfDay)
+	}
+	var morningBucket, nightBucket *StudyAnalyticsBucket
+	for i := range ctx.ByTimeOfDay {
+		switch ctx.ByTimeOfDay[i].Bucket {
+		case "morning":
+			morningBucket = &ctx.ByTimeOfDay[i]
+		case "night":
+			nightBucket = &ctx.ByTimeOfDay[i]
+		}
+	}
+	if morningBucket == nil || morningBucket.Sessions != 2 || morningBucket.AvgRating == nil || *morningBucket.AvgRating != 4 {
+		t.Errorf("unexpected morning bucket: %+v", morningBucket)
+	}
+	if nightBucket == nil || nightBucket.Sessions != 1 || nightBucket.AvgRating != nil {
+		t.Errorf("unexpected night bucket: %+v", nightBucket)
+	}
+
+	if len(ctx.ByMethod) != 2 {
+		t.Fatalf("expected 2 method buckets, got %+v", ctx.ByMethod)
+	}
+
+	if ctx.Summary != "Your best-rated sessions happen in the morning." {
+		t.Errorf("unexpected summary: %q", ctx.Summary)
+	}
+}
+
+func TestGetStudySessionAnalyticsContextFiltersByDateRange(t *testing.T) {
+	db := setupTestDB(t)
+
+	plan := models.StudyPlan{UserID: 1, Name: "Go"}
+	if err := db.Create(&plan).Error; err != nil {
+		t.Fatal(err)
+	}
+	item := models.StudyItem{PlanID: plan.ID}
+	if err := db.Create(&item).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	inRange := time.Date(2026, 1, 15, 9, 0, 0, 0, time.UTC)
+	outOfRange := time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC)
+	for _, rt := range []time.Time{inRange, outOfRange} {
+		log := models.StudyLog{StudyItemID: item.ID, ReviewType: models.ReviewTypeManual, ReviewedAt: rt}
+		if err := db.Create(&log).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 1, 31, 23, 59, 59, 0, time.UTC)
+	ctx, err := GetStudySessionAnalyticsContext(db, plan.ID, from, to)
+	if err != nil {
+		t.Fatalf("GetStudySessionAnalyticsContext returned error: %v", err)
+	}
+
+	var total int64
+	for _, b := range ctx.ByTimeOfDay {
+		total += b.Sessions
+	}
+	if total != 1 {
+		t.Errorf("expected 1 session in range, got %d", total)
+	}
+}
+
+func TestGetStudySessionAnalyticsContextWithNoLogsReturnsEmptyBuckets(t *testing.T) {
+	db := setupTestDB(t)
+
+	plan := models.StudyPlan{UserID: 1, Name: "Go"}
+	if err := db.Create(&plan).Error; err != nil {
+		t.Fatal(err)
+	}
+	item := models.StudyItem{PlanID: plan.ID}
+	if err := db.Create(&item).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, err := GetStudySessionAnalyticsContext(db, plan.ID, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("GetStudySessionAnalyticsContext returned error: %v", err)
+	}
+	if len(ctx.ByTimeOfDay) != 0 || len(ctx.ByMethod) != 0 || ctx.Summary != "" {
+		t.Errorf("expected empty context, got %+v", ctx)
+	}
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/1055373165/blog/models"
+)
+
+func TestLogArticleReadIsIdempotentPerDay(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Article{}, &models.Annotation{})
+	now := time.Now()
+
+	plan := models.StudyPlan{UserID: 7, Name: "Go"}
+	db.Create(&plan)
+	articleID := uint(42)
+	item := models.StudyItem{PlanID: plan.ID, ArticleID: &articleID, Status: models.StudyItemStatusReview}
+	db.Create(&item)
+
+	if err := LogArticleRead(db, 7, articleID, now, 30*time.Second); err != nil {
+		t.Fatalf("first log failed: %v", err)
+	}
+	if err := LogArticleRead(db, 7, articleID, now.Add(time.Minute), 45*time.Second); err != nil {
+		t.Fatalf("second log failed: %v", err)
+	}
+
+	var count int64
+	db.Model(&models.StudyLog{}).Where("study_item_id = ? AND review_type = ?", item.ID, models.ReviewTypeRead).Count(&count)
+	if count != 1 {
+		t.Errorf("expected exactly 1 read log for the day, got %d", count)
+	}
+
+	items, err := GetStudyItems(db, plan.ID)
+	if err != nil {
+		t.Fatalf("GetStudyItems failed: %v", err)
+	}
+	if len(items) != 1 || items[0].LastReadAt == nil {
+		t.Fatalf("expected last_read_at to be populated, got %+v", items)
+	}
+}
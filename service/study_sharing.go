@@ -0,0 +1,202 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+
+	"github.com/1055373165/blog/models"
+	"gorm.io/gorm"
+)
+
+// maxStudyPlanCloneItems caps how many StudyItems CloneSharedStudyPlan
+// will copy in one call, mirroring the cap pattern used for home-feed
+// pinning (maxPinnedArticles) and category pinning (maxCategoryPins).
+const maxStudyPlanCloneItems = 100
+
+var (
+	ErrStudyPlanNotFound      = errors.New("study plan not found")
+	ErrStudyPlanNotShared     = errors.New("study plan is not shared")
+	ErrStudyPlanCloneTooLarge = errors.New("study plan has too many items to clone")
+)
+
+// SharedStudyItem is the public, no-personal-data preview of a StudyItem
+// returned by GetSharedStudyPlan.
+type SharedStudyItem struct {
+	ArticleTitle string `json:"article_title"`
+	ArticleSlug  string `json:"article_slug"`
+}
+
+// SharedStudyPlan is the response shape for a shared plan lookup: its
+// name and a stripped item list, omitting StudyPlan.UserID and every
+// per-item personal or progress field.
+type SharedStudyPlan struct {
+	Name  string            `json:"name"`
+	Items []SharedStudyItem `json:"items"`
+}
+
+// ShareStudyPlan marks a plan owned by userID public and issues it a
+// fresh share slug, replacing any previous one so an old link can't be
+// reused once the plan has been unshared and shared again.
+func ShareStudyPlan(db *gorm.DB, planID, userID uint) (*models.StudyPlan, error) {
+	plan, err := findOwnedStudyPlan(db, planID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	slug := randomShareSlug()
+	if err := db.Model(plan).Updates(map[string]any{
+		"is_public":  true,
+		"share_slug": slug,
+	}).Error; err != nil {
+		return nil, err
+	}
+	plan.IsPublic = true
+	plan.ShareSlug = &slug
+	return plan, nil
+}
+
+// UnshareStudyPlan makes a plan owned by userID private and clears its
+// share slug, invalidating it immediately.
+func UnshareStudyPlan(db *gorm.DB, planID, userID uint) (*models.StudyPlan, error) {
+	plan, err := findOwnedStudyPlan(db, planID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Model(plan).Updates(map[string]any{
+		"is_public":  false,
+		"share_slug": nil,
+	}).Error; err != nil {
+		return nil, err
+	}
+	plan.IsPublic = false
+	plan.ShareSlug = nil
+	return plan, nil
+}
+
+func findOwnedStudyPlan(db *gorm.DB, planID, userID uint) (*models.StudyPlan, error) {
+	var plan models.StudyPlan
+	if err := db.Where("id = ? AND user_id = ?", planID, userID).First(&plan).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrStudyPlanNotFound
+		}
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// GetSharedStudyPlan returns the public preview of a shared plan by its
+// slug: name and item article titles/slugs only, no personal notes or
+// progress. Unsharing clears the slug, so this returns ErrStudyPlanNotShared
+// once that's happened, even if the plan row itself still exists.
+func GetSharedStudyPlan(db *gorm.DB, slug string) (*SharedStudyPlan, error) {
+	plan, items, err := loadSharedPlanAndItems(db, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &SharedStudyPlan{Name: plan.Name, Items: make([]SharedStudyItem, 0, len(items))}
+	for _, item := range items {
+		if item.ArticleID == nil {
+			continue
+		}
+		var article models.Article
+		if err := db.Select("title", "slug").First(&article, *item.ArticleID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		out.Items = append(out.Items, SharedStudyItem{ArticleTitle: article.Title, ArticleSlug: article.Slug})
+	}
+	return out, nil
+}
+
+// CloneSharedStudyPlan creates a fresh StudyPlan for userID by copying a
+// shared plan's items, resetting each copy to status "new" with its
+// personal/progress fields (LastReviewedAt, NextReviewAt, PinMastered)
+// blanked. Items whose article is unpublished are skipped rather than
+// copied; the number skipped is returned alongside the new plan. A plan
+// whose post-skip item count exceeds maxStudyPlanCloneItems is rejected
+// outright rather than silently truncated.
+func CloneSharedStudyPlan(db *gorm.DB, slug string, userID uint) (*models.StudyPlan, int, error) {
+	source, items, err := loadSharedPlanAndItems(db, slug)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	toCopy := make([]models.StudyItem, 0, len(items))
+	skipped := 0
+	for _, item := range items {
+		if item.ArticleID != nil {
+			var article models.Article
+			err := db.Select("status").First(&article, *item.ArticleID).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				skipped++
+				continue
+			}
+			if err != nil {
+				return nil, 0, err
+			}
+			if article.Status != models.ArticleStatusPublished {
+				skipped++
+				continue
+			}
+		}
+		toCopy = append(toCopy, item)
+	}
+
+	if len(toCopy) > maxStudyPlanCloneItems {
+		return nil, 0, ErrStudyPlanCloneTooLarge
+	}
+
+	var cloned models.StudyPlan
+	err = db.Transaction(func(tx *gorm.DB) error {
+		cloned = models.StudyPlan{
+			UserID:                userID,
+			Name:                  source.Name,
+			MasteryReviewInterval: source.MasteryReviewInterval,
+		}
+		if err := tx.Create(&cloned).Error; err != nil {
+			return err
+		}
+		for _, item := range toCopy {
+			fresh := models.StudyItem{
+				PlanID:    cloned.ID,
+				ArticleID: item.ArticleID,
+				Status:    models.StudyItemStatusNew,
+			}
+			if err := tx.Create(&fresh).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return &cloned, skipped, nil
+}
+
+func loadSharedPlanAndItems(db *gorm.DB, slug string) (*models.StudyPlan, []models.StudyItem, error) {
+	var plan models.StudyPlan
+	if err := db.Where("share_slug = ? AND is_public = ?", slug, true).First(&plan).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrStudyPlanNotShared
+		}
+		return nil, nil, err
+	}
+
+	var items []models.StudyItem
+	if err := db.Where("plan_id = ?", plan.ID).Order("id asc").Find(&items).Error; err != nil {
+		return nil, nil, err
+	}
+	return &plan, items, nil
+}
+
+func randomShareSlug() string {
+	buf := make([]byte, 12)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/1055373165/blog/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupStudySharingTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	conn, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	if err := conn.AutoMigrate(&models.StudyPlan{}, &models.StudyItem{}, &models.Article{}); err != nil {
+		t.Fatalf("failed to migrate test db: %v", err)
+	}
+	return conn
+}
+
+func TestShareAndUnshareStudyPlan(t *testing.T) {
+	db := setupStudySharingTestDB(t)
+	plan := models.StudyPlan{UserID: 1, Name: "Go internals"}
+	db.Create(&plan)
+
+	shared, err := ShareStudyPlan(db, plan.ID, plan.UserID)
+	if err != nil {
+		t.Fatalf("ShareStudyPlan returned error: %v", err)
+	}
+	if !shared.IsPublic || shared.ShareSlug == nil || *shared.ShareSlug == "" {
+		t.Fatalf("expected plan to be public with a share slug, got %+v", shared)
+	}
+	firstSlug := *shared.ShareSlug
+
+	if _, err := ShareStudyPlan(db, plan.ID, 999); err != ErrStudyPlanNotFound {
+		t.Errorf("expected ErrStudyPlanNotFound for a non-owner, got %v", err)
+	}
+
+	resharedSlug, err := ShareStudyPlan(db, plan.ID, plan.UserID)
+	if err != nil {
+		t.Fatalf("re-sharing returned error: %v", err)
+	}
+	if *resharedSlug.ShareSlug == firstSlug {
+		t.Error("expected re-sharing to issue a fresh slug")
+	}
+
+	unshared, err := UnshareStudyPlan(db, plan.ID, plan.UserID)
+	if err != nil {
+		t.Fatalf("UnshareStudyPlan returned error: %v", err)
+	}
+	if unshared.IsPublic || unshared.ShareSlug != nil {
+		t.Fatalf("expected plan to be private with no share slug, got %+v", unshared)
+	}
+
+	if _, err := GetSharedStudyPlan(db, *resharedSlug.ShareSlug); err != ErrStudyPlanNotShared {
+		t.Errorf("expected ErrStudyPlanNotShared for an unshared slug, got %v", err)
+	}
+}
+
+func TestGetSharedStudyPlanOmitsPersonalData(t *testing.T) {
+	db := setupStudySharingTestDB(t)
+	article := models.Article{Title: "Deep Dive", Slug: "deep-dive", Status: models.ArticleStatusPublished}
+	db.Create(&article)
+
+	plan := models.StudyPlan{UserID: 1, Name: "Go internals"}
+	db.Create(&plan)
+	db.Create(&models.StudyItem{PlanID: plan.ID, ArticleID: &article.ID, Status: models.StudyItemStatusMastered})
+
+	shared, err := ShareStudyPlan(db, plan.ID, plan.UserID)
+	if err != nil {
+		t.Fatalf("ShareStudyPlan returned error: %v", err)
+	}
+
+	got, err := GetSharedStudyPlan(db, *shared.ShareSlug)
+	if err != nil {
+		t.Fatalf("GetSharedStudyPlan returned error: %v", err)
+	}
+	if got.Name != plan.Name {
+		t.Errorf("got name %q, want %q", got.Name, plan.Name)
+	}
+	if len(got.Items) != 1 || got.Items[0].ArticleSlug != "deep-dive" || got.Items[0].ArticleTitle != "Deep Dive" {
+		t.Fatalf("expected a single deep-dive item, got %+v", got.Items)
+	}
+}
+
+func TestCloneSharedStudyPlanSkipsUnpublishedAndResetsProgress(t *testing.T) {
+	db := setupStudySharingTestDB(t)
+	published := models.Article{Title: "Pub", Slug: "pub", Status: models.ArticleStatusPublished}
+	draft := models.Article{Title: "Draft", Slug: "draft", Status: models.ArticleStatusDraft}
+	db.Create(&published)
+	db.Create(&draft)
+
+	plan := models.StudyPlan{UserID: 1, Name: "Go internals", MasteryReviewInterval: 30}
+	db.Create(&plan)
+	db.Create(&models.StudyItem{PlanID: plan.ID, ArticleID: &published.ID, Status: models.StudyItemStatusMastered, PinMastered: true})
+	db.Create(&models.StudyItem{PlanID: plan.ID, ArticleID: &draft.ID, Status: models.StudyItemStatusReview})
+
+	shared, err := ShareStudyPlan(db, plan.ID, plan.UserID)
+	if err != nil {
+		t.Fatalf("ShareStudyPlan returned error: %v", err)
+	}
+
+	cloned, skipped, err := CloneSharedStudyPlan(db, *shared.ShareSlug, 2)
+	if err != nil {
+		t.Fatalf("CloneSharedStudyPlan returned error: %v", err)
+	}
+	if skipped != 1 {
+		t.Errorf("expected 1 skipped item, got %d", skipped)
+	}
+	if cloned.UserID != 2 {
+		t.Errorf("expected cloned plan owned by user 2, got %d", cloned.UserID)
+	}
+
+	var items []models.StudyItem
+	db.Where("plan_id = ?", cloned.ID).Find(&items)
+	if len(items) != 1 {
+		t.Fatalf("expected 1 cloned item, got %d", len(items))
+	}
+	item := items[0]
+	if item.Status != models.StudyItemStatusNew {
+		t.Errorf("expected cloned item status new, got %s", item.Status)
+	}
+	if item.PinMastered || item.LastReviewedAt != nil || item.NextReviewAt != nil {
+		t.Errorf("expected cloned item's progress fields blanked, got %+v", item)
+	}
+}
+
+func TestCloneSharedStudyPlanRejectsTooLarge(t *testing.T) {
+	db := setupStudySharingTestDB(t)
+	plan := models.StudyPlan{UserID: 1, Name: "Huge plan"}
+	db.Create(&plan)
+	for i := 0; i < maxStudyPlanCloneItems+1; i++ {
+		db.Create(&models.StudyItem{PlanID: plan.ID, Status: models.StudyItemStatusNew})
+	}
+
+	shared, err := ShareStudyPlan(db, plan.ID, plan.UserID)
+	if err != nil {
+		t.Fatalf("ShareStudyPlan returned error: %v", err)
+	}
+
+	if _, _, err := CloneSharedStudyPlan(db, *shared.ShareSlug, 2); err != ErrStudyPlanCloneTooLarge {
+		t.Errorf("expected ErrStudyPlanCloneTooLarge, got %v", err)
+	}
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/1055373165/blog/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	conn, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	if err := conn.AutoMigrate(&models.StudyPlan{}, &models.StudyItem{}, &models.StudyLog{}); err != nil {
+		t.Fatalf("failed to migrate test db: %v", err)
+	}
+	return conn
+}
+
+func TestRunMasteryDecay(t *testing.T) {
+	db := setupTestDB(t)
+	now := time.Now()
+
+	plan := models.StudyPlan{UserID: 1, Name: "Go", MasteryReviewInterval: 90}
+	if err := db.Create(&plan).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	stale := now.AddDate(0, 0, -100)
+	fresh := now.AddDate(0, 0, -10)
+
+	staleItem := models.StudyItem{PlanID: plan.ID, Status: models.StudyItemStatusMastered, LastReviewedAt: &stale}
+	freshItem := models.StudyItem{PlanID: plan.ID, Status: models.StudyItemStatusMastered, LastReviewedAt: &fresh}
+	pinnedItem := models.StudyItem{PlanID: plan.ID, Status: models.StudyItemStatusMastered, LastReviewedAt: &stale, PinMastered: true}
+	for _, item := range []*models.StudyItem{&staleItem, &freshItem, &pinnedItem} {
+		if err := db.Create(item).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	decayed, err := RunMasteryDecay(db, now)
+	if err != nil {
+		t.Fatalf("RunMasteryDecay returned error: %v", err)
+	}
+	if decayed != 1 {
+		t.Fatalf("expected 1 decayed item, got %d", decayed)
+	}
+
+	var got models.StudyItem
+	if err := db.First(&got, staleItem.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != models.StudyItemStatusReview {
+		t.Errorf("expected stale item status %q, got %q", models.StudyItemStatusReview, got.Status)
+	}
+
+	var fresh2 models.StudyItem
+	db.First(&fresh2, freshItem.ID)
+	if fresh2.Status != models.StudyItemStatusMastered {
+		t.Errorf("fresh item should remain mastered, got %q", fresh2.Status)
+	}
+
+	var pinned2 models.StudyItem
+	db.First(&pinned2, pinnedItem.ID)
+	if pinned2.Status != models.StudyItemStatusMastered {
+		t.Errorf("pinned item should remain mastered, got %q", pinned2.Status)
+	}
+
+	var logs []models.StudyLog
+	db.Where("study_item_id = ?", staleItem.ID).Find(&logs)
+	if len(logs) != 1 || logs[0].ReviewType != models.ReviewTypeDecayCheck {
+		t.Errorf("expected one decay_check log for the decayed item, got %+v", logs)
+	}
+}
+
+func TestGetDueStudyItems(t *testing.T) {
+	db := setupTestDB(t)
+	now := time.Now()
+
+	plan := models.StudyPlan{UserID: 1, Name: "Go"}
+	db.Create(&plan)
+
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+	due := models.StudyItem{PlanID: plan.ID, Status: models.StudyItemStatusReview, NextReviewAt: &past}
+	notDue := models.StudyItem{PlanID: plan.ID, Status: models.StudyItemStatusReview, NextReviewAt: &future}
+	mastered := models.StudyItem{PlanID: plan.ID, Status: models.StudyItemStatusMastered, NextReviewAt: &past}
+	db.Create(&due)
+	db.Create(&notDue)
+	db.Create(&mastered)
+
+	items, total, err := GetDueStudyItems(db, plan.ID, nil, now, 1, 20)
+	if err != nil {
+		t.Fatalf("GetDueStudyItems returned error: %v", err)
+	}
+	if total != 1 {
+		t.Errorf("expected total 1, got %d", total)
+	}
+	if len(items) != 1 || items[0].ID != due.ID {
+		t.Errorf("expected only the due item, got %+v", items)
+	}
+}
+
+func TestGetDueStudyItemsNeverReviewedCountsAsDue(t *testing.T) {
+	db := setupTestDB(t)
+	now := time.Now()
+
+	plan := models.StudyPlan{UserID: 1, Name: "Go"}
+	db.Create(&plan)
+
+	neverReviewed := models.StudyItem{PlanID: plan.ID, Status: models.StudyItemStatusNew}
+	db.Create(&neverReviewed)
+
+	items, total, err := GetDueStudyItems(db, plan.ID, nil, now, 1, 20)
+	if err != nil {
+		t.Fatalf("GetDueStudyItems returned error: %v", err)
+	}
+	if total != 1 || len(items) != 1 || items[0].ID != neverReviewed.ID {
+		t.Errorf("expected the never-reviewed item to count as due, got total=%d items=%+v", total, items)
+	}
+}
+
+func TestGetDueStudyItemsFiltersByStatus(t *testing.T) {
+	db := setupTestDB(t)
+	now := time.Now()
+
+	plan := models.StudyPlan{UserID: 1, Name: "Go"}
+	db.Create(&plan)
+
+	past := now.Add(-time.Hour)
+	newItem := models.StudyItem{PlanID: plan.ID, Status: models.StudyItemStatusNew, NextReviewAt: &past}
+	reviewItem := models.StudyItem{PlanID: plan.ID, Status: models.StudyItemStatusReview, NextReviewAt: &past}
+	db.Create(&newItem)
+	db.Create(&reviewItem)
+
+	reviewOnly := models.StudyItemStatusReview
+	items, total, err := GetDueStudyItems(db, plan.ID, &reviewOnly, now, 1, 20)
+	if err != nil {
+		t.Fatalf("GetDueStudyItems returned error: %v", err)
+	}
+	if total != 1 || len(items) != 1 || items[0].ID != reviewItem.ID {
+		t.Errorf("expected only the review item, got total=%d items=%+v", total, items)
+	}
+}
+
+func TestGetDueStudyItemsPaginates(t *testing.T) {
+	db := setupTestDB(t)
+	now := time.Now()
+
+	plan := models.StudyPlan{UserID: 1, Name: "Go"}
+	db.Create(&plan)
+
+	for i := 0; i < 5; i++ {
+		reviewAt := now.Add(-time.Duration(5-i) * time.Hour)
+		db.Create(&models.StudyItem{PlanID: plan.ID, Status: models.StudyItemStatusReview, NextReviewAt: &reviewAt})
+	}
+
+	firstPage, total, err := GetDueStudyItems(db, plan.ID, nil, now, 1, 2)
+	if err != nil {
+		t.Fatalf("GetDueStudyItems returned error: %v", err)
+	}
+	if total != 5 {
+		t.Errorf("expected total 5, got %d", total)
+	}
+	if len(firstPage) != 2 {
+		t.Fatalf("expected 2 items on the first page, got %d", len(firstPage))
+	}
+
+	secondPage, _, err := GetDueStudyItems(db, plan.ID, nil, now, 2, 2)
+	if err != nil {
+		t.Fatalf("GetDueStudyItems returned error: %v", err)
+	}
+	if len(secondPage) != 2 {
+		t.Fatalf("expected 2 items on the second page, got %d", len(secondPage))
+	}
+	if firstPage[0].ID == secondPage[0].ID {
+		t.Error("expected the second page to start with a different item than the first")
+	}
+
+	thirdPage, _, err := GetDueStudyItems(db, plan.ID, nil, now, 3, 2)
+	if err != nil {
+		t.Fatalf("GetDueStudyItems returned error: %v", err)
+	}
+	if len(thirdPage) != 1 {
+		t.Errorf("expected 1 item on the third (partial) page, got %d", len(thirdPage))
+	}
+}
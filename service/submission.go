@@ -0,0 +1,371 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/1055373165/blog/diff"
+	"github.com/1055373165/blog/models"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrSubmissionAlreadyAssigned is returned by ClaimSubmission when the
+	// submission already has a reviewer.
+	ErrSubmissionAlreadyAssigned = errors.New("submission already assigned")
+	// ErrSubmissionReviewerMismatch is returned by ReviewSubmission when
+	// the caller isn't the assigned reviewer and didn't pass override.
+	ErrSubmissionReviewerMismatch = errors.New("submission assigned to a different reviewer")
+	// ErrInvalidReviewerID is returned by AssignReviewer when reviewerID
+	// doesn't belong to an admin user.
+	ErrInvalidReviewerID = errors.New("reviewer id must belong to an admin user")
+	// ErrInvalidSeriesID is returned by CreateSubmission and
+	// PublishSubmission when seriesID doesn't reference an existing
+	// Series.
+	ErrInvalidSeriesID = errors.New("series id does not reference an existing series")
+)
+
+// ValidateSeriesID returns ErrInvalidSeriesID if seriesID is non-nil
+// and doesn't reference an existing Series. A nil seriesID is always
+// valid.
+func ValidateSeriesID(db *gorm.DB, seriesID *uint) error {
+	if seriesID == nil {
+		return nil
+	}
+	if err := db.First(&models.Series{}, *seriesID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidSeriesID
+		}
+		return err
+	}
+	return nil
+}
+
+// GetAllSubmissions returns submissions for the admin queue, newest
+// first, optionally filtered by status and by assignment. assignedTo
+// may be "unassigned", a numeric user ID, or "" for no assignment
+// filter; a zero assignedToUserID with assignedTo == "me" is the
+// caller's own ID, resolved by the handler before calling in.
+func GetAllSubmissions(db *gorm.DB, status models.SubmissionStatus, assignedTo string, assignedToUserID uint) ([]models.Submission, error) {
+	query := db.Model(&models.Submission{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	switch assignedTo {
+	case "":
+		// no assignment filter
+	case "unassigned":
+		query = query.Where("assigned_reviewer_id IS NULL")
+	default:
+		query = query.Where("assigned_reviewer_id = ?", assignedToUserID)
+	}
+
+	var submissions []models.Submission
+	if err := query.Order("created_at desc").Find(&submissions).Error; err != nil {
+		return nil, err
+	}
+	return submissions, nil
+}
+
+// AssignReviewer sets submissionID's assigned reviewer to reviewerID,
+// which must belong to an admin user.
+func AssignReviewer(db *gorm.DB, submissionID, reviewerID uint) error {
+	var reviewer models.User
+	if err := db.First(&reviewer, reviewerID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidReviewerID
+		}
+		return err
+	}
+	if reviewer.Role != models.RoleAdmin {
+		return ErrInvalidReviewerID
+	}
+
+	var submission models.Submission
+	if err := db.First(&submission, submissionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSubmissionNotFound
+		}
+		return err
+	}
+	return db.Model(&submission).Update("assigned_reviewer_id", reviewerID).Error
+}
+
+// ClaimSubmission self-assigns submissionID to reviewerID if it is
+// currently unassigned.
+func ClaimSubmission(db *gorm.DB, submissionID, reviewerID uint) error {
+	var submission models.Submission
+	if err := db.First(&submission, submissionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSubmissionNotFound
+		}
+		return err
+	}
+	if submission.AssignedReviewerID != nil {
+		return ErrSubmissionAlreadyAssigned
+	}
+	return db.Model(&submission).Update("assigned_reviewer_id", reviewerID).Error
+}
+
+// ReviewSubmission approves, rejects, or requests changes on
+// submissionID. If it's assigned to a different reviewer than
+// reviewerID and override is false, it returns
+// ErrSubmissionReviewerMismatch instead of applying the review.
+func ReviewSubmission(db *gorm.DB, submissionID, reviewerID uint, status models.SubmissionStatus, notes string, override bool) (*models.Submission, error) {
+	var submission models.Submission
+	if err := db.First(&submission, submissionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSubmissionNotFound
+		}
+		return nil, err
+	}
+	if submission.AssignedReviewerID != nil && *submission.AssignedReviewerID != reviewerID && !override {
+		return nil, ErrSubmissionReviewerMismatch
+	}
+
+	now := time.Now()
+	updates := map[string]any{
+		"status":       status,
+		"review_notes": notes,
+		"reviewed_at":  now,
+	}
+	if err := db.Model(&submission).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+	submission.Status = status
+	submission.ReviewNotes = notes
+	submission.ReviewedAt = &now
+	return &submission, nil
+}
+
+// LinkSubmissionToArticle records that submissionID became articleID
+// once approved, so its content can later be diffed against the
+// published version.
+func LinkSubmissionToArticle(db *gorm.DB, submissionID, articleID uint) error {
+	var submission models.Submission
+	if err := db.First(&submission, submissionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSubmissionNotFound
+		}
+		return err
+	}
+	return db.Model(&submission).Update("article_id", articleID).Error
+}
+
+// ErrSubmissionNotApproved is returned by PublishSubmission when the
+// submission hasn't been approved yet.
+var ErrSubmissionNotApproved = errors.New("submission is not approved")
+
+// PublishSubmissionRequest carries the fields the reviewing admin
+// supplies to turn an approved submission into a published Article.
+// SeriesID and SeriesOrder override the submission's own fields of the
+// same name when set; a non-nil SeriesID with a nil SeriesOrder appends
+// to the end of that series instead of leaving the new article
+// unordered.
+type PublishSubmissionRequest struct {
+	Slug        string
+	SeriesID    *uint
+	SeriesOrder *int
+	AccessLevel models.ArticleAccessLevel
+}
+
+// PublishSubmission creates a published Article from an approved
+// submission and links the two (see LinkSubmissionToArticle), in one
+// transaction. It's the only path that actually produces the Article a
+// submission becomes - ReviewSubmission approving a submission only
+// changes its Status.
+//
+// If the resolved series placement's slot is already taken, every
+// article at or after that slot in the series has its order shifted
+// down by one first, so the new article can take the requested position
+// instead of colliding with Article's unique (series_id, series_order)
+// index; see claimSeriesOrderSlot.
+func PublishSubmission(db *gorm.DB, submissionID uint, req PublishSubmissionRequest) (*models.Article, error) {
+	var article models.Article
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var submission models.Submission
+		if err := tx.First(&submission, submissionID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrSubmissionNotFound
+			}
+			return err
+		}
+		if submission.Status != models.SubmissionStatusApproved {
+			return ErrSubmissionNotApproved
+		}
+
+		seriesID := submission.SeriesID
+		if req.SeriesID != nil {
+			seriesID = req.SeriesID
+		}
+		seriesOrder := submission.SeriesOrder
+		if req.SeriesOrder != nil {
+			seriesOrder = req.SeriesOrder
+		}
+
+		if seriesID != nil {
+			if err := ValidateSeriesID(tx, seriesID); err != nil {
+				return err
+			}
+			resolved, err := claimSeriesOrderSlot(tx, *seriesID, seriesOrder)
+			if err != nil {
+				return err
+			}
+			seriesOrder = &resolved
+		} else {
+			seriesOrder = nil
+		}
+
+		accessLevel := req.AccessLevel
+		if accessLevel == "" {
+			accessLevel = models.ArticleAccessPublic
+		}
+
+		now := time.Now()
+		article = models.Article{
+			AuthorID:    submission.AuthorID,
+			Title:       submission.Title,
+			Slug:        req.Slug,
+			Content:     submission.Content,
+			Status:      models.ArticleStatusPublished,
+			AccessLevel: accessLevel,
+			SeriesID:    seriesID,
+			SeriesOrder: seriesOrder,
+			Excerpt:     submission.Excerpt,
+			ExcerptAuto: submission.ExcerptAuto,
+			PublishedAt: &now,
+		}
+		if err := tx.Create(&article).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&submission).Update("article_id", article.ID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	TriggerArticleFollowerNotifications(db, article.ID)
+	return &article, nil
+}
+
+// claimSeriesOrderSlot returns the series_order a new article should
+// take in seriesID: requested, if given, after shifting every article
+// already at or after that slot down by one to make room; or one past
+// the series' current highest order, to append, if requested is nil.
+//
+// The shift walks from the highest existing order down, so each update
+// frees the slot the next one needs before claiming it, never
+// colliding with another row mid-walk against the unique
+// (series_id, series_order) index.
+func claimSeriesOrderSlot(tx *gorm.DB, seriesID uint, requested *int) (int, error) {
+	if requested == nil {
+		var max *int
+		if err := tx.Model(&models.Article{}).Where("series_id = ?", seriesID).
+			Select("MAX(series_order)").Scan(&max).Error; err != nil {
+			return 0, err
+		}
+		if max == nil {
+			return 1, nil
+		}
+		return *max + 1, nil
+	}
+
+	var conflict models.Article
+	err := tx.Where("series_id = ? AND series_order = ?", seriesID, *requested).First(&conflict).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return *requested, nil
+	case err != nil:
+		return 0, err
+	}
+
+	var toShift []models.Article
+	if err := tx.Where("series_id = ? AND series_order >= ?", seriesID, *requested).
+		Order("series_order desc").Find(&toShift).Error; err != nil {
+		return 0, err
+	}
+	for _, a := range toShift {
+		if err := tx.Model(&models.Article{}).Where("id = ?", a.ID).
+			Update("series_order", *a.SeriesOrder+1).Error; err != nil {
+			return 0, fmt.Errorf("shifting article %d down: %w", a.ID, err)
+		}
+	}
+	return *requested, nil
+}
+
+// ErrSubmissionNotLinked is returned by DiffSubmissionAgainstPublished
+// when the submission has no linked article yet.
+var ErrSubmissionNotLinked = errors.New("submission has no linked article")
+
+// DiffSubmissionAgainstPublished returns a word-level diff between
+// submissionID's current content and its linked Article's content.
+func DiffSubmissionAgainstPublished(db *gorm.DB, submissionID uint) (diff.Result, error) {
+	var submission models.Submission
+	if err := db.First(&submission, submissionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return diff.Result{}, ErrSubmissionNotFound
+		}
+		return diff.Result{}, err
+	}
+	if submission.ArticleID == nil {
+		return diff.Result{}, ErrSubmissionNotLinked
+	}
+
+	var article models.Article
+	if err := db.First(&article, *submission.ArticleID).Error; err != nil {
+		return diff.Result{}, err
+	}
+	return diff.Words(article.Content, submission.Content), nil
+}
+
+// ReviewerQueueStat summarizes one reviewer's open submission count and
+// average time-to-review, for GET /api/submissions/admin/queue-stats.
+type ReviewerQueueStat struct {
+	ReviewerID       uint    `json:"reviewer_id"`
+	OpenCount        int64   `json:"open_count"`
+	AvgReviewSeconds float64 `json:"avg_review_seconds"`
+}
+
+// GetReviewerQueueStats returns, per reviewer with any assigned
+// submission, their current open (unreviewed) count and the average
+// time-to-review in seconds across their already-reviewed submissions.
+func GetReviewerQueueStats(db *gorm.DB) ([]ReviewerQueueStat, error) {
+	var reviewerIDs []uint
+	if err := db.Model(&models.Submission{}).
+		Where("assigned_reviewer_id IS NOT NULL").
+		Distinct().Pluck("assigned_reviewer_id", &reviewerIDs).Error; err != nil {
+		return nil, err
+	}
+
+	stats := make([]ReviewerQueueStat, 0, len(reviewerIDs))
+	for _, reviewerID := range reviewerIDs {
+		var openCount int64
+		if err := db.Model(&models.Submission{}).
+			Where("assigned_reviewer_id = ? AND reviewed_at IS NULL", reviewerID).
+			Count(&openCount).Error; err != nil {
+			return nil, err
+		}
+
+		var reviewed []models.Submission
+		if err := db.Where("assigned_reviewer_id = ? AND reviewed_at IS NOT NULL", reviewerID).
+			Find(&reviewed).Error; err != nil {
+			return nil, err
+		}
+		var avgSeconds float64
+		if len(reviewed) > 0 {
+			var total float64
+			for _, s := range reviewed {
+				total += s.ReviewedAt.Sub(s.SubmittedAt).Seconds()
+			}
+			avgSeconds = total / float64(len(reviewed))
+		}
+
+		stats = append(stats, ReviewerQueueStat{
+			ReviewerID:       reviewerID,
+			OpenCount:        openCount,
+			AvgReviewSeconds: avgSeconds,
+		})
+	}
+	return stats, nil
+}
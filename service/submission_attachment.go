@@ -0,0 +1,82 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/1055373165/blog/models"
+	"gorm.io/gorm"
+)
+
+// ErrAttachmentReferenced is returned by DeleteSubmissionAttachment when
+// the attachment's URL still appears in the submission's content, since
+// deleting it would leave a broken reference behind.
+var ErrAttachmentReferenced = errors.New("attachment is referenced in the submission content")
+
+// ErrAttachmentNotFound is returned when the attachment doesn't exist or
+// doesn't belong to the given submission.
+var ErrAttachmentNotFound = errors.New("attachment not found")
+
+// CreateSubmissionAttachment records an uploaded file against submissionID.
+func CreateSubmissionAttachment(db *gorm.DB, submissionID uint, filename, url, contentType string, size int64) (*models.SubmissionAttachment, error) {
+	attachment := models.SubmissionAttachment{
+		SubmissionID: submissionID,
+		Filename:     filename,
+		URL:          url,
+		ContentType:  contentType,
+		Size:         size,
+	}
+	if err := db.Create(&attachment).Error; err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+// GetSubmissionAttachments returns submissionID's attachments, oldest
+// first.
+func GetSubmissionAttachments(db *gorm.DB, submissionID uint) ([]models.SubmissionAttachment, error) {
+	var attachments []models.SubmissionAttachment
+	err := db.Where("submission_id = ?", submissionID).Order("created_at asc").Find(&attachments).Error
+	return attachments, err
+}
+
+// GetSubmissionAttachment returns a single attachment, scoped to
+// submissionID so a caller can't fetch another submission's attachment
+// by guessing its ID.
+func GetSubmissionAttachment(db *gorm.DB, submissionID, attachmentID uint) (*models.SubmissionAttachment, error) {
+	var attachment models.SubmissionAttachment
+	err := db.Where("id = ? AND submission_id = ?", attachmentID, submissionID).First(&attachment).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+// DeleteSubmissionAttachment removes an attachment, refusing if its URL
+// is still referenced in the submission's content.
+func DeleteSubmissionAttachment(db *gorm.DB, submissionID, attachmentID uint) error {
+	var submission models.Submission
+	if err := db.First(&submission, submissionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSubmissionNotFound
+		}
+		return err
+	}
+
+	var attachment models.SubmissionAttachment
+	if err := db.Where("id = ? AND submission_id = ?", attachmentID, submissionID).First(&attachment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrAttachmentNotFound
+		}
+		return err
+	}
+
+	if strings.Contains(submission.Content, attachment.URL) {
+		return ErrAttachmentReferenced
+	}
+
+	return db.Delete(&attachment).Error
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/1055373165/blog/models"
+)
+
+func TestDeleteSubmissionAttachmentRejectsReferenced(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Submission{}, &models.SubmissionAttachment{})
+
+	submission := models.Submission{AuthorID: 1, Title: "t", Content: "see https://example.com/files/submissions/1/abc.png"}
+	if err := db.Create(&submission).Error; err != nil {
+		t.Fatal(err)
+	}
+	attachment, err := CreateSubmissionAttachment(db, submission.ID, "abc.png", "https://example.com/files/submissions/1/abc.png", "image/png", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := DeleteSubmissionAttachment(db, submission.ID, attachment.ID); err != ErrAttachmentReferenced {
+		t.Fatalf("expected ErrAttachmentReferenced, got %v", err)
+	}
+
+	if err := db.Model(&submission).Update("content", "no longer references it").Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := DeleteSubmissionAttachment(db, submission.ID, attachment.ID); err != nil {
+		t.Fatalf("expected deletion to succeed once unreferenced, got %v", err)
+	}
+}
+
+func TestGetSubmissionAttachmentsOrdersOldestFirst(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Submission{}, &models.SubmissionAttachment{})
+
+	submission := models.Submission{AuthorID: 1, Title: "t"}
+	if err := db.Create(&submission).Error; err != nil {
+		t.Fatal(err)
+	}
+	first, err := CreateSubmissionAttachment(db, submission.ID, "a.png", "https://example.com/a.png", "image/png", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := CreateSubmissionAttachment(db, submission.ID, "b.png", "https://example.com/b.png", "image/png", 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attachments, err := GetSubmissionAttachments(db, submission.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(attachments) != 2 || attachments[0].ID != first.ID || attachments[1].ID != second.ID {
+		t.Fatalf("expected [%d, %d], got %+v", first.ID, second.ID, attachments)
+	}
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/1055373165/blog/models"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrSubmissionNotFound is returned when a submission comment
+	// operation targets a submission that doesn't exist.
+	ErrSubmissionNotFound = errors.New("submission not found")
+	// ErrSubmissionAccessDenied is returned when the caller is neither
+	// the submission's author nor an admin.
+	ErrSubmissionAccessDenied = errors.New("submission access denied")
+	// ErrSubmissionCommentNotFound is returned when a comment doesn't
+	// exist on the given submission.
+	ErrSubmissionCommentNotFound = errors.New("submission comment not found")
+)
+
+// checkSubmissionAccess loads the submission and confirms userID is
+// either its author or an admin. There is no reviewer-assignment
+// concept in this tree yet (see README "Known gaps"), so access is
+// limited to those two roles.
+func checkSubmissionAccess(db *gorm.DB, submissionID, userID uint, isAdmin bool) (*models.Submission, error) {
+	var submission models.Submission
+	if err := db.First(&submission, submissionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSubmissionNotFound
+		}
+		return nil, err
+	}
+	if !isAdmin && submission.AuthorID != userID {
+		return nil, ErrSubmissionAccessDenied
+	}
+	return &submission, nil
+}
+
+// CreateSubmissionComment adds a comment (or threaded reply, if
+// parentID is non-nil) to submissionID, after checking that userID may
+// see the submission. Any @mentions in content are resolved and, in the
+// same transaction as the comment, recorded as CommentMention rows and
+// notified (see ParseMentions) — a failure partway through (e.g. a
+// notification write failing) must not leave a comment with only some
+// of its mentions recorded.
+func CreateSubmissionComment(db *gorm.DB, submissionID, userID uint, isAdmin bool, content, anchor string, parentID *uint) (*models.SubmissionComment, []MentionedUser, error) {
+	if _, err := checkSubmissionAccess(db, submissionID, userID, isAdmin); err != nil {
+		return nil, nil, err
+	}
+
+	comment := models.SubmissionComment{
+		SubmissionID: submissionID,
+		AuthorID:     userID,
+		Content:      content,
+		Anchor:       anchor,
+		ParentID:     parentID,
+	}
+
+	mentions, err := ParseMentions(db, content, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&comment).Error; err != nil {
+			return err
+		}
+		for _, m := range mentions {
+			if err := tx.Create(&models.CommentMention{SubmissionCommentID: comment.ID, UserID: m.UserID}).Error; err != nil {
+				return err
+			}
+			if err := CreateNotification(tx, m.UserID, "mention", "You were mentioned in a submission comment"); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &comment, mentions, nil
+}
+
+// GetSubmissionComments returns submissionID's comments, oldest first,
+// after checking that userID may see the submission.
+func GetSubmissionComments(db *gorm.DB, submissionID, userID uint, isAdmin bool) ([]models.SubmissionComment, error) {
+	if _, err := checkSubmissionAccess(db, submissionID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+	var comments []models.SubmissionComment
+	if err := db.Where("submission_id = ?", submissionID).Order("created_at asc").Find(&comments).Error; err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// ResolveSubmissionComment marks commentID as resolved, after checking
+// that userID may see its submission.
+func ResolveSubmissionComment(db *gorm.DB, submissionID, commentID, userID uint, isAdmin bool) error {
+	if _, err := checkSubmissionAccess(db, submissionID, userID, isAdmin); err != nil {
+		return err
+	}
+	var comment models.SubmissionComment
+	if err := db.Where("id = ? AND submission_id = ?", commentID, submissionID).First(&comment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSubmissionCommentNotFound
+		}
+		return err
+	}
+	if comment.IsResolved {
+		return nil
+	}
+	return db.Model(&comment).Update("is_resolved", true).Error
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/1055373165/blog/models"
+)
+
+func TestSubmissionCommentLifecycleAndAccess(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Submission{}, &models.SubmissionComment{}, &models.User{}, &models.CommentMention{}, &models.Notification{})
+
+	submission := models.Submission{AuthorID: 1, Title: "draft", Status: models.SubmissionStatusPending}
+	db.Create(&submission)
+
+	if _, _, err := CreateSubmissionComment(db, submission.ID, 2, false, "not mine", "", nil); err != ErrSubmissionAccessDenied {
+		t.Errorf("expected ErrSubmissionAccessDenied for a non-author non-admin, got %v", err)
+	}
+
+	comment, _, err := CreateSubmissionComment(db, submission.ID, 1, false, "please clarify this paragraph", "para-2", nil)
+	if err != nil {
+		t.Fatalf("CreateSubmissionComment returned error for the author: %v", err)
+	}
+
+	reply, _, err := CreateSubmissionComment(db, submission.ID, 9, true, "clarified, see below", "", &comment.ID)
+	if err != nil {
+		t.Fatalf("CreateSubmissionComment returned error for an admin: %v", err)
+	}
+	if reply.ParentID == nil || *reply.ParentID != comment.ID {
+		t.Errorf("expected reply.ParentID to be %d, got %v", comment.ID, reply.ParentID)
+	}
+
+	comments, err := GetSubmissionComments(db, submission.ID, 1, false)
+	if err != nil {
+		t.Fatalf("GetSubmissionComments returned error: %v", err)
+	}
+	if len(comments) != 2 {
+		t.Fatalf("expected 2 comments, got %d", len(comments))
+	}
+
+	if _, err := GetSubmissionComments(db, submission.ID, 2, false); err != ErrSubmissionAccessDenied {
+		t.Errorf("expected ErrSubmissionAccessDenied for a non-author non-admin, got %v", err)
+	}
+
+	if err := ResolveSubmissionComment(db, submission.ID, comment.ID, 1, false); err != nil {
+		t.Fatalf("ResolveSubmissionComment returned error: %v", err)
+	}
+	var reloaded models.SubmissionComment
+	db.First(&reloaded, comment.ID)
+	if !reloaded.IsResolved {
+		t.Error("expected comment to be marked resolved")
+	}
+
+	if err := ResolveSubmissionComment(db, submission.ID, 9999, 1, false); err != ErrSubmissionCommentNotFound {
+		t.Errorf("expected ErrSubmissionCommentNotFound for an unknown comment, got %v", err)
+	}
+}
+
+func TestCreateSubmissionCommentRollsBackOnNotificationFailure(t *testing.T) {
+	db := setupTestDB(t)
+	// Notification is deliberately not migrated, so CreateNotification's
+	// write fails partway through the mention loop; the comment and any
+	// already-written CommentMention rows must not survive that.
+	db.AutoMigrate(&models.Submission{}, &models.SubmissionComment{}, &models.User{}, &models.CommentMention{})
+
+	submission := models.Submission{AuthorID: 1, Title: "draft", Status: models.SubmissionStatusPending}
+	db.Create(&submission)
+	// A filler user pushes bob to a later ID so it doesn't collide with
+	// the commenter's userID (1) below and get excluded as a self-mention.
+	db.Create(&models.User{Username: "filler", Email: "filler@example.com"})
+	bob := models.User{Username: "bob", Email: "bob@example.com"}
+	db.Create(&bob)
+
+	if _, _, err := CreateSubmissionComment(db, submission.ID, 1, false, "hey @bob", "", nil); err == nil {
+		t.Fatal("expected CreateSubmissionComment to fail when notification creation fails")
+	}
+
+	var commentCount, mentionCount int64
+	db.Model(&models.SubmissionComment{}).Count(&commentCount)
+	db.Model(&models.CommentMention{}).Count(&mentionCount)
+	if commentCount != 0 {
+		t.Errorf("expected no comment to persist after rollback, got %d", commentCount)
+	}
+	if mentionCount != 0 {
+		t.Errorf("expected no CommentMention to persist after rollback, got %d", mentionCount)
+	}
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/1055373165/blog/models"
+)
+
+func TestDiffSubmissionAgainstPublishedRequiresLinkedArticle(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Submission{}, &models.Article{})
+
+	submission := models.Submission{AuthorID: 1, Title: "draft", Content: "hello world", Status: models.SubmissionStatusApproved}
+	db.Create(&submission)
+
+	if _, err := DiffSubmissionAgainstPublished(db, submission.ID); err != ErrSubmissionNotLinked {
+		t.Errorf("expected ErrSubmissionNotLinked, got %v", err)
+	}
+
+	article := models.Article{AuthorID: 1, Title: "hello world", Slug: "hello-world", Content: "hello new world", Status: models.ArticleStatusPublished}
+	db.Create(&article)
+	if err := LinkSubmissionToArticle(db, submission.ID, article.ID); err != nil {
+		t.Fatalf("LinkSubmissionToArticle returned error: %v", err)
+	}
+
+	result, err := DiffSubmissionAgainstPublished(db, submission.ID)
+	if err != nil {
+		t.Fatalf("DiffSubmissionAgainstPublished returned error: %v", err)
+	}
+	var removed string
+	for _, s := range result.Segments {
+		if s.Type == "removed" {
+			removed += s.Text
+		}
+	}
+	if removed != "new " {
+		t.Errorf("expected the published article's extra word 'new' to show as removed, got %q", removed)
+	}
+}
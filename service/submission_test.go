@@ -0,0 +1,203 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/1055373165/blog/models"
+)
+
+func TestAssignAndClaimSubmission(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Submission{}, &models.User{})
+
+	admin := models.User{Username: "editor", Email: "editor@example.com", Role: models.RoleAdmin}
+	db.Create(&admin)
+	other := models.User{Username: "other-editor", Email: "other@example.com", Role: models.RoleAdmin}
+	db.Create(&other)
+	author := models.User{Username: "author", Email: "author@example.com", Role: models.RoleUser}
+	db.Create(&author)
+
+	submission := models.Submission{AuthorID: author.ID, Title: "draft", Status: models.SubmissionStatusPending}
+	db.Create(&submission)
+
+	if err := AssignReviewer(db, submission.ID, author.ID); err != ErrInvalidReviewerID {
+		t.Errorf("expected ErrInvalidReviewerID for a non-admin reviewer, got %v", err)
+	}
+	if err := AssignReviewer(db, submission.ID, admin.ID); err != nil {
+		t.Fatalf("AssignReviewer returned error: %v", err)
+	}
+
+	if err := ClaimSubmission(db, submission.ID, other.ID); err != ErrSubmissionAlreadyAssigned {
+		t.Errorf("expected ErrSubmissionAlreadyAssigned for an already-assigned submission, got %v", err)
+	}
+
+	unassigned := models.Submission{AuthorID: author.ID, Title: "another draft", Status: models.SubmissionStatusPending}
+	db.Create(&unassigned)
+	if err := ClaimSubmission(db, unassigned.ID, other.ID); err != nil {
+		t.Fatalf("ClaimSubmission returned error: %v", err)
+	}
+	var reloaded models.Submission
+	db.First(&reloaded, unassigned.ID)
+	if reloaded.AssignedReviewerID == nil || *reloaded.AssignedReviewerID != other.ID {
+		t.Errorf("expected unassigned submission claimed by %d, got %v", other.ID, reloaded.AssignedReviewerID)
+	}
+}
+
+func TestReviewSubmissionRequiresOverrideForMismatchedReviewer(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Submission{})
+
+	reviewerID := uint(1)
+	submission := models.Submission{AuthorID: 2, Title: "draft", Status: models.SubmissionStatusPending, AssignedReviewerID: &reviewerID}
+	db.Create(&submission)
+
+	if _, err := ReviewSubmission(db, submission.ID, 99, models.SubmissionStatusApproved, "lgtm", false); err != ErrSubmissionReviewerMismatch {
+		t.Errorf("expected ErrSubmissionReviewerMismatch, got %v", err)
+	}
+
+	reviewed, err := ReviewSubmission(db, submission.ID, 99, models.SubmissionStatusApproved, "approving anyway", true)
+	if err != nil {
+		t.Fatalf("ReviewSubmission with override returned error: %v", err)
+	}
+	if reviewed.Status != models.SubmissionStatusApproved || reviewed.ReviewedAt == nil {
+		t.Errorf("expected submission approved with ReviewedAt set, got %+v", reviewed)
+	}
+}
+
+func TestPublishSubmissionRequiresApproval(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Submission{}, &models.Article{})
+
+	submission := models.Submission{AuthorID: 1, Title: "draft", Content: "body", Status: models.SubmissionStatusPending}
+	db.Create(&submission)
+
+	if _, err := PublishSubmission(db, submission.ID, PublishSubmissionRequest{Slug: "draft"}); err != ErrSubmissionNotApproved {
+		t.Errorf("expected ErrSubmissionNotApproved, got %v", err)
+	}
+}
+
+func TestPublishSubmissionLinksTheCreatedArticle(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Submission{}, &models.Article{})
+
+	submission := models.Submission{AuthorID: 1, Title: "my post", Content: "body", Status: models.SubmissionStatusApproved}
+	db.Create(&submission)
+
+	article, err := PublishSubmission(db, submission.ID, PublishSubmissionRequest{Slug: "my-post"})
+	if err != nil {
+		t.Fatalf("PublishSubmission returned error: %v", err)
+	}
+	if article.Status != models.ArticleStatusPublished || article.Title != "my post" || article.AuthorID != 1 {
+		t.Errorf("unexpected article: %+v", article)
+	}
+
+	var reloaded models.Submission
+	db.First(&reloaded, submission.ID)
+	if reloaded.ArticleID == nil || *reloaded.ArticleID != article.ID {
+		t.Errorf("expected submission linked to article %d, got %v", article.ID, reloaded.ArticleID)
+	}
+}
+
+// TestPublishSubmissionIntoTakenSlotShiftsLaterArticlesDown publishes two
+// submissions requesting the same series_order slot and asserts the
+// second publish shifts the first article down rather than erroring.
+func TestPublishSubmissionIntoTakenSlotShiftsLaterArticlesDown(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Submission{}, &models.Article{}, &models.Series{})
+
+	series := models.Series{Title: "chapters", Slug: "chapters"}
+	db.Create(&series)
+	seriesID := series.ID
+	first := models.Submission{AuthorID: 1, Title: "chapter one", Content: "body", Status: models.SubmissionStatusApproved}
+	db.Create(&first)
+	second := models.Submission{AuthorID: 1, Title: "chapter two", Content: "body", Status: models.SubmissionStatusApproved}
+	db.Create(&second)
+
+	slot := 1
+	firstArticle, err := PublishSubmission(db, first.ID, PublishSubmissionRequest{
+		Slug: "chapter-one", SeriesID: &seriesID, SeriesOrder: &slot,
+	})
+	if err != nil {
+		t.Fatalf("publishing first submission: %v", err)
+	}
+
+	secondArticle, err := PublishSubmission(db, second.ID, PublishSubmissionRequest{
+		Slug: "chapter-two", SeriesID: &seriesID, SeriesOrder: &slot,
+	})
+	if err != nil {
+		t.Fatalf("publishing second submission into the same slot: %v", err)
+	}
+
+	articles, err := GetArticlesBySeries(db, seriesID)
+	if err != nil {
+		t.Fatalf("GetArticlesBySeries returned error: %v", err)
+	}
+	if len(articles) != 2 {
+		t.Fatalf("expected 2 articles in the series, got %d", len(articles))
+	}
+	if articles[0].ID != secondArticle.ID || articles[0].SeriesOrder == nil || *articles[0].SeriesOrder != 1 {
+		t.Errorf("expected the second submission to take slot 1, got %+v", articles[0])
+	}
+	if articles[1].ID != firstArticle.ID || articles[1].SeriesOrder == nil || *articles[1].SeriesOrder != 2 {
+		t.Errorf("expected the first submission shifted down to slot 2, got %+v", articles[1])
+	}
+}
+
+func TestPublishSubmissionRejectsANonexistentSeriesID(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Submission{}, &models.Article{}, &models.Series{})
+
+	submission := models.Submission{AuthorID: 1, Title: "draft", Content: "body", Status: models.SubmissionStatusApproved}
+	db.Create(&submission)
+
+	bogusSeriesID := uint(999)
+	if _, err := PublishSubmission(db, submission.ID, PublishSubmissionRequest{Slug: "draft", SeriesID: &bogusSeriesID}); err != ErrInvalidSeriesID {
+		t.Errorf("expected ErrInvalidSeriesID, got %v", err)
+	}
+}
+
+func TestPublishSubmissionWithoutAnOrderAppendsToTheEndOfTheSeries(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Submission{}, &models.Article{}, &models.Series{})
+
+	series := models.Series{Title: "ongoing", Slug: "ongoing"}
+	db.Create(&series)
+	seriesID := series.ID
+	existingOrder := 3
+	db.Create(&models.Article{Title: "existing", Slug: "existing", SeriesID: &seriesID, SeriesOrder: &existingOrder})
+
+	submission := models.Submission{AuthorID: 1, Title: "new chapter", Content: "body", Status: models.SubmissionStatusApproved}
+	db.Create(&submission)
+
+	article, err := PublishSubmission(db, submission.ID, PublishSubmissionRequest{Slug: "new-chapter", SeriesID: &seriesID})
+	if err != nil {
+		t.Fatalf("PublishSubmission returned error: %v", err)
+	}
+	if article.SeriesOrder == nil || *article.SeriesOrder != 4 {
+		t.Errorf("expected the unordered publish to append at slot 4, got %v", article.SeriesOrder)
+	}
+}
+
+func TestGetReviewerQueueStatsComputesOpenCountAndAverage(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Submission{})
+
+	reviewerID := uint(1)
+	db.Create(&models.Submission{AuthorID: 2, Title: "open", Status: models.SubmissionStatusPending, AssignedReviewerID: &reviewerID})
+	reviewed := models.Submission{AuthorID: 2, Title: "reviewed", Status: models.SubmissionStatusApproved, AssignedReviewerID: &reviewerID}
+	db.Create(&reviewed)
+	if _, err := ReviewSubmission(db, reviewed.ID, reviewerID, models.SubmissionStatusApproved, "ok", false); err != nil {
+		t.Fatalf("ReviewSubmission returned error: %v", err)
+	}
+
+	stats, err := GetReviewerQueueStats(db)
+	if err != nil {
+		t.Fatalf("GetReviewerQueueStats returned error: %v", err)
+	}
+	if len(stats) != 1 {
+		t.Fatalf("expected stats for 1 reviewer, got %d", len(stats))
+	}
+	if stats[0].ReviewerID != reviewerID || stats[0].OpenCount != 1 {
+		t.Errorf("expected reviewer %d with open count 1, got %+v", reviewerID, stats[0])
+	}
+}
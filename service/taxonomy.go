@@ -0,0 +1,162 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	"github.com/1055373165/blog/models"
+	"gorm.io/gorm"
+)
+
+// MonthlyTagStat is one month's published-article count for a tag.
+//
+// Article views and likes are tracked only as running totals, not as a
+// time series, so this series covers article counts only.
+type MonthlyTagStat struct {
+	Month        string `json:"month"` // YYYY-MM
+	ArticleCount int64  `json:"article_count"`
+}
+
+// GetTagMonthlyStats returns a monthly series of published-article counts
+// for tagID, covering the last months months (oldest first, zero-filled).
+func GetTagMonthlyStats(db *gorm.DB, tagID uint, months int) ([]MonthlyTagStat, error) {
+	if months < 1 {
+		months = 12
+	}
+
+	type row struct {
+		Month string
+		Count int64
+	}
+	var rows []row
+	since := monthsAgo(months - 1)
+	err := db.Table("article_tags").
+		Select("strftime('%Y-%m', articles.created_at) as month, COUNT(*) as count").
+		Joins("JOIN articles ON articles.id = article_tags.article_id").
+		Where("article_tags.tag_id = ? AND articles.status = ? AND articles.created_at >= ?",
+			tagID, models.ArticleStatusPublished, since).
+		Group("month").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, r := range rows {
+		counts[r.Month] = r.Count
+	}
+
+	series := monthSeries(months)
+	stats := make([]MonthlyTagStat, len(series))
+	for i, month := range series {
+		stats[i] = MonthlyTagStat{Month: month, ArticleCount: counts[month]}
+	}
+	return stats, nil
+}
+
+// TagTrend ranks a tag by how much its published-article count grew
+// between the first and second half of the trend window.
+type TagTrend struct {
+	models.Tag
+	RecentCount int64   `json:"recent_count"`
+	PriorCount  int64   `json:"prior_count"`
+	GrowthRate  float64 `json:"growth_rate"`
+}
+
+// GetTaxonomyTrends returns the top 10 tags ranked by article-count growth
+// rate between the first and second half of the last months months.
+func GetTaxonomyTrends(db *gorm.DB, months int) ([]TagTrend, error) {
+	if months < 2 {
+		months = 6
+	}
+	half := months / 2
+
+	boundary := monthsAgo(half)
+	start := monthsAgo(months)
+
+	recent, err := tagArticleCounts(db, boundary, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	prior, err := tagArticleCounts(db, start, boundary)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []models.Tag
+	if err := db.Find(&tags).Error; err != nil {
+		return nil, err
+	}
+
+	trends := make([]TagTrend, 0, len(tags))
+	for _, tag := range tags {
+		r, p := recent[tag.ID], prior[tag.ID]
+		if r == 0 && p == 0 {
+			continue
+		}
+		trends = append(trends, TagTrend{Tag: tag, RecentCount: r, PriorCount: p, GrowthRate: growthRate(p, r)})
+	}
+
+	sort.Slice(trends, func(i, j int) bool { return trends[i].GrowthRate > trends[j].GrowthRate })
+	if len(trends) > 10 {
+		trends = trends[:10]
+	}
+	return trends, nil
+}
+
+// tagArticleCounts counts published articles per tag with created_at in
+// [since, until). A zero until means no upper bound.
+func tagArticleCounts(db *gorm.DB, since, until time.Time) (map[uint]int64, error) {
+	type row struct {
+		TagID uint
+		Count int64
+	}
+
+	query := db.Table("article_tags").
+		Select("article_tags.tag_id as tag_id, COUNT(*) as count").
+		Joins("JOIN articles ON articles.id = article_tags.article_id AND articles.status = ?", models.ArticleStatusPublished).
+		Where("articles.created_at >= ?", since)
+	if !until.IsZero() {
+		query = query.Where("articles.created_at < ?", until)
+	}
+
+	var rows []row
+	if err := query.Group("article_tags.tag_id").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint]int64, len(rows))
+	for _, r := range rows {
+		counts[r.TagID] = r.Count
+	}
+	return counts, nil
+}
+
+// growthRate compares recent against prior. Growth from a zero prior count
+// is reported as the raw recent count, since a ratio is undefined there.
+func growthRate(prior, recent int64) float64 {
+	if prior == 0 {
+		return float64(recent)
+	}
+	return float64(recent-prior) / float64(prior)
+}
+
+// monthSeries returns the last months calendar months as "YYYY-MM"
+// strings, oldest first, ending at the current month.
+func monthSeries(months int) []string {
+	now := time.Now().UTC()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -(months - 1), 0)
+
+	series := make([]string, months)
+	for i := 0; i < months; i++ {
+		series[i] = start.AddDate(0, i, 0).Format("2006-01")
+	}
+	return series
+}
+
+// monthsAgo returns the start of the calendar month n months before the
+// current one.
+func monthsAgo(n int) time.Time {
+	now := time.Now().UTC()
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -n, 0)
+}
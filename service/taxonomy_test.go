@@ -0,0 +1,81 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/1055373165/blog/models"
+)
+
+func TestGetTagMonthlyStats(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Article{}, &models.Tag{})
+
+	tag := models.Tag{Name: "Go", Slug: "go"}
+	db.Create(&tag)
+
+	now := time.Now().UTC()
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	lastMonth := thisMonth.AddDate(0, -1, 0)
+
+	a1 := models.Article{AuthorID: 1, Title: "A1", Slug: "a1", Status: models.ArticleStatusPublished, Tags: []models.Tag{tag}}
+	a2 := models.Article{AuthorID: 1, Title: "A2", Slug: "a2", Status: models.ArticleStatusPublished, Tags: []models.Tag{tag}}
+	db.Create(&a1)
+	db.Create(&a2)
+	db.Model(&a1).UpdateColumn("created_at", thisMonth)
+	db.Model(&a2).UpdateColumn("created_at", lastMonth)
+
+	stats, err := GetTagMonthlyStats(db, tag.ID, 3)
+	if err != nil {
+		t.Fatalf("GetTagMonthlyStats returned error: %v", err)
+	}
+	if len(stats) != 3 {
+		t.Fatalf("expected 3 months, got %d", len(stats))
+	}
+
+	byMonth := map[string]int64{}
+	for _, s := range stats {
+		byMonth[s.Month] = s.ArticleCount
+	}
+	if byMonth[thisMonth.Format("2006-01")] != 1 {
+		t.Errorf("expected 1 article this month, got %d", byMonth[thisMonth.Format("2006-01")])
+	}
+	if byMonth[lastMonth.Format("2006-01")] != 1 {
+		t.Errorf("expected 1 article last month, got %d", byMonth[lastMonth.Format("2006-01")])
+	}
+}
+
+func TestGetTaxonomyTrends(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Article{}, &models.Tag{})
+
+	rising := models.Tag{Name: "Rust", Slug: "rust"}
+	flat := models.Tag{Name: "Cobol", Slug: "cobol"}
+	db.Create(&rising)
+	db.Create(&flat)
+
+	now := time.Now().UTC()
+	recent := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	old := recent.AddDate(0, -3, 0)
+
+	for i := 0; i < 3; i++ {
+		a := models.Article{AuthorID: 1, Title: "Rising", Slug: "rising-" + string(rune('a'+i)),
+			Status: models.ArticleStatusPublished, Tags: []models.Tag{rising}}
+		db.Create(&a)
+		db.Model(&a).UpdateColumn("created_at", recent)
+	}
+	oldArticle := models.Article{AuthorID: 1, Title: "Flat", Slug: "flat-1", Status: models.ArticleStatusPublished, Tags: []models.Tag{flat}}
+	db.Create(&oldArticle)
+	db.Model(&oldArticle).UpdateColumn("created_at", old)
+
+	trends, err := GetTaxonomyTrends(db, 6)
+	if err != nil {
+		t.Fatalf("GetTaxonomyTrends returned error: %v", err)
+	}
+	if len(trends) == 0 {
+		t.Fatal("expected at least one trend")
+	}
+	if trends[0].Tag.ID != rising.ID {
+		t.Errorf("expected rising tag to rank first, got %+v", trends[0])
+	}
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/1055373165/blog/models"
+	"gorm.io/gorm"
+)
+
+var ErrSameArticle = errors.New("cannot link an article to itself")
+
+// LinkTranslation marks articleID and targetID as translations of each
+// other, merging them into a single translation group. If either
+// article already belongs to a group, both groups are merged into the
+// other's.
+func LinkTranslation(db *gorm.DB, articleID, targetID uint) error {
+	if articleID == targetID {
+		return ErrSameArticle
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		var a, b models.Article
+		if err := tx.First(&a, articleID).Error; err != nil {
+			return err
+		}
+		if err := tx.First(&b, targetID).Error; err != nil {
+			return err
+		}
+
+		group := a.ID
+		if a.TranslationGroupID != nil {
+			group = *a.TranslationGroupID
+		} else if b.TranslationGroupID != nil {
+			group = *b.TranslationGroupID
+		}
+
+		for _, old := range []*uint{a.TranslationGroupID, b.TranslationGroupID} {
+			if old != nil && *old != group {
+				if err := tx.Model(&models.Article{}).Where("translation_group_id = ?", *old).
+					Update("translation_group_id", group).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		return tx.Model(&models.Article{}).Where("id IN ?", []uint{a.ID, b.ID}).
+			Update("translation_group_id", group).Error
+	})
+}
+
+// TranslationInfo identifies one language version within a translation
+// group.
+type TranslationInfo struct {
+	Language string `json:"language"`
+	Slug     string `json:"slug"`
+}
+
+// GetTranslations returns the other language versions of article, or
+// nil if article belongs to no translation group.
+func GetTranslations(db *gorm.DB, article models.Article) ([]TranslationInfo, error) {
+	if article.TranslationGroupID == nil {
+		return nil, nil
+	}
+	var others []models.Article
+	if err := db.Where("translation_group_id = ? AND id != ?", *article.TranslationGroupID, article.ID).
+		Find(&others).Error; err != nil {
+		return nil, err
+	}
+	infos := make([]TranslationInfo, len(others))
+	for i, o := range others {
+		infos[i] = TranslationInfo{Language: o.Language, Slug: o.Slug}
+	}
+	return infos, nil
+}
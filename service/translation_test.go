@@ -0,0 +1,72 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/1055373165/blog/models"
+)
+
+func TestLinkTranslationSetsSharedGroup(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Article{})
+
+	zh := models.Article{AuthorID: 1, Title: "你好", Slug: "hello-zh", Language: "zh-CN", Status: models.ArticleStatusPublished}
+	en := models.Article{AuthorID: 1, Title: "Hello", Slug: "hello-en", Language: "en-US", Status: models.ArticleStatusPublished}
+	db.Create(&zh)
+	db.Create(&en)
+
+	if err := LinkTranslation(db, zh.ID, en.ID); err != nil {
+		t.Fatalf("LinkTranslation returned error: %v", err)
+	}
+
+	var reloaded models.Article
+	db.First(&reloaded, zh.ID)
+	translations, err := GetTranslations(db, reloaded)
+	if err != nil {
+		t.Fatalf("GetTranslations returned error: %v", err)
+	}
+	if len(translations) != 1 || translations[0].Slug != "hello-en" || translations[0].Language != "en-US" {
+		t.Errorf("expected hello-en/en-US translation, got %+v", translations)
+	}
+}
+
+func TestLinkTranslationMergesExistingGroups(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Article{})
+
+	zh := models.Article{AuthorID: 1, Title: "你好", Slug: "hello-zh", Language: "zh-CN", Status: models.ArticleStatusPublished}
+	en := models.Article{AuthorID: 1, Title: "Hello", Slug: "hello-en", Language: "en-US", Status: models.ArticleStatusPublished}
+	ja := models.Article{AuthorID: 1, Title: "こんにちは", Slug: "hello-ja", Language: "ja-JP", Status: models.ArticleStatusPublished}
+	db.Create(&zh)
+	db.Create(&en)
+	db.Create(&ja)
+
+	if err := LinkTranslation(db, zh.ID, en.ID); err != nil {
+		t.Fatalf("LinkTranslation returned error: %v", err)
+	}
+	if err := LinkTranslation(db, en.ID, ja.ID); err != nil {
+		t.Fatalf("LinkTranslation returned error: %v", err)
+	}
+
+	var reloaded models.Article
+	db.First(&reloaded, zh.ID)
+	translations, err := GetTranslations(db, reloaded)
+	if err != nil {
+		t.Fatalf("GetTranslations returned error: %v", err)
+	}
+	if len(translations) != 2 {
+		t.Errorf("expected zh to be linked to both en and ja after merge, got %+v", translations)
+	}
+}
+
+func TestLinkTranslationRejectsSameArticle(t *testing.T) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&models.Article{})
+
+	article := models.Article{AuthorID: 1, Title: "A", Slug: "a", Status: models.ArticleStatusPublished}
+	db.Create(&article)
+
+	if err := LinkTranslation(db, article.ID, article.ID); err != ErrSameArticle {
+		t.Errorf("expected ErrSameArticle, got %v", err)
+	}
+}
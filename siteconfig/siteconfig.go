@@ -0,0 +1,278 @@
+// Package siteconfig maintains the typed, cached set of runtime
+// configuration values backed by models.SiteConfig, so operators can
+// change site behavior (maintenance mode, rate limits, comment
+// moderation, ...) without a redeploy. Only the keys declared in
+// knownKeys are readable or writable; anything else is rejected as
+// unknown. Other packages read through the Get* accessors below, never
+// a copied struct, so a value changed via Set takes effect for every
+// subsequent read without a restart.
+package siteconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/1055373165/blog/models"
+	"gorm.io/gorm"
+)
+
+// ValueType is the declared type of a known config key, used to
+// validate values written through Set.
+type ValueType string
+
+const (
+	TypeString ValueType = "string"
+	TypeInt    ValueType = "int"
+	TypeBool   ValueType = "bool"
+	TypeJSON   ValueType = "json"
+)
+
+// KeyMaintenanceMode, when "true", makes middleware.MaintenanceMode
+// return 503 for every non-admin, non-health request.
+const KeyMaintenanceMode = "maintenance_mode"
+
+// KeyContentMaxBytes bounds long-form content fields - article and
+// submission body, blog transcript - in raw bytes of the submitted
+// string, enforced by utils.EnforceContentLength.
+const KeyContentMaxBytes = "content_max_bytes"
+
+// KeyNoteMaxBytes bounds short free-text fields attached to an existing
+// record - submission comments, review notes - in raw bytes, enforced
+// by utils.EnforceContentLength.
+const KeyNoteMaxBytes = "note_max_bytes"
+
+type keyDef struct {
+	Type        ValueType
+	Default     string
+	Sensitive   bool
+	Description string
+}
+
+// knownKeys is the allowlist of configuration keys the API will read or
+// write. A key not listed here is rejected by Set and never appears in
+// Snapshot, regardless of what's in the database.
+var knownKeys = map[string]keyDef{
+	KeyMaintenanceMode: {
+		Type: TypeBool, Default: "false",
+		Description: "When true, non-admin, non-health requests receive 503.",
+	},
+	"comments_require_approval": {
+		Type: TypeBool, Default: "false",
+		Description: "When true, new submission comments start unapproved.",
+	},
+	"rate_limit_per_minute": {
+		Type: TypeInt, Default: "60",
+		Description: "Requests per caller per minute before throttling.",
+	},
+	KeyContentMaxBytes: {
+		Type: TypeInt, Default: "2097152",
+		Description: "Max bytes for article/submission content and blog transcripts.",
+	},
+	KeyNoteMaxBytes: {
+		Type: TypeInt, Default: "102400",
+		Description: "Max bytes for submission comments and review notes.",
+	},
+	"public_base_url": {
+		Type: TypeString, Default: "",
+		Description: "Externally reachable base URL used to build absolute links.",
+	},
+	"smtp_password": {
+		Type: TypeString, Default: "", Sensitive: true,
+		Description: "Password for the outgoing SMTP relay.",
+	},
+}
+
+// ErrUnknownKey is returned by Set when key isn't in the allowlist.
+var ErrUnknownKey = errors.New("unknown config key")
+
+// ErrInvalidValue is returned by Set when value doesn't parse as key's
+// declared type.
+var ErrInvalidValue = errors.New("invalid value for config key")
+
+var (
+	mu        sync.RWMutex
+	values    map[string]string
+	updatedAt map[string]time.Time
+)
+
+// Entry is a single key's current state, for the admin listing. Value
+// is masked to "********" for sensitive keys that have been set.
+// UpdatedAt is nil for a key that has never been explicitly set, since
+// it's still showing its compiled-in default rather than a stored row.
+type Entry struct {
+	Key         string     `json:"key"`
+	Value       string     `json:"value"`
+	Type        ValueType  `json:"type"`
+	Sensitive   bool       `json:"sensitive"`
+	Description string     `json:"description"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+}
+
+// Load reads every SiteConfig row into the in-process cache, falling
+// back to each known key's default when no row exists yet. Call once at
+// startup, mirroring search.Init and events.Init.
+func Load(db *gorm.DB) error {
+	var rows []models.SiteConfig
+	if err := db.Find(&rows).Error; err != nil {
+		return err
+	}
+
+	nextValues := make(map[string]string, len(knownKeys))
+	for key, def := range knownKeys {
+		nextValues[key] = def.Default
+	}
+	nextUpdatedAt := make(map[string]time.Time, len(rows))
+	for _, row := range rows {
+		if _, ok := knownKeys[row.Key]; ok {
+			nextValues[row.Key] = row.Value
+			nextUpdatedAt[row.Key] = row.UpdatedAt
+		}
+	}
+
+	mu.Lock()
+	values = nextValues
+	updatedAt = nextUpdatedAt
+	mu.Unlock()
+	return nil
+}
+
+// Set validates rawValue against key's declared type, persists it, and
+// invalidates the cache so every subsequent read across the process
+// sees the new value immediately.
+func Set(db *gorm.DB, key string, rawValue json.RawMessage) (Entry, error) {
+	def, ok := knownKeys[key]
+	if !ok {
+		return Entry{}, ErrUnknownKey
+	}
+
+	value, err := normalize(def.Type, rawValue)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	now := time.Now()
+	row := models.SiteConfig{Key: key, Value: value, Type: string(def.Type), UpdatedAt: now}
+	var existing models.SiteConfig
+	err = db.First(&existing, "key = ?", key).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if err := db.Create(&row).Error; err != nil {
+			return Entry{}, err
+		}
+	case err != nil:
+		return Entry{}, err
+	default:
+		if err := db.Model(&existing).Select("Value", "Type", "UpdatedAt").Updates(row).Error; err != nil {
+			return Entry{}, err
+		}
+	}
+
+	mu.Lock()
+	if values == nil {
+		values = make(map[string]string)
+	}
+	if updatedAt == nil {
+		updatedAt = make(map[string]time.Time)
+	}
+	values[key] = value
+	updatedAt[key] = now
+	mu.Unlock()
+
+	return entryFor(key, def, value, &now), nil
+}
+
+// normalize validates raw against typ and returns its canonical stored
+// string form.
+func normalize(typ ValueType, raw json.RawMessage) (string, error) {
+	switch typ {
+	case TypeString:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return "", ErrInvalidValue
+		}
+		return s, nil
+	case TypeBool:
+		var b bool
+		if err := json.Unmarshal(raw, &b); err != nil {
+			return "", ErrInvalidValue
+		}
+		return strconv.FormatBool(b), nil
+	case TypeInt:
+		var n int64
+		if err := json.Unmarshal(raw, &n); err != nil {
+			return "", ErrInvalidValue
+		}
+		return strconv.FormatInt(n, 10), nil
+	case TypeJSON:
+		var v any
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return "", ErrInvalidValue
+		}
+		compact, err := json.Marshal(v)
+		if err != nil {
+			return "", ErrInvalidValue
+		}
+		return string(compact), nil
+	default:
+		return "", ErrInvalidValue
+	}
+}
+
+func entryFor(key string, def keyDef, value string, at *time.Time) Entry {
+	if def.Sensitive && value != "" {
+		value = "********"
+	}
+	return Entry{Key: key, Value: value, Type: def.Type, Sensitive: def.Sensitive, Description: def.Description, UpdatedAt: at}
+}
+
+// Snapshot returns every known key's current entry, sorted by key, with
+// sensitive values masked.
+func Snapshot() []Entry {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	entries := make([]Entry, 0, len(knownKeys))
+	for key, def := range knownKeys {
+		value := values[key]
+		var at *time.Time
+		if ts, ok := updatedAt[key]; ok {
+			at = &ts
+		}
+		entries = append(entries, entryFor(key, def, value, at))
+	}
+	for i := 1; i < len(entries); i++ {
+		for j := i; j > 0 && entries[j-1].Key > entries[j].Key; j-- {
+			entries[j-1], entries[j] = entries[j], entries[j-1]
+		}
+	}
+	return entries
+}
+
+// GetString returns key's current raw string value, or "" if unknown.
+func GetString(key string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return values[key]
+}
+
+// GetBool returns key's current value parsed as a bool, or false if
+// unknown or unparsable.
+func GetBool(key string) bool {
+	b, _ := strconv.ParseBool(GetString(key))
+	return b
+}
+
+// GetInt returns key's current value parsed as an int, or 0 if unknown
+// or unparsable.
+func GetInt(key string) int {
+	n, _ := strconv.Atoi(GetString(key))
+	return n
+}
+
+// MaintenanceMode reports whether KeyMaintenanceMode is currently true.
+func MaintenanceMode() bool {
+	return GetBool(KeyMaintenanceMode)
+}
@@ -0,0 +1,113 @@
+package siteconfig
+
+import (
+	"testing"
+
+	"github.com/1055373165/blog/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	conn, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	if err := conn.AutoMigrate(&models.SiteConfig{}); err != nil {
+		t.Fatalf("failed to migrate test db: %v", err)
+	}
+	return conn
+}
+
+func TestLoadFallsBackToDefaultsWhenNoRowsExist(t *testing.T) {
+	db := setupTestDB(t)
+
+	if err := Load(db); err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if MaintenanceMode() {
+		t.Error("expected maintenance_mode default to be false")
+	}
+	if GetInt("rate_limit_per_minute") != 60 {
+		t.Errorf("expected default rate_limit_per_minute 60, got %d", GetInt("rate_limit_per_minute"))
+	}
+}
+
+func TestSetRejectsUnknownKey(t *testing.T) {
+	db := setupTestDB(t)
+	Load(db)
+
+	if _, err := Set(db, "not_a_real_key", []byte(`"x"`)); err != ErrUnknownKey {
+		t.Errorf("expected ErrUnknownKey, got %v", err)
+	}
+}
+
+func TestSetRejectsValueOfWrongType(t *testing.T) {
+	db := setupTestDB(t)
+	Load(db)
+
+	if _, err := Set(db, "rate_limit_per_minute", []byte(`"not a number"`)); err != ErrInvalidValue {
+		t.Errorf("expected ErrInvalidValue, got %v", err)
+	}
+}
+
+func TestSetTakesEffectImmediatelyWithoutReload(t *testing.T) {
+	db := setupTestDB(t)
+	Load(db)
+
+	if _, err := Set(db, KeyMaintenanceMode, []byte("true")); err != nil {
+		t.Fatalf("Set returned error: %v", err)
+	}
+	if !MaintenanceMode() {
+		t.Error("expected MaintenanceMode to be true immediately after Set, no reload")
+	}
+
+	var row models.SiteConfig
+	if err := db.First(&row, "key = ?", KeyMaintenanceMode).Error; err != nil {
+		t.Fatalf("failed to load persisted row: %v", err)
+	}
+	if row.Value != "true" {
+		t.Errorf("expected persisted value true, got %q", row.Value)
+	}
+}
+
+func TestSetOverwritesExistingRow(t *testing.T) {
+	db := setupTestDB(t)
+	Load(db)
+
+	if _, err := Set(db, "rate_limit_per_minute", []byte("30")); err != nil {
+		t.Fatalf("first Set returned error: %v", err)
+	}
+	if _, err := Set(db, "rate_limit_per_minute", []byte("90")); err != nil {
+		t.Fatalf("second Set returned error: %v", err)
+	}
+
+	var rows []models.SiteConfig
+	db.Where("key = ?", "rate_limit_per_minute").Find(&rows)
+	if len(rows) != 1 {
+		t.Fatalf("expected exactly 1 row for the key, got %d", len(rows))
+	}
+	if GetInt("rate_limit_per_minute") != 90 {
+		t.Errorf("expected 90, got %d", GetInt("rate_limit_per_minute"))
+	}
+}
+
+func TestSnapshotMasksSensitiveValuesOnceSet(t *testing.T) {
+	db := setupTestDB(t)
+	Load(db)
+
+	if _, err := Set(db, "smtp_password", []byte(`"s3cret"`)); err != nil {
+		t.Fatalf("Set returned error: %v", err)
+	}
+
+	var masked string
+	for _, entry := range Snapshot() {
+		if entry.Key == "smtp_password" {
+			masked = entry.Value
+		}
+	}
+	if masked != "********" {
+		t.Errorf("expected masked sensitive value, got %q", masked)
+	}
+}
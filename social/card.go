@@ -0,0 +1,92 @@
+// Package social renders Open Graph/Twitter card images for articles.
+//
+// Text is drawn with x/image's bundled basicfont, which only covers ASCII.
+// A truly CJK-capable font needs a bundled CJK font file (e.g. Noto Sans
+// CJK), which this environment has no way to fetch; until one is vendored,
+// non-ASCII titles will render with missing glyphs instead of readable
+// text.
+package social
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// CardWidth and CardHeight match the standard Open Graph image size.
+const (
+	CardWidth  = 1200
+	CardHeight = 630
+)
+
+var (
+	backgroundColor = color.RGBA{R: 0x16, G: 0x1b, B: 0x22, A: 0xff}
+	titleColor      = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	mutedColor      = color.RGBA{R: 0x9c, G: 0xa3, B: 0xaf, A: 0xff}
+)
+
+// GenerateCard renders a CardWidth x CardHeight PNG with title, authorName,
+// and brand (site name) and returns the encoded image bytes.
+func GenerateCard(title, authorName, brand string) ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, CardWidth, CardHeight))
+	draw.Draw(img, img.Bounds(), image.NewUniform(backgroundColor), image.Point{}, draw.Src)
+
+	face := basicfont.Face7x13
+	lineHeight := face.Metrics().Height.Ceil() + 10
+
+	titleLines := wrapText(title, 60)
+	drawText(img, face, titleColor, 80, 260, titleLines)
+	drawLine(img, face, mutedColor, 80, 260+(len(titleLines)+1)*lineHeight, "by "+authorName)
+	drawLine(img, face, mutedColor, 80, CardHeight-60, brand)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// wrapText greedily wraps text into lines no longer than width runes.
+func wrapText(text string, width int) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return nil
+	}
+
+	var lines []string
+	line := words[0]
+	for _, word := range words[1:] {
+		if len(line)+1+len(word) > width {
+			lines = append(lines, line)
+			line = word
+			continue
+		}
+		line += " " + word
+	}
+	lines = append(lines, line)
+	return lines
+}
+
+func drawText(img draw.Image, face font.Face, col color.Color, x, y int, lines []string) {
+	lineHeight := face.Metrics().Height.Ceil() + 10
+	for i, line := range lines {
+		drawLine(img, face, col, x, y+i*lineHeight, line)
+	}
+}
+
+func drawLine(img draw.Image, face font.Face, col color.Color, x, y int, text string) {
+	d := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(col),
+		Face: face,
+		Dot:  fixed.Point26_6{X: fixed.I(x), Y: fixed.I(y)},
+	}
+	d.DrawString(text)
+}
@@ -0,0 +1,36 @@
+package social
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+)
+
+func TestGenerateCardProducesValidPNGOfExpectedSize(t *testing.T) {
+	data, err := GenerateCard("A Long Enough Title To Force Wrapping Across Lines", "Jane Doe", "Blog")
+	if err != nil {
+		t.Fatalf("GenerateCard returned error: %v", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to decode generated PNG: %v", err)
+	}
+
+	bounds := img.Bounds()
+	if bounds.Dx() != CardWidth || bounds.Dy() != CardHeight {
+		t.Errorf("expected %dx%d image, got %dx%d", CardWidth, CardHeight, bounds.Dx(), bounds.Dy())
+	}
+}
+
+func TestWrapTextSplitsLongText(t *testing.T) {
+	lines := wrapText("one two three four five six seven eight nine ten", 20)
+	if len(lines) < 2 {
+		t.Fatalf("expected text to wrap across multiple lines, got %v", lines)
+	}
+	for _, line := range lines {
+		if len(line) > 20 {
+			t.Errorf("line %q exceeds width 20", line)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/1055373165/blog/config"
+	"github.com/1055373165/blog/siteconfig"
+	"github.com/gin-gonic/gin"
+)
+
+// excerptLength caps Excerpt's output at this many runes, long enough to
+// preview a gated article without exposing most of it.
+const excerptLength = 280
+
+var (
+	excerptCodeBlock        = regexp.MustCompile("(?s)```.*?```")
+	excerptInlineCode       = regexp.MustCompile("`([^`]*)`")
+	excerptImage            = regexp.MustCompile(`!\[[^\]]*\]\([^)]*\)`)
+	excerptLink             = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	excerptHeading          = regexp.MustCompile(`(?m)^[ \t]{0,3}#{1,6}[ \t]*`)
+	excerptHorizontalRule   = regexp.MustCompile(`(?m)^[ \t]*(?:-{3,}|\*{3,}|_{3,})[ \t]*$`)
+	excerptBlockquote       = regexp.MustCompile(`(?m)^[ \t]*>[ \t]?`)
+	excerptListMarker       = regexp.MustCompile(`(?m)^[ \t]*(?:[-*+]|\d+\.)[ \t]+`)
+	excerptBoldStar         = regexp.MustCompile(`\*\*(.+?)\*\*`)
+	excerptBoldUnderscore   = regexp.MustCompile(`__(.+?)__`)
+	excerptItalicStar       = regexp.MustCompile(`\*(.+?)\*`)
+	excerptItalicUnderscore = regexp.MustCompile(`_(.+?)_`)
+	excerptWhitespace       = regexp.MustCompile(`\s+`)
+	excerptSentenceEnd      = "。！？.!?"
+)
+
+// StripMarkdown strips Markdown syntax from content, leaving roughly
+// the plain text a reader would see rendered. Fenced code blocks are
+// dropped entirely (including their content); images are dropped
+// entirely (including their alt text, which isn't prose); links keep
+// their link text but drop the URL; headings, emphasis, blockquotes,
+// list markers, and horizontal rules have their syntax stripped; and
+// all whitespace, including the newlines Markdown uses for structure,
+// collapses to single spaces.
+func StripMarkdown(content string) string {
+	s := excerptCodeBlock.ReplaceAllString(content, " ")
+	s = excerptImage.ReplaceAllString(s, "")
+	s = excerptLink.ReplaceAllString(s, "$1")
+	s = excerptInlineCode.ReplaceAllString(s, "$1")
+	s = excerptHeading.ReplaceAllString(s, "")
+	s = excerptHorizontalRule.ReplaceAllString(s, "")
+	s = excerptBlockquote.ReplaceAllString(s, "")
+	s = excerptListMarker.ReplaceAllString(s, "")
+	s = excerptBoldStar.ReplaceAllString(s, "$1")
+	s = excerptBoldUnderscore.ReplaceAllString(s, "$1")
+	s = excerptItalicStar.ReplaceAllString(s, "$1")
+	s = excerptItalicUnderscore.ReplaceAllString(s, "$1")
+	s = excerptWhitespace.ReplaceAllString(s, " ")
+	return strings.TrimSpace(s)
+}
+
+// Excerpt strips Markdown syntax from content (see StripMarkdown) and
+// truncates the result to excerptLength runes, preferring to cut at
+// the end of a sentence, then a word, rather than mid-word or
+// mid-multibyte-character, appending an ellipsis if anything was cut.
+func Excerpt(content string) string {
+	return truncateAtBoundary(StripMarkdown(content), excerptLength)
+}
+
+// truncateAtBoundary truncates plain to at most maxRunes runes,
+// preferring the last sentence boundary in range, then the last word
+// boundary, falling back to a hard cut only when neither exists,
+// appending an ellipsis if anything was cut.
+func truncateAtBoundary(plain string, maxRunes int) string {
+	runes := []rune(plain)
+	if len(runes) <= maxRunes {
+		return plain
+	}
+
+	cut := string(runes[:maxRunes])
+	if i := strings.LastIndexAny(cut, excerptSentenceEnd); i > 0 {
+		_, size := utf8.DecodeRuneInString(cut[i:])
+		return strings.TrimSpace(cut[:i+size]) + "..."
+	}
+	if i := strings.LastIndexAny(cut, " \t\n"); i > 0 {
+		return strings.TrimSpace(cut[:i]) + "..."
+	}
+	return strings.TrimSpace(cut) + "..."
+}
+
+// absoluteUploadURL matches an absolute URL pointing at our own file or
+// media endpoints, e.g. http://localhost:3001/api/files/a.png or
+// https://www.godepth.top/api/media/a.png. The first submatch is the
+// canonical relative form.
+var absoluteUploadURL = regexp.MustCompile(`https?://[^/\s"']+(/api/(?:files|media)/[^\s"')]+)`)
+
+// relativeUploadURL matches a canonical relative upload reference.
+var relativeUploadURL = regexp.MustCompile(`(/api/(?:files|media)/[^\s"')]+)`)
+
+// uploadReference matches a standalone reference (as opposed to one
+// embedded in a larger body of content) that is either the canonical
+// relative form or an absolute URL pointing at the same endpoint,
+// capturing the stored filename.
+var uploadReference = regexp.MustCompile(`^(?:https?://[^/\s"']+)?/api/(?:files|media)/([^\s"'?#]+)$`)
+
+// ParseUploadReference extracts the stored filename from ref if ref is
+// a standalone reference to this site's own upload storage - either
+// the canonical relative form ("/api/files/<name>") or an absolute URL
+// pointing at the same endpoint - reporting ok=false for anything else,
+// including external hotlinks.
+func ParseUploadReference(ref string) (filename string, ok bool) {
+	m := uploadReference.FindStringSubmatch(ref)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+// NormalizeContentURLs rewrites any absolute URL pointing at our own
+// upload endpoints into its canonical relative form, so stored content
+// survives an environment or domain change without a manual migration.
+// It returns the rewritten content and the number of URLs rewritten.
+func NormalizeContentURLs(content string) (string, int) {
+	count := 0
+	rewritten := absoluteUploadURL.ReplaceAllString(content, "$1")
+	if rewritten != content {
+		count = len(absoluteUploadURL.FindAllString(content, -1))
+	}
+	return rewritten, count
+}
+
+// ExpandContentURLs rewrites canonical relative upload references in
+// content into absolute URLs under baseURL, for responses served to
+// clients that resolve relative URLs against a different origin.
+func ExpandContentURLs(content, baseURL string) string {
+	if baseURL == "" {
+		return content
+	}
+	return relativeUploadURL.ReplaceAllString(content, trimTrailingSlash(baseURL)+"$1")
+}
+
+// EnforceContentLength reports whether content is within limitKey's
+// current siteconfig-tunable byte ceiling, writing a 422
+// FailContentTooLarge response and reporting false otherwise. Length is
+// measured with len(content) - the raw byte count of the string, not
+// its rune count - so multi-byte UTF-8 content (e.g. Chinese prose)
+// isn't undercounted relative to what's actually stored. Callers should
+// return immediately when it reports false.
+func EnforceContentLength(c *gin.Context, field, content, limitKey string) bool {
+	limit := siteconfig.GetInt(limitKey)
+	if limit <= 0 || len(content) <= limit {
+		return true
+	}
+	FailContentTooLarge(c, field, limit, len(content))
+	return false
+}
+
+// ResolvePublicBase returns the configured public base URL: the
+// siteconfig "public_base_url" key if an admin has set one (so it takes
+// effect without a restart), else the BLOG_PUBLIC_BASE env var. When
+// neither is configured, it derives one from the request's Host and
+// X-Forwarded-Proto headers so reverse-proxied deployments work
+// unconfigured.
+func ResolvePublicBase(c *gin.Context) string {
+	if v := siteconfig.GetString("public_base_url"); v != "" {
+		return trimTrailingSlash(v)
+	}
+	if config.App.PublicBase != "" {
+		return trimTrailingSlash(config.App.PublicBase)
+	}
+
+	scheme := c.GetHeader("X-Forwarded-Proto")
+	if scheme == "" {
+		scheme = "http"
+		if c.Request.TLS != nil {
+			scheme = "https"
+		}
+	}
+	return scheme + "://" + c.Request.Host
+}
+
+func trimTrailingSlash(s string) string {
+	if len(s) > 0 && s[len(s)-1] == '/' {
+		return s[:len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,148 @@
+package utils
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/1055373165/blog/config"
+	"github.com/gin-gonic/gin"
+)
+
+func TestNormalizeContentURLsRewritesAbsoluteForms(t *testing.T) {
+	content := `<p><img src="http://localhost:3001/api/files/a.png"></p>` +
+		`<img src="https://www.godepth.top/api/media/b.mp4">`
+
+	got, count := NormalizeContentURLs(content)
+	want := `<p><img src="/api/files/a.png"></p><img src="/api/media/b.mp4">`
+
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if count != 2 {
+		t.Errorf("expected 2 urls rewritten, got %d", count)
+	}
+}
+
+func TestNormalizeContentURLsLeavesRelativeFormsAlone(t *testing.T) {
+	content := `<img src="/api/files/a.png">`
+	got, count := NormalizeContentURLs(content)
+	if got != content {
+		t.Errorf("expected content unchanged, got %q", got)
+	}
+	if count != 0 {
+		t.Errorf("expected 0 urls rewritten, got %d", count)
+	}
+}
+
+func TestExpandContentURLsAddsBaseURL(t *testing.T) {
+	content := `<img src="/api/files/a.png"> and <img src="/api/media/b.mp4">`
+	got := ExpandContentURLs(content, "https://www.godepth.top/")
+	want := `<img src="https://www.godepth.top/api/files/a.png"> and <img src="https://www.godepth.top/api/media/b.mp4">`
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestExpandContentURLsNoopWithoutBaseURL(t *testing.T) {
+	content := `<img src="/api/files/a.png">`
+	if got := ExpandContentURLs(content, ""); got != content {
+		t.Errorf("expected content unchanged, got %q", got)
+	}
+}
+
+func TestResolvePublicBaseUsesConfiguredValue(t *testing.T) {
+	config.App = &config.Config{PublicBase: "https://blog.example.com/"}
+
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/", nil)
+
+	if got, want := ResolvePublicBase(c), "https://blog.example.com"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestStripMarkdownDropsCodeBlocksAndImages(t *testing.T) {
+	content := "# Title\n\nSome **bold** and _italic_ text with a [link](https://example.com/a) " +
+		"and an image ![alt text](https://example.com/b.png).\n\n" +
+		"```go\nfunc main() {}\n```\n\nInline `code()` stays readable."
+	got := StripMarkdown(content)
+	want := "Title Some bold and italic text with a link and an image . Inline code() stays readable."
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestStripMarkdownHandlesListsQuotesAndRules(t *testing.T) {
+	content := "> A quote\n\n- one\n- two\n\n1. first\n2. second\n\n---\n\nDone"
+	got := StripMarkdown(content)
+	want := "A quote one two first second Done"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestStripMarkdownHandlesCJKAndEmoji(t *testing.T) {
+	content := "# 标题\n\n这是**重点**内容 🎉，附带一个链接 [点击这里](https://example.com)。"
+	got := StripMarkdown(content)
+	want := "标题 这是重点内容 🎉，附带一个链接 点击这里。"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestExcerptReturnsShortContentUnchanged(t *testing.T) {
+	content := "# Short\n\nJust a little text."
+	if got, want := Excerpt(content), "Short Just a little text."; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestExcerptTruncatesAtSentenceBoundary(t *testing.T) {
+	sentence := "This is a complete sentence with enough words to matter. "
+	content := strings.Repeat(sentence, 10)
+	got := Excerpt(content)
+	if !strings.HasSuffix(got, "...") {
+		t.Fatalf("expected excerpt to end with an ellipsis, got %q", got)
+	}
+	if strings.HasSuffix(strings.TrimSuffix(got, "..."), " with enough words to matter") {
+		t.Errorf("expected truncation at a sentence boundary, got %q", got)
+	}
+	if utf8.RuneCountInString(got) > excerptLength+3 {
+		t.Errorf("expected excerpt to stay near %d runes, got %d: %q", excerptLength, utf8.RuneCountInString(got), got)
+	}
+}
+
+func TestExcerptTruncatesCJKAtRuneBoundaryNotMidCharacter(t *testing.T) {
+	content := strings.Repeat("测试内容没有任何标点符号所以只能按字切分", 20)
+	got := Excerpt(content)
+	if !utf8.ValidString(got) {
+		t.Fatalf("expected valid UTF-8, got invalid string of length %d", len(got))
+	}
+	if !strings.HasSuffix(got, "...") {
+		t.Errorf("expected excerpt to end with an ellipsis, got %q", got)
+	}
+}
+
+func TestExcerptSkipsImageAltTextLeadingContent(t *testing.T) {
+	content := "![a screenshot of the dashboard](https://example.com/shot.png)\n\nThe dashboard now loads instantly."
+	if got, want := Excerpt(content), "The dashboard now loads instantly."; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestResolvePublicBaseFallsBackToRequest(t *testing.T) {
+	config.App = &config.Config{PublicBase: ""}
+
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/", nil)
+	c.Request.Host = "example.test"
+	c.Request.Header.Set("X-Forwarded-Proto", "https")
+
+	if got, want := ResolvePublicBase(c), "https://example.test"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
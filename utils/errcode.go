@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorCode is a stable, machine-readable identifier carried alongside
+// a failure response's human-readable Message, so API clients can
+// branch on errors without string-matching locale-dependent prose.
+type ErrorCode string
+
+const (
+	ErrCodeArticleNotFound ErrorCode = "ARTICLE_NOT_FOUND"
+	ErrCodeAdminRequired   ErrorCode = "ADMIN_REQUIRED"
+	ErrCodeSlugConflict    ErrorCode = "SLUG_CONFLICT"
+	ErrCodeContentTooLarge ErrorCode = "CONTENT_TOO_LARGE"
+)
+
+// defaultLocale is served when Accept-Language names no locale the
+// catalog has a translation for, or names none at all.
+const defaultLocale = "zh-CN"
+
+// catalog holds, for each migrated ErrorCode, its message in every
+// locale FailWithCode can select - see README "Known gaps" for which
+// error sites have and haven't been migrated onto this yet.
+var catalog = map[ErrorCode]map[string]string{
+	ErrCodeArticleNotFound: {
+		"zh-CN": "文章不存在",
+		"en-US": "article not found",
+	},
+	ErrCodeAdminRequired: {
+		"zh-CN": "需要管理员权限",
+		"en-US": "admin privileges required",
+	},
+	ErrCodeSlugConflict: {
+		"zh-CN": "slug 已被占用",
+		"en-US": "slug already in use",
+	},
+	ErrCodeContentTooLarge: {
+		"zh-CN": "内容超出长度限制",
+		"en-US": "content exceeds the length limit",
+	},
+}
+
+// localeFromAcceptLanguage maps header (an Accept-Language value, e.g.
+// "en-US,en;q=0.9,zh-CN;q=0.8") to the first of the catalog's supported
+// locales it names, falling back to defaultLocale for an empty,
+// unparseable, or otherwise unsupported header. It only looks at
+// language prefixes ("zh"/"en"), not region subtags or q-weights, since
+// the catalog only ever carries one locale per language so far.
+func localeFromAcceptLanguage(header string) string {
+	header = strings.ToLower(header)
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(strings.SplitN(tag, ";", 2)[0])
+		switch {
+		case strings.HasPrefix(tag, "zh"):
+			return "zh-CN"
+		case strings.HasPrefix(tag, "en"):
+			return "en-US"
+		}
+	}
+	return defaultLocale
+}
+
+// localizedMessage resolves code's catalog entry for the request's
+// Accept-Language header, falling back to defaultLocale when the
+// header names no locale the catalog has a translation for.
+func localizedMessage(c *gin.Context, code ErrorCode) string {
+	locale := localeFromAcceptLanguage(c.GetHeader("Accept-Language"))
+	messages := catalog[code]
+	msg := messages[locale]
+	if msg == "" {
+		msg = messages[defaultLocale]
+	}
+	return msg
+}
+
+// FailWithCode writes a failure response like Fail, but selects its
+// message from the catalog by code and the request's Accept-Language
+// header, and includes code itself as ErrorCode so clients can branch
+// on it instead of the localized message text.
+func FailWithCode(c *gin.Context, status int, code ErrorCode) {
+	c.JSON(status, Response{Code: status, Message: localizedMessage(c, code), ErrorCode: string(code)})
+}
+
+// ContentTooLargeDetail is the Details payload FailContentTooLarge
+// writes, so a client can report which field was over the limit, by
+// how much, without parsing the localized message.
+type ContentTooLargeDetail struct {
+	Field       string `json:"field"`
+	LimitBytes  int    `json:"limit_bytes"`
+	ActualBytes int    `json:"actual_bytes"`
+}
+
+// FailContentTooLarge writes a 422 ErrCodeContentTooLarge failure for a
+// field whose byte length exceeds a siteconfig-tunable cap, carrying
+// the limit and the caller's actual size in Details so a client can
+// warn before resubmitting rather than just retrying blind.
+func FailContentTooLarge(c *gin.Context, field string, limitBytes, actualBytes int) {
+	c.JSON(422, Response{
+		Code:      422,
+		Message:   localizedMessage(c, ErrCodeContentTooLarge),
+		ErrorCode: string(ErrCodeContentTooLarge),
+		Details:   ContentTooLargeDetail{Field: field, LimitBytes: limitBytes, ActualBytes: actualBytes},
+	})
+}
@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestLocaleFromAcceptLanguage(t *testing.T) {
+	cases := []struct {
+		header string
+		want   string
+	}{
+		{"zh-CN,zh;q=0.9", "zh-CN"},
+		{"en-US,en;q=0.9", "en-US"},
+		{"fr-FR", defaultLocale},
+		{"", defaultLocale},
+		{"en;q=0.8,zh-CN;q=0.9", "en-US"},
+	}
+	for _, tc := range cases {
+		if got := localeFromAcceptLanguage(tc.header); got != tc.want {
+			t.Errorf("localeFromAcceptLanguage(%q) = %q, want %q", tc.header, got, tc.want)
+		}
+	}
+}
+
+func TestFailWithCodeSelectsMessageByAcceptLanguage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/", nil)
+	c.Request.Header.Set("Accept-Language", "en-US")
+
+	FailWithCode(c, 404, ErrCodeArticleNotFound)
+
+	if w.Code != 404 {
+		t.Fatalf("expected 404, got %d", w.Code)
+	}
+	var resp Response
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.ErrorCode != string(ErrCodeArticleNotFound) {
+		t.Errorf("expected error_code %q, got %q", ErrCodeArticleNotFound, resp.ErrorCode)
+	}
+	if resp.Message != "article not found" {
+		t.Errorf("expected English message, got %q", resp.Message)
+	}
+}
+
+func TestFailWithCodeDefaultsToZhCNWhenNoAcceptLanguage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/", nil)
+
+	FailWithCode(c, 404, ErrCodeArticleNotFound)
+
+	var resp Response
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.Message != "文章不存在" {
+		t.Errorf("expected the zh-CN fallback message, got %q", resp.Message)
+	}
+}
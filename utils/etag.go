@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConditionalJSON serializes data, compares its weak ETag against the
+// request's If-None-Match header, and responds 304 with no body on a
+// match or 200 with the body and a fresh ETag otherwise. Callers must
+// strip per-viewer state (e.g. is_liked) from data before calling this,
+// since that state would otherwise defeat caching across users.
+func ConditionalJSON(c *gin.Context, data any) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		Fail(c, 500, "failed to encode response")
+		return
+	}
+
+	etag := WeakETag(body)
+	c.Header("ETag", etag)
+
+	if match := c.GetHeader("If-None-Match"); match != "" && match == etag {
+		c.AbortWithStatus(304)
+		return
+	}
+
+	c.Data(200, "application/json; charset=utf-8", body)
+}
+
+// WeakETag computes a weak ETag (RFC 7232) from an arbitrary byte slice.
+func WeakETag(data []byte) string {
+	sum := md5.Sum(data)
+	return `W/"` + hex.EncodeToString(sum[:]) + `"`
+}
@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestConditionalJSONReturns304OnMatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	data := map[string]string{"hello": "world"}
+
+	w1 := httptest.NewRecorder()
+	c1, _ := gin.CreateTestContext(w1)
+	c1.Request = httptest.NewRequest("GET", "/", nil)
+	ConditionalJSON(c1, data)
+	if w1.Code != 200 {
+		t.Fatalf("expected 200 on first request, got %d", w1.Code)
+	}
+	etag := w1.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("expected ETag header to be set")
+	}
+
+	w2 := httptest.NewRecorder()
+	c2, _ := gin.CreateTestContext(w2)
+	c2.Request = httptest.NewRequest("GET", "/", nil)
+	c2.Request.Header.Set("If-None-Match", etag)
+	ConditionalJSON(c2, data)
+	if w2.Code != 304 {
+		t.Fatalf("expected 304 when If-None-Match matches, got %d", w2.Code)
+	}
+}
@@ -0,0 +1,43 @@
+package utils
+
+import "encoding/json"
+
+// OptionalUint distinguishes an absent JSON field from an explicit JSON
+// null in a PATCH-style update request. A plain `*uint` field can't do
+// this on its own: both an omitted key and `"key": null` decode to a nil
+// pointer, so a handler can never tell "leave unchanged" apart from
+// "clear this nullable foreign key". encoding/json only calls
+// UnmarshalJSON when the key is present in the payload at all, so Set
+// stays false for an omitted key and becomes true for both null and a
+// real value - that's the signal a handler needs.
+//
+// Use it for a *uint field a caller must be able to null out, e.g. an
+// optional foreign key. Zero value (Set: false) means "not provided".
+type OptionalUint struct {
+	Set   bool
+	Value *uint
+}
+
+func (o *OptionalUint) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	if string(data) == "null" {
+		o.Value = nil
+		return nil
+	}
+	return json.Unmarshal(data, &o.Value)
+}
+
+// OptionalInt is OptionalUint for a *int field.
+type OptionalInt struct {
+	Set   bool
+	Value *int
+}
+
+func (o *OptionalInt) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	if string(data) == "null" {
+		o.Value = nil
+		return nil
+	}
+	return json.Unmarshal(data, &o.Value)
+}
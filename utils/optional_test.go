@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestOptionalUintDistinguishesAbsentNullAndValue(t *testing.T) {
+	var req struct {
+		ID OptionalUint `json:"id"`
+	}
+
+	if err := json.Unmarshal([]byte(`{}`), &req); err != nil {
+		t.Fatalf("unmarshal returned error: %v", err)
+	}
+	if req.ID.Set {
+		t.Error("expected Set=false for an omitted field")
+	}
+
+	if err := json.Unmarshal([]byte(`{"id":null}`), &req); err != nil {
+		t.Fatalf("unmarshal returned error: %v", err)
+	}
+	if !req.ID.Set || req.ID.Value != nil {
+		t.Errorf("expected Set=true, Value=nil for an explicit null, got Set=%v Value=%v", req.ID.Set, req.ID.Value)
+	}
+
+	if err := json.Unmarshal([]byte(`{"id":5}`), &req); err != nil {
+		t.Fatalf("unmarshal returned error: %v", err)
+	}
+	if !req.ID.Set || req.ID.Value == nil || *req.ID.Value != 5 {
+		t.Errorf("expected Set=true, Value=5, got Set=%v Value=%v", req.ID.Set, req.ID.Value)
+	}
+}
+
+func TestOptionalIntDistinguishesAbsentNullAndValue(t *testing.T) {
+	var req struct {
+		Order OptionalInt `json:"order"`
+	}
+
+	if err := json.Unmarshal([]byte(`{}`), &req); err != nil {
+		t.Fatalf("unmarshal returned error: %v", err)
+	}
+	if req.Order.Set {
+		t.Error("expected Set=false for an omitted field")
+	}
+
+	if err := json.Unmarshal([]byte(`{"order":null}`), &req); err != nil {
+		t.Fatalf("unmarshal returned error: %v", err)
+	}
+	if !req.Order.Set || req.Order.Value != nil {
+		t.Errorf("expected Set=true, Value=nil for an explicit null, got Set=%v Value=%v", req.Order.Set, req.Order.Value)
+	}
+
+	if err := json.Unmarshal([]byte(`{"order":-2}`), &req); err != nil {
+		t.Fatalf("unmarshal returned error: %v", err)
+	}
+	if !req.Order.Set || req.Order.Value == nil || *req.Order.Value != -2 {
+		t.Errorf("expected Set=true, Value=-2, got Set=%v Value=%v", req.Order.Set, req.Order.Value)
+	}
+}
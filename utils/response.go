@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// statusClientClosedRequest is nginx's unofficial 499, used for a request
+// whose context was canceled (client disconnect) or timed out server-side
+// before a database call finished, so it doesn't look like a 500 server
+// failure in logs/metrics.
+const statusClientClosedRequest = 499
+
+// Response is the envelope returned by every API endpoint.
+type Response struct {
+	Code      int    `json:"code"`
+	Message   string `json:"message"`
+	Data      any    `json:"data,omitempty"`
+	Details   any    `json:"details,omitempty"`
+	ErrorCode string `json:"error_code,omitempty"`
+}
+
+// OK writes a 200 response wrapping data.
+func OK(c *gin.Context, data any) {
+	c.JSON(200, Response{Code: 0, Message: "success", Data: data})
+}
+
+// Fail writes a failure response with the given HTTP status and message.
+func Fail(c *gin.Context, status int, message string) {
+	c.JSON(status, Response{Code: status, Message: message})
+}
+
+// FailFromDBError writes a failure response for a database error: 499 if
+// err is (or wraps) a canceled or deadline-exceeded context - the caller
+// disconnected, or a query hit its db.WithCtx timeout - rather than the
+// generic 500 a real server-side failure gets under fallbackMessage.
+func FailFromDBError(c *gin.Context, err error, fallbackMessage string) {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		Fail(c, statusClientClosedRequest, "client closed request")
+		return
+	}
+	Fail(c, 500, fallbackMessage)
+}
+
+// FailValidation writes a 400 response carrying field-level validation
+// errors in Details, for clients that can't present raw validator prose.
+func FailValidation(c *gin.Context, errs []FieldError) {
+	c.JSON(400, Response{Code: 400, Message: "validation failed", Details: errs})
+}
+
+// RespondNotFoundOrForbidden answers a permission failure without
+// letting the response itself confirm whether a private resource
+// exists: when visible is false, it always writes a 404 with
+// notFoundMessage, whether the resource is missing or simply not the
+// caller's to see. Pass visible true only for resources that are
+// legitimately visible to the caller already (e.g. a published
+// article), where a 403 with forbiddenMessage doesn't leak anything a
+// GET hadn't already.
+func RespondNotFoundOrForbidden(c *gin.Context, visible bool, notFoundMessage, forbiddenMessage string) {
+	if visible {
+		Fail(c, 403, forbiddenMessage)
+		return
+	}
+	Fail(c, 404, notFoundMessage)
+}
+
+// SlugRedirect responds to a stale-slug lookup with the new slug. By
+// default it's a 301 JSON payload the frontend can follow itself; with
+// ?follow=true it issues an actual HTTP redirect to newLocation.
+func SlugRedirect(c *gin.Context, newLocation, newSlug string) {
+	if c.Query("follow") == "true" {
+		c.Redirect(301, newLocation)
+		return
+	}
+	c.JSON(301, Response{Code: 301, Message: "moved permanently", Data: gin.H{"slug": newSlug}})
+}
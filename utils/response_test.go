@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRespondNotFoundOrForbiddenHidesExistenceWhenNotVisible(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	RespondNotFoundOrForbidden(c, false, "submission not found", "not authorized")
+
+	if w.Code != 404 {
+		t.Fatalf("expected 404 when the resource isn't visible to the caller, got %d", w.Code)
+	}
+}
+
+func TestRespondNotFoundOrForbiddenAllows403WhenVisible(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	RespondNotFoundOrForbidden(c, true, "submission not found", "not authorized")
+
+	if w.Code != 403 {
+		t.Fatalf("expected 403 when the resource is already visible to the caller, got %d", w.Code)
+	}
+}
@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IsSameOriginRequest reports whether c's Origin header - or, if the
+// browser omitted it, its Referer - names the same host the request
+// actually arrived on. It's meant to gate lightweight write endpoints
+// (e.g. a view-count increment) against trivial cross-origin or
+// curl-level abuse: a real page load always sends one of these headers
+// naming its own origin, while a bot hitting the endpoint directly
+// typically sends neither. This is not CSRF protection - nothing here
+// is unguessable - it only raises the cost of the kind of drive-by
+// inflation the endpoint is meant to discourage.
+func IsSameOriginRequest(c *gin.Context) bool {
+	header := c.GetHeader("Origin")
+	if header == "" {
+		header = c.GetHeader("Referer")
+	}
+	if header == "" {
+		return false
+	}
+	u, err := url.Parse(header)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	return u.Host == c.Request.Host
+}
@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newSameOriginTestContext(host, origin, referer string) *gin.Context {
+	req := httptest.NewRequest("POST", "http://"+host+"/x", nil)
+	req.Host = host
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	if referer != "" {
+		req.Header.Set("Referer", referer)
+	}
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = req
+	return c
+}
+
+func TestIsSameOriginRequest(t *testing.T) {
+	cases := []struct {
+		name    string
+		host    string
+		origin  string
+		referer string
+		want    bool
+	}{
+		{"matching origin", "example.com", "http://example.com", "", true},
+		{"matching referer fallback", "example.com", "", "http://example.com/page", true},
+		{"mismatched origin", "example.com", "http://evil.example", "", false},
+		{"no headers", "example.com", "", "", false},
+		{"unparseable origin", "example.com", "://not a url", "", false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := newSameOriginTestContext(tc.host, tc.origin, tc.referer)
+			if got := IsSameOriginRequest(c); got != tc.want {
+				t.Errorf("IsSameOriginRequest() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+)
+
+// ErrUploadPathNotFound is returned by ResolveUploadPath when requestPath
+// doesn't resolve to an existing file under the target subdirectory.
+var ErrUploadPathNotFound = errors.New("upload path not found")
+
+// ErrUploadPathForbidden is returned by ResolveUploadPath when
+// requestPath - after cleaning and resolving symlinks - would escape the
+// subdirectory it's meant to be served from, e.g. via "..", a null
+// byte, an absolute path, or a symlink planted inside the subdirectory
+// that points back out of it.
+var ErrUploadPathForbidden = errors.New("upload path forbidden")
+
+// ResolveUploadPath resolves requestPath to a file under root/subdir
+// (subdir may be "" to serve directly out of root) and returns its
+// fully-resolved path on disk, verifying containment within that
+// specific subdirectory rather than just root - so, e.g., a request
+// against the media route can't be used to reach a file only ever
+// stored under the files route's subdirectory, and vice versa.
+//
+// requestPath is reduced to its final path segment via filepath.Base
+// before joining, same as the rest of this codebase's upload handling,
+// so "..", "/etc/passwd", and similar are neutralized outright. On top
+// of that, both the subdirectory root and the resolved candidate are
+// passed through filepath.EvalSymlinks before the containment check, so
+// a symlink sitting inside the subdirectory can't be used to serve a
+// file that lives outside it. A null byte anywhere in requestPath is
+// rejected unconditionally, since it can't appear in a legitimate
+// filename and historically has been used to truncate path checks in
+// other languages' runtimes.
+func ResolveUploadPath(root, subdir, requestPath string) (string, error) {
+	if strings.ContainsRune(requestPath, 0) {
+		return "", ErrUploadPathForbidden
+	}
+
+	base := filepath.Base(filepath.Clean(requestPath))
+	if base == "." || base == ".." || base == string(filepath.Separator) {
+		return "", ErrUploadPathForbidden
+	}
+
+	subdirRoot := filepath.Join(root, subdir)
+	resolvedRoot, err := filepath.EvalSymlinks(subdirRoot)
+	if err != nil {
+		return "", ErrUploadPathNotFound
+	}
+
+	resolvedCandidate, err := filepath.EvalSymlinks(filepath.Join(subdirRoot, base))
+	if err != nil {
+		return "", ErrUploadPathNotFound
+	}
+
+	if resolvedCandidate != resolvedRoot && !strings.HasPrefix(resolvedCandidate, resolvedRoot+string(filepath.Separator)) {
+		return "", ErrUploadPathForbidden
+	}
+
+	return resolvedCandidate, nil
+}
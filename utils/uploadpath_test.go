@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveUploadPathServesFileInRoot(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "a.png"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ResolveUploadPath(root, "", "a.png")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want, _ := filepath.EvalSymlinks(filepath.Join(root, "a.png"))
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestResolveUploadPathServesFileInSubdir(t *testing.T) {
+	root := t.TempDir()
+	subdir := filepath.Join("submissions", "5")
+	if err := os.MkdirAll(filepath.Join(root, subdir), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, subdir, "report.pdf"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ResolveUploadPath(root, subdir, "report.pdf")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want, _ := filepath.EvalSymlinks(filepath.Join(root, subdir, "report.pdf"))
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestResolveUploadPathRejectsTraversal(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "files"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "secret.txt"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []string{
+		"../secret.txt",
+		"..",
+		"/etc/passwd",
+		"a\x00b",
+	}
+	for _, rp := range cases {
+		_, err := ResolveUploadPath(root, "files", rp)
+		if !errors.Is(err, ErrUploadPathForbidden) && !errors.Is(err, ErrUploadPathNotFound) {
+			t.Errorf("requestPath %q: got err %v, want forbidden or not found", rp, err)
+		}
+	}
+}
+
+func TestResolveUploadPathRejectsSymlinkEscape(t *testing.T) {
+	root := t.TempDir()
+	subdir := filepath.Join(root, "files")
+	if err := os.MkdirAll(subdir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	outside := t.TempDir()
+	secret := filepath.Join(outside, "secret.txt")
+	if err := os.WriteFile(secret, []byte("top secret"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	link := filepath.Join(subdir, "escape.txt")
+	if err := os.Symlink(secret, link); err != nil {
+		t.Skipf("symlinks not supported in this environment: %v", err)
+	}
+
+	_, err := ResolveUploadPath(root, "files", "escape.txt")
+	if !errors.Is(err, ErrUploadPathForbidden) {
+		t.Fatalf("got err %v, want ErrUploadPathForbidden", err)
+	}
+}
+
+func TestResolveUploadPathNotFound(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "files"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := ResolveUploadPath(root, "files", "missing.txt")
+	if !errors.Is(err, ErrUploadPathNotFound) {
+		t.Fatalf("got err %v, want ErrUploadPathNotFound", err)
+	}
+}
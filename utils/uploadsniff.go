@@ -0,0 +1,120 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ErrUploadContentMismatch is returned by VerifyUploadContentType when
+// an upload's sniffed content contradicts what its filename claims to
+// be - e.g. an executable renamed to end in ".pdf" so it's served back
+// out with a trusted, harmless-looking content type.
+var ErrUploadContentMismatch = errors.New("upload content does not match its claimed extension")
+
+// executableSignatures are magic numbers for formats that should never
+// be let through under a non-executable extension, regardless of what
+// http.DetectContentType calls them (it has no dedicated case for a
+// Windows PE or Mach-O binary and falls back to
+// "application/octet-stream", the same as plenty of legitimate binary
+// formats - so the check here is the byte signature itself, not
+// DetectContentType's answer).
+var executableSignatures = [][]byte{
+	[]byte("MZ"),               // Windows PE (.exe, .dll)
+	[]byte("\x7fELF"),          // Linux ELF
+	[]byte("\xca\xfe\xba\xbe"), // Mach-O / Java class (fat binary magic)
+	[]byte("\xfe\xed\xfa\xce"), // Mach-O 32-bit
+	[]byte("\xfe\xed\xfa\xcf"), // Mach-O 64-bit
+	[]byte("\xcf\xfa\xed\xfe"), // Mach-O 64-bit, byte-swapped
+}
+
+// executableExtensions are the only extensions executableSignatures are
+// allowed to appear under.
+var executableExtensions = map[string]bool{
+	".exe": true, ".dll": true, ".so": true, ".dylib": true, ".bin": true,
+}
+
+// imageExtensions are the only extensions an upload sniffing as HTML or
+// XML is allowed to claim - browsers that content-sniff a response
+// despite its declared image Content-Type (see the nosniff header
+// serveUploadedFile sends) would otherwise execute an HTML or SVG file
+// uploaded as e.g. "photo.png" when it's later served back out inline.
+var imageExtensions = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".webp": true, ".bmp": true, ".ico": true,
+}
+
+// zipBasedExtensions are container formats http.DetectContentType can
+// only ever report generically as "application/zip" - it has no
+// signature for the format-specific internal layout that actually
+// makes a .docx a .docx rather than an arbitrary zip. The value is a
+// path VerifyUploadContentType requires to exist inside the archive for
+// that claim to hold.
+var zipBasedExtensions = map[string]string{
+	".docx": "word/document.xml",
+	".xlsx": "xl/workbook.xml",
+	".pptx": "ppt/presentation.xml",
+	".epub": "mimetype",
+}
+
+// VerifyUploadContentType checks sniffHeader (an upload's first ~512
+// bytes, as passed to http.DetectContentType elsewhere in this
+// codebase) against ext, the claimed extension, rejecting the
+// combination with ErrUploadContentMismatch when:
+//
+//   - sniffHeader carries an executable's magic number under a
+//     non-executable ext (e.g. malware.exe renamed to report.pdf)
+//   - ext names a ZIP-based office/epub format but sniffHeader doesn't
+//     sniff as a ZIP at all
+//
+// full and size, when full is non-nil, let a ZIP-based ext be opened as
+// an actual archive (via io.ReaderAt, so a large upload already on disk
+// doesn't need loading into memory) to check for its format-defining
+// entry; callers that can't provide it (e.g. because the file hasn't
+// finished streaming to disk yet) can pass nil and still get the
+// executable-signature check.
+func VerifyUploadContentType(sniffHeader []byte, ext string, full io.ReaderAt, size int64) error {
+	ext = strings.ToLower(ext)
+	sniffed := http.DetectContentType(sniffHeader)
+
+	for _, sig := range executableSignatures {
+		if bytes.HasPrefix(sniffHeader, sig) && !executableExtensions[ext] {
+			return ErrUploadContentMismatch
+		}
+	}
+
+	if imageExtensions[ext] && (strings.HasPrefix(sniffed, "text/html") || strings.HasPrefix(sniffed, "text/xml")) {
+		return ErrUploadContentMismatch
+	}
+
+	if requiredEntry, ok := zipBasedExtensions[ext]; ok {
+		if sniffed != "application/zip" {
+			return ErrUploadContentMismatch
+		}
+		if full != nil && !zipContainsEntry(full, size, requiredEntry) {
+			return ErrUploadContentMismatch
+		}
+	}
+
+	return nil
+}
+
+// zipContainsEntry reports whether the size bytes readable through r,
+// read as a ZIP archive, contain a file at name. Content that doesn't
+// parse as a ZIP at all reports false rather than erroring, since
+// VerifyUploadContentType has already confirmed it sniffs as one before
+// calling this.
+func zipContainsEntry(r io.ReaderAt, size int64, name string) bool {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return false
+	}
+	for _, f := range zr.File {
+		if f.Name == name {
+			return true
+		}
+	}
+	return false
+}
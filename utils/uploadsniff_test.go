@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+)
+
+func makeZip(t *testing.T, entries map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range entries {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestVerifyUploadContentTypeRejectsExecutableUnderSafeExtension(t *testing.T) {
+	elf := append([]byte("\x7fELF"), bytes.Repeat([]byte{0}, 60)...)
+	if err := VerifyUploadContentType(elf, ".pdf", nil, 0); err != ErrUploadContentMismatch {
+		t.Errorf("expected ErrUploadContentMismatch, got %v", err)
+	}
+}
+
+func TestVerifyUploadContentTypeAllowsExecutableUnderItsOwnExtension(t *testing.T) {
+	elf := append([]byte("\x7fELF"), bytes.Repeat([]byte{0}, 60)...)
+	if err := VerifyUploadContentType(elf, ".bin", nil, 0); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestVerifyUploadContentTypeAllowsPlainText(t *testing.T) {
+	if err := VerifyUploadContentType([]byte("hello world"), ".txt", nil, 0); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func sniffHeaderOf(data []byte) []byte {
+	if len(data) > 512 {
+		return data[:512]
+	}
+	return data
+}
+
+func TestVerifyUploadContentTypeRejectsDocxMissingItsDefiningEntry(t *testing.T) {
+	data := makeZip(t, map[string]string{"some/other/file.xml": "<xml/>"})
+	if err := VerifyUploadContentType(sniffHeaderOf(data), ".docx", bytes.NewReader(data), int64(len(data))); err != ErrUploadContentMismatch {
+		t.Errorf("expected ErrUploadContentMismatch, got %v", err)
+	}
+}
+
+func TestVerifyUploadContentTypeAcceptsDocxWithItsDefiningEntry(t *testing.T) {
+	data := makeZip(t, map[string]string{"word/document.xml": "<xml/>"})
+	if err := VerifyUploadContentType(sniffHeaderOf(data), ".docx", bytes.NewReader(data), int64(len(data))); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestVerifyUploadContentTypeRejectsHTMLUnderImageExtension(t *testing.T) {
+	html := []byte("<!DOCTYPE html><html><body><script>alert(1)</script></body></html>")
+	if err := VerifyUploadContentType(html, ".png", nil, 0); err != ErrUploadContentMismatch {
+		t.Errorf("expected ErrUploadContentMismatch, got %v", err)
+	}
+}
+
+func TestVerifyUploadContentTypeAllowsRealImage(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n")
+	if err := VerifyUploadContentType(png, ".png", nil, 0); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestVerifyUploadContentTypeRejectsNonZipUnderDocxExtension(t *testing.T) {
+	if err := VerifyUploadContentType([]byte("not a zip file"), ".docx", nil, 0); err != ErrUploadContentMismatch {
+		t.Errorf("expected ErrUploadContentMismatch, got %v", err)
+	}
+}
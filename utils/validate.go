@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// IsBodyTooLarge reports whether err was ultimately caused by a request
+// body that exceeded a middleware.MaxBodyBytes cap, however many layers
+// of wrapping (e.g. multipart's own "NextPart: ..." wrapping) sit
+// between the http.MaxBytesReader and the caller.
+func IsBodyTooLarge(err error) bool {
+	var tooLarge *http.MaxBytesError
+	return errors.As(err, &tooLarge)
+}
+
+// FieldError describes a single failed validation rule in a form the
+// frontend can render without parsing English prose.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// zhMessages maps a validator tag to a Chinese message template. %s
+// placeholders are filled with the field name and, where the template
+// takes a second verb, the rule's parameter (e.g. min=1 -> "1").
+var zhMessages = map[string]string{
+	"required": "%s 为必填项",
+	"min":      "%s 不能小于 %s",
+	"max":      "%s 不能大于 %s",
+	"len":      "%s 长度必须为 %s",
+	"email":    "%s 不是合法的邮箱地址",
+	"oneof":    "%s 必须是以下之一: %s",
+	"gt":       "%s 必须大于 %s",
+	"gte":      "%s 必须大于或等于 %s",
+	"lt":       "%s 必须小于 %s",
+	"lte":      "%s 必须小于或等于 %s",
+}
+
+// BindJSON binds the request body into obj and, on failure, writes a
+// response itself: field-level validation failures are translated via
+// FailValidation, a body over a middleware.MaxBodyBytes cap is reported
+// as 413 rather than the raw reader error, and anything else (malformed
+// JSON, type mismatches) falls back to Fail with the raw error. Callers
+// should return immediately when it reports false.
+func BindJSON(c *gin.Context, obj any) bool {
+	if err := c.ShouldBindJSON(obj); err != nil {
+		var verrs validator.ValidationErrors
+		switch {
+		case errors.As(err, &verrs):
+			FailValidation(c, translateValidationErrors(obj, verrs))
+		case IsBodyTooLarge(err):
+			Fail(c, 413, "request body too large")
+		default:
+			Fail(c, 400, err.Error())
+		}
+		return false
+	}
+	return true
+}
+
+// translateValidationErrors converts validator.ValidationErrors into
+// field-level errors keyed by each field's json tag.
+func translateValidationErrors(obj any, verrs validator.ValidationErrors) []FieldError {
+	t := reflect.TypeOf(obj)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	out := make([]FieldError, 0, len(verrs))
+	for _, fe := range verrs {
+		field := jsonFieldName(t, fe.StructField())
+		out = append(out, FieldError{
+			Field:   field,
+			Rule:    fe.Tag(),
+			Message: messageFor(field, fe),
+		})
+	}
+	return out
+}
+
+// jsonFieldName resolves structField's json tag name, falling back to
+// its lowercased Go name if the field is missing, untagged, or tagged "-".
+func jsonFieldName(t reflect.Type, structField string) string {
+	if t.Kind() != reflect.Struct {
+		return strings.ToLower(structField)
+	}
+	f, ok := t.FieldByName(structField)
+	if !ok {
+		return strings.ToLower(structField)
+	}
+	tag := strings.Split(f.Tag.Get("json"), ",")[0]
+	if tag == "" || tag == "-" {
+		return strings.ToLower(structField)
+	}
+	return tag
+}
+
+// messageFor renders a zh message for fe under the given (json-tag)
+// field name, falling back to an English description of the failed
+// rule when no zh template is registered.
+func messageFor(field string, fe validator.FieldError) string {
+	tmpl, ok := zhMessages[fe.Tag()]
+	if !ok {
+		if fe.Param() != "" {
+			return fmt.Sprintf("%s failed validation on the '%s=%s' rule", field, fe.Tag(), fe.Param())
+		}
+		return fmt.Sprintf("%s failed validation on the '%s' rule", field, fe.Tag())
+	}
+	if strings.Count(tmpl, "%s") == 2 {
+		return fmt.Sprintf(tmpl, field, fe.Param())
+	}
+	return fmt.Sprintf(tmpl, field)
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type testBindRequest struct {
+	Title string `json:"title" binding:"required"`
+	Slug  string `json:"slug" binding:"required"`
+}
+
+func TestBindJSONTranslatesMissingFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/", strings.NewReader(`{}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	var req testBindRequest
+	if ok := BindJSON(c, &req); ok {
+		t.Fatal("expected BindJSON to report failure for missing fields")
+	}
+	if w.Code != 400 {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+
+	var resp struct {
+		Code    int          `json:"code"`
+		Message string       `json:"message"`
+		Details []FieldError `json:"details"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	if len(resp.Details) != 2 {
+		t.Fatalf("expected 2 field errors, got %d: %+v", len(resp.Details), resp.Details)
+	}
+
+	byField := map[string]FieldError{}
+	for _, fe := range resp.Details {
+		byField[fe.Field] = fe
+	}
+
+	for _, field := range []string{"title", "slug"} {
+		fe, ok := byField[field]
+		if !ok {
+			t.Fatalf("expected an error for field %q, got %+v", field, resp.Details)
+		}
+		if fe.Rule != "required" {
+			t.Errorf("field %q: expected rule 'required', got %q", field, fe.Rule)
+		}
+		if fe.Message == "" {
+			t.Errorf("field %q: expected a non-empty message", field)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// Package waveform computes a downsampled amplitude-peaks array from an
+// audio file, for rendering a waveform in the frontend player without it
+// having to decode the whole file client-side.
+//
+// Only uncompressed PCM WAV is decoded in this tree: mp3 and m4a are
+// compressed codecs and decoding them needs a real MP3/AAC decoder, which
+// this environment has no way to fetch or vendor. Callers should treat
+// ErrUnsupportedFormat as expected and non-fatal (see
+// service.GenerateBlogWaveform), not as a bug to fix here.
+package waveform
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+)
+
+// ErrUnsupportedFormat is returned when the input isn't a PCM WAV file.
+var ErrUnsupportedFormat = errors.New("waveform: unsupported audio format")
+
+// ComputePeaks decodes the PCM WAV file at path and returns peakCount
+// normalized (0..1) amplitude peaks, one per downsampled window spanning
+// the whole file.
+func ComputePeaks(path string, peakCount int) ([]float64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	samples, bitsPerSample, numChannels, err := decodeWAV(f)
+	if err != nil {
+		return nil, err
+	}
+	if len(samples) == 0 || peakCount <= 0 {
+		return nil, nil
+	}
+
+	return downsamplePeaks(samples, bitsPerSample, numChannels, peakCount), nil
+}
+
+// decodeWAV reads a RIFF/WAVE container and returns its raw PCM sample
+// bytes along with bitsPerSample and numChannels from the fmt chunk. Only
+// PCM (format tag 1) is supported.
+func decodeWAV(r io.Reader) (data []byte, bitsPerSample, numChannels int, err error) {
+	var riffHeader [12]byte
+	if _, err := io.ReadFull(r, riffHeader[:]); err != nil {
+		return nil, 0, 0, ErrUnsupportedFormat
+	}
+	if string(riffHeader[0:4]) != "RIFF" || string(riffHeader[8:12]) != "WAVE" {
+		return nil, 0, 0, ErrUnsupportedFormat
+	}
+
+	var formatTag uint16
+chunks:
+	for {
+		var chunkHeader [8]byte
+		if _, err := io.ReadFull(r, chunkHeader[:]); err != nil {
+			break
+		}
+		chunkID := string(chunkHeader[0:4])
+		chunkSize := binary.LittleEndian.Uint32(chunkHeader[4:8])
+
+		switch chunkID {
+		case "fmt ":
+			body := make([]byte, chunkSize)
+			if _, err := io.ReadFull(r, body); err != nil {
+				return nil, 0, 0, ErrUnsupportedFormat
+			}
+			if len(body) < 16 {
+				return nil, 0, 0, ErrUnsupportedFormat
+			}
+			formatTag = binary.LittleEndian.Uint16(body[0:2])
+			numChannels = int(binary.LittleEndian.Uint16(body[2:4]))
+			bitsPerSample = int(binary.LittleEndian.Uint16(body[14:16]))
+		case "data":
+			data = make([]byte, chunkSize)
+			if _, err := io.ReadFull(r, data); err != nil {
+				return nil, 0, 0, ErrUnsupportedFormat
+			}
+		default:
+			if _, err := io.CopyN(io.Discard, r, int64(chunkSize)); err != nil {
+				break chunks
+			}
+		}
+		// chunkSize is padded to an even byte boundary.
+		if chunkSize%2 == 1 {
+			io.CopyN(io.Discard, r, 1)
+		}
+		if formatTag != 0 && data != nil {
+			break
+		}
+	}
+
+	if formatTag != 1 || bitsPerSample != 16 || numChannels < 1 || data == nil {
+		return nil, 0, 0, ErrUnsupportedFormat
+	}
+	return data, bitsPerSample, numChannels, nil
+}
+
+// downsamplePeaks reduces interleaved 16-bit PCM samples to peakCount
+// normalized peaks, each the maximum absolute amplitude within its window
+// across all channels.
+func downsamplePeaks(data []byte, bitsPerSample, numChannels, peakCount int) []float64 {
+	bytesPerSample := bitsPerSample / 8
+	frameSize := bytesPerSample * numChannels
+	totalFrames := len(data) / frameSize
+	if totalFrames == 0 {
+		return nil
+	}
+
+	peaks := make([]float64, peakCount)
+	framesPerPeak := float64(totalFrames) / float64(peakCount)
+
+	for i := 0; i < peakCount; i++ {
+		start := int(float64(i) * framesPerPeak)
+		end := int(float64(i+1) * framesPerPeak)
+		if end <= start {
+			end = start + 1
+		}
+		if end > totalFrames {
+			end = totalFrames
+		}
+
+		var max float64
+		for frame := start; frame < end; frame++ {
+			for ch := 0; ch < numChannels; ch++ {
+				offset := frame*frameSize + ch*bytesPerSample
+				sample := int16(binary.LittleEndian.Uint16(data[offset : offset+2]))
+				amplitude := abs(float64(sample) / 32768.0)
+				if amplitude > max {
+					max = amplitude
+				}
+			}
+		}
+		peaks[i] = max
+	}
+	return peaks
+}
+
+func abs(v float64) float64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,77 @@
+package waveform
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTestWAV builds a minimal mono 16-bit PCM WAV file containing
+// samples, for use as a ComputePeaks fixture.
+func writeTestWAV(t *testing.T, samples []int16) string {
+	t.Helper()
+
+	var data bytes.Buffer
+	for _, s := range samples {
+		binary.Write(&data, binary.LittleEndian, s)
+	}
+
+	var fmtChunk bytes.Buffer
+	binary.Write(&fmtChunk, binary.LittleEndian, uint16(1))  // PCM
+	binary.Write(&fmtChunk, binary.LittleEndian, uint16(1))  // mono
+	binary.Write(&fmtChunk, binary.LittleEndian, uint32(44100))
+	binary.Write(&fmtChunk, binary.LittleEndian, uint32(44100*2))
+	binary.Write(&fmtChunk, binary.LittleEndian, uint16(2))
+	binary.Write(&fmtChunk, binary.LittleEndian, uint16(16))
+
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(4+8+fmtChunk.Len()+8+data.Len()))
+	buf.WriteString("WAVE")
+	buf.WriteString("fmt ")
+	binary.Write(&buf, binary.LittleEndian, uint32(fmtChunk.Len()))
+	buf.Write(fmtChunk.Bytes())
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, uint32(data.Len()))
+	buf.Write(data.Bytes())
+
+	path := filepath.Join(t.TempDir(), "test.wav")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatalf("failed to write test wav: %v", err)
+	}
+	return path
+}
+
+func TestComputePeaksDownsamplesWAV(t *testing.T) {
+	samples := make([]int16, 1000)
+	for i := range samples {
+		samples[i] = 16384
+	}
+	path := writeTestWAV(t, samples)
+
+	peaks, err := ComputePeaks(path, 10)
+	if err != nil {
+		t.Fatalf("ComputePeaks returned error: %v", err)
+	}
+	if len(peaks) != 10 {
+		t.Fatalf("expected 10 peaks, got %d", len(peaks))
+	}
+	for _, p := range peaks {
+		if p < 0.49 || p > 0.51 {
+			t.Errorf("expected peaks near 0.5, got %v", p)
+		}
+	}
+}
+
+func TestComputePeaksRejectsUnsupportedFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fake.mp3")
+	if err := os.WriteFile(path, []byte("not a wav file"), 0o644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+
+	if _, err := ComputePeaks(path, 10); err != ErrUnsupportedFormat {
+		t.Errorf("expected ErrUnsupportedFormat, got %v", err)
+	}
+}